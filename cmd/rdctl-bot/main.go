@@ -2,14 +2,17 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // the scratch image ships no zoneinfo for app.timezone
 
 	"github.com/crazyuploader/rdctl-bot/internal/bot"
 	"github.com/crazyuploader/rdctl-bot/internal/config"
@@ -77,10 +80,9 @@ The bot also supports direct message handling:
 		Use:   "version",
 		Short: "Print version information",
 		Long:  "Display the version, build date, and git commit of the bot",
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Printf("rdctl-bot version %s\n", Version)
-			fmt.Printf("Build date: %s\n", BuildDate)
-			fmt.Printf("Git commit: %s\n", GitCommit)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString("output")
+			return writeVersion(cmd.OutOrStdout(), output)
 		},
 	}
 
@@ -93,6 +95,26 @@ The bot also supports direct message handling:
 	}
 )
 
+// versionInfo is the JSON form of the version command output
+type versionInfo struct {
+	Version   string `json:"version"`
+	BuildDate string `json:"build_date"`
+	GitCommit string `json:"git_commit"`
+}
+
+// writeVersion prints the build information to w in the given output format
+func writeVersion(w io.Writer, output string) error {
+	switch output {
+	case "", "text":
+		_, err := fmt.Fprintf(w, "rdctl-bot version %s\nBuild date: %s\nGit commit: %s\n", Version, BuildDate, GitCommit)
+		return err
+	case "json":
+		return json.NewEncoder(w).Encode(versionInfo{Version: Version, BuildDate: BuildDate, GitCommit: GitCommit})
+	default:
+		return fmt.Errorf("unknown output format %q (want text or json)", output)
+	}
+}
+
 // init configures CLI flags, binds them to viper configuration keys, and registers subcommands.
 func init() {
 	// Initialize Cobra
@@ -106,6 +128,7 @@ func init() {
 	rootCmd.Flags().Duration("shutdown-timeout", 10*time.Second, "timeout for graceful shutdown")
 	rootCmd.Flags().Bool("validate-config", false, "validate configuration and exit")
 	rootCmd.Flags().Bool("web-only", false, "enable web-only mode (disable Telegram bot)")
+	versionCmd.Flags().StringP("output", "o", "text", "output format: text or json")
 
 	// Bind flags to viper
 	if err := viper.BindPFlag("app.debug", rootCmd.PersistentFlags().Lookup("debug")); err != nil {
@@ -168,6 +191,12 @@ func runBot(cmd *cobra.Command, args []string) {
 		log.Fatalf("Invalid configuration: %v", err)
 	}
 
+	units, err := realdebrid.ParseSizeUnits(cfg.App.SizeUnits)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	realdebrid.SetSizeUnits(units)
+
 	// Validate config and exit if flag is set
 	validateOnly, _ := cmd.Flags().GetBool("validate-config")
 	if validateOnly {
@@ -187,7 +216,12 @@ func runBot(cmd *cobra.Command, args []string) {
 	defer stop()
 
 	// Initialize database
-	database, err := db.Init(ctx, cfg.Database.GetDSN())
+	database, err := db.Init(ctx, cfg.Database.GetDSN(), db.Options{
+		LogLevel:      cfg.Database.LogLevel,
+		SlowThreshold: time.Duration(cfg.Database.SlowThresholdMs) * time.Millisecond,
+		AutoMigrate:   cfg.Database.AutoMigrate,
+		HealthCheck:   time.Duration(cfg.Database.HealthCheckSeconds) * time.Second,
+	})
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
@@ -235,11 +269,17 @@ func runBot(cmd *cobra.Command, args []string) {
 		}
 		// Connect token store to bot for /dashboard command
 		b.SetTokenStore(tokenStore)
+		b.RegisterShutdownHook(func(context.Context) error {
+			tokenStore.Stop()
+			return nil
+		})
 	}
 
 	// Initialize dependencies for web handlers
+	webRDClient := realdebrid.NewClientWithTransport(cfg.RealDebrid.BaseURL, cfg.RealDebrid.APIToken, cfg.RealDebrid.Proxy, time.Duration(cfg.RealDebrid.Timeout)*time.Second, cfg.RealDebrid.Transport.Options())
+	webRDClient.SetSlowThreshold(time.Duration(cfg.RealDebrid.SlowThreshold) * time.Millisecond)
 	deps := web.Dependencies{
-		RDClient:     realdebrid.NewClient(cfg.RealDebrid.BaseURL, cfg.RealDebrid.APIToken, cfg.RealDebrid.Proxy, time.Duration(cfg.RealDebrid.Timeout)*time.Second),
+		RDClient:     webRDClient,
 		UserRepo:     db.NewUserRepository(database),
 		ActivityRepo: db.NewActivityRepository(database),
 		TorrentRepo:  db.NewTorrentRepository(database),
@@ -249,6 +289,21 @@ func runBot(cmd *cobra.Command, args []string) {
 		KeptRepo:     db.NewKeptTorrentRepository(database),
 		Config:       cfg,
 		TokenStore:   tokenStore,
+		DB:           database,
+	}
+	if b != nil {
+		deps.Bot = b
+	}
+
+	activityLogging := db.ActivityLogging(cfg.App.ActivityLogging)
+	deps.ActivityRepo.SetActivityLogging(activityLogging)
+	deps.TorrentRepo.SetActivityLogging(activityLogging)
+	deps.DownloadRepo.SetActivityLogging(activityLogging)
+
+	// Share one metrics cache between Prometheus, the web API and the /metrics command
+	deps.Metrics = web.NewRDCollector(deps)
+	if b != nil {
+		b.SetMetrics(deps.Metrics)
 	}
 
 	// Initialize web server
@@ -264,6 +319,28 @@ func runBot(cmd *cobra.Command, args []string) {
 		}
 	}()
 
+	// Log a one-line summary once the web server is accepting connections
+	go func() {
+		select {
+		case <-webServer.Ready():
+		case <-ctx.Done():
+			return
+		}
+		summary := startupSummary{
+			Version:      Version,
+			AllowedChats: len(cfg.Telegram.AllowedChatIDs),
+			SuperAdmins:  len(cfg.Telegram.SuperAdminIDs),
+			RDAccount:    rdAccountType(webRDClient),
+			Proxy:        cfg.RealDebrid.Proxy != "",
+			WebAddr:      cfg.Web.ListenAddr,
+			DBConnected:  dbConnected(ctx, database),
+		}
+		if b != nil {
+			summary.BotUsername = b.Username()
+		}
+		log.Printf("Startup complete: %s", summary)
+	}()
+
 	if !webOnly {
 		// Start bot in goroutine
 		go func() {
@@ -308,9 +385,7 @@ func runBot(cmd *cobra.Command, args []string) {
 
 		// Stop bot and close database if bot was running
 		if !webOnly && b != nil {
-			b.Stop()
-			// Also stop token store cleanup
-			tokenStore.Stop()
+			b.Stop(shutdownCtx)
 			log.Println("Bot cleanup completed")
 		} else {
 			// Stop token store cleanup even in web-only mode
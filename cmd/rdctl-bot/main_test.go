@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestWriteVersion_JSON verifies the JSON output carries the build information.
+func TestWriteVersion_JSON(t *testing.T) {
+	Version, BuildDate, GitCommit = "1.2.3", "2024-01-02", "abc123"
+
+	var buf bytes.Buffer
+	if err := writeVersion(&buf, "json"); err != nil {
+		t.Fatalf("writeVersion() error = %v", err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+	}
+	want := map[string]string{"version": "1.2.3", "build_date": "2024-01-02", "git_commit": "abc123"}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+// TestWriteVersion_Text verifies the default output stays human readable and unknown formats fail.
+func TestWriteVersion_Text(t *testing.T) {
+	Version = "1.2.3"
+
+	var buf bytes.Buffer
+	if err := writeVersion(&buf, "text"); err != nil {
+		t.Fatalf("writeVersion() error = %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "rdctl-bot version 1.2.3\n") {
+		t.Errorf("text output = %q", buf.String())
+	}
+	if err := writeVersion(&buf, "yaml"); err == nil {
+		t.Error("writeVersion(yaml) succeeded, want error")
+	}
+}
+
+// TestStartupSummary verifies every field appears in the startup summary, with readable fallbacks.
+func TestStartupSummary(t *testing.T) {
+	summary := startupSummary{
+		Version:      "1.2.3",
+		BotUsername:  "rdbot",
+		AllowedChats: 3,
+		SuperAdmins:  1,
+		RDAccount:    "premium",
+		Proxy:        true,
+		WebAddr:      ":8080",
+		DBConnected:  true,
+	}
+	want := "version=1.2.3 bot=@rdbot allowed_chats=3 superadmins=1 rd_account=premium proxy=true web=:8080 db=connected"
+	if got := summary.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	got := startupSummary{Version: "dev", WebAddr: ":8080"}.String()
+	for _, want := range []string{"bot=disabled", "rd_account=unknown", "proxy=false", "db=unreachable"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("String() = %q, missing %q", got, want)
+		}
+	}
+}
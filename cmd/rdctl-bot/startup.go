@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// startupSummary is the one-line overview logged once everything has initialized
+type startupSummary struct {
+	Version      string
+	BotUsername  string // empty in web-only mode
+	AllowedChats int
+	SuperAdmins  int
+	RDAccount    string // Real-Debrid account type, e.g. "premium"
+	Proxy        bool
+	WebAddr      string
+	DBConnected  bool
+}
+
+// String renders the summary as space separated key=value pairs
+func (s startupSummary) String() string {
+	bot := "disabled"
+	if s.BotUsername != "" {
+		bot = "@" + s.BotUsername
+	}
+	rdAccount := s.RDAccount
+	if rdAccount == "" {
+		rdAccount = "unknown"
+	}
+	db := "unreachable"
+	if s.DBConnected {
+		db = "connected"
+	}
+
+	fields := []string{
+		"version=" + s.Version,
+		"bot=" + bot,
+		fmt.Sprintf("allowed_chats=%d", s.AllowedChats),
+		fmt.Sprintf("superadmins=%d", s.SuperAdmins),
+		"rd_account=" + rdAccount,
+		fmt.Sprintf("proxy=%t", s.Proxy),
+		"web=" + s.WebAddr,
+		"db=" + db,
+	}
+	return strings.Join(fields, " ")
+}
+
+// rdAccountType returns the Real-Debrid account type, or "" if it cannot be fetched
+func rdAccountType(client *realdebrid.Client) string {
+	user, err := client.GetUser()
+	if err != nil {
+		log.Printf("Warning: failed to fetch Real-Debrid account for startup summary: %v", err)
+		return ""
+	}
+	return user.Type
+}
+
+// dbConnected reports whether the database answers a ping
+func dbConnected(ctx context.Context, pool *pgxpool.Pool) bool {
+	return pool.Ping(ctx) == nil
+}
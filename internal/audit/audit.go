@@ -0,0 +1,195 @@
+// Package audit mirrors the bot's command and activity logs to an external system,
+// such as a SIEM ingesting JSON lines from a file or an HTTP endpoint.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Event is one command or activity log entry
+type Event struct {
+	Time       time.Time              `json:"time"`
+	Type       string                 `json:"type"`    // "command", or the activity type such as "torrent_add"
+	UserID     int64                  `json:"user_id"` // Telegram user ID
+	Username   string                 `json:"username,omitempty"`
+	ChatPK     int64                  `json:"chat_pk"` // internal chats.id, as in the database
+	ThreadID   int                    `json:"thread_id,omitempty"`
+	MessageID  int64                  `json:"message_id,omitempty"`
+	Command    string                 `json:"command,omitempty"`
+	Text       string                 `json:"text,omitempty"` // full command text
+	Success    bool                   `json:"success"`
+	Error      string                 `json:"error,omitempty"`
+	DurationMs int64                  `json:"duration_ms,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Sink delivers events to an external system
+type Sink interface {
+	Write(ctx context.Context, e Event) error
+	Close() error
+}
+
+// FileSink appends events to a file, one JSON object per line
+type FileSink struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+}
+
+// NewFileSink opens path for appending, creating it if needed
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file: %w", err)
+	}
+	return &FileSink{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+// Write appends e as a JSON line
+func (s *FileSink) Write(_ context.Context, e Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(e)
+}
+
+// Close closes the file
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
+
+// HTTPSink POSTs each event as a JSON body to an endpoint
+type HTTPSink struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewHTTPSink creates an HTTPSink posting to url. token, if set, is sent as a bearer token.
+func NewHTTPSink(url, token string, timeout time.Duration) *HTTPSink {
+	return &HTTPSink{url: url, token: token, client: &http.Client{Timeout: timeout}}
+}
+
+// Write posts e, failing on any non-2xx response
+func (s *HTTPSink) Write(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("audit endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Close releases idle connections
+func (s *HTTPSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// Buffered hands events to a Sink from a background goroutine, so a slow or
+// unreachable sink never holds up the caller. Events arriving while the buffer is
+// full, or after Close, are dropped and counted. A nil Buffered drops everything.
+type Buffered struct {
+	sink    Sink
+	events  chan Event
+	done    chan struct{}
+	mu      sync.RWMutex // guards closed against sends on the closed channel
+	closed  bool
+	dropped atomic.Int64
+}
+
+// NewBuffered starts delivering events to sink, holding up to size of them
+func NewBuffered(sink Sink, size int) *Buffered {
+	b := &Buffered{sink: sink, events: make(chan Event, size), done: make(chan struct{})}
+	go b.run()
+	return b
+}
+
+// run writes queued events until the buffer is closed and drained
+func (b *Buffered) run() {
+	defer close(b.done)
+	for e := range b.events {
+		if err := b.sink.Write(context.Background(), e); err != nil {
+			log.Printf("Warning: failed to write audit event %s: %v", e.Type, err)
+		}
+	}
+}
+
+// Emit queues e without blocking
+func (b *Buffered) Emit(e Event) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		b.dropped.Add(1)
+		return
+	}
+	select {
+	case b.events <- e:
+	default:
+		b.dropped.Add(1)
+	}
+}
+
+// Dropped returns how many events were discarded so far
+func (b *Buffered) Dropped() int64 {
+	if b == nil {
+		return 0
+	}
+	return b.dropped.Load()
+}
+
+// Close stops accepting events, waits until the queued ones are written or ctx is
+// done, and closes the sink
+func (b *Buffered) Close(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	close(b.events)
+	b.mu.Unlock()
+
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		return fmt.Errorf("audit sink not drained: %w", ctx.Err())
+	}
+	if n := b.dropped.Load(); n > 0 {
+		log.Printf("Audit sink dropped %d events", n)
+	}
+	return b.sink.Close()
+}
@@ -0,0 +1,140 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestFileSink verifies events are appended as JSON lines across reopenings.
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	for _, command := range []string{"add", "delete"} {
+		sink, err := NewFileSink(path)
+		if err != nil {
+			t.Fatalf("NewFileSink() error = %v", err)
+		}
+		if err := sink.Write(context.Background(), Event{Type: "command", UserID: 42, Command: command, Success: true}); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		if err := sink.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var commands []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("line %q is not an event: %v", scanner.Text(), err)
+		}
+		if e.UserID != 42 {
+			t.Errorf("user_id = %d, want 42", e.UserID)
+		}
+		commands = append(commands, e.Command)
+	}
+	if len(commands) != 2 || commands[0] != "add" || commands[1] != "delete" {
+		t.Errorf("commands = %v, want [add delete]", commands)
+	}
+}
+
+// TestHTTPSink verifies events are posted as JSON with the bearer token, and that
+// error responses are reported.
+func TestHTTPSink(t *testing.T) {
+	var got Event
+	status := http.StatusNoContent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	sink := NewHTTPSink(srv.URL, "secret", time.Second)
+	if err := sink.Write(context.Background(), Event{Type: "torrent_add", UserID: 7}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got.Type != "torrent_add" || got.UserID != 7 {
+		t.Errorf("posted %+v", got)
+	}
+	status = http.StatusInternalServerError
+	if err := sink.Write(context.Background(), Event{Type: "command"}); err == nil {
+		t.Error("Write() succeeded on a 500 response")
+	}
+}
+
+// blockingSink is a Sink whose writes wait until release is closed.
+type blockingSink struct {
+	release chan struct{}
+	written chan Event
+}
+
+func (s *blockingSink) Write(_ context.Context, e Event) error {
+	<-s.release
+	s.written <- e
+	return nil
+}
+
+func (s *blockingSink) Close() error { return nil }
+
+// TestBuffered_NeverBlocks verifies Emit returns at once while the sink is stuck,
+// dropping what doesn't fit, and that Close drains the buffer.
+func TestBuffered_NeverBlocks(t *testing.T) {
+	sink := &blockingSink{release: make(chan struct{}), written: make(chan Event, 10)}
+	b := NewBuffered(sink, 2)
+
+	done := make(chan struct{})
+	go func() {
+		// One event is held by the stuck writer, two fill the buffer, the rest are dropped
+		for i := 0; i < 6; i++ {
+			b.Emit(Event{Type: "command"})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Emit blocked on a stuck sink")
+	}
+	if d := b.Dropped(); d < 3 || d > 4 {
+		t.Errorf("Dropped() = %d, want 3 or 4", d)
+	}
+
+	close(sink.release)
+	if err := b.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if written := len(sink.written); int64(written)+b.Dropped() != 6 {
+		t.Errorf("written %d + dropped %d, want 6", written, b.Dropped())
+	}
+
+	b.Emit(Event{Type: "command"})
+	if b.Dropped() == 0 {
+		t.Error("Emit after Close was not dropped")
+	}
+}
+
+// TestBuffered_Nil verifies a disabled sink is a no-op.
+func TestBuffered_Nil(t *testing.T) {
+	var b *Buffered
+	b.Emit(Event{})
+	if err := b.Close(context.Background()); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// formatAccountSettings renders the RD account settings shown by /settings
+func formatAccountSettings(s *realdebrid.Settings) string {
+	var text strings.Builder
+	text.WriteString("<b>⚙️ Real-Debrid Account Settings</b>\n\n")
+
+	locale := s.Locale
+	if name, ok := s.Locales[s.Locale]; ok {
+		locale = fmt.Sprintf("%s (%s)", name, s.Locale)
+	}
+	fmt.Fprintf(&text, "<i>Locale:</i> %s\n", html.EscapeString(locale))
+	fmt.Fprintf(&text, "<i>Download port:</i> %s\n", html.EscapeString(s.DownloadPort))
+	fmt.Fprintf(&text, "<i>Streaming quality:</i> %s\n", html.EscapeString(s.StreamingQuality))
+	fmt.Fprintf(&text, "<i>Mobile streaming quality:</i> %s\n", html.EscapeString(s.MobileStreamingQuality))
+	if s.StreamingLanguagePreference != "" {
+		fmt.Fprintf(&text, "<i>Streaming language:</i> %s\n", html.EscapeString(s.StreamingLanguagePreference))
+	}
+	if len(s.StreamingQualities) > 0 {
+		fmt.Fprintf(&text, "\n<i>Available qualities:</i> %s\n", html.EscapeString(strings.Join(s.StreamingQualities, ", ")))
+	}
+	if len(s.DownloadPorts) > 0 {
+		fmt.Fprintf(&text, "<i>Available ports:</i> %s\n", html.EscapeString(strings.Join(s.DownloadPorts, ", ")))
+	}
+
+	text.WriteString("\nChange a setting with <code>/setsetting &lt;name&gt; &lt;value&gt;</code>.")
+	return text.String()
+}
+
+// handleSettingsCommand handles the /settings command (moderators and superadmins)
+func (b *Bot) handleSettingsCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "settings")
+
+		if !role.CanModerate() {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, b.localize(chatID, "error.moderator_only"), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "settings", update.Message.Text, startTime, false, "Unauthorized - not moderator", 0)
+			return
+		}
+
+		settings, err := b.rdClient.GetSettings()
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Failed to retrieve account settings: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "settings", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := formatAccountSettings(settings)
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "settings", update.Message.Text, startTime, true, "", len(text))
+	})
+}
+
+// handleSetSettingCommand handles the /setsetting command (superadmin only)
+func (b *Bot) handleSetSettingCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "setsetting")
+
+		if !role.IsSuperAdmin() {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, b.localize(chatID, "error.superadmin_only"), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "setsetting", update.Message.Text, startTime, false, "Unauthorized - not superadmin", 0)
+			return
+		}
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) != 3 || !slices.Contains(realdebrid.SettingNames, parts[1]) {
+			text := fmt.Sprintf("<b>Usage:</b> /setsetting &lt;name&gt; &lt;value&gt;\n\n<i>Settings:</i> %s", strings.Join(realdebrid.SettingNames, ", "))
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "setsetting", update.Message.Text, startTime, false, "Invalid arguments", 0)
+			return
+		}
+		name, value := parts[1], parts[2]
+
+		if err := b.rdClient.UpdateSettings(name, value); err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Failed to update setting: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "setsetting", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := fmt.Sprintf("<b>[OK]</b> <code>%s</code> set to <code>%s</code>.", html.EscapeString(name), html.EscapeString(value))
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "setsetting", update.Message.Text, startTime, true, "", len(text))
+	})
+}
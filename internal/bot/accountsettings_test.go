@@ -0,0 +1,25 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// TestFormatAccountSettings verifies the current values and the locale name are shown.
+func TestFormatAccountSettings(t *testing.T) {
+	text := formatAccountSettings(&realdebrid.Settings{
+		Locale:                 "fr",
+		Locales:                map[string]string{"fr": "Français"},
+		DownloadPort:           "secured",
+		StreamingQuality:       "1080",
+		MobileStreamingQuality: "720",
+		StreamingQualities:     []string{"original", "1080", "720"},
+	})
+	for _, want := range []string{"Français (fr)", "secured", "1080", "720", "original, 1080, 720", "/setsetting"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("formatAccountSettings() missing %q in %q", want, text)
+		}
+	}
+}
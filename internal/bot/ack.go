@@ -0,0 +1,81 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"slices"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// pendingReply is the reply to a command that may have been acknowledged up front.
+// If an acknowledgment was sent, the result replaces it; otherwise it is sent as usual.
+type pendingReply struct {
+	b        *Bot
+	chatID   int64
+	threadID int
+	replyTo  int
+	ackID    int // message ID of the acknowledgment, 0 if none was sent
+}
+
+// ackProcessing sends app.processing_ack.message right away if command is listed in
+// app.processing_ack.commands, so users of slow commands see they were heard while
+// the bot works through Real-Debrid calls and rate limits. The returned pendingReply
+// delivers the result.
+func (b *Bot) ackProcessing(ctx context.Context, chatID int64, messageThreadID, replyTo int, command string) *pendingReply {
+	p := &pendingReply{b: b, chatID: chatID, threadID: messageThreadID, replyTo: replyTo}
+	ack := b.config.App.ProcessingAck
+	if !slices.Contains(ack.Commands, command) {
+		return p
+	}
+
+	params := &bot.SendMessageParams{
+		ChatID:    chatID,
+		Text:      ack.Message,
+		ParseMode: models.ParseModeHTML,
+	}
+	if messageThreadID != 0 {
+		params.MessageThreadID = messageThreadID
+	}
+	if replyTo != 0 {
+		params.ReplyParameters = &models.ReplyParameters{MessageID: replyTo}
+	}
+	if err := b.middleware.WaitForRateLimitWithContext(ctx); err != nil {
+		return p
+	}
+	sent, err := b.api.SendMessage(ctx, params)
+	if err != nil {
+		log.Printf("Failed to send processing acknowledgment for /%s: %v", command, err)
+		return p
+	}
+	p.ackID = sent.ID
+	return p
+}
+
+// finish delivers text by editing the acknowledgment, or as a new reply if there is none
+// or it can no longer be edited
+func (p *pendingReply) finish(ctx context.Context, text string) {
+	p.finishWithMarkup(ctx, text, nil)
+}
+
+// finishWithMarkup is finish with reply markup such as inline buttons; nil adds none
+func (p *pendingReply) finishWithMarkup(ctx context.Context, text string, markup models.ReplyMarkup) {
+	if p.ackID != 0 {
+		if err := p.b.middleware.WaitForRateLimitWithContext(ctx); err != nil {
+			log.Printf("Rate limit error: %v", err)
+		}
+		_, err := p.b.api.EditMessageText(ctx, &bot.EditMessageTextParams{
+			ChatID:      p.chatID,
+			MessageID:   p.ackID,
+			Text:        p.b.withFooter(text),
+			ParseMode:   models.ParseModeHTML,
+			ReplyMarkup: markup,
+		})
+		if err == nil {
+			return
+		}
+		log.Printf("Failed to edit processing acknowledgment %d, sending the result instead: %v", p.ackID, err)
+	}
+	p.b.sendHTMLMessageWithMarkup(ctx, p.chatID, p.threadID, text, p.replyTo, markup)
+}
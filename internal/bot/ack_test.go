@@ -0,0 +1,57 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/config"
+)
+
+// newAckTestBot returns a Bot acknowledging the given commands and its recorded API requests
+func newAckTestBot(t *testing.T, commands ...string) (*Bot, func() []string) {
+	t.Helper()
+	api, requests := newTestTelegramAPI(t)
+	cfg := &config.Config{}
+	cfg.App.RateLimit = config.RateLimitConfig{MessagesPerSecond: 100, Burst: 10}
+	cfg.App.ProcessingAck = config.ProcessingAckConfig{Commands: commands, Message: "working on it"}
+	return &Bot{api: api, config: cfg, middleware: NewMiddleware(cfg)}, requests
+}
+
+// TestAckProcessing verifies a listed command is acknowledged before the result, which then
+// replaces the acknowledgment.
+func TestAckProcessing(t *testing.T) {
+	b, requests := newAckTestBot(t, "top")
+
+	reply := b.ackProcessing(context.Background(), -100, 0, 5, "top")
+	if got := requests(); len(got) != 1 {
+		t.Fatalf("requests after ack = %d, want the acknowledgment only", len(got))
+	}
+	reply.finish(context.Background(), "the result")
+
+	reqs := requests()
+	if len(reqs) != 2 {
+		t.Fatalf("requests = %v, want acknowledgment then result", reqs)
+	}
+	if !strings.HasSuffix(strings.SplitN(reqs[0], "\n", 2)[0], "/sendMessage") || !strings.Contains(reqs[0], "working on it") {
+		t.Errorf("first request is not the acknowledgment: %s", reqs[0])
+	}
+	if !strings.HasSuffix(strings.SplitN(reqs[1], "\n", 2)[0], "/editMessageText") || !strings.Contains(reqs[1], "the result") {
+		t.Errorf("second request does not edit in the result: %s", reqs[1])
+	}
+}
+
+// TestAckProcessing_NotListed verifies other commands get a single plain reply.
+func TestAckProcessing_NotListed(t *testing.T) {
+	b, requests := newAckTestBot(t, "top")
+
+	b.ackProcessing(context.Background(), -100, 0, 5, "list").finish(context.Background(), "the result")
+
+	reqs := requests()
+	if len(reqs) != 1 {
+		t.Fatalf("requests = %v, want a single reply", reqs)
+	}
+	if !strings.HasSuffix(strings.SplitN(reqs[0], "\n", 2)[0], "/sendMessage") || !strings.Contains(reqs[0], "the result") {
+		t.Errorf("reply = %s", reqs[0])
+	}
+}
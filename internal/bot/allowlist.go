@@ -0,0 +1,123 @@
+package bot
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// allowlistReloadDelay debounces bursts of file events (truncate + write, rename + create)
+// so a half-written file is not loaded
+const allowlistReloadDelay = 200 * time.Millisecond
+
+// parseAllowlist reads newline-separated chat IDs. Blank lines and lines starting with "#" are ignored.
+func parseAllowlist(r io.Reader) (map[int64]struct{}, error) {
+	ids := make(map[int64]struct{})
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		id, err := strconv.ParseInt(line, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid chat ID %q", lineNo, line)
+		}
+		ids[id] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// SetAllowlist replaces the live set of chat IDs allowed in addition to telegram.allowed_chat_ids
+func (m *Middleware) SetAllowlist(ids map[int64]struct{}) {
+	m.allowMu.Lock()
+	defer m.allowMu.Unlock()
+	m.fileAllowed = ids
+}
+
+// isFileAllowed reports whether chatID is in the live allowlist file set
+func (m *Middleware) isFileAllowed(chatID int64) bool {
+	m.allowMu.RLock()
+	defer m.allowMu.RUnlock()
+	_, ok := m.fileAllowed[chatID]
+	return ok
+}
+
+// LoadAllowlistFile reads path and replaces the live allowlist with its contents.
+// On error the previous allowlist is kept.
+func (m *Middleware) LoadAllowlistFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open allowlist file: %w", err)
+	}
+	defer f.Close()
+
+	ids, err := parseAllowlist(f)
+	if err != nil {
+		return fmt.Errorf("failed to parse allowlist file %s: %w", path, err)
+	}
+	m.SetAllowlist(ids)
+	return nil
+}
+
+// WatchAllowlistFile reloads the allowlist whenever path changes, until ctx is cancelled.
+// The parent directory is watched so editors that replace the file via rename are handled.
+func (m *Middleware) WatchAllowlistFile(ctx context.Context, path string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create allowlist watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to watch allowlist file: %w", err)
+	}
+
+	name := filepath.Clean(path)
+	reload := time.NewTimer(allowlistReloadDelay)
+	reload.Stop()
+	defer reload.Stop()
+
+	log.Printf("Allowlist watcher started for %s", path)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Allowlist watcher stopped")
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(event.Name) != name || !event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+				continue
+			}
+			reload.Reset(allowlistReloadDelay)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("Allowlist watcher error: %v", err)
+		case <-reload.C:
+			if err := m.LoadAllowlistFile(path); err != nil {
+				log.Printf("Allowlist reload failed, keeping previous list: %v", err)
+				continue
+			}
+			log.Printf("Allowlist reloaded from %s", path)
+		}
+	}
+}
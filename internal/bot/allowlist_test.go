@@ -0,0 +1,106 @@
+package bot
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/config"
+)
+
+// TestParseAllowlist verifies IDs are read one per line, skipping blanks and comments.
+func TestParseAllowlist(t *testing.T) {
+	ids, err := parseAllowlist(strings.NewReader("# friends\n123\n\n  -100456  \n# 789\n"))
+	if err != nil {
+		t.Fatalf("parseAllowlist() error = %v", err)
+	}
+	if len(ids) != 2 {
+		t.Fatalf("got %d IDs, want 2: %v", len(ids), ids)
+	}
+	for _, id := range []int64{123, -100456} {
+		if _, ok := ids[id]; !ok {
+			t.Errorf("ID %d missing from %v", id, ids)
+		}
+	}
+
+	if _, err := parseAllowlist(strings.NewReader("123\nabc\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("parseAllowlist() error = %v, want line 2 error", err)
+	}
+}
+
+// TestSetAllowlist_UpdatesAuthorization verifies that replacing the live set changes decisions
+// without touching the static config.
+func TestSetAllowlist_UpdatesAuthorization(t *testing.T) {
+	m := NewMiddleware(&config.Config{Telegram: config.TelegramConfig{AllowedChatIDs: []int64{-100}}})
+
+	if allowed, _ := m.CheckAuthorization(-200, 7); allowed {
+		t.Fatal("chat -200 allowed before being added to the allowlist")
+	}
+
+	m.SetAllowlist(map[int64]struct{}{-200: {}})
+	if allowed, _ := m.CheckAuthorization(-200, 7); !allowed {
+		t.Error("chat -200 denied after being added to the allowlist")
+	}
+	if allowed, _ := m.CheckAuthorization(-100, 7); !allowed {
+		t.Error("configured chat -100 denied after allowlist update")
+	}
+
+	m.SetAllowlist(nil)
+	if allowed, _ := m.CheckAuthorization(-200, 7); allowed {
+		t.Error("chat -200 still allowed after being removed from the allowlist")
+	}
+}
+
+// TestWatchAllowlistFile verifies edits to the file are picked up and a broken edit keeps the old list.
+func TestWatchAllowlistFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "allowlist.txt")
+	if err := os.WriteFile(path, []byte("111\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewMiddleware(&config.Config{})
+	if err := m.LoadAllowlistFile(path); err != nil {
+		t.Fatalf("LoadAllowlistFile() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.WatchAllowlistFile(ctx, path) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("WatchAllowlistFile() error = %v", err)
+		}
+	}()
+
+	waitFor := func(chatID int64, want bool) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if allowed, _ := m.CheckAuthorization(chatID, 1); allowed == want {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		t.Fatalf("chat %d allowed != %v after waiting for reload", chatID, want)
+	}
+
+	// Give the watcher a moment to register before editing
+	time.Sleep(50 * time.Millisecond)
+	if err := os.WriteFile(path, []byte("222\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(222, true)
+	waitFor(111, false)
+
+	if err := os.WriteFile(path, []byte("not-an-id\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(4 * allowlistReloadDelay)
+	if allowed, _ := m.CheckAuthorization(222, 1); !allowed {
+		t.Error("invalid edit dropped the previous allowlist")
+	}
+}
@@ -0,0 +1,26 @@
+package bot
+
+import (
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/audit"
+	"github.com/crazyuploader/rdctl-bot/internal/config"
+)
+
+// newAuditSink creates the sink configured in app.audit_sink, or nil when none is
+func newAuditSink(cfg config.AuditSinkConfig) (*audit.Buffered, error) {
+	var sink audit.Sink
+	switch cfg.Type {
+	case "file":
+		fileSink, err := audit.NewFileSink(cfg.Path)
+		if err != nil {
+			return nil, err
+		}
+		sink = fileSink
+	case "http":
+		sink = audit.NewHTTPSink(cfg.URL, cfg.Token, time.Duration(cfg.TimeoutSeconds)*time.Second)
+	default:
+		return nil, nil
+	}
+	return audit.NewBuffered(sink, cfg.BufferSize), nil
+}
@@ -46,17 +46,13 @@ func (b *Bot) getAutoDeleteCheckInterval(ctx context.Context) time.Duration {
 
 // handleAutoDeleteCommand handles the /autodelete command (superadmin only)
 func (b *Bot) handleAutoDeleteCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
-	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, isSuperAdmin bool, user *db.User) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
 		startTime := time.Now()
 		b.middleware.LogCommand(update, "autodelete")
 
-		if !isSuperAdmin {
-			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>[ERROR]</b> Access Denied. This command is for superadmins only.", update.Message.ID)
-			if user != nil {
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "autodelete", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), false, "Unauthorized - not superadmin", 0); err != nil {
-					log.Printf("Warning: failed to log unauthorized autodelete command: %v", err)
-				}
-			}
+		if !role.IsSuperAdmin() {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, b.localize(chatID, "error.superadmin_only"), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "autodelete", update.Message.Text, startTime, false, "Unauthorized - not superadmin", 0)
 			return
 		}
 
@@ -107,6 +103,12 @@ func (b *Bot) handleAutoDeleteCommand(ctx context.Context, _ *bot.Bot, update *m
 			return
 		}
 
+		// With a torrent ID and hours, schedule that torrent's deletion instead
+		if len(parts) >= 3 {
+			b.scheduleTorrentDeletion(ctx, update, user, chatID, chatPK, messageThreadID, parts[1], parts[2], startTime)
+			return
+		}
+
 		// Parse the days argument
 		daysStr := parts[1]
 		days, err := strconv.Atoi(daysStr)
@@ -143,17 +145,13 @@ func (b *Bot) handleAutoDeleteCommand(ctx context.Context, _ *bot.Bot, update *m
 
 // handleAutoDeleteIntervalCommand handles the /autodelete-interval command (superadmin only)
 func (b *Bot) handleAutoDeleteIntervalCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
-	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, isSuperAdmin bool, user *db.User) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
 		startTime := time.Now()
 		b.middleware.LogCommand(update, "autodelete-interval")
 
-		if !isSuperAdmin {
-			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>[ERROR]</b> Access Denied. This command is for superadmins only.", update.Message.ID)
-			if user != nil {
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "autodelete-interval", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), false, "Unauthorized - not superadmin", 0); err != nil {
-					log.Printf("Warning: failed to log unauthorized autodelete-interval command: %v", err)
-				}
-			}
+		if !role.IsSuperAdmin() {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, b.localize(chatID, "error.superadmin_only"), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "autodelete-interval", update.Message.Text, startTime, false, "Unauthorized - not superadmin", 0)
 			return
 		}
 
@@ -241,9 +239,11 @@ func (b *Bot) startAutoDeleteWorker(ctx context.Context) {
 	defer ticker.Stop()
 
 	log.Printf("Auto-delete worker started (checking every %s)", formatDuration(interval))
+	b.health.registerWorker("auto_delete")
 
 	// Run first check immediately on startup
 	b.runAutoDeleteCheck(ctx)
+	b.health.markWorkerRun("auto_delete")
 
 	for {
 		select {
@@ -252,6 +252,7 @@ func (b *Bot) startAutoDeleteWorker(ctx context.Context) {
 			return
 		case <-ticker.C:
 			b.runAutoDeleteCheck(ctx)
+			b.health.markWorkerRun("auto_delete")
 			// Re-read interval and reset ticker if it changed
 			newInterval := b.getAutoDeleteCheckInterval(ctx)
 			if newInterval != interval {
@@ -387,7 +388,7 @@ func (b *Bot) runAutoDeleteCheck(ctx context.Context) {
 		totalDeleted++
 
 		// Log the deletion to the DB for auditing (use system user ID)
-		if err := b.torrentRepo.LogTorrentActivity(ctx, "", b.systemUserID, 0, t.ID, t.Hash, t.Filename, "", "delete", "auto_deleted", t.Bytes, t.Progress, true, "", map[string]interface{}{"auto_delete_days": days}); err != nil {
+		if err := b.torrentRepo.LogTorrentActivity(ctx, "", b.systemUserID, b.systemChatPK, t.ID, t.Hash, t.Filename, "", "delete", "auto_deleted", t.Bytes, t.Progress, true, "", map[string]interface{}{"auto_delete_days": days}); err != nil {
 			log.Printf("Auto-delete: failed to log torrent deletion: %v", err)
 		}
 
@@ -552,7 +553,7 @@ func (b *Bot) runAutoDeleteDownloads(ctx context.Context, days int) {
 		log.Printf("Auto-delete: deleted download %s (%s), generated on %s", d.ID, d.Filename, d.Generated.Format("2006-01-02"))
 		successfullyDeleted = append(successfullyDeleted, d)
 
-		if err := b.downloadRepo.LogDownloadActivity(ctx, "", b.systemUserID, 0, d.ID, "", d.Filename, "", "delete", d.Filesize, true, "auto_deleted", nil, nil); err != nil {
+		if err := b.downloadRepo.LogDownloadActivity(ctx, "", b.systemUserID, b.systemChatPK, d.ID, "", d.Filename, "", "delete", d.Filesize, true, "auto_deleted", nil, nil); err != nil {
 			log.Printf("Auto-delete: failed to log download deletion: %v", err)
 		}
 
@@ -633,6 +634,7 @@ func (b *Bot) startAutoDeleteWarningWorker(ctx context.Context) {
 	defer ticker.Stop()
 
 	log.Println("Auto-delete warning worker started (checking every hour)")
+	b.health.registerWorker("auto_delete_warning")
 
 	// Run first check after a short delay; scan the full warning window so existing
 	// at-risk torrents are always notified, not just newly-entered ones.
@@ -641,6 +643,7 @@ func (b *Bot) startAutoDeleteWarningWorker(ctx context.Context) {
 		return
 	case <-time.After(30 * time.Second):
 		b.runAutoDeleteWarningCheck(ctx, true)
+		b.health.markWorkerRun("auto_delete_warning")
 	}
 
 	for {
@@ -650,6 +653,7 @@ func (b *Bot) startAutoDeleteWarningWorker(ctx context.Context) {
 			return
 		case <-ticker.C:
 			b.runAutoDeleteWarningCheck(ctx, false)
+			b.health.markWorkerRun("auto_delete_warning")
 		}
 	}
 }
@@ -0,0 +1,79 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// stuckTorrents returns the torrents that have waited for a file selection since at
+// least minAge before now. Torrents without an added time are skipped.
+func stuckTorrents(torrents []realdebrid.Torrent, now time.Time, minAge time.Duration) []realdebrid.Torrent {
+	var stuck []realdebrid.Torrent
+	for _, t := range torrents {
+		if t.Status != "waiting_files_selection" || t.Added.IsZero() {
+			continue
+		}
+		if now.Sub(t.Added) >= minAge {
+			stuck = append(stuck, t)
+		}
+	}
+	return stuck
+}
+
+// runAutoSelectSweep selects all files of every torrent stuck waiting for a file selection
+func (b *Bot) runAutoSelectSweep(ctx context.Context) {
+	torrents, err := b.fetchAllTorrents()
+	if err != nil {
+		log.Printf("Auto-select sweeper: failed to get torrents: %v", err)
+		return
+	}
+
+	minAge := time.Duration(b.config.RealDebrid.AutoSelectSweeper.MinAgeMinutes) * time.Minute
+	for _, t := range stuckTorrents(torrents, time.Now(), minAge) {
+		if ctx.Err() != nil {
+			return
+		}
+		waited := formatDuration(time.Since(t.Added))
+		if err := b.rdClient.SelectAllFiles(t.ID); err != nil {
+			log.Printf("Auto-select sweeper: failed to select files of %s (%s), waiting %s: %v", t.ID, t.Filename, waited, err)
+			if logErr := b.torrentRepo.LogTorrentActivity(ctx, "", b.systemUserID, b.systemChatPK, t.ID, t.Hash, t.Filename, "", "select_files", "error", t.Bytes, t.Progress, false, err.Error(), map[string]interface{}{"source": "auto_select_sweeper"}); logErr != nil {
+				log.Printf("Auto-select sweeper: failed to log error: %v", logErr)
+			}
+			continue
+		}
+		log.Printf("Auto-select sweeper: selected all files of %s (%s) after waiting %s", t.ID, t.Filename, waited)
+		if err := b.torrentRepo.LogTorrentActivity(ctx, "", b.systemUserID, b.systemChatPK, t.ID, t.Hash, t.Filename, "", "select_files", "files_selected", t.Bytes, t.Progress, true, "", map[string]interface{}{"source": "auto_select_sweeper"}); err != nil {
+			log.Printf("Auto-select sweeper: failed to log recovery: %v", err)
+		}
+	}
+}
+
+// startAutoSelectSweeper periodically selects all files of torrents left waiting for a
+// file selection, e.g. when the selection during /add failed. It returns immediately
+// unless realdebrid.auto_select_sweeper.enabled is set.
+func (b *Bot) startAutoSelectSweeper(ctx context.Context) {
+	cfg := b.config.RealDebrid.AutoSelectSweeper
+	if !cfg.Enabled {
+		return
+	}
+	interval := time.Duration(cfg.IntervalMinutes) * time.Minute
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Printf("Auto-select sweeper started (checking every %s for torrents waiting over %d minutes)", formatDuration(interval), cfg.MinAgeMinutes)
+	b.health.registerWorker("auto_select_sweeper")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Auto-select sweeper stopped")
+			return
+		case <-ticker.C:
+			b.runAutoSelectSweep(ctx)
+			b.health.markWorkerRun("auto_select_sweeper")
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// TestStuckTorrents verifies only torrents waiting for a selection longer than the
+// minimum age are picked up.
+func TestStuckTorrents(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	torrents := []realdebrid.Torrent{
+		{ID: "old", Status: "waiting_files_selection", Added: now.Add(-time.Hour)},
+		{ID: "exact", Status: "waiting_files_selection", Added: now.Add(-15 * time.Minute)},
+		{ID: "fresh", Status: "waiting_files_selection", Added: now.Add(-time.Minute)},
+		{ID: "downloading", Status: "downloading", Added: now.Add(-time.Hour)},
+		{ID: "converting", Status: "magnet_conversion", Added: now.Add(-time.Hour)},
+		{ID: "no date", Status: "waiting_files_selection"},
+	}
+
+	got := stuckTorrents(torrents, now, 15*time.Minute)
+	var ids []string
+	for _, s := range got {
+		ids = append(ids, s.ID)
+	}
+	if len(ids) != 2 || ids[0] != "old" || ids[1] != "exact" {
+		t.Errorf("stuckTorrents() = %v, want [old exact]", ids)
+	}
+}
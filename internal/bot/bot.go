@@ -2,14 +2,11 @@ package bot
 
 import (
 	"context"
-	cryptorand "crypto/rand"
 	"encoding/base64"
-	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
-	"math"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -17,13 +14,16 @@ import (
 	"sync"
 	"time"
 
+	"github.com/crazyuploader/rdctl-bot/internal/audit"
 	"github.com/crazyuploader/rdctl-bot/internal/config"
 	"github.com/crazyuploader/rdctl-bot/internal/db"
 	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/crazyuploader/rdctl-bot/internal/retry"
 	"github.com/crazyuploader/rdctl-bot/internal/web"
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/sync/singleflight"
 )
 
 // RealDebridClient defines the required interface for Real-Debrid operations.
@@ -42,30 +42,58 @@ type RealDebridClient interface {
 	GetDownloads(limit, offset int) ([]realdebrid.Download, error)
 	GetDownloadsWithCount(limit, offset int) (*realdebrid.DownloadsResult, error)
 	UnrestrictLink(link string) (*realdebrid.UnrestrictedLink, error)
+	CheckLink(link string) (*realdebrid.LinkCheck, error)
 	DeleteDownload(downloadID string) error
 	GetSupportedRegex() ([]string, error)
+	GetSettings() (*realdebrid.Settings, error)
+	UpdateSettings(name, value string) error
+	GetTraffic() (realdebrid.Traffic, error)
 }
 
 // Bot represents the Telegram bot
 type Bot struct {
-	api            *bot.Bot
-	rdClient       RealDebridClient
-	middleware     *Middleware
-	supportedRegex []*regexp.Regexp
-	config         *config.Config
-	db             *pgxpool.Pool
-	userRepo       *db.UserRepository
-	activityRepo   *db.ActivityRepository
-	torrentRepo    *db.TorrentRepository
-	downloadRepo   *db.DownloadRepository
-	commandRepo    *db.CommandRepository
-	settingRepo    *db.SettingRepository
-	keptRepo       *db.KeptTorrentRepository
-	chatRepo       *db.ChatRepository
-	tokenStore     *web.TokenStore
-	wg             sync.WaitGroup
-	cancel         context.CancelFunc
-	systemUserID   int64
+	api              *bot.Bot
+	rdClient         RealDebridClient
+	middleware       *Middleware
+	supportedRegex   []*regexp.Regexp
+	config           *config.Config
+	db               *pgxpool.Pool
+	userRepo         *db.UserRepository
+	activityRepo     *db.ActivityRepository
+	torrentRepo      *db.TorrentRepository
+	downloadRepo     *db.DownloadRepository
+	commandRepo      *db.CommandRepository
+	settingRepo      *db.SettingRepository
+	keptRepo         *db.KeptTorrentRepository
+	chatRepo         *db.ChatRepository
+	subscriptionRepo *db.SubscriptionRepository
+	scheduledRepo    *db.ScheduledDeletionRepository
+	queueRepo        *db.QueueRepository
+	prefRepo         *db.PreferenceRepository
+	audit            *audit.Buffered // mirrors command and activity logs; nil when disabled
+	tokenStore       *web.TokenStore
+	metrics          *web.RDCollector
+	ipTest           IPTestConfig
+	fetchClient      *http.Client   // downloads files for /fetch; shared so idle connections are reused
+	location         *time.Location // app.timezone, for times shown in replies
+	logLevel         *logLevel      // live log level, switched with /debug
+	prompts          *promptStore
+	statusBoards     *statusBoardStore
+	deleteBatches    *deleteBatchStore
+	infoRefreshes    *refreshLimiter
+	threads          *threadMemory
+	janitor          *janitor
+	userFlight       singleflight.Group
+	wg               sync.WaitGroup
+	cancel           context.CancelFunc
+	shutdownMu       sync.Mutex
+	shutdownHooks    []func(context.Context) error
+	systemUserID     int64
+	systemChatPK     int64
+	username         string // the bot's own Telegram username
+	bulkQueue        *bulkQueue
+	traffic          trafficCache
+	health           *healthState
 }
 
 // IPTestConfig holds configuration for proxy IP testing
@@ -79,17 +107,26 @@ type IPTestConfig struct {
 // NewBot creates and returns a fully configured Bot.
 func NewBot(cfg *config.Config, database *pgxpool.Pool, ipTest IPTestConfig) (*Bot, error) {
 	// Perform IP tests first
-	if err := performIPTests(ipTest); err != nil {
+	health := newHealthState()
+	ipResult, err := performIPTests(context.Background(), ipTest)
+	health.recordIPTest(ipResult, err)
+	if err != nil {
 		return nil, fmt.Errorf("IP test failed: %w", err)
 	}
 
-	// Create bot options
+	// Create bot options. Debug output is always produced and filtered by the live
+	// log level, so /debug can turn it on without recreating the client.
+	level := newLogLevel(cfg.App.LogLevel)
 	opts := []bot.Option{
 		bot.WithDefaultHandler(defaultHandler),
-	}
-
-	if cfg.App.LogLevel == "debug" {
-		opts = append(opts, bot.WithDebug())
+		bot.WithDebug(),
+		bot.WithDebugHandler(level.debugf),
+		bot.WithMiddlewares(health.trackUpdates),
+		bot.WithHTTPClient(telegramPollTimeout, newPollBackoff(
+			&http.Client{Timeout: telegramPollTimeout},
+			time.Duration(cfg.Telegram.Reconnect.InitialDelaySeconds)*time.Second,
+			time.Duration(cfg.Telegram.Reconnect.MaxDelaySeconds)*time.Second,
+		)),
 	}
 
 	// Create Telegram bot
@@ -99,15 +136,33 @@ func NewBot(cfg *config.Config, database *pgxpool.Pool, ipTest IPTestConfig) (*B
 	}
 
 	// Create Real-Debrid client
-	rdClient := realdebrid.NewClient(
+	rdClient := realdebrid.NewClientWithTransport(
 		cfg.RealDebrid.BaseURL,
 		cfg.RealDebrid.APIToken,
 		ipTest.ProxyURL,
 		time.Duration(cfg.RealDebrid.Timeout)*time.Second,
+		cfg.RealDebrid.Transport.Options(),
 	)
+	rdClient.SetSlowThreshold(time.Duration(cfg.RealDebrid.SlowThreshold) * time.Millisecond)
+	rdClient.SetDebug(level.isDebug())
 
 	// Create middleware
 	middleware := NewMiddleware(cfg)
+	for chat, locale := range cfg.Telegram.ChatLocales {
+		if !isKnownLocale(locale) {
+			log.Printf("Warning: unknown locale %q for chat %s, falling back to %s", locale, chat, defaultLocale)
+		}
+	}
+	if cfg.Telegram.AllowlistFile != "" {
+		if err := middleware.LoadAllowlistFile(cfg.Telegram.AllowlistFile); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.App.RateLimit.StateFile != "" {
+		if err := middleware.RestoreRateLimitState(cfg.App.RateLimit.StateFile); err != nil {
+			log.Printf("Warning: starting with a full rate limit burst: %v", err)
+		}
+	}
 
 	me, err := api.GetMe(context.Background())
 	if err != nil {
@@ -145,23 +200,59 @@ func NewBot(cfg *config.Config, database *pgxpool.Pool, ipTest IPTestConfig) (*B
 		log.Printf("Loaded %d supported host regexes", len(supportedRegex))
 	}
 
-	b := &Bot{
-		api:            api,
-		rdClient:       rdClient,
-		middleware:     middleware,
-		supportedRegex: supportedRegex,
-		config:         cfg,
-		db:             database,
-		userRepo:       db.NewUserRepository(database),
-		activityRepo:   db.NewActivityRepository(database),
-		torrentRepo:    db.NewTorrentRepository(database),
-		downloadRepo:   db.NewDownloadRepository(database),
-		commandRepo:    db.NewCommandRepository(database),
-		settingRepo:    db.NewSettingRepository(database),
-		keptRepo:       db.NewKeptTorrentRepository(database),
-		chatRepo:       db.NewChatRepository(database),
+	location, err := time.LoadLocation(cfg.App.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone: %w", err)
+	}
+
+	auditSink, err := newAuditSink(cfg.App.AuditSink)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchClient, err := newFetchClient(ipTest.ProxyURL)
+	if err != nil {
+		return nil, err
 	}
 
+	b := &Bot{
+		api:              api,
+		rdClient:         rdClient,
+		middleware:       middleware,
+		supportedRegex:   supportedRegex,
+		config:           cfg,
+		db:               database,
+		userRepo:         db.NewUserRepository(database),
+		activityRepo:     db.NewActivityRepository(database),
+		torrentRepo:      db.NewTorrentRepository(database),
+		downloadRepo:     db.NewDownloadRepository(database),
+		commandRepo:      db.NewCommandRepository(database),
+		settingRepo:      db.NewSettingRepository(database),
+		keptRepo:         db.NewKeptTorrentRepository(database),
+		chatRepo:         db.NewChatRepository(database),
+		subscriptionRepo: db.NewSubscriptionRepository(database),
+		scheduledRepo:    db.NewScheduledDeletionRepository(database),
+		queueRepo:        db.NewQueueRepository(database),
+		prefRepo:         db.NewPreferenceRepository(database),
+		ipTest:           ipTest,
+		fetchClient:      fetchClient,
+		location:         location,
+		logLevel:         level,
+		prompts:          newPromptStore(promptTTL),
+		statusBoards:     newStatusBoardStore(),
+		deleteBatches:    newDeleteBatchStore(deleteConfirmTTL),
+		infoRefreshes:    newRefreshLimiter(infoRefreshCooldown),
+		username:         me.Username,
+		bulkQueue:        newBulkQueue(cfg.App.BulkUnrestrict.MaxQueued),
+		health:           health,
+		audit:            auditSink,
+	}
+
+	activityLogging := db.ActivityLogging(cfg.App.ActivityLogging)
+	b.activityRepo.SetActivityLogging(activityLogging)
+	b.torrentRepo.SetActivityLogging(activityLogging)
+	b.downloadRepo.SetActivityLogging(activityLogging)
+
 	// Create or retrieve system user for automated operations
 	systemUser, err := b.userRepo.GetOrCreateUser(context.Background(), 0, "system", "System", "Bot", "", false, false, false)
 	if err != nil {
@@ -169,6 +260,31 @@ func NewBot(cfg *config.Config, database *pgxpool.Pool, ipTest IPTestConfig) (*B
 	}
 	b.systemUserID = systemUser.ID
 
+	// Automated operations have no originating chat, so they log against the system chat
+	// seeded by the initial migration; the upsert keeps its seeded values
+	systemChat, err := b.chatRepo.GetOrCreateChat(context.Background(), 0, "System Chat", "", "system", false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create system chat: %w", err)
+	}
+	b.systemChatPK = systemChat.ID
+
+	// Background workers must finish before anything they use is torn down
+	b.RegisterShutdownHook(b.stopWorkers)
+
+	sweepers := []sweeper{b.prompts, b.deleteBatches, b.infoRefreshes}
+	if cfg.Telegram.RememberThreads {
+		b.threads = newThreadMemory(threadMemoryTTL)
+		sweepers = append(sweepers, b.threads)
+	}
+
+	// Sweep expired prompts, confirmations, refresh cooldowns and remembered topics from memory
+	b.janitor = newJanitor(time.Duration(cfg.App.JanitorIntervalSeconds)*time.Second, sweepers...)
+	b.janitor.start()
+	b.RegisterShutdownHook(b.janitor.Stop)
+
+	// Flush the audit sink once nothing logs anymore
+	b.RegisterShutdownHook(b.audit.Close)
+
 	return b, nil
 }
 
@@ -194,49 +310,155 @@ func (b *Bot) Start(ctx context.Context) error {
 		b.startAutoDeleteWarningWorker(botCtx)
 	}()
 
+	// Start torrent subscription worker
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.startSubscriptionWorker(botCtx)
+	}()
+
+	// Start scheduled deletion worker
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.startScheduledDeletionWorker(botCtx)
+	}()
+
+	// Start the sweeper for torrents stuck waiting for a file selection
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.startAutoSelectSweeper(botCtx)
+	}()
+
+	// Start pinned status board worker
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.startStatusBoardWorker(botCtx)
+	}()
+
+	// Start daily account status broadcast
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.startStatusBroadcastWorker(botCtx)
+	}()
+
+	// Watch the allowlist file for changes
+	if path := b.config.Telegram.AllowlistFile; path != "" {
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			if err := b.middleware.WatchAllowlistFile(botCtx, path); err != nil {
+				log.Printf("Allowlist watcher disabled: %v", err)
+			}
+		}()
+	}
+
+	// Save the rate limiter state, at most once per interval and on shutdown
+	if b.config.App.RateLimit.StateFile != "" {
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			b.middleware.RunRateLimitStateSaver(botCtx, rateLimitStateInterval)
+		}()
+	}
+
 	log.Println("Bot started. Waiting for messages...")
+	// Start only returns once botCtx is cancelled; failed getUpdates calls are retried
+	// with the telegram.reconnect backoff of the client set up in NewBot
 	b.api.Start(botCtx)
 	return nil
 }
 
-// registerHandlers sets up all command and callback handlers
+// registerHandlers sets up all command and callback handlers.
+// Every handler is wrapped in recoverHandler so a panic only fails its own update.
 func (b *Bot) registerHandlers() {
-	// Command handlers
-	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/start", bot.MatchTypeExact, b.handleStartCommand)
-	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/help", bot.MatchTypeExact, b.handleHelpCommand)
-	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/list", bot.MatchTypeExact, b.handleListCommand)
-	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/add", bot.MatchTypePrefix, b.handleAddCommand)
-	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/info", bot.MatchTypePrefix, b.handleInfoCommand)
-	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/delete", bot.MatchTypePrefix, b.handleDeleteCommand)
-	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/del", bot.MatchTypePrefix, b.handleDeleteCommand)
-	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/unrestrict", bot.MatchTypePrefix, b.handleUnrestrictCommand)
-	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/downloads", bot.MatchTypeExact, b.handleDownloadsCommand)
-	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/removelink", bot.MatchTypePrefix, b.handleRemoveLinkCommand)
-	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/status", bot.MatchTypeExact, b.handleStatusCommand)
-	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/stats", bot.MatchTypeExact, b.handleStatsCommand)
-	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/dashboard", bot.MatchTypeExact, b.handleDashboardCommand)
-	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/autodelete-interval", bot.MatchTypePrefix, b.handleAutoDeleteIntervalCommand)
-	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/autodelete", bot.MatchTypePrefix, b.handleAutoDeleteCommand)
-	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/keep", bot.MatchTypePrefix, b.handleKeepCommand)
-	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/unkeep", bot.MatchTypePrefix, b.handleUnkeepCommand)
+	// Answers to force-reply prompts take precedence over link and command matching
+	b.api.RegisterHandlerMatchFunc(b.matchPromptReply, b.recoverHandler("prompt_reply", b.handlePromptReply))
+
+	// Command handlers. Commands that take arguments use matchCommand so they only
+	// trigger on a word boundary: "/del" must not catch "/delay" or "/delicious".
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/start", bot.MatchTypeExact, b.recoverHandler("start", b.handleStartCommand))
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/help", bot.MatchTypeExact, b.recoverHandler("help", b.handleHelpCommand))
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/list", bot.MatchTypeExact, b.recoverHandler("list", b.handleListCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/add"), b.recoverHandler("add", b.handleAddCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/inspect"), b.recoverHandler("inspect", b.handleInspectCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/info"), b.recoverHandler("info", b.handleInfoCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/fileprogress"), b.recoverHandler("fileprogress", b.handleFileProgressCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/copy"), b.recoverHandler("copy", b.handleCopyCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/rename"), b.recoverHandler("rename", b.handleRenameCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/setpref"), b.recoverHandler("setpref", b.handleSetPrefCommand))
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/getpref", bot.MatchTypeExact, b.recoverHandler("getpref", b.handleGetPrefCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/links"), b.recoverHandler("links", b.handleLinksCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/failed"), b.recoverHandler("failed", b.handleFailedCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/retry"), b.recoverHandler("retry", b.handleRetryCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/top"), b.recoverHandler("top", b.handleTopCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/selectall"), b.recoverHandler("selectall", b.handleSelectAllCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/reselect"), b.recoverHandler("reselect", b.handleReselectCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/delete"), b.recoverHandler("delete", b.handleDeleteCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/del"), b.recoverHandler("del", b.handleDeleteCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/unrestrict"), b.recoverHandler("unrestrict", b.handleUnrestrictCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/check"), b.recoverHandler("check", b.handleCheckCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/dryunrestrict"), b.recoverHandler("dryunrestrict", b.handleDryUnrestrictCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/fetch"), b.recoverHandler("fetch", b.handleFetchCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/import"), b.recoverHandler("import", b.handleImportCommand))
+	b.api.RegisterHandlerMatchFunc(matchImportDocument, b.recoverHandler("import", b.handleImportCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/downloads"), b.recoverHandler("downloads", b.handleDownloadsCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/hoststats"), b.recoverHandler("hoststats", b.handleHostStatsCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/removelink"), b.recoverHandler("removelink", b.handleRemoveLinkCommand))
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/status", bot.MatchTypeExact, b.recoverHandler("status", b.handleStatusCommand))
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/expiry", bot.MatchTypeExact, b.recoverHandler("expiry", b.handleExpiryCommand))
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/traffic", bot.MatchTypeExact, b.recoverHandler("traffic", b.handleTrafficCommand))
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/settings", bot.MatchTypeExact, b.recoverHandler("settings", b.handleSettingsCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/setsetting"), b.recoverHandler("setsetting", b.handleSetSettingCommand))
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/stats", bot.MatchTypeExact, b.recoverHandler("stats", b.handleStatsCommand))
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/globalstats", bot.MatchTypeExact, b.recoverHandler("globalstats", b.handleGlobalStatsCommand))
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/metrics", bot.MatchTypeExact, b.recoverHandler("metrics", b.handleMetricsCommand))
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/refreshmetrics", bot.MatchTypeExact, b.recoverHandler("refreshmetrics", b.handleRefreshMetricsCommand))
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/capacity", bot.MatchTypeExact, b.recoverHandler("capacity", b.handleCapacityCommand))
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/limits", bot.MatchTypeExact, b.recoverHandler("limits", b.handleLimitsCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/security"), b.recoverHandler("security", b.handleSecurityCommand))
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/dashboard", bot.MatchTypeExact, b.recoverHandler("dashboard", b.handleDashboardCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/autodelete-interval"), b.recoverHandler("autodelete-interval", b.handleAutoDeleteIntervalCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/autodelete"), b.recoverHandler("autodelete", b.handleAutoDeleteCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/canceldelete"), b.recoverHandler("canceldelete", b.handleCancelDeleteCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/purgeuser"), b.recoverHandler("purgeuser", b.handlePurgeUserCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/perf"), b.recoverHandler("perf", b.handlePerfCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/keep"), b.recoverHandler("keep", b.handleKeepCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/unkeep"), b.recoverHandler("unkeep", b.handleUnkeepCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/subscribe"), b.recoverHandler("subscribe", b.handleSubscribeCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/unsubscribe"), b.recoverHandler("unsubscribe", b.handleUnsubscribeCommand))
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/myqueue", bot.MatchTypeExact, b.recoverHandler("myqueue", b.handleMyQueueCommand))
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/notifytest", bot.MatchTypeExact, b.recoverHandler("notifytest", b.handleNotifyTestCommand))
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/proxytest", bot.MatchTypeExact, b.recoverHandler("proxytest", b.handleProxyTestCommand))
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "/token", bot.MatchTypeExact, b.recoverHandler("token", b.handleTokenCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/debug"), b.recoverHandler("debug", b.handleDebugCommand))
+	b.api.RegisterHandlerMatchFunc(matchCommand("/pinstatus"), b.recoverHandler("pinstatus", b.handlePinStatusCommand))
+
+	// Inline button handlers
+	b.api.RegisterHandler(bot.HandlerTypeCallbackQueryData, deleteCallbackPrefix, bot.MatchTypePrefix, b.recoverHandler("delete_confirm", b.handleDeleteConfirmCallback))
+	b.api.RegisterHandler(bot.HandlerTypeCallbackQueryData, listCallbackPrefix, bot.MatchTypePrefix, b.recoverHandler("list_page", b.handleListPageCallback))
+	b.api.RegisterHandler(bot.HandlerTypeCallbackQueryData, infoRefreshPrefix, bot.MatchTypePrefix, b.recoverHandler("info_refresh", b.handleInfoRefreshCallback))
 
 	// Message handlers for links
-	b.api.RegisterHandler(bot.HandlerTypeMessageText, "magnet:?", bot.MatchTypeContains, b.handleMagnetLink)
-	b.api.RegisterHandler(bot.HandlerTypeMessageText, "http://", bot.MatchTypePrefix, b.handleHosterLink)
-	b.api.RegisterHandler(bot.HandlerTypeMessageText, "https://", bot.MatchTypePrefix, b.handleHosterLink)
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "magnet:?", bot.MatchTypeContains, b.recoverHandler("magnet", b.handleMagnetLink))
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "http://", bot.MatchTypePrefix, b.recoverHandler("hoster_link", b.handleHosterLink))
+	b.api.RegisterHandler(bot.HandlerTypeMessageText, "https://", bot.MatchTypePrefix, b.recoverHandler("hoster_link", b.handleHosterLink))
+	b.api.RegisterHandlerMatchFunc(b.matchCaptionLink, b.recoverHandler("caption_link", b.handleCaptionLink))
+	b.api.RegisterHandlerMatchFunc(b.matchEntityLink, b.recoverHandler("entity_link", b.handleEntityLink))
+
+	// Links posted in channels where the bot is an admin
+	b.api.RegisterHandlerMatchFunc(matchChannelPostLink, b.recoverHandler("channel_post", b.handleChannelPost))
 }
 
-// Stop gracefully stops the bot and closes the database connection
-func (b *Bot) Stop() {
+// Stop gracefully stops the bot: it runs the shutdown hooks within ctx's deadline
+// and then closes the database connection
+func (b *Bot) Stop(ctx context.Context) {
 	log.Println("Bot stopping...")
 
-	// Cancel the bot context to signal all workers to stop
-	if b.cancel != nil {
-		b.cancel()
-	}
-
-	// Wait for background workers to finish
-	b.wg.Wait()
+	b.runShutdownHooks(ctx)
 
 	db.Close(b.db)
 	log.Println("Bot stopped")
@@ -247,6 +469,16 @@ func (b *Bot) SetTokenStore(ts *web.TokenStore) {
 	b.tokenStore = ts
 }
 
+// Username returns the bot's Telegram username, without the leading @
+func (b *Bot) Username() string {
+	return b.username
+}
+
+// SetMetrics sets the shared Real-Debrid metrics cache used by /metrics
+func (b *Bot) SetMetrics(m *web.RDCollector) {
+	b.metrics = m
+}
+
 // defaultHandler ignores unhandled updates
 func defaultHandler(_ context.Context, _ *bot.Bot, _ *models.Update) {
 	// Silently ignore
@@ -265,22 +497,37 @@ type UserInfo struct {
 	UserID          int64
 }
 
+// messageFromUpdate returns the update's message or channel post, if any
+func messageFromUpdate(update *models.Update) *models.Message {
+	if update.Message != nil {
+		return update.Message
+	}
+	return update.ChannelPost
+}
+
 // getUserFromUpdate extracts user information from an update
 func getUserFromUpdate(update *models.Update) UserInfo {
 	var info UserInfo
 	var from *models.User
-	if update.Message != nil {
-		info.ChatID = update.Message.Chat.ID
-		if update.Message.MessageThreadID != 0 {
-			info.MessageThreadID = update.Message.MessageThreadID
+	if msg := messageFromUpdate(update); msg != nil {
+		info.ChatID = msg.Chat.ID
+		if msg.MessageThreadID != 0 {
+			info.MessageThreadID = msg.MessageThreadID
+		}
+		from = msg.From
+		if from == nil && msg.SenderChat != nil {
+			// Channel posts (and anonymous admins) are sent on behalf of a chat, not a user
+			info.Username = msg.SenderChat.Title
 		}
-		from = update.Message.From
 	} else if update.CallbackQuery != nil {
-		if update.CallbackQuery.Message.Message != nil {
-			info.ChatID = update.CallbackQuery.Message.Message.Chat.ID
-			if update.CallbackQuery.Message.Message.MessageThreadID != 0 {
-				info.MessageThreadID = update.CallbackQuery.Message.Message.MessageThreadID
+		if msg := update.CallbackQuery.Message.Message; msg != nil {
+			info.ChatID = msg.Chat.ID
+			if msg.MessageThreadID != 0 {
+				info.MessageThreadID = msg.MessageThreadID
 			}
+		} else if msg := update.CallbackQuery.Message.InaccessibleMessage; msg != nil {
+			// Messages older than 48h are inaccessible; only the chat is known
+			info.ChatID = msg.Chat.ID
 		}
 		from = &update.CallbackQuery.From
 	}
@@ -303,10 +550,14 @@ func getUserFromUpdate(update *models.Update) UserInfo {
 // getChatFromUpdate extracts chat info from an update
 func getChatFromUpdate(update *models.Update) (chatID int64, title, chatUsername, chatType string, isForum bool) {
 	var chat *models.Chat
-	if update.Message != nil {
-		chat = &update.Message.Chat
-	} else if update.CallbackQuery != nil && update.CallbackQuery.Message.Message != nil {
-		chat = &update.CallbackQuery.Message.Message.Chat
+	if msg := messageFromUpdate(update); msg != nil {
+		chat = &msg.Chat
+	} else if update.CallbackQuery != nil {
+		if msg := update.CallbackQuery.Message.Message; msg != nil {
+			chat = &msg.Chat
+		} else if msg := update.CallbackQuery.Message.InaccessibleMessage; msg != nil {
+			chat = &msg.Chat
+		}
 	}
 	if chat != nil {
 		chatID = chat.ID
@@ -324,12 +575,63 @@ func getChatFromUpdate(update *models.Update) (chatID int64, title, chatUsername
 	return
 }
 
+// authContext holds the resolved identity for an authorized update
+type authContext struct {
+	chatID          int64
+	chatPK          int64
+	messageThreadID int
+	role            Role
+	user            *db.User
+}
+
 // withAuth is a middleware to check authorization and execute the handler
-func (b *Bot) withAuth(ctx context.Context, update *models.Update, handler func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, isSuperAdmin bool, user *db.User)) {
+func (b *Bot) withAuth(ctx context.Context, update *models.Update, handler func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User)) {
+	auth, ok := b.authorize(ctx, update, true)
+	if !ok {
+		return
+	}
+	handler(ctx, auth.chatID, auth.chatPK, auth.messageThreadID, auth.role, auth.user)
+}
+
+// withAuthCallback is the callback-query counterpart of withAuth. Denied presses are
+// answered with an alert instead of a chat message so the button spinner always stops;
+// on success the handler is responsible for answering the callback query.
+func (b *Bot) withAuthCallback(ctx context.Context, update *models.Update, handler func(ctx context.Context, query *models.CallbackQuery, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User)) {
+	query := update.CallbackQuery
+	if query == nil {
+		return
+	}
+
+	auth, ok := b.authorize(ctx, update, false)
+	if !ok {
+		b.answerCallback(ctx, query.ID, b.localize(getUserFromUpdate(update).ChatID, "error.unauthorized_callback"), true)
+		return
+	}
+
+	handler(ctx, query, auth.chatID, auth.chatPK, auth.messageThreadID, auth.role, auth.user)
+}
+
+// answerCallback acknowledges a button press so Telegram stops showing a spinner.
+// A non-empty text is shown as a brief toast, or as a dialog when alert is set.
+// Every callback handler should answer through here exactly once.
+func (b *Bot) answerCallback(ctx context.Context, callbackID, text string, alert bool) {
+	if _, err := b.api.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{
+		CallbackQueryID: callbackID,
+		Text:            text,
+		ShowAlert:       alert,
+	}); err != nil {
+		log.Printf("Error answering callback query %s: %v", callbackID, err)
+	}
+}
+
+// authorize resolves the chat and user of an update and checks access. When notify is
+// set, unauthorized users are told so in the chat; callers that reply some other way
+// (such as callback queries) pass false.
+func (b *Bot) authorize(ctx context.Context, update *models.Update, notify bool) (authContext, bool) {
 	userInfo := getUserFromUpdate(update)
 	_, title, chatUsername, chatType, isForum := getChatFromUpdate(update)
 
-	isAllowed, isSuperAdmin := b.middleware.CheckAuthorization(userInfo.ChatID, userInfo.UserID)
+	isAllowed, role := b.middleware.CheckAuthorization(userInfo.ChatID, userInfo.UserID)
 
 	chatPK := int64(0)
 	chat, err := b.chatRepo.GetOrCreateChat(ctx, userInfo.ChatID, title, chatUsername, chatType, isForum)
@@ -342,10 +644,10 @@ func (b *Bot) withAuth(ctx context.Context, update *models.Update, handler func(
 
 	var user *db.User
 	if userInfo.UserID != 0 {
-		user, err = b.userRepo.GetOrCreateUser(ctx, userInfo.UserID, userInfo.Username, userInfo.FirstName, userInfo.LastName, userInfo.LanguageCode, userInfo.IsBot, userInfo.IsPremium, isSuperAdmin)
+		user, err = b.userRepo.GetOrCreateUser(ctx, userInfo.UserID, userInfo.Username, userInfo.FirstName, userInfo.LastName, userInfo.LanguageCode, userInfo.IsBot, userInfo.IsPremium, role.IsSuperAdmin())
 		if err != nil {
 			log.Printf("Error getting/creating user: %v", err)
-			if userInfo.ChatID != 0 {
+			if notify && userInfo.ChatID != 0 {
 				if err2 := b.middleware.WaitForRateLimit(); err2 != nil {
 					log.Printf("Rate limit error: %v", err2)
 				}
@@ -355,44 +657,43 @@ func (b *Bot) withAuth(ctx context.Context, update *models.Update, handler func(
 					MessageThreadID: userInfo.MessageThreadID,
 				})
 			}
-			return
+			return authContext{}, false
 		}
-	} else {
+	} else if chatType != string(models.ChatTypeChannel) {
 		log.Printf("Warning: missing user ID in update, skipping user tracking")
 	}
 
 	if !isAllowed {
 		b.middleware.LogUnauthorized(userInfo.Username, userInfo.ChatID, userInfo.UserID)
-		b.sendUnauthorizedMessage(ctx, userInfo.ChatID, userInfo.MessageThreadID, userInfo.UserID)
-		if user != nil {
-			if err := b.activityRepo.LogActivity(ctx, "", user.ID, chatPK, userInfo.Username, db.ActivityTypeUnauthorized, "", 0, userInfo.MessageThreadID, false, "Unauthorized access attempt", nil); err != nil {
-				log.Printf("Warning: failed to log unauthorized activity: %v", err)
-			}
+		// Posting the notice into a channel would publish it to every subscriber
+		if notify && chatType != string(models.ChatTypeChannel) {
+			b.sendUnauthorizedMessage(ctx, userInfo.ChatID, userInfo.MessageThreadID, userInfo.UserID)
 		}
-		return
+		b.logActivityHelper(ctx, user, chatPK, 0, userInfo.MessageThreadID, db.ActivityTypeUnauthorized, "", false, "Unauthorized access attempt", nil)
+		return authContext{}, false
 	}
 
 	// Check topic restrictions if configured
 	if !b.config.IsAllowedTopic(userInfo.ChatID, userInfo.MessageThreadID) {
 		log.Printf("Topic %d not allowed for chat %d (config topics: %v)", userInfo.MessageThreadID, userInfo.ChatID, b.config.Telegram.AllowedTopicIDs[fmt.Sprintf("%d", userInfo.ChatID)])
 		b.middleware.LogUnauthorized(userInfo.Username, userInfo.ChatID, userInfo.UserID)
-		return
+		return authContext{}, false
 	}
 
-	handler(ctx, userInfo.ChatID, chatPK, userInfo.MessageThreadID, isSuperAdmin, user)
+	b.threads.remember(userInfo.ChatID, userInfo.UserID, userInfo.MessageThreadID, time.Now())
+
+	return authContext{
+		chatID:          userInfo.ChatID,
+		chatPK:          chatPK,
+		messageThreadID: userInfo.MessageThreadID,
+		role:            role,
+		user:            user,
+	}, true
 }
 
 // sendUnauthorizedMessage sends an unauthorized message
 func (b *Bot) sendUnauthorizedMessage(ctx context.Context, chatID int64, messageThreadID int, userID int64) {
-	text := fmt.Sprintf(
-		"[UNAUTHORIZED]\n\n"+
-			"You are not authorized to use this bot.\n\n"+
-			"Your User ID is: <code>%d</code>\n"+
-			"Chat ID: <code>%d</code>\n\n"+
-			"Please contact the administrator to add your User ID to the super admin list or add this chat to the allowed chats list.",
-		userID,
-		chatID,
-	)
+	text := b.localize(chatID, "error.unauthorized", userID, chatID)
 
 	params := &bot.SendMessageParams{
 		ChatID:    chatID,
@@ -421,35 +722,53 @@ func maskUsername(username string) string {
 	return "*****" + username[5:]
 }
 
+// IPTestResult holds the outbound IPs detected by performIPTests
+type IPTestResult struct {
+	PrimaryIP   string // IP seen by the test URL; empty if the lookup failed
+	PrimaryErr  error  // Why the primary lookup failed, if it did
+	StremThruIP string // IP StremThru reports as its outbound; empty if not configured
+}
+
+// Verified reports whether the bot and StremThru were seen from the same IP
+func (r IPTestResult) Verified() bool {
+	return r.PrimaryIP != "" && r.PrimaryIP == r.StremThruIP
+}
+
 // performIPTests checks the bot's outbound IP. With cfg.StremThruURL set, it also
 // queries /v0/health/__debug__ to log StremThru's outbound IP (exposed["*"] or machine).
 // With cfg.ProxyURL set, confirms StremThru sees the proxy as the caller.
-// On StremThru unreachability, retries indefinitely: exponential backoff 2s-5min, +-20% jitter.
-func performIPTests(cfg IPTestConfig) error {
+// On StremThru unreachability, retries with exponential backoff 2s-5min, +-20% jitter,
+// until ctx is done.
+func performIPTests(ctx context.Context, cfg IPTestConfig) (IPTestResult, error) {
 	ipTestURL := "https://api.ipify.org?format=json"
 	if cfg.TestURL != "" {
 		ipTestURL = cfg.TestURL
 	}
 
-	primaryIP := fetchPrimaryIP(buildIPTestClient(cfg.ProxyURL), ipTestURL)
+	var result IPTestResult
+	result.PrimaryIP, result.PrimaryErr = fetchPrimaryIP(ctx, buildIPTestClient(cfg.ProxyURL), ipTestURL)
+	if result.PrimaryErr != nil {
+		log.Printf("Warning: %v", result.PrimaryErr)
+	}
 
 	if cfg.StremThruURL == "" {
-		return nil
+		return result, nil
 	}
 
-	stOutboundIP, err := queryStremThruOutboundIP(cfg)
+	stOutboundIP, err := queryStremThruOutboundIP(ctx, cfg)
 	if err != nil {
-		return err
+		return result, err
 	}
+	result.StremThruIP = stOutboundIP
 
-	if primaryIP != "" && primaryIP != stOutboundIP {
-		return fmt.Errorf(
+	if result.PrimaryIP != "" && result.PrimaryIP != stOutboundIP {
+		return result, fmt.Errorf(
 			"IP mismatch: bot uses %s but StremThru proxies from %s; configure a proxy so both IPs match",
-			primaryIP, stOutboundIP,
+			result.PrimaryIP, stOutboundIP,
 		)
 	}
 	log.Printf("IP check passed: bot and StremThru both use %s", stOutboundIP)
-	return nil
+	return result, nil
 }
 
 func buildIPTestClient(proxyURL string) *http.Client {
@@ -469,11 +788,30 @@ func buildIPTestClient(proxyURL string) *http.Client {
 	}
 }
 
-func fetchPrimaryIP(client *http.Client, testURL string) string {
-	resp, err := client.Get(testURL)
+// newIPTestRequest creates a GET request for an IP test endpoint. Credentials in the
+// URL's userinfo are moved to a Basic Authorization header, so self-hosted endpoints
+// behind basic auth work regardless of how the request is proxied or redirected.
+func newIPTestRequest(ctx context.Context, rawURL string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	if user := req.URL.User; user != nil {
+		password, _ := user.Password()
+		req.SetBasicAuth(user.Username(), password)
+		req.URL.User = nil
+	}
+	return req, nil
+}
+
+func fetchPrimaryIP(ctx context.Context, client *http.Client, testURL string) (string, error) {
+	req, err := newIPTestRequest(ctx, testURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to create primary IP test request: %w", err)
+	}
+	resp, err := client.Do(req)
 	if err != nil {
-		log.Printf("Warning: failed to perform primary IP test: %v", err)
-		return ""
+		return "", fmt.Errorf("failed to perform primary IP test: %w", err)
 	}
 	defer func() {
 		if cerr := resp.Body.Close(); cerr != nil {
@@ -482,54 +820,55 @@ func fetchPrimaryIP(client *http.Client, testURL string) string {
 	}()
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("Warning: failed to read primary IP test response: %v", err)
-		return ""
+		return "", fmt.Errorf("failed to read primary IP test response: %w", err)
 	}
 	var ipResponse struct {
 		IP string `json:"ip"`
 	}
 	if err := json.Unmarshal(body, &ipResponse); err != nil {
-		log.Printf("Warning: failed to parse primary IP test response: %v", err)
-		return ""
+		return "", fmt.Errorf("failed to parse primary IP test response: %w", err)
 	}
 	log.Printf("Primary IP detected: %s", ipResponse.IP)
-	return ipResponse.IP
+	return ipResponse.IP, nil
 }
 
-func queryStremThruOutboundIP(cfg IPTestConfig) (string, error) {
-	const (
-		initialBackoff = 2 * time.Second
-		maxBackoff     = 5 * time.Minute
-		jitterFactor   = 0.2 // +-20%
-	)
+// stremThruRetryPolicy retries StremThru verification with exponential backoff 2s-5min
+// and +-20% jitter until the context is done
+var stremThruRetryPolicy = retry.Policy{
+	InitialDelay: 2 * time.Second,
+	MaxDelay:     5 * time.Minute,
+	Jitter:       0.2,
+	OnRetry: func(attempt int, err error, wait time.Duration) {
+		log.Printf("StremThru not available (attempt %d): %v. Retrying in %s...", attempt, err, wait.Round(time.Millisecond))
+	},
+}
+
+func queryStremThruOutboundIP(ctx context.Context, cfg IPTestConfig) (string, error) {
 	verifyURL := strings.TrimRight(cfg.StremThruURL, "/") + "/v0/health/__debug__"
 	// StremThru is always dialed directly; the local proxy routes bot traffic only.
 	stClient := &http.Client{Timeout: 10 * time.Second}
-	backoff := initialBackoff
-
-	for attempt := 1; ; attempt++ {
-		log.Printf("Performing StremThru IP verification test (attempt %d)...", attempt)
 
-		req, err := http.NewRequest(http.MethodGet, verifyURL, http.NoBody)
-		if err != nil {
-			return "", fmt.Errorf("failed to create StremThru verify request: %w", err)
-		}
-		if cfg.StremThruAuth != "" {
-			encoded := base64.StdEncoding.EncodeToString([]byte(cfg.StremThruAuth))
-			req.Header.Set("X-StremThru-Authorization", "Basic "+encoded)
-		}
-
-		resp, err := stClient.Do(req)
-		if err != nil {
-			waitDuration := jitteredBackoff(backoff, jitterFactor, initialBackoff)
-			log.Printf("StremThru not available (attempt %d): %v. Retrying in %s...", attempt, err, waitDuration.Round(time.Millisecond))
-			time.Sleep(waitDuration)
-			backoff = time.Duration(math.Min(float64(backoff*2), float64(maxBackoff)))
-			continue
-		}
+	req, err := newIPTestRequest(ctx, verifyURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to create StremThru verify request: %w", err)
+	}
+	if cfg.StremThruAuth != "" {
+		encoded := base64.StdEncoding.EncodeToString([]byte(cfg.StremThruAuth))
+		req.Header.Set("X-StremThru-Authorization", "Basic "+encoded)
+	}
 
-		return parseStremThruOutboundIP(resp)
+	var resp *http.Response
+	attempt := 0
+	err = retry.Do(ctx, stremThruRetryPolicy, func(context.Context) error {
+		attempt++
+		log.Printf("Performing StremThru IP verification test (attempt %d)...", attempt)
+		resp, err = stClient.Do(req)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("StremThru verification aborted: %w", err)
 	}
+	return parseStremThruOutboundIP(resp)
 }
 
 func parseStremThruOutboundIP(resp *http.Response) (string, error) {
@@ -562,18 +901,3 @@ func parseStremThruOutboundIP(resp *http.Response) (string, error) {
 	log.Printf("StremThru outbound IP (seen by upstream services): %s", outboundIP)
 	return outboundIP, nil
 }
-
-// jitteredBackoff returns backoff +- (factor * 100)% using crypto/rand.
-// Falls back to plain backoff if the random read fails.
-func jitteredBackoff(backoff time.Duration, factor float64, minDuration time.Duration) time.Duration {
-	var rb [8]byte
-	if _, err := cryptorand.Read(rb[:]); err != nil {
-		return backoff
-	}
-	ratio := float64(binary.BigEndian.Uint64(rb[:]))/float64(^uint64(0))*2 - 1 // -1.0 to +1.0
-	d := backoff + time.Duration(float64(backoff)*factor*ratio)
-	if d < minDuration {
-		return minDuration
-	}
-	return d
-}
@@ -0,0 +1,259 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// bulkStatusLines is how many result lines the bulk status message shows
+const bulkStatusLines = 20
+
+// extractLinks returns the http(s) links of a message, in order
+func extractLinks(text string) []string {
+	var links []string
+	for _, field := range strings.Fields(text) {
+		if strings.HasPrefix(field, "http://") || strings.HasPrefix(field, "https://") {
+			links = append(links, field)
+		}
+	}
+	return links
+}
+
+// bulkJob tracks the links of one multi-link message and the status message reporting
+// them. Queued links of a user may come from messages in different chats, so each job
+// carries the chat, topic and sender its links are logged and reported with.
+type bulkJob struct {
+	mu       sync.Mutex
+	user     *db.User
+	chatID   int64
+	chatPK   int64
+	threadID int
+	statusID int // message ID of the status message, 0 if it could not be sent
+	total    int
+	lines    []string // one result line per processed link, in order
+}
+
+// record adds the result line of a processed link and returns the updated status text
+func (j *bulkJob) record(line string) string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.lines = append(j.lines, line)
+	return j.statusText()
+}
+
+// statusText renders the progress of the job; the caller must hold j.mu
+func (j *bulkJob) statusText() string {
+	var text strings.Builder
+	if len(j.lines) < j.total {
+		fmt.Fprintf(&text, "<b>⏳ Unrestricting Links</b> (processing %d of %d)\n\n", len(j.lines)+1, j.total)
+	} else {
+		fmt.Fprintf(&text, "<b>Bulk Unrestrict Complete</b> (%d links)\n\n", j.total)
+	}
+	shown := j.lines
+	if len(shown) > bulkStatusLines {
+		fmt.Fprintf(&text, "<i>... %d earlier links</i>\n", len(shown)-bulkStatusLines)
+		shown = shown[len(shown)-bulkStatusLines:]
+	}
+	text.WriteString(strings.Join(shown, "\n"))
+	return text.String()
+}
+
+// bulkItem is a queued link and the job it belongs to
+type bulkItem struct {
+	link string
+	job  *bulkJob
+}
+
+// bulkQueue holds the overflow links of bulk unrestricts, per user and in order. At
+// most one drain runs per user, so a user's links are handled one after another.
+type bulkQueue struct {
+	mu       sync.Mutex
+	pending  map[int64][]bulkItem
+	draining map[int64]bool
+	max      int // links a user may have waiting
+}
+
+// newBulkQueue creates a queue holding up to max waiting links per user
+func newBulkQueue(max int) *bulkQueue {
+	return &bulkQueue{pending: make(map[int64][]bulkItem), draining: make(map[int64]bool), max: max}
+}
+
+// push appends items to the user's queue as far as there is room. It returns how many
+// were accepted and whether the caller has to start draining the queue.
+func (q *bulkQueue) push(userID int64, items []bulkItem) (accepted int, start bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	accepted = min(len(items), max(q.max-len(q.pending[userID]), 0))
+	if accepted == 0 {
+		return 0, false
+	}
+	q.pending[userID] = append(q.pending[userID], items[:accepted]...)
+	start = !q.draining[userID]
+	q.draining[userID] = true
+	return accepted, start
+}
+
+// pop removes the user's next link. Once the queue is empty, draining stops.
+func (q *bulkQueue) pop(userID int64) (bulkItem, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	items := q.pending[userID]
+	if len(items) == 0 {
+		delete(q.pending, userID)
+		delete(q.draining, userID)
+		return bulkItem{}, false
+	}
+	q.pending[userID] = items[1:]
+	return items[0], true
+}
+
+// drain processes the user's queue in order, pausing interval before each link, until it
+// is empty or ctx is done
+func (q *bulkQueue) drain(ctx context.Context, userID int64, interval time.Duration, process func(bulkItem)) {
+	for {
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			q.mu.Lock()
+			delete(q.pending, userID)
+			delete(q.draining, userID)
+			q.mu.Unlock()
+			return
+		case <-timer.C:
+		}
+		item, ok := q.pop(userID)
+		if !ok {
+			return
+		}
+		process(item)
+	}
+}
+
+// handleBulkUnrestrict unrestricts a message with several hoster links. The first
+// app.bulk_unrestrict.max_per_message are handled right away and the rest queued,
+// with a single status message edited as links are processed.
+func (b *Bot) handleBulkUnrestrict(ctx context.Context, update *models.Update, user *db.User, chatID, chatPK int64, messageThreadID int, links []string, startTime time.Time) {
+	cfg := b.config.App.BulkUnrestrict
+	immediate := links[:min(cfg.MaxPerMessage, len(links))]
+	overflow := links[len(immediate):]
+
+	job := &bulkJob{user: user, chatID: chatID, chatPK: chatPK, threadID: messageThreadID, total: len(links)}
+	items := make([]bulkItem, len(overflow))
+	for i, link := range overflow {
+		items[i] = bulkItem{link: link, job: job}
+	}
+	// The status message must exist before a running drain can pick up queued links
+	job.statusID = b.sendBulkStatus(ctx, job, update.Message.ID)
+
+	queueKey := chatID
+	if update.Message.From != nil {
+		queueKey = update.Message.From.ID
+	}
+	accepted, start := b.bulkQueue.push(queueKey, items)
+	for _, item := range items[accepted:] {
+		job.record(fmt.Sprintf("⏭️ <code>%s</code>: skipped, your queue is full", html.EscapeString(item.link)))
+	}
+	for _, link := range immediate {
+		b.processBulkLink(ctx, bulkItem{link: link, job: job})
+	}
+	if start {
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			b.bulkQueue.drain(ctx, queueKey, time.Duration(cfg.IntervalSeconds)*time.Second, func(item bulkItem) {
+				b.processBulkLink(ctx, item)
+			})
+		}()
+	}
+
+	b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "hoster_link", update.Message.Text, startTime, true, "", 0)
+	b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeHosterLink, "hoster_link", true, "", map[string]any{"links": len(links), "queued": accepted})
+}
+
+// processBulkLink unrestricts one link of a bulk job and updates its status message
+func (b *Bot) processBulkLink(ctx context.Context, item bulkItem) {
+	user, chatPK := item.job.user, item.job.chatPK
+	var line string
+	if err := b.checkBulkLink(item.link); err != nil {
+		line = fmt.Sprintf("❌ <code>%s</code>: %s", html.EscapeString(item.link), html.EscapeString(err.Error()))
+	} else if unrestricted, err := b.rdClient.UnrestrictLink(item.link); err != nil {
+		line = fmt.Sprintf("❌ <code>%s</code>: %s", html.EscapeString(item.link), html.EscapeString(err.Error()))
+		if user != nil {
+			if err := b.downloadRepo.LogDownloadActivity(ctx, "", user.ID, chatPK, "", item.link, "", "", "unrestrict", 0, false, err.Error(), nil, nil); err != nil {
+				log.Printf("Warning: failed to log bulk unrestrict error: %v", err)
+			}
+		}
+	} else {
+		line = fmt.Sprintf("✅ <code>%s</code> (%s)", html.EscapeString(truncateName(unrestricted.Filename, b.config.App.MaxFilenameDisplay)), realdebrid.FormatSize(unrestricted.Filesize))
+		if user != nil {
+			if err := b.downloadRepo.LogDownloadActivity(ctx, "", user.ID, chatPK, unrestricted.ID, item.link, unrestricted.Filename, unrestricted.Host, "unrestrict", unrestricted.Filesize, true, "", nil, nil); err != nil {
+				log.Printf("Warning: failed to log bulk unrestrict: %v", err)
+			}
+		}
+	}
+
+	text := item.job.record(line)
+	if item.job.statusID == 0 {
+		return
+	}
+	if err := b.middleware.WaitForRateLimitWithContext(ctx); err != nil {
+		return
+	}
+	if _, err := b.api.EditMessageText(ctx, &bot.EditMessageTextParams{
+		ChatID:    item.job.chatID,
+		MessageID: item.job.statusID,
+		Text:      b.withFooter(text),
+		ParseMode: models.ParseModeHTML,
+	}); err != nil {
+		log.Printf("Failed to update bulk unrestrict status %d: %v", item.job.statusID, err)
+	}
+}
+
+// checkBulkLink applies the checks a single hoster link goes through before unrestricting
+func (b *Bot) checkBulkLink(link string) error {
+	if inputTooLong(link, b.config.App.MaxInputLength) {
+		return fmt.Errorf("link is too long")
+	}
+	if err := b.config.CheckLinkHost(link); err != nil {
+		return err
+	}
+	if !b.isSupportedLink(link) {
+		return fmt.Errorf("unsupported host")
+	}
+	return nil
+}
+
+// sendBulkStatus sends the status message of a bulk job and returns its ID, 0 on failure
+func (b *Bot) sendBulkStatus(ctx context.Context, job *bulkJob, replyTo int) int {
+	job.mu.Lock()
+	text := job.statusText()
+	job.mu.Unlock()
+
+	params := &bot.SendMessageParams{
+		ChatID:          job.chatID,
+		Text:            b.withFooter(text),
+		ParseMode:       models.ParseModeHTML,
+		MessageThreadID: job.threadID,
+		ReplyParameters: &models.ReplyParameters{MessageID: replyTo},
+	}
+	if err := b.middleware.WaitForRateLimitWithContext(ctx); err != nil {
+		return 0
+	}
+	sent, err := b.api.SendMessage(ctx, params)
+	if err != nil {
+		log.Printf("Failed to send bulk unrestrict status: %v", err)
+		return 0
+	}
+	return sent.ID
+}
@@ -0,0 +1,90 @@
+package bot
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/config"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot/models"
+)
+
+// TestExtractLinks verifies only http(s) fields are taken from a message, in order.
+func TestExtractLinks(t *testing.T) {
+	got := extractLinks("https://a.example/1\nsome text http://b.example/2  ftp://c.example/3")
+	want := []string{"https://a.example/1", "http://b.example/2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractLinks() = %v, want %v", got, want)
+	}
+}
+
+// TestBulkQueue_Limits verifies the per-user cap and that only the first push starts a drain.
+func TestBulkQueue_Limits(t *testing.T) {
+	q := newBulkQueue(3)
+	items := []bulkItem{{link: "a"}, {link: "b"}}
+
+	if accepted, start := q.push(7, items); accepted != 2 || !start {
+		t.Errorf("first push = (%d, %v), want (2, true)", accepted, start)
+	}
+	if accepted, start := q.push(7, items); accepted != 1 || start {
+		t.Errorf("second push = (%d, %v), want (1, false)", accepted, start)
+	}
+	if accepted, _ := q.push(8, items); accepted != 2 {
+		t.Errorf("other user push accepted %d, want 2", accepted)
+	}
+}
+
+// bulkClient is a RealDebridClient recording the links it unrestricts.
+type bulkClient struct {
+	RealDebridClient
+	mu    sync.Mutex
+	links []string
+}
+
+func (c *bulkClient) UnrestrictLink(link string) (*realdebrid.UnrestrictedLink, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.links = append(c.links, link)
+	return &realdebrid.UnrestrictedLink{Filename: link, Filesize: 1}, nil
+}
+
+// TestHandleBulkUnrestrict_Overflow verifies links over the per-message cap are queued and
+// eventually unrestricted in order, with the status message edited to completion.
+func TestHandleBulkUnrestrict_Overflow(t *testing.T) {
+	api, requests := newTestTelegramAPI(t)
+	cfg := &config.Config{}
+	cfg.App.RateLimit = config.RateLimitConfig{MessagesPerSecond: 100, Burst: 10}
+	cfg.App.BulkUnrestrict = config.BulkUnrestrictConfig{MaxPerMessage: 2, MaxQueued: 10}
+	client := &bulkClient{}
+	b := &Bot{api: api, config: cfg, middleware: NewMiddleware(cfg), rdClient: client, bulkQueue: newBulkQueue(10)}
+
+	links := []string{"https://h.example/1", "https://h.example/2", "https://h.example/3", "https://h.example/4", "https://h.example/5"}
+	update := &models.Update{Message: &models.Message{ID: 9, Text: strings.Join(links, "\n"), From: &models.User{ID: 7}}}
+	b.handleBulkUnrestrict(context.Background(), update, nil, -100, 0, 0, links, time.Now())
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("queued links were not processed")
+	}
+
+	if !reflect.DeepEqual(client.links, links) {
+		t.Errorf("unrestricted %v, want %v", client.links, links)
+	}
+	reqs := requests()
+	if len(reqs) != 1+len(links) {
+		t.Fatalf("got %d requests, want a status message and %d edits", len(reqs), len(links))
+	}
+	if last := reqs[len(reqs)-1]; !strings.Contains(last, "editMessageText") || !strings.Contains(last, "Bulk Unrestrict Complete") {
+		t.Errorf("last request is not the completed status: %s", last)
+	}
+}
@@ -0,0 +1,158 @@
+package bot
+
+import (
+	"log"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// cachedFileIDs returns the file IDs of the largest cached Real-Debrid variant of hash.
+// Real-Debrid answers {"<hash>": {"rd": [{"<file id>": {"filename": ..., "filesize": ...}}, ...]}},
+// where each variant is a set of files cached together. Selecting exactly one variant
+// keeps the torrent instantly available. It returns nil when nothing is cached.
+func cachedFileIDs(availability realdebrid.InstantAvailability, hash string) []int {
+	for key, value := range availability {
+		if !strings.EqualFold(key, hash) {
+			continue
+		}
+		hosts, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		variants, _ := hosts["rd"].([]interface{})
+
+		var best []int
+		for _, variant := range variants {
+			files, ok := variant.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ids := make([]int, 0, len(files))
+			for fileID := range files {
+				if id, err := strconv.Atoi(fileID); err == nil {
+					ids = append(ids, id)
+				}
+			}
+			if len(ids) > len(best) {
+				best = ids
+			}
+		}
+		sort.Ints(best)
+		return best
+	}
+	return nil
+}
+
+// cacheState records whether a magnet was cached on Real-Debrid when it was added
+type cacheState int
+
+const (
+	cacheUnknown cacheState = iota // not checked, or the check failed
+	cacheHit
+	cacheMiss
+)
+
+// metadata adds the cache state to the metadata of an add activity under "cached",
+// leaving it out when unknown. metadata may be nil.
+func (s cacheState) metadata(metadata map[string]interface{}) map[string]interface{} {
+	if s == cacheUnknown {
+		return metadata
+	}
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	metadata["cached"] = s == cacheHit
+	return metadata
+}
+
+// selectAddedFiles selects the files of a torrent just added from magnetLink, keeping
+// those matched by filter (see filterFileIDs). With realdebrid.select_cached_only set,
+// only the cached files among them are selected, unless none of them are cached. When
+// filter matches nothing, or the file list isn't known yet, it falls back to the cached
+// files or all files. The cache is only checked when select_cached_only or
+// app.list_cached_badge is set, and the returned state says what it found.
+func (b *Bot) selectAddedFiles(torrentID, magnetLink, filter string) (cacheState, error) {
+	state := cacheUnknown
+	var cachedIDs []int
+	if b.config.RealDebrid.SelectCachedOnly || b.config.App.ListCachedBadge {
+		ids, ok := b.lookupCachedFiles(magnetLink)
+		switch {
+		case !ok:
+		case len(ids) == 0:
+			state = cacheMiss
+		default:
+			state = cacheHit
+			if b.config.RealDebrid.SelectCachedOnly {
+				cachedIDs = ids
+			}
+		}
+	}
+
+	if filter != "" && filter != "all" {
+		ids, err := b.filterAddedFiles(torrentID, filter, cachedIDs)
+		if err == nil {
+			return state, b.rdClient.SelectFiles(torrentID, ids)
+		}
+		log.Printf("Warning: could not apply the %q file selection to torrent %s: %v", filter, torrentID, err)
+	}
+	if cachedIDs != nil {
+		return state, b.rdClient.SelectFiles(torrentID, cachedIDs)
+	}
+	return state, b.rdClient.SelectAllFiles(torrentID)
+}
+
+// filterAddedFiles returns the files of torrentID kept by filter, narrowed down to
+// cachedIDs when any of them are cached
+func (b *Bot) filterAddedFiles(torrentID, filter string, cachedIDs []int) ([]int, error) {
+	torrent, err := b.rdClient.GetTorrentInfo(torrentID)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := filterFileIDs(torrent.Files, filter)
+	if err != nil {
+		return nil, err
+	}
+	var cached []int
+	for _, id := range ids {
+		if slices.Contains(cachedIDs, id) {
+			cached = append(cached, id)
+		}
+	}
+	if len(cached) > 0 {
+		return cached, nil
+	}
+	return ids, nil
+}
+
+// lookupCachedFiles returns the cached file IDs of magnetLink, or nil if none are cached.
+// ok is false when the lookup fails.
+func (b *Bot) lookupCachedFiles(magnetLink string) (ids []int, ok bool) {
+	info, err := parseMagnet(magnetLink)
+	if err != nil {
+		return nil, false
+	}
+	availability, err := b.rdClient.CheckInstantAvailability([]string{info.hash})
+	if err != nil {
+		log.Printf("Warning: cache check for %s failed, selecting all files: %v", info.hash, err)
+		return nil, false
+	}
+	return cachedFileIDs(availability, info.hash), true
+}
+
+// cachedBadge renders the /list line saying whether torrent id was cached when added,
+// or "" when that was not recorded
+func cachedBadge(flags map[string]bool, id string) string {
+	cached, ok := flags[id]
+	switch {
+	case !ok:
+		return ""
+	case cached:
+		return "<i>Cached:</i> ⚡ instant\n"
+	default:
+		return "<i>Cached:</i> no\n"
+	}
+}
@@ -0,0 +1,161 @@
+package bot
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/config"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+const cachedHash = "c12fe1c06bba254a9dc9f519b335aa7c1367a88a"
+
+// sampleAvailability is an instantAvailability response with two cached variants.
+const sampleAvailability = `{
+	"C12FE1C06BBA254A9DC9F519B335AA7C1367A88A": {
+		"rd": [
+			{"2": {"filename": "movie.mkv", "filesize": 1073741824}},
+			{"2": {"filename": "movie.mkv", "filesize": 1073741824}, "5": {"filename": "subs.srt", "filesize": 2048}}
+		]
+	}
+}`
+
+// TestCachedFileIDs verifies the largest cached variant is picked from an availability payload.
+func TestCachedFileIDs(t *testing.T) {
+	var availability realdebrid.InstantAvailability
+	if err := json.Unmarshal([]byte(sampleAvailability), &availability); err != nil {
+		t.Fatalf("unmarshal sample: %v", err)
+	}
+
+	if got := cachedFileIDs(availability, cachedHash); !reflect.DeepEqual(got, []int{2, 5}) {
+		t.Errorf("cachedFileIDs() = %v, want [2 5]", got)
+	}
+	if got := cachedFileIDs(availability, "other"); got != nil {
+		t.Errorf("cachedFileIDs(unknown hash) = %v, want nil", got)
+	}
+	uncached := realdebrid.InstantAvailability{cachedHash: []interface{}{}}
+	if got := cachedFileIDs(uncached, cachedHash); got != nil {
+		t.Errorf("cachedFileIDs(uncached) = %v, want nil", got)
+	}
+}
+
+// cachedClient is a RealDebridClient recording how the files of an added torrent are selected.
+type cachedClient struct {
+	RealDebridClient
+	availability realdebrid.InstantAvailability
+	files        []realdebrid.File
+	selectedIDs  []int
+	selectedAll  bool
+}
+
+func (c *cachedClient) GetTorrentInfo(id string) (*realdebrid.Torrent, error) {
+	return &realdebrid.Torrent{ID: id, Files: c.files}, nil
+}
+
+func (c *cachedClient) CheckInstantAvailability([]string) (realdebrid.InstantAvailability, error) {
+	return c.availability, nil
+}
+
+func (c *cachedClient) SelectFiles(_ string, fileIDs []int) error {
+	c.selectedIDs = fileIDs
+	return nil
+}
+
+func (c *cachedClient) SelectAllFiles(string) error {
+	c.selectedAll = true
+	return nil
+}
+
+// TestSelectAddedFiles verifies cached files are selected when enabled, falling back to all
+// files, that the file filter narrows the selection, and that the cache state is reported
+// whenever it was checked.
+func TestSelectAddedFiles(t *testing.T) {
+	var availability realdebrid.InstantAvailability
+	if err := json.Unmarshal([]byte(sampleAvailability), &availability); err != nil {
+		t.Fatalf("unmarshal sample: %v", err)
+	}
+	magnet := "magnet:?xt=urn:btih:" + cachedHash
+	files := []realdebrid.File{
+		{ID: 1, Path: "/sample.mkv", Bytes: 1 << 20},
+		{ID: 2, Path: "/movie.mkv", Bytes: 1 << 30},
+		{ID: 5, Path: "/subs.srt", Bytes: 2048},
+	}
+
+	tests := []struct {
+		name         string
+		cachedOnly   bool
+		badge        bool
+		availability realdebrid.InstantAvailability
+		filter       string
+		wantIDs      []int
+		wantAll      bool
+		wantState    cacheState
+	}{
+		{"disabled", false, false, availability, "all", nil, true, cacheUnknown},
+		{"cached", true, false, availability, "all", []int{2, 5}, false, cacheHit},
+		{"nothing cached", true, false, realdebrid.InstantAvailability{}, "all", nil, true, cacheMiss},
+		{"badge only", false, true, availability, "all", nil, true, cacheHit},
+		{"video", false, false, availability, "video", []int{1, 2}, false, cacheUnknown},
+		{"largest", false, false, availability, "largest", []int{2}, false, cacheUnknown},
+		{"cached video", true, false, availability, "video", []int{2}, false, cacheHit},
+		{"uncached video", true, false, realdebrid.InstantAvailability{}, "video", []int{1, 2}, false, cacheMiss},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &cachedClient{availability: tt.availability, files: files}
+			cfg := &config.Config{}
+			cfg.RealDebrid.SelectCachedOnly = tt.cachedOnly
+			cfg.App.ListCachedBadge = tt.badge
+			b := &Bot{rdClient: client, config: cfg}
+
+			state, err := b.selectAddedFiles("ID", magnet, tt.filter)
+			if err != nil {
+				t.Fatalf("selectAddedFiles() error = %v", err)
+			}
+			if state != tt.wantState {
+				t.Errorf("state = %v, want %v", state, tt.wantState)
+			}
+			if !reflect.DeepEqual(client.selectedIDs, tt.wantIDs) || client.selectedAll != tt.wantAll {
+				t.Errorf("selected ids %v, all %v; want ids %v, all %v", client.selectedIDs, client.selectedAll, tt.wantIDs, tt.wantAll)
+			}
+		})
+	}
+
+	t.Run("no match", func(t *testing.T) {
+		client := &cachedClient{files: []realdebrid.File{{ID: 1, Path: "/readme.txt"}}}
+		b := &Bot{rdClient: client, config: &config.Config{}}
+		if _, err := b.selectAddedFiles("ID", magnet, "video"); err != nil {
+			t.Fatalf("selectAddedFiles() error = %v", err)
+		}
+		if !client.selectedAll {
+			t.Errorf("selected ids %v, want all files", client.selectedIDs)
+		}
+	})
+}
+
+// TestCachedBadge verifies the cache state round-trips through add metadata into the /list line.
+func TestCachedBadge(t *testing.T) {
+	if meta := cacheUnknown.metadata(nil); meta != nil {
+		t.Errorf("unknown state added metadata %v", meta)
+	}
+	meta := cacheHit.metadata(map[string]interface{}{"source": "import"})
+	if meta["cached"] != true || meta["source"] != "import" {
+		t.Errorf("metadata = %v", meta)
+	}
+	if cacheMiss.metadata(nil)["cached"] != false {
+		t.Error("miss not recorded as cached=false")
+	}
+
+	flags := map[string]bool{"A": true, "B": false}
+	if got := cachedBadge(flags, "A"); !strings.Contains(got, "instant") {
+		t.Errorf("cachedBadge(A) = %q", got)
+	}
+	if got := cachedBadge(flags, "B"); !strings.Contains(got, "no") {
+		t.Errorf("cachedBadge(B) = %q", got)
+	}
+	if got := cachedBadge(flags, "C"); got != "" {
+		t.Errorf("cachedBadge(C) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,218 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/config"
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/go-telegram/bot/models"
+)
+
+// newCallbackUpdate builds a callback-query update pressed by userID on a message in chatID.
+func newCallbackUpdate(chatID, userID int64, threadID int) *models.Update {
+	return &models.Update{
+		CallbackQuery: &models.CallbackQuery{
+			ID:   "cb-1",
+			From: models.User{ID: userID, Username: "presser", FirstName: "Press"},
+			Message: models.MaybeInaccessibleMessage{
+				Type: models.MaybeInaccessibleMessageTypeMessage,
+				Message: &models.Message{
+					ID:              42,
+					MessageThreadID: threadID,
+					Chat:            models.Chat{ID: chatID, Type: models.ChatTypeSupergroup, Title: "Group", IsForum: true},
+				},
+			},
+			Data: "refresh:abc",
+		},
+	}
+}
+
+// TestGetUserFromUpdate_CallbackQuery verifies that the presser, not the message author, is extracted.
+func TestGetUserFromUpdate_CallbackQuery(t *testing.T) {
+	info := getUserFromUpdate(newCallbackUpdate(-100, 7, 3))
+
+	if info.UserID != 7 {
+		t.Errorf("UserID = %d, want 7", info.UserID)
+	}
+	if info.ChatID != -100 {
+		t.Errorf("ChatID = %d, want -100", info.ChatID)
+	}
+	if info.MessageThreadID != 3 {
+		t.Errorf("MessageThreadID = %d, want 3", info.MessageThreadID)
+	}
+	if info.Username != "presser" {
+		t.Errorf("Username = %q, want %q", info.Username, "presser")
+	}
+}
+
+// TestGetUserFromUpdate_InaccessibleCallbackMessage verifies that the chat is still resolved
+// when the callback's message is no longer accessible.
+func TestGetUserFromUpdate_InaccessibleCallbackMessage(t *testing.T) {
+	update := &models.Update{
+		CallbackQuery: &models.CallbackQuery{
+			ID:   "cb-2",
+			From: models.User{ID: 9},
+			Message: models.MaybeInaccessibleMessage{
+				Type:                models.MaybeInaccessibleMessageTypeInaccessibleMessage,
+				InaccessibleMessage: &models.InaccessibleMessage{Chat: models.Chat{ID: -200, Title: "Old"}},
+			},
+		},
+	}
+
+	info := getUserFromUpdate(update)
+	if info.UserID != 9 || info.ChatID != -200 {
+		t.Errorf("got user %d chat %d, want user 9 chat -200", info.UserID, info.ChatID)
+	}
+
+	chatID, title, _, _, _ := getChatFromUpdate(update)
+	if chatID != -200 || title != "Old" {
+		t.Errorf("getChatFromUpdate() = (%d, %q), want (-200, %q)", chatID, title, "Old")
+	}
+}
+
+// TestGetChatFromUpdate_CallbackQuery verifies chat metadata is taken from the callback's message.
+func TestGetChatFromUpdate_CallbackQuery(t *testing.T) {
+	chatID, title, _, chatType, isForum := getChatFromUpdate(newCallbackUpdate(-100, 7, 0))
+
+	if chatID != -100 || title != "Group" || chatType != "supergroup" || !isForum {
+		t.Errorf("getChatFromUpdate() = (%d, %q, %q, %v), want (-100, %q, %q, true)", chatID, title, chatType, isForum, "Group", "supergroup")
+	}
+}
+
+// TestCallbackAuthorization covers the authorization decision for button presses,
+// including users outside the allowlist pressing buttons in an allowed chat.
+func TestCallbackAuthorization(t *testing.T) {
+	cfg := &config.Config{
+		Telegram: config.TelegramConfig{
+			AllowedChatIDs: []int64{-100},
+			SuperAdminIDs:  []int64{1},
+		},
+	}
+	m := NewMiddleware(cfg)
+
+	tests := []struct {
+		name           string
+		chatID, userID int64
+		wantAllowed    bool
+		wantSuperAdmin bool
+	}{
+		{"member in allowed chat", -100, 7, true, false},
+		{"superadmin in private chat", 1, 1, true, true},
+		{"stranger in foreign chat", -999, 7, false, false},
+		{"stranger in private chat", 7, 7, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := getUserFromUpdate(newCallbackUpdate(tt.chatID, tt.userID, 0))
+			allowed, role := m.CheckAuthorization(info.ChatID, info.UserID)
+			superAdmin := role.IsSuperAdmin()
+			if allowed != tt.wantAllowed || superAdmin != tt.wantSuperAdmin {
+				t.Errorf("CheckAuthorization() = (%v, %v), want (%v, %v)", allowed, superAdmin, tt.wantAllowed, tt.wantSuperAdmin)
+			}
+		})
+	}
+}
+
+// newCallbackAuthBot returns a bot allowing chat -100 whose Telegram API and database
+// are fakes, with the recorded Telegram requests and SQL statements
+func newCallbackAuthBot(t *testing.T) (*Bot, func() []string, func() []string) {
+	t.Helper()
+	api, requests := newTestTelegramAPI(t)
+	pool, queries := newFakePostgres(t)
+	cfg := &config.Config{Telegram: config.TelegramConfig{AllowedChatIDs: []int64{-100}}}
+	b := &Bot{
+		api:          api,
+		config:       cfg,
+		middleware:   NewMiddleware(cfg),
+		chatRepo:     db.NewChatRepository(pool),
+		userRepo:     db.NewUserRepository(pool),
+		activityRepo: db.NewActivityRepository(pool),
+	}
+	return b, requests, queries
+}
+
+// TestWithAuthCallback_Unauthorized verifies a button pressed by a stranger in a chat
+// that isn't allowed is answered with an alert and never reaches the handler.
+func TestWithAuthCallback_Unauthorized(t *testing.T) {
+	b, requests, queries := newCallbackAuthBot(t)
+
+	called := false
+	b.withAuthCallback(context.Background(), newCallbackUpdate(-999, 7, 0), func(context.Context, *models.CallbackQuery, int64, int64, int, Role, *db.User) {
+		called = true
+	})
+
+	if called {
+		t.Error("handler invoked for an unauthorized callback")
+	}
+	reqs := requests()
+	if len(reqs) != 1 {
+		t.Fatalf("got %d requests, want 1: %q", len(reqs), reqs)
+	}
+	want := translate(defaultLocale, "error.unauthorized_callback")
+	if !strings.Contains(reqs[0], "answerCallbackQuery") || !strings.Contains(reqs[0], "cb-1") || !strings.Contains(reqs[0], want) {
+		t.Errorf("request = %q, want answerCallbackQuery for cb-1 with %q", reqs[0], want)
+	}
+	if !strings.Contains(reqs[0], "show_alert") {
+		t.Errorf("request = %q, want show_alert set", reqs[0])
+	}
+	logged := false
+	for _, q := range queries() {
+		if strings.Contains(q, "INSERT INTO activity_logs") && strings.Contains(q, string(db.ActivityTypeUnauthorized)) {
+			logged = true
+		}
+	}
+	if !logged {
+		t.Errorf("unauthorized attempt not logged, statements: %q", queries())
+	}
+}
+
+// TestWithAuthCallback_Authorized verifies a button pressed in an allowed chat reaches
+// the handler without an answer from the auth layer.
+func TestWithAuthCallback_Authorized(t *testing.T) {
+	b, requests, _ := newCallbackAuthBot(t)
+
+	var gotChatID, gotChatPK int64
+	var gotUser *db.User
+	b.withAuthCallback(context.Background(), newCallbackUpdate(-100, 7, 0), func(_ context.Context, _ *models.CallbackQuery, chatID, chatPK int64, _ int, _ Role, user *db.User) {
+		gotChatID, gotChatPK, gotUser = chatID, chatPK, user
+	})
+
+	if gotChatID != -100 || gotChatPK != 1 || gotUser == nil {
+		t.Errorf("handler got chat %d, chat PK %d, user %v; want chat -100, chat PK 1 and a user", gotChatID, gotChatPK, gotUser)
+	}
+	if reqs := requests(); len(reqs) != 0 {
+		t.Errorf("got requests %q, want none", reqs)
+	}
+}
+
+// TestAnswerCallback verifies the answer is sent for the pressed query with the toast text and alert flag.
+func TestAnswerCallback(t *testing.T) {
+	api, requests := newTestTelegramAPI(t)
+	b := &Bot{api: api}
+
+	b.answerCallback(context.Background(), "cb-1", "You are not authorized to use this bot.", true)
+	b.answerCallback(context.Background(), "cb-2", "Refreshed", false)
+
+	reqs := requests()
+	if len(reqs) != 2 {
+		t.Fatalf("got %d requests, want 2: %q", len(reqs), reqs)
+	}
+	for i, want := range []struct {
+		id, text string
+		alert    bool
+	}{
+		{"cb-1", "You are not authorized to use this bot.", true},
+		{"cb-2", "Refreshed", false},
+	} {
+		req := reqs[i]
+		if !strings.Contains(req, "answerCallbackQuery") || !strings.Contains(req, want.id) || !strings.Contains(req, want.text) {
+			t.Errorf("request %d = %q, want answerCallbackQuery for %s with %q", i, req, want.id, want.text)
+		}
+		if got := strings.Contains(req, "show_alert"); got != want.alert {
+			t.Errorf("request %d show_alert sent = %v, want %v", i, got, want.alert)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/web"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// accountCapacity returns the account's capacity from the shared metrics cache, or
+// from a live active-count call when no cache is configured
+func (b *Bot) accountCapacity() (web.Capacity, error) {
+	if b.metrics != nil {
+		return web.BuildCapacity(b.metrics.Summary()), nil
+	}
+	active, err := b.rdClient.GetActiveCount()
+	if err != nil {
+		return web.Capacity{}, err
+	}
+	return web.BuildCapacity(web.MetricsSummary{ActiveCount: int64(active.Nb), ActiveLimit: int64(active.Limit), ActiveKnown: true, ScrapedAt: time.Now()}), nil
+}
+
+// formatCapacity renders the /capacity reply
+func formatCapacity(c web.Capacity, now time.Time) string {
+	var text strings.Builder
+	text.WriteString("<b>📦 Account Capacity</b>\n\n")
+	slots := c.ActiveTorrents
+	if slots.Unknown {
+		text.WriteString("• Torrent slots: <b>unknown</b>, Real-Debrid could not be reached\n")
+	} else if slots.Unlimited {
+		fmt.Fprintf(&text, "• Torrent slots: <b>%d</b> in use (unlimited)\n", slots.Used)
+	} else {
+		fmt.Fprintf(&text, "• Torrent slots: <b>%d / %d</b> in use, %d free\n", slots.Used, slots.Limit, slots.Available)
+	}
+	if c.Premium {
+		premium := time.Duration(c.PremiumSeconds) * time.Second
+		fmt.Fprintf(&text, "• Link generation: available (premium for %d days, %d hours)\n", int(premium.Hours())/24, int(premium.Hours())%24)
+	} else {
+		text.WriteString("• Link generation: <b>unavailable</b>, no premium time left\n")
+	}
+	if !c.ScrapedAt.IsZero() {
+		fmt.Fprintf(&text, "\n<i>Updated %s ago</i>", formatDuration(now.Sub(c.ScrapedAt)))
+	}
+	return strings.TrimRight(text.String(), "\n")
+}
+
+// handleCapacityCommand handles the /capacity command. It shows how many torrent slots of
+// the shared account are free and whether links can be generated.
+func (b *Bot) handleCapacityCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "capacity")
+
+		capacity, err := b.accountCapacity()
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Failed to retrieve account capacity: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "capacity", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := formatCapacity(capacity, time.Now())
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "capacity", update.Message.Text, startTime, true, "", len(text))
+	})
+}
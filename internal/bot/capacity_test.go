@@ -0,0 +1,55 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/crazyuploader/rdctl-bot/internal/web"
+)
+
+// activeCountClient is a RealDebridClient reporting a fixed active torrent count.
+type activeCountClient struct {
+	RealDebridClient
+	active realdebrid.ActiveCount
+}
+
+func (c *activeCountClient) GetActiveCount() (*realdebrid.ActiveCount, error) {
+	return &c.active, nil
+}
+
+// TestAccountCapacity_Live verifies capacity falls back to a live active count without a metrics cache.
+func TestAccountCapacity_Live(t *testing.T) {
+	b := &Bot{rdClient: &activeCountClient{active: realdebrid.ActiveCount{Nb: 2, Limit: 8}}}
+
+	capacity, err := b.accountCapacity()
+	if err != nil {
+		t.Fatalf("accountCapacity() error = %v", err)
+	}
+	if want := (web.SlotUsage{Used: 2, Limit: 8, Available: 6}); capacity.ActiveTorrents != want {
+		t.Errorf("ActiveTorrents = %+v, want %+v", capacity.ActiveTorrents, want)
+	}
+}
+
+// TestFormatCapacity verifies limited, unlimited, unknown and non-premium accounts are described.
+func TestFormatCapacity(t *testing.T) {
+	now := time.Now()
+	limited := formatCapacity(web.BuildCapacity(web.MetricsSummary{ActiveCount: 3, ActiveLimit: 5, ActiveKnown: true, PremiumSeconds: 2 * 86400, ScrapedAt: now}), now)
+	for _, want := range []string{"<b>3 / 5</b> in use, 2 free", "premium for 2 days", "Updated"} {
+		if !strings.Contains(limited, want) {
+			t.Errorf("limited capacity missing %q:\n%s", want, limited)
+		}
+	}
+
+	unlimited := formatCapacity(web.BuildCapacity(web.MetricsSummary{ActiveCount: 9, ActiveKnown: true}), now)
+	for _, want := range []string{"<b>9</b> in use (unlimited)", "<b>unavailable</b>"} {
+		if !strings.Contains(unlimited, want) {
+			t.Errorf("unlimited capacity missing %q:\n%s", want, unlimited)
+		}
+	}
+
+	if unknown := formatCapacity(web.BuildCapacity(web.MetricsSummary{}), now); !strings.Contains(unknown, "<b>unknown</b>") {
+		t.Errorf("unscraped capacity not reported as unknown:\n%s", unknown)
+	}
+}
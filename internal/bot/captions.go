@@ -0,0 +1,63 @@
+package bot
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// captionLink finds the link in the caption of a media message without text, such as a
+// forwarded post with the magnet or hoster link below a poster. A magnet caption is
+// returned whole since the magnet handler extracts the link itself; for hoster links the
+// first http(s) URL of the caption is returned.
+func captionLink(msg *models.Message) (link string, magnet, ok bool) {
+	if msg == nil || msg.Text != "" || msg.Caption == "" {
+		return "", false, false
+	}
+	if strings.Contains(msg.Caption, "magnet:?") {
+		return msg.Caption, true, true
+	}
+	for _, field := range strings.Fields(msg.Caption) {
+		if strings.HasPrefix(field, "http://") || strings.HasPrefix(field, "https://") {
+			return field, false, true
+		}
+	}
+	return "", false, false
+}
+
+// withLinkText returns a copy of msg whose text is link, so the text-based link
+// handlers can process a link found in a caption
+func withLinkText(msg *models.Message, link string) *models.Message {
+	copied := *msg
+	copied.Text = link
+	return &copied
+}
+
+// matchCaptionLink matches messages whose media caption holds a magnet or hoster link,
+// unless telegram.caption_links is disabled
+func (b *Bot) matchCaptionLink(update *models.Update) bool {
+	if !b.config.Telegram.CaptionLinks {
+		return false
+	}
+	_, _, ok := captionLink(update.Message)
+	return ok
+}
+
+// handleCaptionLink runs the magnet or hoster link flow for a link in a media caption
+func (b *Bot) handleCaptionLink(ctx context.Context, api *bot.Bot, update *models.Update) {
+	link, magnet, ok := captionLink(update.Message)
+	if !ok {
+		return
+	}
+
+	withText := *update
+	withText.Message = withLinkText(update.Message, link)
+
+	if magnet {
+		b.handleMagnetLink(ctx, api, &withText)
+	} else {
+		b.handleHosterLink(ctx, api, &withText)
+	}
+}
@@ -0,0 +1,65 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/config"
+	"github.com/go-telegram/bot/models"
+)
+
+// TestCaptionLink verifies links are extracted from captions of messages without text.
+func TestCaptionLink(t *testing.T) {
+	const magnetCaption = "Some.Show.S01 1080p\nmagnet:?xt=urn:btih:abc"
+	tests := []struct {
+		name       string
+		msg        *models.Message
+		wantLink   string
+		wantMagnet bool
+		wantOK     bool
+	}{
+		{"magnet caption", &models.Message{Caption: magnetCaption}, magnetCaption, true, true},
+		{"hoster caption", &models.Message{Caption: "Movie (2024)\nDownload: https://host.example/f/1 mirror https://other.example/f/2"}, "https://host.example/f/1", false, true},
+		{"forwarded caption", &models.Message{Caption: "https://host.example/f/1", ForwardOrigin: &models.MessageOrigin{}}, "https://host.example/f/1", false, true},
+		{"no link", &models.Message{Caption: "just a poster"}, "", false, false},
+		{"text message", &models.Message{Text: "https://host.example/f/1", Caption: "https://host.example/f/2"}, "", false, false},
+		{"no caption", &models.Message{}, "", false, false},
+		{"nil", nil, "", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, magnet, ok := captionLink(tt.msg)
+			if link != tt.wantLink || magnet != tt.wantMagnet || ok != tt.wantOK {
+				t.Errorf("captionLink() = (%q, %v, %v), want (%q, %v, %v)", link, magnet, ok, tt.wantLink, tt.wantMagnet, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestMatchCaptionLink verifies caption links are matched only when telegram.caption_links is on.
+func TestMatchCaptionLink(t *testing.T) {
+	update := &models.Update{Message: &models.Message{Caption: "https://host.example/f/1"}}
+
+	b := &Bot{config: &config.Config{}}
+	if b.matchCaptionLink(update) {
+		t.Error("matched a caption link with caption_links disabled")
+	}
+	b.config.Telegram.CaptionLinks = true
+	if !b.matchCaptionLink(update) {
+		t.Error("did not match a caption link with caption_links enabled")
+	}
+	if b.matchCaptionLink(&models.Update{}) {
+		t.Error("matched an update without a message")
+	}
+}
+
+// TestChannelPostLink_Caption verifies channel posts with a link in the caption are routed.
+func TestChannelPostLink_Caption(t *testing.T) {
+	ok, magnet := channelPostLink(&models.Message{Caption: "New upload\nmagnet:?xt=urn:btih:abc"})
+	if !ok || !magnet {
+		t.Errorf("channelPostLink(magnet caption) = (%v, %v), want (true, true)", ok, magnet)
+	}
+	msg := withLinkText(&models.Message{ID: 3, Caption: "x https://host.example/f"}, "https://host.example/f")
+	if msg.Text != "https://host.example/f" || msg.ID != 3 {
+		t.Errorf("withLinkText() = %+v", msg)
+	}
+}
@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// channelPostLink reports whether a channel post holds a magnet or hoster link the
+// bot should process, and whether it is a magnet link. Posts without text are checked
+// for a link in their media caption.
+func channelPostLink(post *models.Message) (ok, magnet bool) {
+	if post == nil {
+		return false, false
+	}
+	if post.Text == "" {
+		_, magnet, ok = captionLink(post)
+		return ok, magnet
+	}
+	if strings.Contains(post.Text, "magnet:?") {
+		return true, true
+	}
+	if strings.HasPrefix(post.Text, "http://") || strings.HasPrefix(post.Text, "https://") {
+		return true, false
+	}
+	return false, false
+}
+
+// matchChannelPostLink matches channel posts containing a magnet or hoster link
+func matchChannelPostLink(update *models.Update) bool {
+	ok, _ := channelPostLink(update.ChannelPost)
+	return ok
+}
+
+// handleChannelPost runs the magnet or hoster link flow for a link posted in a channel.
+// Channel posts have no sender, so the post is handled as a message from the channel
+// itself: authorization then rests on the channel being allow-listed.
+func (b *Bot) handleChannelPost(ctx context.Context, api *bot.Bot, update *models.Update) {
+	ok, magnet := channelPostLink(update.ChannelPost)
+	if !ok {
+		return
+	}
+
+	post := *update
+	post.Message = update.ChannelPost
+	post.ChannelPost = nil
+	if link, _, ok := captionLink(update.ChannelPost); ok {
+		post.Message = withLinkText(update.ChannelPost, link)
+	}
+
+	if magnet {
+		b.handleMagnetLink(ctx, api, &post)
+	} else {
+		b.handleHosterLink(ctx, api, &post)
+	}
+}
@@ -0,0 +1,83 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/config"
+	"github.com/go-telegram/bot/models"
+)
+
+// newChannelPostUpdate builds a channel-post update in channel chatID with text.
+func newChannelPostUpdate(chatID int64, text string) *models.Update {
+	channel := models.Chat{ID: chatID, Type: models.ChatTypeChannel, Title: "Drops", Username: "drops"}
+	return &models.Update{ChannelPost: &models.Message{
+		ID:         5,
+		Text:       text,
+		Chat:       channel,
+		SenderChat: &channel,
+	}}
+}
+
+// TestChannelPostLink verifies which channel posts are picked up and how they are routed.
+func TestChannelPostLink(t *testing.T) {
+	tests := []struct {
+		name       string
+		post       *models.Message
+		wantOK     bool
+		wantMagnet bool
+	}{
+		{"magnet", &models.Message{Text: "new: magnet:?xt=urn:btih:abc"}, true, true},
+		{"hoster link", &models.Message{Text: "https://host.example/file"}, true, false},
+		{"plain text", &models.Message{Text: "hello subscribers"}, false, false},
+		{"link mid-text", &models.Message{Text: "see https://host.example/file"}, false, false},
+		{"no text", &models.Message{}, false, false},
+		{"nil", nil, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, magnet := channelPostLink(tt.post)
+			if ok != tt.wantOK || magnet != tt.wantMagnet {
+				t.Errorf("channelPostLink() = (%v, %v), want (%v, %v)", ok, magnet, tt.wantOK, tt.wantMagnet)
+			}
+		})
+	}
+
+	if matchChannelPostLink(&models.Update{Message: &models.Message{Text: "magnet:?xt=urn:btih:abc"}}) {
+		t.Error("matchChannelPostLink matched a regular message")
+	}
+}
+
+// TestGetUserFromUpdate_ChannelPost verifies channel posts resolve to the channel with no user.
+func TestGetUserFromUpdate_ChannelPost(t *testing.T) {
+	info := getUserFromUpdate(newChannelPostUpdate(-1001, "magnet:?xt=urn:btih:abc"))
+
+	if info.ChatID != -1001 {
+		t.Errorf("ChatID = %d, want -1001", info.ChatID)
+	}
+	if info.UserID != 0 {
+		t.Errorf("UserID = %d, want 0 for a channel post", info.UserID)
+	}
+	if info.Username != "Drops" {
+		t.Errorf("Username = %q, want the channel title", info.Username)
+	}
+
+	chatID, title, username, chatType, _ := getChatFromUpdate(newChannelPostUpdate(-1001, "x"))
+	if chatID != -1001 || title != "Drops" || username != "drops" || chatType != "channel" {
+		t.Errorf("getChatFromUpdate() = (%d, %q, %q, %q), want (-1001, Drops, drops, channel)", chatID, title, username, chatType)
+	}
+}
+
+// TestChannelPostAuthorization verifies a channel post is only allowed when the channel is allow-listed.
+func TestChannelPostAuthorization(t *testing.T) {
+	m := NewMiddleware(&config.Config{Telegram: config.TelegramConfig{AllowedChatIDs: []int64{-1001}}})
+
+	for _, tt := range []struct {
+		chatID int64
+		want   bool
+	}{{-1001, true}, {-1002, false}} {
+		info := getUserFromUpdate(newChannelPostUpdate(tt.chatID, "https://host.example/file"))
+		if allowed, _ := m.CheckAuthorization(info.ChatID, info.UserID); allowed != tt.want {
+			t.Errorf("channel %d allowed = %v, want %v", tt.chatID, allowed, tt.want)
+		}
+	}
+}
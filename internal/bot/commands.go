@@ -0,0 +1,32 @@
+package bot
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// isCommand reports whether text invokes command. The command must be followed by
+// whitespace, a bot mention ("/del@MyBot") or the end of the text, so "/del" does
+// not match "/delay" or "/delete".
+func isCommand(text, command string) bool {
+	rest, ok := strings.CutPrefix(text, command)
+	if !ok {
+		return false
+	}
+	if rest == "" || rest[0] == '@' {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(rest)
+	return unicode.IsSpace(r)
+}
+
+// matchCommand returns a handler match func for text messages invoking command
+func matchCommand(command string) bot.MatchFunc {
+	return func(update *models.Update) bool {
+		return update.Message != nil && isCommand(update.Message.Text, command)
+	}
+}
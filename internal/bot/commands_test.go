@@ -0,0 +1,47 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/go-telegram/bot/models"
+)
+
+// TestIsCommand verifies commands only match on a word boundary.
+func TestIsCommand(t *testing.T) {
+	tests := []struct {
+		text, command string
+		want          bool
+	}{
+		{"/del", "/del", true},
+		{"/del ABC", "/del", true},
+		{"/del\nABC", "/del", true},
+		{"/del@RDBot ABC", "/del", true},
+		{"/delete ABC", "/delete", true},
+		{"/delete ABC", "/del", false},
+		{"/delay", "/del", false},
+		{"/delicious", "/del", false},
+		{"/deleteme", "/delete", false},
+		{"/autodelete-interval 6", "/autodelete", false},
+		{"/autodelete-interval 6", "/autodelete-interval", true},
+		{"please /del ABC", "/del", false},
+	}
+	for _, tt := range tests {
+		if got := isCommand(tt.text, tt.command); got != tt.want {
+			t.Errorf("isCommand(%q, %q) = %v, want %v", tt.text, tt.command, got, tt.want)
+		}
+	}
+}
+
+// TestMatchCommand verifies the match func ignores updates without a message.
+func TestMatchCommand(t *testing.T) {
+	match := matchCommand("/del")
+	if !match(&models.Update{Message: &models.Message{Text: "/del ABC"}}) {
+		t.Error("matchCommand did not match /del ABC")
+	}
+	if match(&models.Update{Message: &models.Message{Text: "/delay"}}) {
+		t.Error("matchCommand matched /delay")
+	}
+	if match(&models.Update{CallbackQuery: &models.CallbackQuery{Data: "/del"}}) {
+		t.Error("matchCommand matched a callback query")
+	}
+}
@@ -0,0 +1,116 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// errNoHash is returned when a torrent has no infohash to rebuild a magnet from
+var errNoHash = errors.New("torrent has no known hash")
+
+// buildMagnet reconstructs a magnet link from an infohash, naming it after name if set
+func buildMagnet(hash, name string) (string, error) {
+	if hash == "" {
+		return "", errNoHash
+	}
+	magnet := "magnet:?xt=urn:btih:" + strings.ToLower(hash)
+	if name != "" {
+		magnet += "&dn=" + url.QueryEscape(name)
+	}
+	return magnet, nil
+}
+
+// copyResult is the outcome of re-adding a torrent with /copy
+type copyResult struct {
+	newID     string
+	hash      string
+	name      string
+	magnet    string
+	selectErr error // file selection failed; the copy exists but waits for /selectall
+}
+
+// copyTorrent re-adds torrentID as a fresh torrent from its hash and selects all its files
+func (b *Bot) copyTorrent(torrentID string) (copyResult, error) {
+	torrent, err := b.rdClient.GetTorrentInfo(torrentID)
+	if err != nil {
+		return copyResult{}, fmt.Errorf("could not retrieve torrent info: %w", err)
+	}
+	magnet, err := buildMagnet(torrent.Hash, torrent.Filename)
+	if err != nil {
+		return copyResult{}, err
+	}
+	response, err := b.rdClient.AddMagnet(magnet)
+	if err != nil {
+		return copyResult{}, fmt.Errorf("failed to add torrent: %w", err)
+	}
+	result := copyResult{newID: response.ID, hash: torrent.Hash, name: torrent.Filename, magnet: magnet}
+	if err := b.rdClient.SelectAllFiles(response.ID); err != nil {
+		log.Printf("Error selecting files for copied torrent %s: %v", response.ID, err)
+		result.selectErr = err
+	}
+	return result, nil
+}
+
+// handleCopyCommand handles the /copy command. It re-adds a torrent from its hash,
+// e.g. to bring back one that expired from Real-Debrid, and reports the new ID.
+func (b *Bot) handleCopyCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "copy")
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) < 2 {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /copy &lt;torrent_id&gt;", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "copy", update.Message.Text, startTime, false, "Missing arguments", 0)
+			return
+		}
+		torrentID := parts[1]
+
+		result, err := b.copyTorrent(torrentID)
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Cannot copy <code>%s</code>: %s", html.EscapeString(torrentID), html.EscapeString(err.Error())), update.Message.ID)
+			if user != nil {
+				if logErr := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, torrentID, "", "", "", "copy", "error", 0, 0, false, err.Error(), nil); logErr != nil {
+					log.Printf("Warning: failed to log torrent copy error: %v", logErr)
+				}
+			}
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "copy", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := fmt.Sprintf(
+			"<b>[OK]</b> Torrent Copied\n\n"+
+				"<i>File:</i> <code>%s</code>\n"+
+				"<i>Original ID:</i> <code>%s</code>\n"+
+				"<i>New ID:</i> <code>%s</code>\n\n",
+			html.EscapeString(truncateName(result.name, b.config.App.MaxFilenameDisplay)),
+			html.EscapeString(torrentID),
+			html.EscapeString(result.newID),
+		)
+		status := "files_selected"
+		if result.selectErr != nil {
+			status = "waiting_files_selection"
+			text += fmt.Sprintf("File selection failed: %s\nUse <code>/selectall %s</code> once the magnet is converted.", html.EscapeString(result.selectErr.Error()), html.EscapeString(result.newID))
+		} else {
+			text += fmt.Sprintf("Use <code>/info %s</code> to check its status.", html.EscapeString(result.newID))
+		}
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+
+		if user != nil {
+			if err := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, result.newID, result.hash, result.name, result.magnet, "copy", status, 0, 0, true, "", map[string]interface{}{"source_id": torrentID}); err != nil {
+				log.Printf("Warning: failed to log torrent copy: %v", err)
+			}
+		}
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "copy", update.Message.Text, startTime, true, "", len(text))
+	})
+}
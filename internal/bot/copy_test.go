@@ -0,0 +1,78 @@
+package bot
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// TestBuildMagnet verifies the magnet carries the lowercase hash and escaped name.
+func TestBuildMagnet(t *testing.T) {
+	got, err := buildMagnet("ABCDEF", "My File & more.mkv")
+	if err != nil {
+		t.Fatalf("buildMagnet() error = %v", err)
+	}
+	if want := "magnet:?xt=urn:btih:abcdef&dn=My+File+%26+more.mkv"; got != want {
+		t.Errorf("buildMagnet() = %q, want %q", got, want)
+	}
+	if got, _ := buildMagnet("abc", ""); got != "magnet:?xt=urn:btih:abc" {
+		t.Errorf("buildMagnet() without name = %q", got)
+	}
+	if _, err := buildMagnet("", "x"); !errors.Is(err, errNoHash) {
+		t.Errorf("buildMagnet() without hash error = %v, want errNoHash", err)
+	}
+}
+
+// copyClient is a RealDebridClient recording the calls made by /copy.
+type copyClient struct {
+	RealDebridClient
+	torrent   *realdebrid.Torrent
+	added     string
+	selected  string
+	selectErr error
+}
+
+func (c *copyClient) GetTorrentInfo(string) (*realdebrid.Torrent, error) { return c.torrent, nil }
+
+func (c *copyClient) AddMagnet(magnet string) (*realdebrid.AddMagnetResponse, error) {
+	c.added = magnet
+	return &realdebrid.AddMagnetResponse{ID: "NEW"}, nil
+}
+
+func (c *copyClient) SelectAllFiles(torrentID string) error {
+	c.selected = torrentID
+	return c.selectErr
+}
+
+// TestCopyTorrent verifies the torrent is re-added from its hash and its files selected.
+func TestCopyTorrent(t *testing.T) {
+	client := &copyClient{torrent: &realdebrid.Torrent{ID: "OLD", Hash: "abc", Filename: "f"}}
+	b := &Bot{rdClient: client}
+
+	result, err := b.copyTorrent("OLD")
+	if err != nil {
+		t.Fatalf("copyTorrent() error = %v", err)
+	}
+	if result.newID != "NEW" || client.added != "magnet:?xt=urn:btih:abc&dn=f" || client.selected != "NEW" {
+		t.Errorf("copyTorrent() = %+v, added %q, selected %q", result, client.added, client.selected)
+	}
+
+	client.selectErr = errors.New("not ready")
+	if result, err := b.copyTorrent("OLD"); err != nil || result.selectErr == nil {
+		t.Errorf("copyTorrent() with failing selection = (%+v, %v), want the copy with selectErr set", result, err)
+	}
+}
+
+// TestCopyTorrent_NoHash verifies nothing is added for a torrent without a hash.
+func TestCopyTorrent_NoHash(t *testing.T) {
+	client := &copyClient{torrent: &realdebrid.Torrent{ID: "OLD"}}
+	b := &Bot{rdClient: client}
+
+	if _, err := b.copyTorrent("OLD"); !errors.Is(err, errNoHash) {
+		t.Errorf("copyTorrent() error = %v, want errNoHash", err)
+	}
+	if client.added != "" {
+		t.Errorf("AddMagnet called with %q for a torrent without a hash", client.added)
+	}
+}
@@ -0,0 +1,302 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// deleteConfirmThreshold is the largest /delete batch run without a confirmation button
+	deleteConfirmThreshold = 5
+
+	// deleteConfirmTTL is how long a batch waits for its confirmation
+	deleteConfirmTTL = 5 * time.Minute
+
+	// deleteCallbackPrefix prefixes the confirmation buttons' callback data
+	deleteCallbackPrefix = "delete:"
+
+	// maxDeleteIDs caps the torrents in one /delete so its confirmation and summary
+	// stay within Telegram's message length limit
+	maxDeleteIDs = 50
+)
+
+// batchDeleteDelay spaces out Real-Debrid delete calls within a batch
+var batchDeleteDelay = 500 * time.Millisecond
+
+// parseDeleteIDs returns the torrent IDs given to /delete, in order and without duplicates
+func parseDeleteIDs(text string) []string {
+	parts := strings.Fields(text)
+	if len(parts) < 2 {
+		return nil
+	}
+	seen := make(map[string]bool, len(parts)-1)
+	ids := make([]string, 0, len(parts)-1)
+	for _, id := range parts[1:] {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// deleteResult is the outcome of deleting one torrent in a batch
+type deleteResult struct {
+	id  string
+	err error
+}
+
+// formatDeleteSummary renders the per-ID outcome of a batch delete
+func formatDeleteSummary(results []deleteResult) string {
+	deleted := 0
+	for _, r := range results {
+		if r.err == nil {
+			deleted++
+		}
+	}
+
+	var text strings.Builder
+	if deleted == len(results) {
+		text.WriteString("<b>[OK]</b> ")
+	} else {
+		text.WriteString("<b>[ERROR]</b> ")
+	}
+	fmt.Fprintf(&text, "Deleted %d of %d torrents.\n\n", deleted, len(results))
+	for _, r := range results {
+		if r.err == nil {
+			fmt.Fprintf(&text, "✅ <code>%s</code>\n", html.EscapeString(r.id))
+		} else {
+			fmt.Fprintf(&text, "❌ <code>%s</code> — %s\n", html.EscapeString(r.id), html.EscapeString(r.err.Error()))
+		}
+	}
+	return strings.TrimRight(text.String(), "\n")
+}
+
+// deleteTorrents deletes each torrent in turn, pausing between calls, and logs every
+// deletion as its own torrent activity. It stops early if ctx is cancelled; IDs not
+// attempted are reported with the context error.
+func (b *Bot) deleteTorrents(ctx context.Context, user *db.User, chatPK int64, ids []string) []deleteResult {
+	results := make([]deleteResult, 0, len(ids))
+	for i, id := range ids {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(batchDeleteDelay):
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			results = append(results, deleteResult{id: id, err: err})
+			continue
+		}
+
+		err := b.rdClient.DeleteTorrent(id)
+		results = append(results, deleteResult{id: id, err: err})
+
+		if user == nil {
+			continue
+		}
+		status, errMsg := "deleted", ""
+		if err != nil {
+			status, errMsg = "error", err.Error()
+		}
+		if logErr := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, id, "", "", "", "delete", status, 0, 0, err == nil, errMsg, map[string]interface{}{"batch_size": len(ids)}); logErr != nil {
+			log.Printf("Warning: failed to log batch delete of torrent %s: %v", id, logErr)
+		}
+	}
+	return results
+}
+
+// runDeleteBatch deletes ids, replies with the summary and logs the command
+func (b *Bot) runDeleteBatch(ctx context.Context, user *db.User, chatID, chatPK int64, messageThreadID, messageID int, fullCommand string, ids []string, startTime time.Time) {
+	results := b.deleteTorrents(ctx, user, chatPK, ids)
+	text := formatDeleteSummary(results)
+	b.sendHTMLMessage(ctx, chatID, messageThreadID, text, messageID)
+
+	var failed []string
+	for _, r := range results {
+		if r.err != nil {
+			failed = append(failed, r.id)
+		}
+	}
+	errMsg := ""
+	if len(failed) > 0 {
+		errMsg = "Failed to delete: " + strings.Join(failed, ", ")
+	}
+	b.logCommandHelper(ctx, user, chatPK, int64(messageID), messageThreadID, "delete", fullCommand, startTime, len(failed) == 0, errMsg, len(text))
+	b.logActivityHelper(ctx, user, chatPK, int64(messageID), messageThreadID, db.ActivityTypeTorrentDelete, "delete", len(failed) == 0, errMsg, map[string]any{"torrent_ids": ids, "failed": len(failed)})
+}
+
+// deleteBatchKey identifies whose confirmation a batch is waiting for
+type deleteBatchKey struct {
+	chatID int64
+	userID int64
+}
+
+// pendingDeleteBatch is a /delete batch waiting for confirmation
+type pendingDeleteBatch struct {
+	ids         []string
+	messageID   int // confirmation message carrying the buttons
+	commandID   int // the /delete message being answered
+	fullCommand string
+	expiresAt   time.Time
+}
+
+// deleteBatchStore tracks batches awaiting confirmation. Each user has at most one
+// pending batch per chat; a newer batch replaces the older one.
+type deleteBatchStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	batches map[deleteBatchKey]pendingDeleteBatch
+}
+
+// newDeleteBatchStore creates a deleteBatchStore whose batches expire after ttl
+func newDeleteBatchStore(ttl time.Duration) *deleteBatchStore {
+	return &deleteBatchStore{ttl: ttl, batches: make(map[deleteBatchKey]pendingDeleteBatch)}
+}
+
+// set records that batch in chatID awaits userID's confirmation
+func (s *deleteBatchStore) set(chatID, userID int64, batch pendingDeleteBatch, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweepLocked(now)
+	batch.expiresAt = now.Add(s.ttl)
+	s.batches[deleteBatchKey{chatID, userID}] = batch
+}
+
+// take removes and returns the user's batch if messageID is its confirmation message
+func (s *deleteBatchStore) take(chatID, userID int64, messageID int, now time.Time) (pendingDeleteBatch, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := deleteBatchKey{chatID, userID}
+	p, ok := s.batches[key]
+	if !ok || p.messageID != messageID {
+		return pendingDeleteBatch{}, false
+	}
+	delete(s.batches, key)
+	if !now.Before(p.expiresAt) {
+		return pendingDeleteBatch{}, false
+	}
+	return p, true
+}
+
+// sweep drops expired batches and returns how many were dropped
+func (s *deleteBatchStore) sweep(now time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sweepLocked(now)
+}
+
+// sweepLocked drops expired batches and returns how many were dropped; the caller must hold s.mu
+func (s *deleteBatchStore) sweepLocked(now time.Time) int {
+	dropped := 0
+	for key, p := range s.batches {
+		if !now.Before(p.expiresAt) {
+			delete(s.batches, key)
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// requestDeleteConfirmation asks the user to confirm a large batch with inline buttons
+func (b *Bot) requestDeleteConfirmation(ctx context.Context, update *models.Update, chatID int64, messageThreadID int, ids []string) error {
+	var text strings.Builder
+	fmt.Fprintf(&text, "<b>Delete %d torrents?</b>\n\n", len(ids))
+	for _, id := range ids {
+		fmt.Fprintf(&text, "• <code>%s</code>\n", html.EscapeString(id))
+	}
+	text.WriteString("\n<i>This cannot be undone.</i>")
+
+	params := &bot.SendMessageParams{
+		ChatID:    chatID,
+		Text:      text.String(),
+		ParseMode: models.ParseModeHTML,
+		ReplyParameters: &models.ReplyParameters{
+			MessageID: update.Message.ID,
+		},
+		ReplyMarkup: &models.InlineKeyboardMarkup{
+			InlineKeyboard: [][]models.InlineKeyboardButton{{
+				{Text: "Delete", CallbackData: deleteCallbackPrefix + "confirm"},
+				{Text: "Cancel", CallbackData: deleteCallbackPrefix + "cancel"},
+			}},
+		},
+	}
+	if messageThreadID != 0 {
+		params.MessageThreadID = messageThreadID
+	}
+
+	if err := b.middleware.WaitForRateLimitWithContext(ctx); err != nil {
+		return err
+	}
+	sent, err := b.api.SendMessage(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	b.deleteBatches.set(chatID, update.Message.From.ID, pendingDeleteBatch{
+		ids:         ids,
+		messageID:   sent.ID,
+		commandID:   update.Message.ID,
+		fullCommand: update.Message.Text,
+	}, time.Now())
+	return nil
+}
+
+// handleDeleteConfirmCallback handles the Delete / Cancel buttons of a batch confirmation.
+// Only the superadmin who issued the batch can answer it.
+func (b *Bot) handleDeleteConfirmCallback(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuthCallback(ctx, update, func(ctx context.Context, query *models.CallbackQuery, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "delete_confirm")
+
+		msg := query.Message.Message
+		if msg == nil {
+			b.answerCallback(ctx, query.ID, "This confirmation has expired.", false)
+			return
+		}
+		if !role.IsSuperAdmin() {
+			b.answerCallback(ctx, query.ID, "Access Denied. Only superadmins can delete torrents.", true)
+			return
+		}
+
+		batch, ok := b.deleteBatches.take(chatID, query.From.ID, msg.ID, time.Now())
+		if !ok {
+			b.answerCallback(ctx, query.ID, "This confirmation has expired or belongs to someone else.", true)
+			return
+		}
+
+		if strings.TrimPrefix(query.Data, deleteCallbackPrefix) != "confirm" {
+			b.answerCallback(ctx, query.ID, "Cancelled.", false)
+			b.editDeleteConfirmation(ctx, chatID, msg.ID, "Batch delete cancelled.")
+			b.logCommandHelper(ctx, user, chatPK, int64(batch.commandID), messageThreadID, "delete", batch.fullCommand, startTime, false, "Cancelled", 0)
+			return
+		}
+
+		b.answerCallback(ctx, query.ID, fmt.Sprintf("Deleting %d torrents...", len(batch.ids)), false)
+		b.editDeleteConfirmation(ctx, chatID, msg.ID, fmt.Sprintf("Deleting %d torrents...", len(batch.ids)))
+		b.runDeleteBatch(ctx, user, chatID, chatPK, messageThreadID, batch.commandID, batch.fullCommand, batch.ids, startTime)
+	})
+}
+
+// editDeleteConfirmation replaces a confirmation message's text, removing its buttons
+func (b *Bot) editDeleteConfirmation(ctx context.Context, chatID int64, messageID int, text string) {
+	if err := b.middleware.WaitForRateLimitWithContext(ctx); err != nil {
+		return
+	}
+	if _, err := b.api.EditMessageText(ctx, &bot.EditMessageTextParams{
+		ChatID:    chatID,
+		MessageID: messageID,
+		Text:      text,
+	}); err != nil {
+		log.Printf("Error updating delete confirmation %d: %v", messageID, err)
+	}
+}
@@ -0,0 +1,135 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParseDeleteIDs verifies IDs are read in order after the command, without duplicates.
+func TestParseDeleteIDs(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"/delete", nil},
+		{"/delete   ", nil},
+		{"/delete ABC", []string{"ABC"}},
+		{"/delete a b  c", []string{"a", "b", "c"}},
+		{"/del a b a", []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		if got := parseDeleteIDs(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("parseDeleteIDs(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+// TestFormatDeleteSummary_MaxBatchFits verifies the summary of the largest batch
+// /delete accepts fits in one Telegram message.
+func TestFormatDeleteSummary_MaxBatchFits(t *testing.T) {
+	results := make([]deleteResult, maxDeleteIDs)
+	for i := range results {
+		results[i] = deleteResult{id: strings.Repeat("X", 13), err: errors.New("API error: unknown_ressource (code: 7)")}
+	}
+	if got := len(formatDeleteSummary(results)); got > 4096 {
+		t.Errorf("summary of %d failures is %d characters, want at most 4096", maxDeleteIDs, got)
+	}
+}
+
+// failingDeleteClient is a RealDebridClient whose DeleteTorrent fails for selected IDs.
+type failingDeleteClient struct {
+	RealDebridClient
+	fail    map[string]error
+	deleted []string
+}
+
+func (c *failingDeleteClient) DeleteTorrent(torrentID string) error {
+	if err := c.fail[torrentID]; err != nil {
+		return err
+	}
+	c.deleted = append(c.deleted, torrentID)
+	return nil
+}
+
+// TestDeleteTorrents_PartialFailure verifies every ID is attempted and failures are reported per ID.
+func TestDeleteTorrents_PartialFailure(t *testing.T) {
+	defer func(d time.Duration) { batchDeleteDelay = d }(batchDeleteDelay)
+	batchDeleteDelay = 0
+
+	client := &failingDeleteClient{fail: map[string]error{"b": errors.New("unknown_ressource")}}
+	b := &Bot{rdClient: client}
+
+	results := b.deleteTorrents(context.Background(), nil, 0, []string{"a", "b", "c"})
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	if !slices.Equal(client.deleted, []string{"a", "c"}) {
+		t.Errorf("deleted = %v, want [a c]", client.deleted)
+	}
+	if results[0].err != nil || results[1].err == nil || results[2].err != nil {
+		t.Errorf("results = %+v, want only b to fail", results)
+	}
+
+	text := formatDeleteSummary(results)
+	for _, want := range []string{"<b>[ERROR]</b> Deleted 2 of 3 torrents.", "✅ <code>a</code>", "❌ <code>b</code> — unknown_ressource", "✅ <code>c</code>"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("summary missing %q:\n%s", want, text)
+		}
+	}
+}
+
+// TestDeleteTorrents_Cancelled verifies IDs not reached before cancellation are reported, not deleted.
+func TestDeleteTorrents_Cancelled(t *testing.T) {
+	client := &failingDeleteClient{}
+	b := &Bot{rdClient: client}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := b.deleteTorrents(ctx, nil, 0, []string{"a", "b"})
+	if len(client.deleted) != 0 {
+		t.Errorf("deleted = %v after cancellation, want none", client.deleted)
+	}
+	for _, r := range results {
+		if !errors.Is(r.err, context.Canceled) {
+			t.Errorf("result %s err = %v, want context.Canceled", r.id, r.err)
+		}
+	}
+}
+
+// TestFormatDeleteSummary_AllDeleted verifies a fully successful batch is reported as OK.
+func TestFormatDeleteSummary_AllDeleted(t *testing.T) {
+	text := formatDeleteSummary([]deleteResult{{id: "a"}, {id: "b"}})
+	if !strings.HasPrefix(text, "<b>[OK]</b> Deleted 2 of 2 torrents.") {
+		t.Errorf("summary = %q", text)
+	}
+}
+
+// TestDeleteBatchStore verifies a batch is confirmed once, only from its own message, and expires.
+func TestDeleteBatchStore(t *testing.T) {
+	s := newDeleteBatchStore(time.Minute)
+	now := time.Now()
+	s.set(-100, 7, pendingDeleteBatch{ids: []string{"a", "b"}, messageID: 55}, now)
+
+	if _, ok := s.take(-100, 8, 55, now); ok {
+		t.Error("take() succeeded for a different user")
+	}
+	if _, ok := s.take(-100, 7, 56, now); ok {
+		t.Error("take() succeeded for a different message")
+	}
+	batch, ok := s.take(-100, 7, 55, now)
+	if !ok || !slices.Equal(batch.ids, []string{"a", "b"}) {
+		t.Fatalf("take() = (%v, %v), want the pending batch", batch.ids, ok)
+	}
+	if _, ok := s.take(-100, 7, 55, now); ok {
+		t.Error("second take() succeeded, want batch consumed")
+	}
+
+	s.set(-100, 7, pendingDeleteBatch{ids: []string{"a"}, messageID: 60}, now)
+	if _, ok := s.take(-100, 7, 60, now.Add(2*time.Minute)); ok {
+		t.Error("take() succeeded for an expired batch")
+	}
+}
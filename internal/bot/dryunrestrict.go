@@ -0,0 +1,61 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// formatDryUnrestrict renders the /dryunrestrict preview of link
+func formatDryUnrestrict(check *realdebrid.LinkCheck, link string) string {
+	text := formatLinkCheck(check)
+	if check.IsSupported() {
+		text += fmt.Sprintf("\n\n<i>Nothing was unrestricted.</i> Use <code>/unrestrict %s</code> to generate the download link.", html.EscapeString(link))
+	}
+	return text
+}
+
+// handleDryUnrestrictCommand handles /dryunrestrict <link>. It runs the checks of
+// /unrestrict, including the host allow and block lists, and previews the file through
+// /unrestrict/check, so no download is created, no traffic is used and nothing is
+// added to the download history.
+func (b *Bot) handleDryUnrestrictCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "dryunrestrict")
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) < 2 {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /dryunrestrict &lt;link&gt;", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "dryunrestrict", update.Message.Text, startTime, false, "Missing arguments", 0)
+			return
+		}
+
+		link := strings.Join(parts[1:], " ")
+		if b.rejectLongInput(ctx, update, user, chatID, chatPK, messageThreadID, "dryunrestrict", link, startTime) {
+			return
+		}
+		if b.rejectBlockedHost(ctx, update, user, chatID, chatPK, messageThreadID, "dryunrestrict", link, startTime) {
+			return
+		}
+
+		check, err := b.rdClient.CheckLink(link)
+		if err != nil {
+			text := fmt.Sprintf("<b>[ERROR]</b> Link is not available: %s", html.EscapeString(err.Error()))
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "dryunrestrict", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := formatDryUnrestrict(check, link)
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "dryunrestrict", update.Message.Text, startTime, true, "", len(text))
+	})
+}
@@ -0,0 +1,32 @@
+package bot
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// TestFormatDryUnrestrict verifies the preview of a mocked /unrestrict/check response
+// shows the file details and only points to /unrestrict for supported links.
+func TestFormatDryUnrestrict(t *testing.T) {
+	const link = "https://example.com/f/1?a=b&c=d"
+	var check realdebrid.LinkCheck
+	if err := json.Unmarshal([]byte(`{"host":"example.com","link":"https://example.com/f/1","filename":"movie.mkv","filesize":1073741824,"supported":1}`), &check); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	got := formatDryUnrestrict(&check, link)
+	for _, want := range []string{"Link is supported", "movie.mkv", "1.00 GB", "example.com", "/unrestrict https://example.com/f/1?a=b&amp;c=d"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in %q", want, got)
+		}
+	}
+
+	check.Supported = 0
+	got = formatDryUnrestrict(&check, link)
+	if !strings.Contains(got, "not supported") || strings.Contains(got, "/unrestrict") {
+		t.Errorf("unsupported preview = %q", got)
+	}
+}
@@ -0,0 +1,91 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// entityText returns the part of text covered by e. Telegram measures entity offsets
+// and lengths in UTF-16 code units, so text before the link containing emoji or other
+// characters outside the BMP still yields the right span.
+func entityText(text string, e models.MessageEntity) string {
+	units := utf16.Encode([]rune(text))
+	if e.Offset < 0 || e.Length <= 0 || e.Offset+e.Length > len(units) {
+		return ""
+	}
+	return string(utf16.Decode(units[e.Offset : e.Offset+e.Length]))
+}
+
+// entityLinks returns the http(s) links Telegram marked in a text message, in order and
+// without duplicates: the text of url entities and the target of text_link entities,
+// so links in the middle of a sentence or behind formatted text are found. Links
+// without a scheme, such as "host.example/f/1", are skipped.
+func entityLinks(msg *models.Message) []string {
+	if msg == nil || msg.Text == "" {
+		return nil
+	}
+	var links []string
+	seen := make(map[string]bool)
+	for _, e := range msg.Entities {
+		var link string
+		switch e.Type {
+		case models.MessageEntityTypeURL:
+			link = entityText(msg.Text, e)
+		case models.MessageEntityTypeTextLink:
+			link = e.URL
+		default:
+			continue
+		}
+		if !strings.HasPrefix(link, "http://") && !strings.HasPrefix(link, "https://") {
+			continue
+		}
+		if !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// supportedEntityLinks returns the entity links of msg that match a supported hoster
+func (b *Bot) supportedEntityLinks(msg *models.Message) []string {
+	var links []string
+	for _, link := range entityLinks(msg) {
+		if b.isSupportedLink(link) {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// matchEntityLink matches text messages with a supported hoster link anywhere in them,
+// unless telegram.entity_links is disabled. Commands and messages starting with a link
+// are left to their own handlers. Without the supported hosters every link would match,
+// so nothing does.
+func (b *Bot) matchEntityLink(update *models.Update) bool {
+	if !b.config.Telegram.EntityLinks || update.Message == nil || len(b.supportedRegex) == 0 {
+		return false
+	}
+	text := update.Message.Text
+	if strings.HasPrefix(text, "/") || strings.HasPrefix(text, "http://") || strings.HasPrefix(text, "https://") || strings.Contains(text, "magnet:?") {
+		return false
+	}
+	return len(b.supportedEntityLinks(update.Message)) > 0
+}
+
+// handleEntityLink runs the hoster link flow for the links found in a message's
+// entities; several links are unrestricted as a bulk
+func (b *Bot) handleEntityLink(ctx context.Context, api *bot.Bot, update *models.Update) {
+	links := b.supportedEntityLinks(update.Message)
+	if len(links) == 0 {
+		return
+	}
+
+	withText := *update
+	withText.Message = withLinkText(update.Message, strings.Join(links, "\n"))
+	b.handleHosterLink(ctx, api, &withText)
+}
@@ -0,0 +1,109 @@
+package bot
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/config"
+	"github.com/go-telegram/bot/models"
+)
+
+// TestEntityLinks verifies url and text_link entities yield their links from anywhere in
+// the text, with offsets counted in UTF-16 code units.
+func TestEntityLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *models.Message
+		want []string
+	}{
+		{
+			"mid-text url",
+			&models.Message{Text: "grab this https://host.example/f/1 please", Entities: []models.MessageEntity{
+				{Type: models.MessageEntityTypeURL, Offset: 10, Length: 24},
+			}},
+			[]string{"https://host.example/f/1"},
+		},
+		{
+			"after emoji",
+			// 🎬 is two UTF-16 code units, so the link starts at offset 10 rather than 9
+			&models.Message{Text: "🎬 movie: https://host.example/f/2", Entities: []models.MessageEntity{
+				{Type: models.MessageEntityTypeURL, Offset: 10, Length: 24},
+			}},
+			[]string{"https://host.example/f/2"},
+		},
+		{
+			"text link and url",
+			&models.Message{Text: "part one and http://host.example/f/4", Entities: []models.MessageEntity{
+				{Type: models.MessageEntityTypeBold, Offset: 0, Length: 4},
+				{Type: models.MessageEntityTypeTextLink, Offset: 0, Length: 8, URL: "https://host.example/f/3"},
+				{Type: models.MessageEntityTypeURL, Offset: 13, Length: 23},
+			}},
+			[]string{"https://host.example/f/3", "http://host.example/f/4"},
+		},
+		{
+			"duplicates and schemeless",
+			&models.Message{Text: "host.example/f/5 https://host.example/f/6 https://host.example/f/6", Entities: []models.MessageEntity{
+				{Type: models.MessageEntityTypeURL, Offset: 0, Length: 16},
+				{Type: models.MessageEntityTypeURL, Offset: 17, Length: 24},
+				{Type: models.MessageEntityTypeURL, Offset: 42, Length: 24},
+			}},
+			[]string{"https://host.example/f/6"},
+		},
+		{
+			"out of range",
+			&models.Message{Text: "short", Entities: []models.MessageEntity{{Type: models.MessageEntityTypeURL, Offset: 2, Length: 40}}},
+			nil,
+		},
+		{"no entities", &models.Message{Text: "see https://host.example/f/7"}, nil},
+		{"nil", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := entityLinks(tt.msg); !slices.Equal(got, tt.want) {
+				t.Errorf("entityLinks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMatchEntityLink verifies only supported links in messages not already handled
+// elsewhere are matched, and only with telegram.entity_links on.
+func TestMatchEntityLink(t *testing.T) {
+	message := func(prefix, link string) *models.Update {
+		return &models.Update{Message: &models.Message{Text: prefix + link, Entities: []models.MessageEntity{
+			{Type: models.MessageEntityTypeURL, Offset: len(prefix), Length: len(link)},
+		}}}
+	}
+
+	b := &Bot{config: &config.Config{}, supportedRegex: []*regexp.Regexp{regexp.MustCompile(`^https://host\.example/`)}}
+	if b.matchEntityLink(message("look at ", "https://host.example/f/1")) {
+		t.Error("matched an entity link with entity_links disabled")
+	}
+
+	b.config.Telegram.EntityLinks = true
+	tests := []struct {
+		prefix string
+		link   string
+		want   bool
+	}{
+		{"look at ", "https://host.example/f/1", true},
+		{"look at ", "https://other.example/f/1", false},
+		{"https://host.example/f/0 ", "https://host.example/f/1", false},
+		{"/unrestrict ", "https://host.example/f/1", false},
+		{"magnet:?xt=urn:btih:abc ", "https://host.example/f/1", false},
+	}
+	for _, tt := range tests {
+		if got := b.matchEntityLink(message(tt.prefix, tt.link)); got != tt.want {
+			t.Errorf("matchEntityLink(%q) = %v, want %v", tt.prefix+tt.link, got, tt.want)
+		}
+	}
+	if b.matchEntityLink(&models.Update{}) {
+		t.Error("matched an update without a message")
+	}
+
+	b.supportedRegex = nil
+	if b.matchEntityLink(message("look at ", "https://other.example/f/1")) {
+		t.Error("matched an entity link without supported hosters loaded")
+	}
+}
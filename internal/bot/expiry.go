@@ -0,0 +1,84 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// expiryWarningThreshold is the remaining premium time below which /expiry warns
+const expiryWarningThreshold = 7 * 24 * time.Hour
+
+// formatCountdown renders d in days, hours and minutes, e.g. "3 days, 4 hours, 12 minutes",
+// leaving out zero units. Seconds are dropped.
+func formatCountdown(d time.Duration) string {
+	if d < time.Minute {
+		return "less than a minute"
+	}
+	units := []struct {
+		n    int
+		name string
+	}{
+		{int(d / (24 * time.Hour)), "day"},
+		{int(d/time.Hour) % 24, "hour"},
+		{int(d/time.Minute) % 60, "minute"},
+	}
+	var parts []string
+	for _, u := range units {
+		switch {
+		case u.n == 1:
+			parts = append(parts, "1 "+u.name)
+		case u.n > 1:
+			parts = append(parts, fmt.Sprintf("%d %ss", u.n, u.name))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatExpiry renders the /expiry reply for rdUser, showing the expiry time with
+// displayTime
+func formatExpiry(rdUser *realdebrid.User, displayTime func(time.Time) string) string {
+	remaining := rdUser.GetPremiumDuration()
+	if remaining <= 0 {
+		return "🔴 <b>Premium Expired</b>\n\nThe Real-Debrid account has no premium time left."
+	}
+
+	var text strings.Builder
+	if remaining < expiryWarningThreshold {
+		text.WriteString("⚠️ <b>Premium Expires Soon</b>\n\n")
+	} else {
+		text.WriteString("✅ <b>Premium Active</b>\n\n")
+	}
+	fmt.Fprintf(&text, "<i>Remaining:</i> %s\n", formatCountdown(remaining))
+	if expTime, err := rdUser.GetExpirationTime(); err == nil && !expTime.IsZero() {
+		fmt.Fprintf(&text, "<i>Expires On:</i> %s\n", html.EscapeString(displayTime(expTime)))
+	}
+	return text.String()
+}
+
+// handleExpiryCommand handles the /expiry command, a countdown to the end of the
+// account's premium time
+func (b *Bot) handleExpiryCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "expiry")
+
+		rdUser, err := b.getRDUser()
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Could not retrieve account status: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "expiry", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := formatExpiry(rdUser, b.displayTime)
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "expiry", update.Message.Text, startTime, true, "", len(text))
+	})
+}
@@ -0,0 +1,63 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// TestFormatCountdown verifies every unit is shown, singular or plural, and zero units are left out.
+func TestFormatCountdown(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "less than a minute"},
+		{59 * time.Second, "less than a minute"},
+		{time.Minute, "1 minute"},
+		{90 * time.Second, "1 minute"},
+		{45 * time.Minute, "45 minutes"},
+		{time.Hour, "1 hour"},
+		{2*time.Hour + time.Minute, "2 hours, 1 minute"},
+		{day, "1 day"},
+		{day + 30*time.Minute, "1 day, 30 minutes"},
+		{3*day + 4*time.Hour + 12*time.Minute, "3 days, 4 hours, 12 minutes"},
+		{400 * day, "400 days"},
+	}
+	for _, tt := range tests {
+		if got := formatCountdown(tt.d); got != tt.want {
+			t.Errorf("formatCountdown(%s) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+// TestFormatExpiry verifies the header changes under a week and once premium has run out.
+func TestFormatExpiry(t *testing.T) {
+	display := func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04 MST") }
+	tests := []struct {
+		name    string
+		user    realdebrid.User
+		want    []string
+		notWant string
+	}{
+		{"active", realdebrid.User{Premium: 30 * 86400, Expiration: "2026-11-14T10:00:00.000Z"}, []string{"✅", "30 days", "2026-11-14 10:00 UTC"}, "⚠️"},
+		{"under a week", realdebrid.User{Premium: 2*86400 + 3600}, []string{"⚠️", "2 days, 1 hour"}, "Expires On"},
+		{"expired", realdebrid.User{}, []string{"🔴", "no premium time"}, "Remaining"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatExpiry(&tt.user, display)
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("missing %q in %q", w, got)
+				}
+			}
+			if strings.Contains(got, tt.notWant) {
+				t.Errorf("unexpected %q in %q", tt.notWant, got)
+			}
+		})
+	}
+}
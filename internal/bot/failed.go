@@ -0,0 +1,123 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// maxFailedFilesListed caps how many missing files /failed names
+const maxFailedFilesListed = 20
+
+// fileCorrelation compares the selected files of a torrent with the links RD produced
+type fileCorrelation struct {
+	selected []realdebrid.File
+	links    int
+	archived bool              // several files were packed into a single link
+	missing  []realdebrid.File // selected files left without a link
+}
+
+// complete reports whether every selected file is accounted for by a link
+func (c fileCorrelation) complete() bool {
+	return len(c.missing) == 0
+}
+
+// correlateFiles infers which selected files of t failed to download. RD has no per-file
+// status, but it produces one link per selected file, in file order, or a single link when
+// it packs a multi-file torrent into an archive. With fewer links than that, the files
+// past the last link are taken to be the missing ones; the count is exact, the names a
+// best guess.
+func correlateFiles(t *realdebrid.Torrent) fileCorrelation {
+	var c fileCorrelation
+	for _, f := range t.Files {
+		if f.Selected == 1 {
+			c.selected = append(c.selected, f)
+		}
+	}
+	for _, l := range t.Links {
+		if l != "" {
+			c.links++
+		}
+	}
+
+	switch {
+	case c.links >= len(c.selected):
+	case c.links == 1 && t.Status == "downloaded":
+		c.archived = true
+	default:
+		c.missing = c.selected[c.links:]
+	}
+	return c
+}
+
+// formatFailedFiles renders the /failed reply
+func formatFailedFiles(t *realdebrid.Torrent, c fileCorrelation, maxName int) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "<b>File Check for</b> <code>%s</code>\n\n", html.EscapeString(truncateName(t.Filename, maxName)))
+	fmt.Fprintf(&text, "<i>Status:</i> %s\n", realdebrid.FormatStatus(t.Status))
+	fmt.Fprintf(&text, "<i>Selected files:</i> %d\n", len(c.selected))
+	fmt.Fprintf(&text, "<i>Links:</i> %d\n\n", c.links)
+
+	switch {
+	case len(c.selected) == 0:
+		text.WriteString("No files are selected yet.")
+		return text.String()
+	case c.archived:
+		text.WriteString("✅ All files were packed into a single archive link.")
+		return text.String()
+	case c.complete():
+		text.WriteString("✅ Every selected file has a link.")
+		return text.String()
+	}
+
+	if t.Status != "downloaded" && classifySubscriptionStatus(t.Status) != subscriptionFailed {
+		fmt.Fprintf(&text, "The torrent is not finished yet (%.0f%%), so %d file(s) have no link so far.", t.Progress, len(c.missing))
+		return text.String()
+	}
+
+	fmt.Fprintf(&text, "⚠️ <b>%d file(s) have no link.</b> RD does not report which ones failed; these are the likely ones:\n", len(c.missing))
+	for i, f := range c.missing {
+		if i == maxFailedFilesListed {
+			fmt.Fprintf(&text, "… and %d more\n", len(c.missing)-maxFailedFilesListed)
+			break
+		}
+		fmt.Fprintf(&text, "• <code>%s</code> (%s)\n", html.EscapeString(truncateName(strings.TrimPrefix(f.Path, "/"), maxName)), realdebrid.FormatSize(f.Bytes))
+	}
+	fmt.Fprintf(&text, "\nUse <code>/copy %s</code> to add the torrent again.", html.EscapeString(t.ID))
+	return text.String()
+}
+
+// handleFailedCommand handles the /failed command. It reports the selected files of a
+// torrent that ended up without a download link, so users know whether they got everything.
+func (b *Bot) handleFailedCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "failed")
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) < 2 {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /failed &lt;torrent_id&gt;", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "failed", update.Message.Text, startTime, false, "Missing arguments", 0)
+			return
+		}
+		torrentID := parts[1]
+
+		torrent, err := b.rdClient.GetTorrentInfo(torrentID)
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Could not retrieve torrent info: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "failed", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := formatFailedFiles(torrent, correlateFiles(torrent), b.config.App.MaxFilenameDisplay)
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "failed", update.Message.Text, startTime, true, "", len(text))
+	})
+}
@@ -0,0 +1,76 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// filesTorrent builds a torrent with n selected files, one unselected file and the given links
+func filesTorrent(status string, n int, links ...string) *realdebrid.Torrent {
+	t := &realdebrid.Torrent{ID: "ABC", Filename: "Pack", Status: status, Links: links}
+	t.Files = append(t.Files, realdebrid.File{ID: 1, Path: "/sample.txt", Selected: 0})
+	for i := 0; i < n; i++ {
+		t.Files = append(t.Files, realdebrid.File{ID: i + 2, Path: "/ep" + string(rune('1'+i)) + ".mkv", Bytes: 1024, Selected: 1})
+	}
+	return t
+}
+
+// TestCorrelateFiles verifies the complete, archived and partial cases.
+func TestCorrelateFiles(t *testing.T) {
+	tests := []struct {
+		name         string
+		torrent      *realdebrid.Torrent
+		wantArchived bool
+		wantMissing  []string
+	}{
+		{"complete", filesTorrent("downloaded", 3, "a", "b", "c"), false, nil},
+		{"single file", filesTorrent("downloaded", 1, "a"), false, nil},
+		{"archived", filesTorrent("downloaded", 3, "a"), true, nil},
+		{"partial", filesTorrent("downloaded", 4, "a", "b"), false, []string{"/ep3.mkv", "/ep4.mkv"}},
+		{"empty links ignored", filesTorrent("error", 2, "a", ""), false, []string{"/ep2.mkv"}},
+		{"no links", filesTorrent("error", 2), false, []string{"/ep1.mkv", "/ep2.mkv"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := correlateFiles(tt.torrent)
+			if c.archived != tt.wantArchived {
+				t.Errorf("archived = %v, want %v", c.archived, tt.wantArchived)
+			}
+			var missing []string
+			for _, f := range c.missing {
+				missing = append(missing, f.Path)
+			}
+			if strings.Join(missing, ",") != strings.Join(tt.wantMissing, ",") {
+				t.Errorf("missing = %v, want %v", missing, tt.wantMissing)
+			}
+			if c.complete() != (len(tt.wantMissing) == 0) {
+				t.Errorf("complete() = %v", c.complete())
+			}
+		})
+	}
+}
+
+// TestFormatFailedFiles verifies the complete case is short and missing files are named.
+func TestFormatFailedFiles(t *testing.T) {
+	complete := filesTorrent("downloaded", 2, "a", "b")
+	if text := formatFailedFiles(complete, correlateFiles(complete), 50); !strings.Contains(text, "Every selected file has a link") {
+		t.Errorf("complete reply = %s", text)
+	}
+
+	partial := filesTorrent("downloaded", 3, "a")
+	partial.Links = []string{"a", "b"}
+	text := formatFailedFiles(partial, correlateFiles(partial), 50)
+	for _, want := range []string{"1 file(s) have no link", "<code>ep3.mkv</code>", "/copy ABC"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("partial reply missing %q:\n%s", want, text)
+		}
+	}
+
+	running := filesTorrent("downloading", 2)
+	running.Progress = 40
+	if text := formatFailedFiles(running, correlateFiles(running), 50); !strings.Contains(text, "not finished yet (40%)") {
+		t.Errorf("running reply = %s", text)
+	}
+}
@@ -0,0 +1,134 @@
+package bot
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgproto3"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Postgres type OIDs of the columns the fake database returns
+const (
+	oidBool        = 16
+	oidInt8        = 20
+	oidText        = 25
+	oidTimestamptz = 1184
+)
+
+// newFakePostgres returns a pool connected to a minimal Postgres server speaking the
+// simple query protocol. Upserts into chats and users return a row with ID 1 and
+// every other statement succeeds without rows. The second return value lists the
+// statements received so far.
+func newFakePostgres(t *testing.T) (*pgxpool.Pool, func() []string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	var mu sync.Mutex
+	var queries []string
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakePostgres(conn, func(sql string) {
+				mu.Lock()
+				queries = append(queries, sql)
+				mu.Unlock()
+			})
+		}
+	}()
+
+	cfg, err := pgxpool.ParseConfig("postgres://test@" + ln.Addr().String() + "/test?sslmode=disable")
+	if err != nil {
+		t.Fatalf("pgxpool.ParseConfig() error = %v", err)
+	}
+	cfg.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("pgxpool.NewWithConfig() error = %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	return pool, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), queries...)
+	}
+}
+
+// serveFakePostgres answers one client connection until it terminates
+func serveFakePostgres(conn net.Conn, record func(string)) {
+	defer conn.Close()
+	backend := pgproto3.NewBackend(conn, conn)
+	if _, err := backend.ReceiveStartupMessage(); err != nil {
+		return
+	}
+	backend.Send(&pgproto3.AuthenticationOk{})
+	backend.Send(&pgproto3.ParameterStatus{Name: "client_encoding", Value: "UTF8"})
+	backend.Send(&pgproto3.ParameterStatus{Name: "standard_conforming_strings", Value: "on"})
+	backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+	if err := backend.Flush(); err != nil {
+		return
+	}
+
+	for {
+		msg, err := backend.Receive()
+		if err != nil {
+			return
+		}
+		query, ok := msg.(*pgproto3.Query)
+		if !ok {
+			return
+		}
+		record(query.String)
+		switch {
+		case strings.Contains(query.String, "INSERT INTO chats"):
+			sendFakeRow(backend, db.Chats{})
+		case strings.Contains(query.String, "INSERT INTO users"):
+			sendFakeRow(backend, db.Users{})
+		default:
+			backend.Send(&pgproto3.CommandComplete{CommandTag: []byte("INSERT 0 1")})
+		}
+		backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+		if err := backend.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// sendFakeRow sends one row shaped like the model struct row: int64 columns are 1,
+// bools false and everything else NULL
+func sendFakeRow(backend *pgproto3.Backend, row any) {
+	typ := reflect.TypeOf(row)
+	var fields []pgproto3.FieldDescription
+	var values [][]byte
+	for i := range typ.NumField() {
+		f := typ.Field(i)
+		oid, value := uint32(oidText), []byte(nil)
+		switch f.Type.Kind() {
+		case reflect.Int64:
+			oid, value = oidInt8, []byte("1")
+		case reflect.Bool:
+			oid, value = oidBool, []byte("f")
+		case reflect.Struct:
+			oid = oidTimestamptz
+		}
+		fields = append(fields, pgproto3.FieldDescription{Name: []byte(f.Tag.Get("json")), DataTypeOID: oid, DataTypeSize: -1, TypeModifier: -1})
+		values = append(values, value)
+	}
+	backend.Send(&pgproto3.RowDescription{Fields: fields})
+	backend.Send(&pgproto3.DataRow{Values: values})
+	backend.Send(&pgproto3.CommandComplete{CommandTag: []byte("INSERT 0 1")})
+}
@@ -0,0 +1,158 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// maxFetchBytes is the largest file a bot may upload to Telegram
+	maxFetchBytes = 50 << 20
+
+	// fetchTimeout bounds downloading a file for /fetch
+	fetchTimeout = 5 * time.Minute
+)
+
+// checkFetchSize rejects files /fetch cannot upload. Real-Debrid reports the size on
+// unrestrict, so oversized files are refused before anything is downloaded.
+func checkFetchSize(size int64) error {
+	if size <= 0 {
+		return fmt.Errorf("the file size is unknown, so it cannot be sent (limit %s)", realdebrid.FormatSize(maxFetchBytes))
+	}
+	if size > maxFetchBytes {
+		return fmt.Errorf("the file is %s, over the %s Telegram upload limit", realdebrid.FormatSize(size), realdebrid.FormatSize(maxFetchBytes))
+	}
+	return nil
+}
+
+// newFetchClient returns the HTTP client used to download files, routed through the
+// configured proxy like the rest of the Real-Debrid traffic. NewBot builds it once
+// so every /fetch shares its connection pool.
+func newFetchClient(proxyURL string) (*http.Client, error) {
+	transport := &http.Transport{IdleConnTimeout: 90 * time.Second}
+	if proxyURL != "" {
+		parsed, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(parsed)
+	}
+	return &http.Client{Transport: transport, Timeout: fetchTimeout}, nil
+}
+
+// fetchToTemp downloads link into a temporary file, failing if it is larger than
+// maxBytes whatever the server announced. The caller must close and remove the file.
+func fetchToTemp(ctx context.Context, client *http.Client, link string, maxBytes int64) (*os.File, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download failed: HTTP %d", resp.StatusCode)
+	}
+
+	file, err := os.CreateTemp("", "rdctl-fetch-*")
+	if err != nil {
+		return nil, err
+	}
+	n, err := io.Copy(file, io.LimitReader(resp.Body, maxBytes+1))
+	if err == nil && n > maxBytes {
+		err = fmt.Errorf("download exceeds the %s limit", realdebrid.FormatSize(maxBytes))
+	}
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+	return file, nil
+}
+
+// handleFetchCommand handles the /fetch command. It unrestricts a link and, if the
+// file is small enough, uploads it to the chat as a document.
+func (b *Bot) handleFetchCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "fetch")
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) < 2 {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /fetch &lt;link&gt;", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "fetch", update.Message.Text, startTime, false, "Missing arguments", 0)
+			return
+		}
+		link := parts[1]
+		if b.rejectLongInput(ctx, update, user, chatID, chatPK, messageThreadID, "fetch", link, startTime) {
+			return
+		}
+		if b.rejectBlockedHost(ctx, update, user, chatID, chatPK, messageThreadID, "fetch", link, startTime) {
+			return
+		}
+
+		fail := func(text, errMsg string) {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "fetch", update.Message.Text, startTime, false, errMsg, 0)
+		}
+
+		unrestricted, err := b.rdClient.UnrestrictLink(link)
+		if err != nil {
+			fail(fmt.Sprintf("<b>[ERROR]</b> Failed to unrestrict link: %s", html.EscapeString(err.Error())), err.Error())
+			return
+		}
+		if err := checkFetchSize(unrestricted.Filesize); err != nil {
+			fail(fmt.Sprintf("<b>[ERROR]</b> Cannot send <code>%s</code>: %s. Use /unrestrict to get a download link instead.", html.EscapeString(unrestricted.Filename), html.EscapeString(err.Error())), err.Error())
+			return
+		}
+
+		file, err := fetchToTemp(ctx, b.fetchClient, unrestricted.Download, maxFetchBytes)
+		if err != nil {
+			fail(fmt.Sprintf("<b>[ERROR]</b> Failed to download <code>%s</code>: %s", html.EscapeString(unrestricted.Filename), html.EscapeString(err.Error())), err.Error())
+			return
+		}
+		defer os.Remove(file.Name())
+		defer file.Close()
+
+		params := &bot.SendDocumentParams{
+			ChatID:          chatID,
+			MessageThreadID: messageThreadID,
+			Document:        &models.InputFileUpload{Filename: unrestricted.Filename, Data: file},
+			ReplyParameters: &models.ReplyParameters{MessageID: update.Message.ID},
+		}
+		err = b.middleware.WaitForRateLimitWithContext(ctx)
+		if err == nil {
+			_, err = b.api.SendDocument(ctx, params)
+		}
+		if err != nil {
+			log.Printf("Error sending fetched file %s: %v", unrestricted.Filename, err)
+			fail(fmt.Sprintf("<b>[ERROR]</b> Failed to upload <code>%s</code>: %s", html.EscapeString(unrestricted.Filename), html.EscapeString(err.Error())), err.Error())
+			return
+		}
+
+		if user != nil {
+			if err := b.downloadRepo.LogDownloadActivity(ctx, "", user.ID, chatPK, unrestricted.ID, link, unrestricted.Filename, unrestricted.Host, "fetch", unrestricted.Filesize, true, "", nil, nil); err != nil {
+				log.Printf("Warning: failed to log fetched download: %v", err)
+			}
+		}
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "fetch", update.Message.Text, startTime, true, "", 0)
+	})
+}
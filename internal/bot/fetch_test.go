@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestCheckFetchSize verifies only files with a known size within the upload limit are accepted.
+func TestCheckFetchSize(t *testing.T) {
+	tests := []struct {
+		size    int64
+		wantErr bool
+	}{
+		{1024, false},
+		{maxFetchBytes, false},
+		{maxFetchBytes + 1, true},
+		{0, true},
+	}
+	for _, tt := range tests {
+		if err := checkFetchSize(tt.size); (err != nil) != tt.wantErr {
+			t.Errorf("checkFetchSize(%d) error = %v, wantErr %v", tt.size, err, tt.wantErr)
+		}
+	}
+}
+
+// TestFetchToTemp verifies the download lands in a temp file and oversized bodies are refused and cleaned up.
+func TestFetchToTemp(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	body := strings.Repeat("x", 100)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, body)
+	}))
+	defer srv.Close()
+
+	file, err := fetchToTemp(context.Background(), srv.Client(), srv.URL, 100)
+	if err != nil {
+		t.Fatalf("fetchToTemp() error = %v", err)
+	}
+	got, _ := io.ReadAll(file)
+	file.Close()
+	os.Remove(file.Name())
+	if string(got) != body {
+		t.Errorf("fetched %d bytes, want %d", len(got), len(body))
+	}
+
+	if _, err := fetchToTemp(context.Background(), srv.Client(), srv.URL, 99); err == nil {
+		t.Error("fetchToTemp() over the limit succeeded, want error")
+	}
+	if left, _ := os.ReadDir(tmp); len(left) != 0 {
+		t.Errorf("fetchToTemp() left %d temp files behind", len(left))
+	}
+}
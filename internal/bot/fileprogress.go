@@ -0,0 +1,120 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// fileProgressMaxFiles bounds how many files /fileprogress lists in one message
+const fileProgressMaxFiles = 40
+
+// fileReadiness is the download state of one selected file in a torrent
+type fileReadiness struct {
+	Path  string
+	Bytes int64
+	Ready bool
+}
+
+// selectedFileReadiness correlates a torrent's selected files with its links.
+// RD returns one link per selected file, in file order, once the file is available,
+// so a file is ready when the link at its position is non-empty. When the links
+// don't line up with the files (e.g. RD packed them into an archive) every file is
+// reported ready only once the torrent itself is downloaded. The second return value
+// reports whether the links could be matched file by file.
+func selectedFileReadiness(t *realdebrid.Torrent) ([]fileReadiness, bool) {
+	var files []fileReadiness
+	for _, f := range t.Files {
+		if f.Selected == 1 {
+			files = append(files, fileReadiness{Path: f.Path, Bytes: f.Bytes})
+		}
+	}
+
+	if len(t.Links) == len(files) {
+		for i := range files {
+			files[i].Ready = t.Links[i] != ""
+		}
+		return files, true
+	}
+
+	done := t.Status == "downloaded"
+	for i := range files {
+		files[i].Ready = done
+	}
+	return files, len(t.Links) == 0
+}
+
+// formatFileProgress renders the per-file view for /fileprogress
+func formatFileProgress(t *realdebrid.Torrent, maxNameLen int) string {
+	files, matched := selectedFileReadiness(t)
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "<b>📂 File Progress</b>\n\n<i>Name:</i> <code>%s</code>\n", html.EscapeString(truncateName(t.Filename, maxNameLen)))
+	fmt.Fprintf(&text, "<i>Status:</i> %s (%.1f%%)\n\n", realdebrid.FormatStatus(t.Status), t.Progress)
+
+	if len(files) == 0 {
+		text.WriteString("No files are selected for this torrent yet.")
+		return text.String()
+	}
+
+	ready := 0
+	for i, f := range files {
+		if f.Ready {
+			ready++
+		}
+		if i >= fileProgressMaxFiles {
+			continue
+		}
+		mark := "⏳"
+		if f.Ready {
+			mark = "✅"
+		}
+		name := strings.TrimPrefix(f.Path, "/")
+		fmt.Fprintf(&text, "%s <code>%s</code> — %s\n", mark, html.EscapeString(truncateName(name, maxNameLen)), realdebrid.FormatSize(f.Bytes))
+	}
+	if len(files) > fileProgressMaxFiles {
+		fmt.Fprintf(&text, "<i>…and %d more</i>\n", len(files)-fileProgressMaxFiles)
+	}
+
+	fmt.Fprintf(&text, "\n<i>Ready:</i> %d / %d files", ready, len(files))
+	switch {
+	case !matched:
+		text.WriteString("\n<i>Files are packed into an archive and become available together.</i>")
+	case ready == 0 && t.Status != "downloaded":
+		text.WriteString("\n<i>Real-Debrid usually publishes links once the whole torrent completes.</i>")
+	}
+	return text.String()
+}
+
+// handleFileProgressCommand handles the /fileprogress command
+func (b *Bot) handleFileProgressCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "fileprogress")
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) < 2 {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /fileprogress &lt;torrent_id&gt;", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "fileprogress", update.Message.Text, startTime, false, "Missing arguments", 0)
+			return
+		}
+
+		torrent, err := b.rdClient.GetTorrentInfo(parts[1])
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Could not retrieve torrent info: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "fileprogress", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := formatFileProgress(torrent, b.config.App.MaxFilenameDisplay)
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "fileprogress", update.Message.Text, startTime, true, "", len(text))
+	})
+}
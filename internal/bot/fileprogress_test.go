@@ -0,0 +1,79 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// multiFileTorrent builds a torrent with three selected files and one unselected file.
+func multiFileTorrent(status string, links []string) *realdebrid.Torrent {
+	return &realdebrid.Torrent{
+		Filename: "Season 1",
+		Status:   status,
+		Progress: 60,
+		Files: []realdebrid.File{
+			{ID: 1, Path: "/Season 1/E01.mkv", Bytes: 1 << 30, Selected: 1},
+			{ID: 2, Path: "/Season 1/sample.mkv", Bytes: 1 << 20, Selected: 0},
+			{ID: 3, Path: "/Season 1/E02.mkv", Bytes: 1 << 30, Selected: 1},
+			{ID: 4, Path: "/Season 1/E03.mkv", Bytes: 1 << 30, Selected: 1},
+		},
+		Links: links,
+	}
+}
+
+// TestSelectedFileReadiness covers matched links, links not yet published, and archived torrents.
+func TestSelectedFileReadiness(t *testing.T) {
+	tests := []struct {
+		name        string
+		torrent     *realdebrid.Torrent
+		wantReady   []bool
+		wantMatched bool
+	}{
+		{"links published per file", multiFileTorrent("downloading", []string{"l1", "", "l3"}), []bool{true, false, true}, true},
+		{"no links while downloading", multiFileTorrent("downloading", nil), []bool{false, false, false}, true},
+		{"no links after completion", multiFileTorrent("downloaded", nil), []bool{true, true, true}, true},
+		{"single archive link", multiFileTorrent("downloaded", []string{"rar"}), []bool{true, true, true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, matched := selectedFileReadiness(tt.torrent)
+			if matched != tt.wantMatched {
+				t.Errorf("matched = %v, want %v", matched, tt.wantMatched)
+			}
+			if len(files) != len(tt.wantReady) {
+				t.Fatalf("got %d files, want %d (unselected files must be skipped)", len(files), len(tt.wantReady))
+			}
+			for i, f := range files {
+				if f.Ready != tt.wantReady[i] {
+					t.Errorf("file %d (%s) ready = %v, want %v", i, f.Path, f.Ready, tt.wantReady[i])
+				}
+			}
+		})
+	}
+}
+
+// TestFormatFileProgress verifies ready counts, file names and the pending-links hint.
+func TestFormatFileProgress(t *testing.T) {
+	partial := formatFileProgress(multiFileTorrent("downloading", []string{"l1", "", ""}), 80)
+	for _, want := range []string{"✅ <code>Season 1/E01.mkv</code>", "⏳ <code>Season 1/E02.mkv</code>", "1.00 GB", "Ready:</i> 1 / 3"} {
+		if !strings.Contains(partial, want) {
+			t.Errorf("formatFileProgress() missing %q in %q", want, partial)
+		}
+	}
+	if strings.Contains(partial, "sample.mkv") {
+		t.Error("formatFileProgress() lists an unselected file")
+	}
+
+	waiting := formatFileProgress(multiFileTorrent("downloading", nil), 80)
+	if !strings.Contains(waiting, "once the whole torrent completes") {
+		t.Errorf("formatFileProgress() without links = %q, want completion hint", waiting)
+	}
+
+	empty := formatFileProgress(&realdebrid.Torrent{Filename: "x", Status: "waiting_files_selection"}, 80)
+	if !strings.Contains(empty, "No files are selected") {
+		t.Errorf("formatFileProgress() with no files = %q", empty)
+	}
+}
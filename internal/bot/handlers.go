@@ -1,19 +1,24 @@
 package bot
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"html"
 	"log"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 
+	"github.com/crazyuploader/rdctl-bot/internal/audit"
 	"github.com/crazyuploader/rdctl-bot/internal/db"
 	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/crazyuploader/rdctl-bot/internal/web"
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
 )
@@ -22,17 +27,11 @@ var magnetRegex = regexp.MustCompile(`magnet:\?xt=urn:btih:[a-zA-Z0-9]+.*`)
 
 // handleStartCommand handles the /start command
 func (b *Bot) handleStartCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
-	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, isSuperAdmin bool, user *db.User) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
 		startTime := time.Now()
 		b.middleware.LogCommand(update, "start")
 
-		text := fmt.Sprintf(
-			"<b>Welcome to the Real-Debrid Telegram Bot</b>\n\n"+
-				"This bot helps you manage your Real-Debrid torrents and hoster links.\n\n"+
-				"Your Chat ID is: <code>%d</code>\n\n"+
-				"Use /help to see a list of all available commands.",
-			chatID,
-		)
+		text := b.localize(chatID, "start.welcome", chatID)
 
 		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
 
@@ -44,29 +43,11 @@ func (b *Bot) handleStartCommand(ctx context.Context, _ *bot.Bot, update *models
 
 // handleHelpCommand handles the /help command
 func (b *Bot) handleHelpCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
-	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, isSuperAdmin bool, user *db.User) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
 		startTime := time.Now()
 		b.middleware.LogCommand(update, "help")
 
-		text := "<b>🧭 Available Commands</b>\n\n" +
-			"<b>🎬 Torrent Management:</b>\n" +
-			"• <code>/list</code> — List all active torrents\n" +
-			"• <code>/add &lt;magnet&gt;</code> — Add a new torrent via magnet link\n" +
-			"• <code>/info &lt;id&gt;</code> — Get detailed information about a torrent\n" +
-			"• <code>/delete &lt;id&gt;</code> — Delete a torrent <i>(superadmin only)</i>\n\n" +
-			"<b>📦 Hoster Link Management:</b>\n" +
-			"• <code>/unrestrict &lt;link&gt;</code> — Unrestrict a hoster link\n" +
-			"• <code>/downloads</code> — List recent downloads\n" +
-			"• <code>/removelink &lt;id&gt;</code> — Remove a download from history <i>(superadmin only)</i>\n\n" +
-			"<b>🔒 Keep Management:</b>\n" +
-			"• <code>/keep &lt;id&gt;</code> — Mark a torrent as kept (excluded from auto-delete)\n" +
-			"• <code>/unkeep &lt;id&gt;</code> — Remove keep mark from a torrent\n\n" +
-			"<b>⚙️ General Commands:</b>\n" +
-			"• <code>/status</code> — Show your Real-Debrid account status\n" +
-			"• <code>/stats</code> — Show torrent/download counts and combined size\n" +
-			"• <code>/dashboard</code> — Get a temporary link to the web dashboard\n" +
-			"• <code>/autodelete &lt;days&gt;</code> — Auto-delete torrents older than X days <i>(superadmin only)</i>\n" +
-			"• <code>/help</code> — Display this help message"
+		text := renderHelp(b.localeFor(chatID))
 
 		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
 
@@ -78,123 +59,84 @@ func (b *Bot) handleHelpCommand(ctx context.Context, _ *bot.Bot, update *models.
 
 // handleListCommand handles the /list command
 func (b *Bot) handleListCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
-	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, isSuperAdmin bool, user *db.User) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
 		startTime := time.Now()
 		b.middleware.LogCommand(update, "list")
 
-		torrents, err := b.rdClient.GetTorrents(10, 0)
+		reply := b.ackProcessing(ctx, chatID, messageThreadID, update.Message.ID, "list")
+		page, err := b.buildListPage(ctx, 0)
 		if err != nil {
 			text := fmt.Sprintf("<b>[ERROR]</b> Failed to retrieve torrents: %s", html.EscapeString(err.Error()))
-			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+			reply.finish(ctx, text)
 			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "list", update.Message.Text, startTime, false, err.Error(), 0)
 			b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeTorrentList, "list", false, err.Error(), nil)
 			return
 		}
 
-		if len(torrents) == 0 {
-			b.sendHTMLMessage(ctx, chatID, messageThreadID, "No torrents found.", update.Message.ID)
-			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "list", update.Message.Text, startTime, true, "", 0)
-			b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeTorrentList, "list", true, "", map[string]any{"torrent_count": 0})
-			return
-		}
-
-		var text strings.Builder
-		text.WriteString("<b>Your Recent Torrents</b>\n\n")
-
-		maxTorrents := min(len(torrents), 10)
-		const maxMsgLen = 4000
-		torrentsShown := 0
-		hitLengthLimit := false
-
-		for i := range maxTorrents {
-			t := torrents[i]
-			entry := strings.Builder{}
-			status := realdebrid.FormatStatus(t.Status)
-			size := realdebrid.FormatSize(t.Bytes)
-			progress := fmt.Sprintf("%.1f%%", t.Progress)
-			added := t.Added.Format("2006-01-02 15:04")
-
-			fmt.Fprintf(&entry, "<i>File:</i> <code>%s</code>\n", html.EscapeString(t.Filename))
-			fmt.Fprintf(&entry, "<i>ID:</i> <code>%s</code>\n", t.ID)
-			fmt.Fprintf(&entry, "<i>Status:</i> %s\n", status)
-			fmt.Fprintf(&entry, "<i>Size:</i> %s\n", size)
-			fmt.Fprintf(&entry, "<i>Progress:</i> %s\n", progress)
-			fmt.Fprintf(&entry, "<i>Added:</i> %s\n", added)
-
-			if t.Speed > 0 {
-				speed := realdebrid.FormatSize(t.Speed) + "/s"
-				fmt.Fprintf(&entry, "<i>Speed:</i> %s\n", speed)
-			}
-			if t.Seeders > 0 {
-				fmt.Fprintf(&entry, "<i>Seeders:</i> %d\n", t.Seeders)
-			}
-			entry.WriteString("\n")
-
-			if text.Len()+entry.Len() > maxMsgLen {
-				hitLengthLimit = true
-				break
-			}
-			text.WriteString(entry.String())
-			torrentsShown++
-		}
-
-		if hitLengthLimit {
-			fmt.Fprintf(&text, "<i>Showing the first %d torrents to avoid exceeding message length limits.</i>\n\n", torrentsShown)
-		}
-
-		text.WriteString("Use <code>/info &lt;id&gt;</code> for more details on a specific torrent.")
-		b.sendHTMLMessage(ctx, chatID, messageThreadID, text.String(), update.Message.ID)
-
-		if user != nil {
-			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "list", update.Message.Text, startTime, true, "", len(text.String()))
-			b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeTorrentList, "list", true, "", map[string]any{"torrent_count": len(torrents)})
-		}
+		reply.finishWithMarkup(ctx, page.text, page.markup)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "list", update.Message.Text, startTime, true, "", len(page.text))
+		b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeTorrentList, "list", true, "", map[string]any{"torrent_count": page.count})
 	})
 }
 
 // handleAddCommand handles the /add command
 func (b *Bot) handleAddCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
-	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, isSuperAdmin bool, user *db.User) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
 		startTime := time.Now()
 		b.middleware.LogCommand(update, "add")
 
 		parts := strings.Fields(update.Message.Text)
 		if len(parts) < 2 {
-			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /add &lt;magnet_link&gt;", update.Message.ID)
+			if !b.sendArgumentPrompt(ctx, update, messageThreadID, "add", "Reply with the magnet link to add.", "magnet:?xt=urn:btih:...") {
+				b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /add &lt;magnet_link&gt;", update.Message.ID)
+			}
 			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "add", update.Message.Text, startTime, false, "Missing arguments", 0)
 			return
 		}
 
 		magnetLink := strings.Join(parts[1:], " ")
+		if b.rejectLongInput(ctx, update, user, chatID, chatPK, messageThreadID, "add", magnetLink, startTime) {
+			return
+		}
 		if !strings.HasPrefix(magnetLink, "magnet:?") {
 			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>[ERROR]</b> Invalid magnet link provided.", update.Message.ID)
 			if user != nil {
 				if err := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, "", "", "", magnetLink, "add", "", 0, 0, false, "Invalid magnet link", nil); err != nil {
 					log.Printf("Warning: failed to log invalid magnet: %v", err)
 				}
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "add", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), false, "Invalid magnet link", 0); err != nil {
-					log.Printf("Warning: failed to log add command invalid magnet: %v", err)
-				}
+				b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "add", update.Message.Text, startTime, false, "Invalid magnet link", 0)
 			}
 			return
 		}
 
 		response, err := b.rdClient.AddMagnet(magnetLink)
 		if err != nil {
+			if b.queueMagnetIfFull(ctx, update, user, chatID, chatPK, messageThreadID, "add", magnetLink, err, startTime) {
+				return
+			}
 			text := fmt.Sprintf("<b>[ERROR]</b> Failed to add torrent: %s", html.EscapeString(err.Error()))
 			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
 			if user != nil {
 				if err := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, "", "", "", magnetLink, "add", "error", 0, 0, false, err.Error(), nil); err != nil {
 					log.Printf("Warning: failed to log torrent error: %v", err)
 				}
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "add", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), false, err.Error(), 0); err != nil {
-					log.Printf("Warning: failed to log add error command: %v", err)
-				}
+				b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "add", update.Message.Text, startTime, false, err.Error(), 0)
+			}
+			return
+		}
+
+		if b.isDuplicateAdd(ctx, user, response.ID) {
+			text := formatDuplicateAddMessage(response.ID)
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+			if err := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, response.ID, "", "", magnetLink, "add_duplicate", "", 0, 0, true, "", nil); err != nil {
+				log.Printf("Warning: failed to log duplicate torrent add: %v", err)
 			}
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "add", update.Message.Text, startTime, true, "", len(text))
 			return
 		}
 
-		if err := b.rdClient.SelectAllFiles(response.ID); err != nil {
+		cached, err := b.selectAddedFiles(response.ID, magnetLink, b.fileSelectFor(ctx, userPKOf(user)))
+		if err != nil {
 			log.Printf("Error selecting files for torrent %s: %v", response.ID, err)
 		}
 
@@ -207,33 +149,25 @@ func (b *Bot) handleAddCommand(ctx context.Context, _ *bot.Bot, update *models.U
 		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
 
 		if user != nil {
-			if err := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, response.ID, "", "", magnetLink, "add", "waiting_files_selection", 0, 0, true, "", nil); err != nil {
+			if err := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, response.ID, "", "", magnetLink, "add", "waiting_files_selection", 0, 0, true, "", cached.metadata(nil)); err != nil {
 				log.Printf("Warning: failed to log torrent activity: %v", err)
 			}
-			if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "add", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), true, "", len(text)); err != nil {
-				log.Printf("Warning: failed to log add success command: %v", err)
-			}
-			if err := b.activityRepo.LogActivity(ctx, "", user.ID, chatPK, user.Username, db.ActivityTypeTorrentAdd, "add", int64(update.Message.ID), messageThreadID, true, "", map[string]any{"torrent_id": response.ID}); err != nil {
-				log.Printf("Warning: failed to log torrent add activity: %v", err)
-			}
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "add", update.Message.Text, startTime, true, "", len(text))
+			b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeTorrentAdd, "add", true, "", map[string]any{"torrent_id": response.ID})
 		}
 	})
 }
 
 // handleInfoCommand handles the /info command
 func (b *Bot) handleInfoCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
-	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, isSuperAdmin bool, user *db.User) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
 		startTime := time.Now()
 		b.middleware.LogCommand(update, "info")
 
 		parts := strings.Fields(update.Message.Text)
 		if len(parts) < 2 {
 			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /info &lt;torrent_id&gt;", update.Message.ID)
-			if user != nil {
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "info", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), false, "Missing arguments", 0); err != nil {
-					log.Printf("Warning: failed to log info missing args: %v", err)
-				}
-			}
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "info", update.Message.Text, startTime, false, "Missing arguments", 0)
 			return
 		}
 		torrentID := parts[1]
@@ -241,50 +175,46 @@ func (b *Bot) handleInfoCommand(ctx context.Context, _ *bot.Bot, update *models.
 
 		if user != nil {
 			if err != nil {
-				if err2 := b.activityRepo.LogActivity(ctx, "", user.ID, chatPK, user.Username, db.ActivityTypeTorrentInfo, "info", int64(update.Message.ID), messageThreadID, false, err.Error(), map[string]any{"torrent_id": torrentID}); err2 != nil {
-					log.Printf("Warning: failed to log torrent info activity error: %v", err2)
-				}
-				if err2 := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "info", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), false, err.Error(), 0); err2 != nil {
-					log.Printf("Warning: failed to log info command error: %v", err2)
-				}
+				b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeTorrentInfo, "info", false, err.Error(), map[string]any{"torrent_id": torrentID})
+				b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "info", update.Message.Text, startTime, false, err.Error(), 0)
 			} else {
-				if err2 := b.activityRepo.LogActivity(ctx, "", user.ID, chatPK, user.Username, db.ActivityTypeTorrentInfo, "info", int64(update.Message.ID), messageThreadID, true, "", map[string]any{"torrent_id": torrentID}); err2 != nil {
-					log.Printf("Warning: failed to log torrent info activity: %v", err2)
-				}
-				if err2 := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "info", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), true, "", 0); err2 != nil {
-					log.Printf("Warning: failed to log info command success: %v", err2)
-				} // Response length logged in sendTorrentInfo
+				b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeTorrentInfo, "info", true, "", map[string]any{"torrent_id": torrentID})
+				b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "info", update.Message.Text, startTime, true, "", 0) // Response length logged in sendTorrentInfo
 			}
 		}
 	})
 }
 
-// sendTorrentInfo sends detailed torrent information
-func (b *Bot) sendTorrentInfo(ctx context.Context, chatID int64, messageThreadID int, torrentID string, user *db.User, messageID int, chatPK int64) error {
-	torrent, err := b.rdClient.GetTorrentInfo(torrentID)
-	if err != nil {
-		text := fmt.Sprintf("<b>[ERROR]</b> Could not retrieve torrent info: %s", html.EscapeString(err.Error()))
-		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, messageID)
-		if user != nil {
-			if err := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, torrentID, "", "", "", "info", "error", 0, 0, false, err.Error(), nil); err != nil {
-				log.Printf("Warning: failed to log torrent info error: %v", err)
-			}
-		}
-		return err
-	}
-
+// formatTorrentInfo renders the /info details of a torrent. Host and split size are only
+// shown when RD reports them.
+func (b *Bot) formatTorrentInfo(torrent *realdebrid.Torrent, displayName string) string {
 	status := realdebrid.FormatStatus(torrent.Status)
 	size := realdebrid.FormatSize(torrent.Bytes)
 	progress := fmt.Sprintf("%.1f%%", torrent.Progress)
 
 	var text strings.Builder
 	text.WriteString("<b>Torrent Details</b>\n\n")
-	fmt.Fprintf(&text, "<i>Name:</i> <code>%s</code>\n", html.EscapeString(torrent.Filename))
+	if displayName != "" {
+		fmt.Fprintf(&text, "<i>Name:</i> <code>%s</code>\n", html.EscapeString(displayName))
+		fmt.Fprintf(&text, "<i>File:</i> <code>%s</code>\n", html.EscapeString(torrent.Filename))
+	} else {
+		fmt.Fprintf(&text, "<i>Name:</i> <code>%s</code>\n", html.EscapeString(torrent.Filename))
+	}
 	fmt.Fprintf(&text, "<i>ID:</i> <code>%s</code>\n", torrent.ID)
 	fmt.Fprintf(&text, "<i>Status:</i> %s\n", status)
 	fmt.Fprintf(&text, "<i>Size:</i> %s\n", size)
 	fmt.Fprintf(&text, "<i>Progress:</i> %s\n", progress)
 	fmt.Fprintf(&text, "<i>Hash:</i> <code>%s</code>\n", torrent.Hash)
+	if torrent.Host != "" {
+		fmt.Fprintf(&text, "<i>Host:</i> <code>%s</code>\n", html.EscapeString(torrent.Host))
+	}
+	if torrent.Split > 0 {
+		fmt.Fprintf(&text, "<i>Split:</i> %d GB parts\n", torrent.Split)
+	}
+
+	if took, ok := downloadDuration(torrent); ok {
+		fmt.Fprintf(&text, "<i>Completed:</i> %s (took %s)\n", b.displayTime(*torrent.Ended), formatElapsed(took))
+	}
 
 	if torrent.Speed > 0 {
 		speed := realdebrid.FormatSize(torrent.Speed) + "/s"
@@ -293,9 +223,25 @@ func (b *Bot) sendTorrentInfo(ctx context.Context, chatID int64, messageThreadID
 	if torrent.Seeders > 0 {
 		fmt.Fprintf(&text, "<i>Seeders:</i> %d\n", torrent.Seeders)
 	}
+	return text.String()
+}
+
+// sendTorrentInfo sends detailed torrent information with a button to refresh it
+func (b *Bot) sendTorrentInfo(ctx context.Context, chatID int64, messageThreadID int, torrentID string, user *db.User, messageID int, chatPK int64) error {
+	torrent, err := b.rdClient.GetTorrentInfo(torrentID)
+	if err != nil {
+		text := fmt.Sprintf("<b>[ERROR]</b> Could not retrieve torrent info: %s", html.EscapeString(err.Error()))
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, messageID)
+		if user != nil {
+			if err := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, torrentID, "", "", "", "info", "error", 0, 0, false, err.Error(), nil); err != nil {
+				log.Printf("Warning: failed to log torrent info error: %v", err)
+			}
+		}
+		return err
+	}
 
-	// Send message
-	b.sendHTMLMessage(ctx, chatID, messageThreadID, text.String(), messageID)
+	text := b.formatTorrentInfo(torrent, b.lookupDisplayNames(ctx, torrent.ID)[torrent.ID])
+	b.sendHTMLMessageWithMarkup(ctx, chatID, messageThreadID, text, messageID, infoRefreshKeyboard(torrent.ID))
 
 	if user != nil {
 		if err := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, torrentID, torrent.Hash, torrent.Filename, "", "info", torrent.Status, torrent.Bytes, torrent.Progress, true, "", nil); err != nil {
@@ -307,32 +253,44 @@ func (b *Bot) sendTorrentInfo(ctx context.Context, chatID int64, messageThreadID
 
 // handleDeleteCommand handles the /delete command
 func (b *Bot) handleDeleteCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
-	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, isSuperAdmin bool, user *db.User) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
 		startTime := time.Now()
 		b.middleware.LogCommand(update, "delete")
 
-		if !isSuperAdmin {
-			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>[ERROR]</b> Access Denied. This command is for superadmins only.", update.Message.ID)
-			if user != nil {
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "delete", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), false, "Unauthorized - not superadmin", 0); err != nil {
-					log.Printf("Warning: failed to log unauthorized delete command: %v", err)
-				}
-			}
+		if !role.IsSuperAdmin() {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, b.localize(chatID, "error.superadmin_only"), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "delete", update.Message.Text, startTime, false, "Unauthorized - not superadmin", 0)
 			return
 		}
 
-		parts := strings.Fields(update.Message.Text)
-		if len(parts) < 2 {
-			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /delete &lt;torrent_id&gt;", update.Message.ID)
-			if user != nil {
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "delete", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), false, "Missing arguments", 0); err != nil {
-					log.Printf("Warning: failed to log delete missing args: %v", err)
-				}
+		ids := parseDeleteIDs(update.Message.Text)
+		if len(ids) == 0 {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /delete &lt;torrent_id&gt; [torrent_id...]", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "delete", update.Message.Text, startTime, false, "Missing arguments", 0)
+			return
+		}
+
+		if len(ids) > maxDeleteIDs {
+			text := fmt.Sprintf("<b>[ERROR]</b> Too many torrent IDs (%d). /delete accepts at most %d at a time.", len(ids), maxDeleteIDs)
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "delete", update.Message.Text, startTime, false, "Too many IDs", 0)
+			return
+		}
+
+		if len(ids) > deleteConfirmThreshold && update.Message.From != nil {
+			if err := b.requestDeleteConfirmation(ctx, update, chatID, messageThreadID, ids); err != nil {
+				log.Printf("Error sending delete confirmation: %v", err)
+				b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>[ERROR]</b> Failed to request confirmation. Please try again.", update.Message.ID)
+				b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "delete", update.Message.Text, startTime, false, err.Error(), 0)
 			}
 			return
 		}
+		if len(ids) > 1 {
+			b.runDeleteBatch(ctx, user, chatID, chatPK, messageThreadID, update.Message.ID, update.Message.Text, ids, startTime)
+			return
+		}
 
-		torrentID := parts[1]
+		torrentID := ids[0]
 		if err := b.rdClient.DeleteTorrent(torrentID); err != nil {
 			text := fmt.Sprintf("<b>[ERROR]</b> Failed to delete torrent: %s", html.EscapeString(err.Error()))
 			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
@@ -340,9 +298,7 @@ func (b *Bot) handleDeleteCommand(ctx context.Context, _ *bot.Bot, update *model
 				if err := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, torrentID, "", "", "", "delete", "error", 0, 0, false, err.Error(), nil); err != nil {
 					log.Printf("Warning: failed to log delete torrent error: %v", err)
 				}
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "delete", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), false, err.Error(), 0); err != nil {
-					log.Printf("Warning: failed to log delete error command: %v", err)
-				}
+				b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "delete", update.Message.Text, startTime, false, err.Error(), 0)
 			}
 			return
 		}
@@ -362,22 +318,27 @@ func (b *Bot) handleDeleteCommand(ctx context.Context, _ *bot.Bot, update *model
 
 // handleUnrestrictCommand handles the /unrestrict command
 func (b *Bot) handleUnrestrictCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
-	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, isSuperAdmin bool, user *db.User) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
 		startTime := time.Now()
 		b.middleware.LogCommand(update, "unrestrict")
 
 		parts := strings.Fields(update.Message.Text)
 		if len(parts) < 2 {
-			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /unrestrict &lt;link&gt;", update.Message.ID)
-			if user != nil {
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "unrestrict", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), false, "Missing arguments", 0); err != nil {
-					log.Printf("Warning: failed to log unrestrict missing argument command: %v", err)
-				}
+			if !b.sendArgumentPrompt(ctx, update, messageThreadID, "unrestrict", "Reply with the hoster link to unrestrict.", "https://...") {
+				b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /unrestrict &lt;link&gt;", update.Message.ID)
 			}
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "unrestrict", update.Message.Text, startTime, false, "Missing arguments", 0)
 			return
 		}
 
 		link := strings.Join(parts[1:], " ")
+		if b.rejectLongInput(ctx, update, user, chatID, chatPK, messageThreadID, "unrestrict", link, startTime) {
+			return
+		}
+		if b.rejectBlockedHost(ctx, update, user, chatID, chatPK, messageThreadID, "unrestrict", link, startTime) {
+			return
+		}
+		warning := b.trafficWarningFor(link)
 		unrestricted, err := b.rdClient.UnrestrictLink(link)
 		if err != nil {
 			text := fmt.Sprintf("<b>[ERROR]</b> Failed to unrestrict link: %s", html.EscapeString(err.Error()))
@@ -386,9 +347,7 @@ func (b *Bot) handleUnrestrictCommand(ctx context.Context, _ *bot.Bot, update *m
 				if err := b.downloadRepo.LogDownloadActivity(ctx, "", user.ID, chatPK, "", link, "", "", "unrestrict", 0, false, err.Error(), nil, nil); err != nil {
 					log.Printf("Warning: failed to log download unrestrict error: %v", err)
 				}
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "unrestrict", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), false, err.Error(), 0); err != nil {
-					log.Printf("Warning: failed to log unrestrict error command: %v", err)
-				}
+				b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "unrestrict", update.Message.Text, startTime, false, err.Error(), 0)
 			}
 			return
 		}
@@ -403,6 +362,9 @@ func (b *Bot) handleUnrestrictCommand(ctx context.Context, _ *bot.Bot, update *m
 			size,
 			html.EscapeString(unrestricted.Host),
 		)
+		if warning != "" {
+			text += "\n\n" + warning
+		}
 		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
 
 		if user != nil {
@@ -415,42 +377,111 @@ func (b *Bot) handleUnrestrictCommand(ctx context.Context, _ *bot.Bot, update *m
 	})
 }
 
+// handleCheckCommand handles the /check command
+func (b *Bot) handleCheckCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "check")
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) < 2 {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /check &lt;link&gt;", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "check", update.Message.Text, startTime, false, "Missing arguments", 0)
+			return
+		}
+
+		link := strings.Join(parts[1:], " ")
+		if b.rejectLongInput(ctx, update, user, chatID, chatPK, messageThreadID, "check", link, startTime) {
+			return
+		}
+
+		check, err := b.rdClient.CheckLink(link)
+		if err != nil {
+			text := fmt.Sprintf("<b>[ERROR]</b> Link is not available: %s", html.EscapeString(err.Error()))
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "check", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := formatLinkCheck(check)
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "check", update.Message.Text, startTime, true, "", len(text))
+	})
+}
+
+// formatLinkCheck renders the result of a /check lookup
+func formatLinkCheck(check *realdebrid.LinkCheck) string {
+	var text strings.Builder
+	if check.IsSupported() {
+		text.WriteString("<b>[OK]</b> Link is supported\n\n")
+	} else {
+		text.WriteString("<b>[ERROR]</b> Link is not supported\n\n")
+	}
+	if check.Filename != "" {
+		fmt.Fprintf(&text, "<i>File:</i> <code>%s</code>\n", html.EscapeString(check.Filename))
+	}
+	if check.Filesize > 0 {
+		fmt.Fprintf(&text, "<i>Size:</i> %s\n", realdebrid.FormatSize(check.Filesize))
+	}
+	if check.Host != "" {
+		fmt.Fprintf(&text, "<i>Host:</i> %s\n", html.EscapeString(check.Host))
+	}
+	return strings.TrimRight(text.String(), "\n")
+}
+
 // handleDownloadsCommand handles the /downloads command
 func (b *Bot) handleDownloadsCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
-	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, isSuperAdmin bool, user *db.User) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
 		startTime := time.Now()
 		b.middleware.LogCommand(update, "downloads")
 
-		downloads, err := b.rdClient.GetDownloads(10, 0)
+		opts, err := parseDownloadsArgs(strings.Fields(update.Message.Text)[1:])
+		if err != nil {
+			text := fmt.Sprintf("<b>[ERROR]</b> %s\n\n<b>Usage:</b> /downloads [host=&lt;host&gt;] [sort=size|date]", html.EscapeString(err.Error()))
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "downloads", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		fetch := 10
+		if b.config.App.DownloadsDedupe || opts.host != "" || opts.sortBy != "" {
+			// Fetch extra so collapsed repeats or a filter still leave a full page
+			fetch = 50
+		}
+		downloads, err := b.rdClient.GetDownloads(fetch, 0)
 		if err != nil {
 			text := fmt.Sprintf("<b>[ERROR]</b> Failed to retrieve downloads: %s", html.EscapeString(err.Error()))
 			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
-			if user != nil {
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "downloads", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), false, err.Error(), 0); err != nil {
-					log.Printf("Warning: failed to log downloads error command: %v", err)
-				}
-				if err := b.activityRepo.LogActivity(ctx, "", user.ID, chatPK, user.Username, db.ActivityTypeDownloadList, "downloads", int64(update.Message.ID), messageThreadID, false, err.Error(), nil); err != nil {
-					log.Printf("Warning: failed to log downloads activity error: %v", err)
-				}
-			}
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "downloads", update.Message.Text, startTime, false, err.Error(), 0)
+			b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeDownloadList, "downloads", false, err.Error(), nil)
 			return
 		}
 
 		if len(downloads) == 0 {
 			b.sendHTMLMessage(ctx, chatID, messageThreadID, "No recent downloads found.", update.Message.ID)
-			if user != nil {
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "downloads", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), true, "", 0); err != nil {
-					log.Printf("Warning: failed to log downloads no-results command: %v", err)
-				}
-				if err := b.activityRepo.LogActivity(ctx, "", user.ID, chatPK, user.Username, db.ActivityTypeDownloadList, "downloads", int64(update.Message.ID), messageThreadID, true, "", map[string]any{"download_count": 0}); err != nil {
-					log.Printf("Warning: failed to log downloads activity empty success: %v", err)
-				}
-			}
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "downloads", update.Message.Text, startTime, true, "", 0)
+			b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeDownloadList, "downloads", true, "", map[string]any{"download_count": 0})
 			return
 		}
 
+		if b.config.App.DownloadsDedupe {
+			downloads = dedupeDownloads(downloads)
+		}
+		downloads = applyDownloadsOptions(downloads, opts)
+		if len(downloads) == 0 {
+			text := fmt.Sprintf("No recent downloads from a host matching <code>%s</code> found.", html.EscapeString(opts.host))
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "downloads", update.Message.Text, startTime, true, "", len(text))
+			return
+		}
+		downloads = downloads[:min(len(downloads), 10)]
+
 		var text strings.Builder
-		text.WriteString("<b>Recent Downloads</b>\n\n")
+		text.WriteString("<b>Recent Downloads</b>\n")
+		if summary := opts.summary(); summary != "" {
+			fmt.Fprintf(&text, "<i>%s</i>\n", html.EscapeString(summary))
+		}
+		text.WriteString("\n")
 
 		const maxMsgLen = 4000
 		downloadsShown := 0
@@ -458,7 +489,7 @@ func (b *Bot) handleDownloadsCommand(ctx context.Context, _ *bot.Bot, update *mo
 		for _, d := range downloads {
 			entry := strings.Builder{}
 			size := realdebrid.FormatSize(d.Filesize)
-			fmt.Fprintf(&entry, "<i>File:</i> <code>%s</code>\n", html.EscapeString(d.Filename))
+			fmt.Fprintf(&entry, "<i>File:</i> <code>%s</code>\n", html.EscapeString(truncateName(d.Filename, b.config.App.MaxFilenameDisplay)))
 			fmt.Fprintf(&entry, "<i>ID:</i> <code>%s</code>\n", d.ID)
 			fmt.Fprintf(&entry, "<i>Size:</i> %s\n", size)
 			fmt.Fprintf(&entry, "<i>Host:</i> %s\n", html.EscapeString(d.Host))
@@ -478,37 +509,113 @@ func (b *Bot) handleDownloadsCommand(ctx context.Context, _ *bot.Bot, update *mo
 		text.WriteString("Use <code>/removelink &lt;id&gt;</code> to remove an item from this list.")
 		b.sendHTMLMessage(ctx, chatID, messageThreadID, text.String(), update.Message.ID)
 
-		if user != nil {
-			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "downloads", update.Message.Text, startTime, true, "", len(text.String()))
-			b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeDownloadList, "downloads", true, "", map[string]any{"download_count": len(downloads)})
-		}
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "downloads", update.Message.Text, startTime, true, "", len(text.String()))
+		b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeDownloadList, "downloads", true, "", map[string]any{"download_count": len(downloads)})
 	})
 }
 
+// dedupeDownloads collapses downloads of the same link, or of the same filename when
+// there is no link, into the most recent one. Entries keep the position of their
+// first occurrence.
+func dedupeDownloads(downloads []realdebrid.Download) []realdebrid.Download {
+	result := make([]realdebrid.Download, 0, len(downloads))
+	seen := make(map[string]int, len(downloads))
+	for _, d := range downloads {
+		key := "link:" + d.Link
+		if d.Link == "" {
+			key = "file:" + d.Filename
+		}
+		if i, ok := seen[key]; ok {
+			if d.Generated.After(result[i].Generated) {
+				result[i] = d
+			}
+			continue
+		}
+		seen[key] = len(result)
+		result = append(result, d)
+	}
+	return result
+}
+
+// downloadsOptions are the optional arguments of /downloads
+type downloadsOptions struct {
+	host   string // keep only downloads whose host contains this, lowercased
+	sortBy string // "size" (largest first) or "date" (newest first); "" keeps Real-Debrid's order
+}
+
+// parseDownloadsArgs parses the host=<host> and sort=size|date arguments of /downloads
+func parseDownloadsArgs(args []string) (downloadsOptions, error) {
+	var opts downloadsOptions
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || value == "" {
+			return opts, fmt.Errorf("invalid argument %q", arg)
+		}
+		switch strings.ToLower(key) {
+		case "host":
+			opts.host = strings.ToLower(value)
+		case "sort":
+			value = strings.ToLower(value)
+			if value != "size" && value != "date" {
+				return opts, fmt.Errorf("unknown sort %q, use size or date", value)
+			}
+			opts.sortBy = value
+		default:
+			return opts, fmt.Errorf("unknown argument %q", key)
+		}
+	}
+	return opts, nil
+}
+
+// summary describes the filter and order of opts, or "" for the default listing
+func (o downloadsOptions) summary() string {
+	var parts []string
+	if o.host != "" {
+		parts = append(parts, "host: "+o.host)
+	}
+	switch o.sortBy {
+	case "size":
+		parts = append(parts, "largest first")
+	case "date":
+		parts = append(parts, "newest first")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// applyDownloadsOptions filters downloads by host and sorts them as requested in opts.
+// downloads is not modified.
+func applyDownloadsOptions(downloads []realdebrid.Download, opts downloadsOptions) []realdebrid.Download {
+	result := make([]realdebrid.Download, 0, len(downloads))
+	for _, d := range downloads {
+		if opts.host == "" || strings.Contains(strings.ToLower(d.Host), opts.host) {
+			result = append(result, d)
+		}
+	}
+	switch opts.sortBy {
+	case "size":
+		slices.SortStableFunc(result, func(a, b realdebrid.Download) int { return cmp.Compare(b.Filesize, a.Filesize) })
+	case "date":
+		slices.SortStableFunc(result, func(a, b realdebrid.Download) int { return b.Generated.Compare(a.Generated) })
+	}
+	return result
+}
+
 // handleRemoveLinkCommand handles the /removelink command
 func (b *Bot) handleRemoveLinkCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
-	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, isSuperAdmin bool, user *db.User) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
 		startTime := time.Now()
 		b.middleware.LogCommand(update, "removelink")
 
-		if !isSuperAdmin {
-			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>[ERROR]</b> Access Denied. This command is for superadmins only.", update.Message.ID)
-			if user != nil {
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "removelink", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), false, "Unauthorized - not superadmin", 0); err != nil {
-					log.Printf("Warning: failed to log unauthorized removelink command: %v", err)
-				}
-			}
+		if !role.IsSuperAdmin() {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, b.localize(chatID, "error.superadmin_only"), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "removelink", update.Message.Text, startTime, false, "Unauthorized - not superadmin", 0)
 			return
 		}
 
 		parts := strings.Fields(update.Message.Text)
 		if len(parts) < 2 {
 			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /removelink &lt;download_id&gt;", update.Message.ID)
-			if user != nil {
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "removelink", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), false, "Missing arguments", 0); err != nil {
-					log.Printf("Warning: failed to log removelink missing args: %v", err)
-				}
-			}
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "removelink", update.Message.Text, startTime, false, "Missing arguments", 0)
 			return
 		}
 
@@ -520,9 +627,7 @@ func (b *Bot) handleRemoveLinkCommand(ctx context.Context, _ *bot.Bot, update *m
 				if err := b.downloadRepo.LogDownloadActivity(ctx, "", user.ID, chatPK, downloadID, "", "", "", "delete", 0, false, err.Error(), nil, nil); err != nil {
 					log.Printf("Warning: failed to log remove download error: %v", err)
 				}
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "removelink", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), false, err.Error(), 0); err != nil {
-					log.Printf("Warning: failed to log removelink error command: %v", err)
-				}
+				b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "removelink", update.Message.Text, startTime, false, err.Error(), 0)
 			}
 			return
 		}
@@ -540,60 +645,70 @@ func (b *Bot) handleRemoveLinkCommand(ctx context.Context, _ *bot.Bot, update *m
 	})
 }
 
+// getRDUser fetches the RD account, sharing one upstream call between concurrent callers
+// so a burst of /status commands in a busy group costs a single request
+func (b *Bot) getRDUser() (*realdebrid.User, error) {
+	v, err, _ := b.userFlight.Do("user", func() (interface{}, error) {
+		return b.rdClient.GetUser()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*realdebrid.User), nil
+}
+
 // handleStatusCommand handles the /status command
 func (b *Bot) handleStatusCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
-	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, isSuperAdmin bool, user *db.User) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
 		startTime := time.Now()
 		b.middleware.LogCommand(update, "status")
 
-		rdUser, err := b.rdClient.GetUser()
+		rdUser, err := b.getRDUser()
 		if err != nil {
 			text := fmt.Sprintf("<b>[ERROR]</b> Could not retrieve account status: %s", html.EscapeString(err.Error()))
 			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
-			if user != nil {
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "status", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), false, err.Error(), 0); err != nil {
-					log.Printf("Warning: failed to log status command error: %v", err)
-				}
-				if err := b.activityRepo.LogActivity(ctx, "", user.ID, chatPK, user.Username, db.ActivityTypeCommandStatus, "status", int64(update.Message.ID), messageThreadID, false, err.Error(), nil); err != nil {
-					log.Printf("Warning: failed to log status command activity error: %v", err)
-				}
-			}
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "status", update.Message.Text, startTime, false, err.Error(), 0)
+			b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeCommandStatus, "status", false, err.Error(), nil)
 			return
 		}
 
-		var text strings.Builder
-		text.WriteString("<b>Account Status</b>\n\n")
-		fmt.Fprintf(&text, "<i>Username:</i> <code>%s</code>\n", html.EscapeString(maskUsername(rdUser.Username)))
-		fmt.Fprintf(&text, "<i>Email:</i> <code>%s</code>\n", html.EscapeString(rdUser.Email))
-		fmt.Fprintf(&text, "<i>Account Type:</i> %s\n", html.EscapeString(cases.Title(language.English).String(rdUser.Type)))
+		text := formatAccountStatus(rdUser, b.displayTime)
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
 
-		if rdUser.Points > 0 {
-			fmt.Fprintf(&text, "<i>Fidelity Points:</i> %d\n", rdUser.Points)
-		}
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "status", update.Message.Text, startTime, true, "", len(text))
+		b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeCommandStatus, "status", true, "", nil)
+	})
+}
 
-		if rdUser.Premium > 0 {
-			duration := rdUser.GetPremiumDuration()
-			days := int(duration.Hours() / 24)
-			hours := int(duration.Hours()) % 24
-			fmt.Fprintf(&text, "<i>Premium Remaining:</i> %d days, %d hours\n", days, hours)
-		}
+// formatAccountStatus renders the Real-Debrid account summary shown by /status
+func formatAccountStatus(rdUser *realdebrid.User, displayTime func(time.Time) string) string {
+	var text strings.Builder
+	text.WriteString("<b>Account Status</b>\n\n")
+	fmt.Fprintf(&text, "<i>Username:</i> <code>%s</code>\n", html.EscapeString(maskUsername(rdUser.Username)))
+	fmt.Fprintf(&text, "<i>Email:</i> <code>%s</code>\n", html.EscapeString(rdUser.Email))
+	fmt.Fprintf(&text, "<i>Account Type:</i> %s\n", html.EscapeString(cases.Title(language.English).String(rdUser.Type)))
 
-		if expTime, err := rdUser.GetExpirationTime(); err == nil && !expTime.IsZero() {
-			fmt.Fprintf(&text, "<i>Expires On:</i> %s\n", expTime.UTC().Format("2006-01-02 15:04 UTC"))
-		}
+	if rdUser.Points > 0 {
+		fmt.Fprintf(&text, "<i>Fidelity Points:</i> %d\n", rdUser.Points)
+	}
 
-		b.sendHTMLMessage(ctx, chatID, messageThreadID, text.String(), update.Message.ID)
+	if rdUser.Premium > 0 {
+		duration := rdUser.GetPremiumDuration()
+		days := int(duration.Hours() / 24)
+		hours := int(duration.Hours()) % 24
+		fmt.Fprintf(&text, "<i>Premium Remaining:</i> %d days, %d hours\n", days, hours)
+	}
 
-		if user != nil {
-			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "status", update.Message.Text, startTime, true, "", len(text.String()))
-			b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeCommandStatus, "status", true, "", nil)
-		}
-	})
+	if expTime, err := rdUser.GetExpirationTime(); err == nil && !expTime.IsZero() {
+		fmt.Fprintf(&text, "<i>Expires On:</i> %s\n", displayTime(expTime))
+	}
+
+	return text.String()
 }
 
 // handleStatsCommand handles the /stats command
 func (b *Bot) handleStatsCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
-	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, isSuperAdmin bool, user *db.User) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
 		startTime := time.Now()
 		b.middleware.LogCommand(update, "stats")
 
@@ -678,7 +793,7 @@ func (b *Bot) handleStatsCommand(ctx context.Context, _ *bot.Bot, update *models
 
 // handleMagnetLink handles magnet links sent as messages
 func (b *Bot) handleMagnetLink(ctx context.Context, _ *bot.Bot, update *models.Update) {
-	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, isSuperAdmin bool, user *db.User) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
 		startTime := time.Now()
 		b.middleware.LogCommand(update, "magnet_link")
 
@@ -693,22 +808,37 @@ func (b *Bot) handleMagnetLink(ctx context.Context, _ *bot.Bot, update *models.U
 				}
 			}
 		}
+		if b.rejectLongInput(ctx, update, user, chatID, chatPK, messageThreadID, "magnet_link", magnetLink, startTime) {
+			return
+		}
 		response, err := b.rdClient.AddMagnet(magnetLink)
 		if err != nil {
+			if b.queueMagnetIfFull(ctx, update, user, chatID, chatPK, messageThreadID, "magnet_link", magnetLink, err, startTime) {
+				return
+			}
 			text := fmt.Sprintf("<b>[ERROR]</b> Failed to add torrent: %s", html.EscapeString(err.Error()))
 			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
 			if user != nil {
 				if err := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, "", "", "", magnetLink, "add", "error", 0, 0, false, err.Error(), nil); err != nil {
 					log.Printf("Warning: failed to log magnet link error: %v", err)
 				}
-				if err := b.activityRepo.LogActivity(ctx, "", user.ID, chatPK, user.Username, db.ActivityTypeMagnetLink, "magnet_link", int64(update.Message.ID), messageThreadID, false, err.Error(), nil); err != nil {
-					log.Printf("Warning: failed to log magnet link activity error: %v", err)
-				}
+				b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeMagnetLink, "magnet_link", false, err.Error(), nil)
 			}
 			return
 		}
 
-		if err := b.rdClient.SelectAllFiles(response.ID); err != nil {
+		if b.isDuplicateAdd(ctx, user, response.ID) {
+			text := formatDuplicateAddMessage(response.ID)
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+			if err := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, response.ID, "", "", magnetLink, "add_duplicate", "", 0, 0, true, "", nil); err != nil {
+				log.Printf("Warning: failed to log duplicate torrent add: %v", err)
+			}
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "magnet_link", magnetLink, startTime, true, "", len(text))
+			return
+		}
+
+		cached, err := b.selectAddedFiles(response.ID, magnetLink, b.fileSelectFor(ctx, userPKOf(user)))
+		if err != nil {
 			log.Printf("Error selecting files for torrent %s: %v", response.ID, err)
 		}
 
@@ -721,7 +851,7 @@ func (b *Bot) handleMagnetLink(ctx context.Context, _ *bot.Bot, update *models.U
 		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
 
 		if user != nil {
-			if err := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, response.ID, "", "", magnetLink, "add", "waiting_files_selection", 0, 0, true, "", nil); err != nil {
+			if err := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, response.ID, "", "", magnetLink, "add", "waiting_files_selection", 0, 0, true, "", cached.metadata(nil)); err != nil {
 				log.Printf("Warning: failed to log magnet link success: %v", err)
 			}
 			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "magnet_link", magnetLink, startTime, true, "", len(text))
@@ -730,29 +860,46 @@ func (b *Bot) handleMagnetLink(ctx context.Context, _ *bot.Bot, update *models.U
 	})
 }
 
-// handleHosterLink handles hoster links sent as messages
+// isSupportedLink reports whether link matches one of Real-Debrid's supported host
+// patterns. Every link passes if the patterns could not be fetched.
+func (b *Bot) isSupportedLink(link string) bool {
+	if len(b.supportedRegex) == 0 {
+		return true
+	}
+	for _, regex := range b.supportedRegex {
+		if regex.MatchString(link) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleHosterLink handles hoster links sent as messages. A message with several
+// links is handled as a bulk unrestrict.
 func (b *Bot) handleHosterLink(ctx context.Context, _ *bot.Bot, update *models.Update) {
-	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, isSuperAdmin bool, user *db.User) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
 		startTime := time.Now()
 		b.middleware.LogCommand(update, "hoster_link")
 
 		link := update.Message.Text
+		if links := extractLinks(link); len(links) > 1 {
+			b.handleBulkUnrestrict(ctx, update, user, chatID, chatPK, messageThreadID, links, startTime)
+			return
+		}
 
-		// Check if link is supported
-		if len(b.supportedRegex) > 0 {
-			matched := false
-			for _, regex := range b.supportedRegex {
-				if regex.MatchString(link) {
-					matched = true
-					break
-				}
-			}
-			if !matched {
-				// Silently ignore unsupported links
-				return
-			}
+		// Silently ignore unsupported links
+		if !b.isSupportedLink(link) {
+			return
 		}
 
+		if b.rejectLongInput(ctx, update, user, chatID, chatPK, messageThreadID, "hoster_link", link, startTime) {
+			return
+		}
+		if b.rejectBlockedHost(ctx, update, user, chatID, chatPK, messageThreadID, "hoster_link", link, startTime) {
+			return
+		}
+
+		warning := b.trafficWarningFor(link)
 		unrestricted, err := b.rdClient.UnrestrictLink(link)
 		if err != nil {
 			text := fmt.Sprintf("<b>[ERROR]</b> Failed to unrestrict link: %s", html.EscapeString(err.Error()))
@@ -761,9 +908,7 @@ func (b *Bot) handleHosterLink(ctx context.Context, _ *bot.Bot, update *models.U
 				if err := b.downloadRepo.LogDownloadActivity(ctx, "", user.ID, chatPK, "", link, "", "", "unrestrict", 0, false, err.Error(), nil, nil); err != nil {
 					log.Printf("Warning: failed to log hoster unrestrict error: %v", err)
 				}
-				if err := b.activityRepo.LogActivity(ctx, "", user.ID, chatPK, user.Username, db.ActivityTypeHosterLink, "hoster_link", int64(update.Message.ID), messageThreadID, false, err.Error(), nil); err != nil {
-					log.Printf("Warning: failed to log hoster link activity error: %v", err)
-				}
+				b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeHosterLink, "hoster_link", false, err.Error(), nil)
 			}
 			return
 		}
@@ -778,6 +923,9 @@ func (b *Bot) handleHosterLink(ctx context.Context, _ *bot.Bot, update *models.U
 			size,
 			html.EscapeString(unrestricted.Host),
 		)
+		if warning != "" {
+			text += "\n\n" + warning
+		}
 		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
 
 		if user != nil {
@@ -792,7 +940,7 @@ func (b *Bot) handleHosterLink(ctx context.Context, _ *bot.Bot, update *models.U
 
 // handleDashboardCommand handles the /dashboard command
 func (b *Bot) handleDashboardCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
-	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, isSuperAdmin bool, user *db.User) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
 		startTime := time.Now()
 		b.middleware.LogCommand(update, "dashboard")
 
@@ -811,15 +959,11 @@ func (b *Bot) handleDashboardCommand(ctx context.Context, _ *bot.Bot, update *mo
 			userID = user.UserID
 		}
 
-		tokenID, err := b.tokenStore.GenerateToken(userID, username, firstName, isSuperAdmin)
+		tokenID, err := b.tokenStore.GenerateToken(userID, username, firstName, role.IsSuperAdmin())
 		if err != nil {
 			text := fmt.Sprintf("<b>[ERROR]</b> Failed to generate dashboard token: %s", html.EscapeString(err.Error()))
 			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
-			if user != nil {
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "dashboard", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), false, err.Error(), 0); err != nil {
-					log.Printf("Warning: failed to log dashboard error command: %v", err)
-				}
-			}
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "dashboard", update.Message.Text, startTime, false, err.Error(), 0)
 			return
 		}
 
@@ -835,7 +979,7 @@ func (b *Bot) handleDashboardCommand(ctx context.Context, _ *bot.Bot, update *mo
 		dashboardURL := b.config.Web.DashboardURL + "?code=" + exchangeCode
 
 		var roleDesc string
-		if isSuperAdmin {
+		if role.IsSuperAdmin() {
 			roleDesc = "Admin (full access)"
 		} else {
 			roleDesc = "Viewer (read-only)"
@@ -866,13 +1010,365 @@ func (b *Bot) handleDashboardCommand(ctx context.Context, _ *bot.Bot, update *mo
 	})
 }
 
+// handleProxyTestCommand handles the /proxytest command (moderators and superadmins)
+func (b *Bot) handleProxyTestCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "proxytest")
+
+		if !role.CanModerate() {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, b.localize(chatID, "error.moderator_only"), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "proxytest", update.Message.Text, startTime, false, "Unauthorized - not moderator", 0)
+			return
+		}
+
+		// Unlike startup, don't wait indefinitely for StremThru
+		testCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+
+		result, testErr := performIPTests(testCtx, b.ipTest)
+		b.health.recordIPTest(result, testErr)
+		text := formatIPTestResult(b.ipTest, result, testErr)
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+
+		errMsg := ""
+		if testErr != nil {
+			errMsg = testErr.Error()
+		}
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "proxytest", update.Message.Text, startTime, testErr == nil, errMsg, len(text))
+	})
+}
+
+// formatIPTestResult renders the outcome of an on-demand IP test
+func formatIPTestResult(cfg IPTestConfig, result IPTestResult, testErr error) string {
+	var text strings.Builder
+	if testErr != nil || result.PrimaryErr != nil {
+		text.WriteString("<b>[ERROR]</b> Proxy test failed\n\n")
+	} else {
+		text.WriteString("<b>[OK]</b> Proxy test passed\n\n")
+	}
+
+	if cfg.ProxyURL != "" {
+		text.WriteString("<i>Proxy:</i> configured\n")
+	} else {
+		text.WriteString("<i>Proxy:</i> none (direct)\n")
+	}
+
+	if result.PrimaryIP != "" {
+		fmt.Fprintf(&text, "<i>Primary IP:</i> <code>%s</code>\n", html.EscapeString(result.PrimaryIP))
+	} else {
+		text.WriteString("<i>Primary IP:</i> unavailable\n")
+	}
+
+	if cfg.StremThruURL != "" {
+		if result.StremThruIP != "" {
+			fmt.Fprintf(&text, "<i>StremThru IP:</i> <code>%s</code>\n", html.EscapeString(result.StremThruIP))
+		}
+		if result.Verified() {
+			text.WriteString("<i>Verification:</i> IPs match\n")
+		} else {
+			text.WriteString("<i>Verification:</i> failed\n")
+		}
+	}
+
+	if result.PrimaryErr != nil {
+		fmt.Fprintf(&text, "\n<i>Error:</i> %s", html.EscapeString(result.PrimaryErr.Error()))
+	}
+	if testErr != nil {
+		fmt.Fprintf(&text, "\n<i>Error:</i> %s", html.EscapeString(testErr.Error()))
+	}
+	return strings.TrimRight(text.String(), "\n")
+}
+
+// handleGlobalStatsCommand handles the /globalstats command (moderators and superadmins)
+func (b *Bot) handleGlobalStatsCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "globalstats")
+
+		if !role.CanModerate() {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, b.localize(chatID, "error.moderator_only"), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "globalstats", update.Message.Text, startTime, false, "Unauthorized - not moderator", 0)
+			return
+		}
+
+		stats, err := b.commandRepo.GetGlobalStats(ctx)
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Failed to retrieve global stats: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "globalstats", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := formatGlobalStats(stats)
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "globalstats", update.Message.Text, startTime, true, "", len(text))
+	})
+}
+
+// formatGlobalStats renders the result of CommandRepository.GetGlobalStats
+func formatGlobalStats(stats map[string]interface{}) string {
+	var text strings.Builder
+	text.WriteString("<b>🌐 Global Bot Stats</b>\n\n")
+	fmt.Fprintf(&text, "• Users: <b>%v</b>\n", stats["total_users"])
+	fmt.Fprintf(&text, "• Commands: <b>%v</b>\n", stats["total_commands"])
+	fmt.Fprintf(&text, "• Torrents added: <b>%v</b>\n", stats["total_torrents"])
+	fmt.Fprintf(&text, "• Downloads: <b>%v</b>\n", stats["total_downloads"])
+	if top, _ := stats["top_command"].(string); top != "" {
+		fmt.Fprintf(&text, "• Top command: <code>/%s</code> (%v uses)\n", html.EscapeString(top), stats["top_command_uses"])
+	} else {
+		text.WriteString("• Top command: none yet\n")
+	}
+	return strings.TrimRight(text.String(), "\n")
+}
+
+// handleMetricsCommand handles the /metrics command (moderators and superadmins)
+func (b *Bot) handleMetricsCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "metrics")
+
+		if !role.CanModerate() {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, b.localize(chatID, "error.moderator_only"), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "metrics", update.Message.Text, startTime, false, "Unauthorized - not moderator", 0)
+			return
+		}
+
+		if b.metrics == nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>[ERROR]</b> Metrics are not available.", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "metrics", update.Message.Text, startTime, false, "Metrics not configured", 0)
+			return
+		}
+
+		text := formatMetricsSummary(b.metrics.Summary(), time.Now())
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "metrics", update.Message.Text, startTime, true, "", len(text))
+	})
+}
+
+// handleRefreshMetricsCommand handles the /refreshmetrics command (superadmins). It
+// scrapes Real-Debrid immediately instead of waiting for the metrics cache to expire.
+func (b *Bot) handleRefreshMetricsCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "refreshmetrics")
+
+		if !role.IsSuperAdmin() {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, b.localize(chatID, "error.superadmin_only"), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "refreshmetrics", update.Message.Text, startTime, false, "Unauthorized - not superadmin", 0)
+			return
+		}
+
+		if b.metrics == nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>[ERROR]</b> Metrics are not available.", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "refreshmetrics", update.Message.Text, startTime, false, "Metrics not configured", 0)
+			return
+		}
+
+		took := b.metrics.ForceScrape()
+		text := fmt.Sprintf("<b>[OK]</b> Metrics refreshed in %s.\n\n%s", took.Round(time.Millisecond), formatMetricsSummary(b.metrics.Summary(), time.Now()))
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "refreshmetrics", update.Message.Text, startTime, true, "", len(text))
+	})
+}
+
+// formatMetricsSummary renders the cached Prometheus metric values for /metrics
+func formatMetricsSummary(s web.MetricsSummary, now time.Time) string {
+	var text strings.Builder
+	text.WriteString("<b>📈 Metrics Summary</b>\n\n")
+	fmt.Fprintf(&text, "• Torrents: <b>%d</b> (%d active)\n", s.TorrentCount, s.ActiveCount)
+	fmt.Fprintf(&text, "• Total size: <b>%s</b>\n", realdebrid.FormatSize(s.TotalSizeBytes))
+	fmt.Fprintf(&text, "• Downloads: <b>%d</b>\n", s.DownloadCount)
+	fmt.Fprintf(&text, "• Fidelity points: <b>%d</b>\n", s.FidelityPoints)
+	if s.PremiumSeconds > 0 {
+		premium := time.Duration(s.PremiumSeconds) * time.Second
+		fmt.Fprintf(&text, "• Premium remaining: <b>%d days, %d hours</b>\n", int(premium.Hours())/24, int(premium.Hours())%24)
+	} else {
+		text.WriteString("• Premium remaining: <b>none</b>\n")
+	}
+	if !s.ScrapedAt.IsZero() {
+		fmt.Fprintf(&text, "\n<i>Cached %s ago</i>", formatDuration(now.Sub(s.ScrapedAt)))
+	}
+	return strings.TrimRight(text.String(), "\n")
+}
+
 // --- Helper Functions ---
 
+// userPKOf returns the internal ID of user, or 0 for updates without a sender such as
+// channel posts
+func userPKOf(user *db.User) int64 {
+	if user == nil {
+		return 0
+	}
+	return user.ID
+}
+
+// displayTime formats t in the configured timezone for replies
+func (b *Bot) displayTime(t time.Time) string {
+	loc := b.location
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format("2006-01-02 15:04 MST")
+}
+
+// downloadDuration returns how long a torrent took from being added to finishing.
+// ok is false while it has not finished.
+func downloadDuration(t *realdebrid.Torrent) (took time.Duration, ok bool) {
+	if t.Ended == nil || t.Ended.IsZero() {
+		return 0, false
+	}
+	return max(t.Ended.Sub(t.Added), 0), true
+}
+
+// formatElapsed renders a download duration; cached torrents finish in seconds
+func formatElapsed(d time.Duration) string {
+	if d < time.Minute {
+		return "under a minute"
+	}
+	return formatDuration(d)
+}
+
+// telegramMaxMessageLength is the most characters Telegram accepts in one message
+const telegramMaxMessageLength = 4096
+
+// messageFooterSeparator separates a reply from the configured footer
+const messageFooterSeparator = "\n\n———\n"
+
+// appendFooter appends footer to text, unless footer is empty or the result would
+// exceed Telegram's message length limit, in which case text is returned unchanged
+func appendFooter(text, footer string) string {
+	if footer == "" {
+		return text
+	}
+	withFooter := text + messageFooterSeparator + footer
+	if utf8.RuneCountInString(withFooter) > telegramMaxMessageLength {
+		return text
+	}
+	return withFooter
+}
+
+// withFooter appends the configured telegram.message_footer to an outgoing reply
+func (b *Bot) withFooter(text string) string {
+	if b.config == nil {
+		return text
+	}
+	return appendFooter(text, b.config.Telegram.MessageFooter)
+}
+
+// duplicateAddScanLimit bounds how many recent torrent activities are checked for a prior add
+const duplicateAddScanLimit = 100
+
+// isDuplicateAdd reports whether the user already added torrentID within the configured
+// window. Real-Debrid returns the existing torrent's ID when a magnet is re-added.
+func (b *Bot) isDuplicateAdd(ctx context.Context, user *db.User, torrentID string) bool {
+	if user == nil {
+		return false
+	}
+	activities, err := b.torrentRepo.GetTorrentActivities(ctx, user.ID, duplicateAddScanLimit)
+	if err != nil {
+		log.Printf("Warning: failed to check for duplicate torrent add: %v", err)
+		return false
+	}
+	window := time.Duration(b.config.App.DuplicateAddWindowHours) * time.Hour
+	return hasPriorAdd(activities, torrentID, time.Now().Add(-window))
+}
+
+// hasPriorAdd reports whether activities contain a successful add of torrentID since the given time
+func hasPriorAdd(activities []db.TorrentActivity, torrentID string, since time.Time) bool {
+	if torrentID == "" {
+		return false
+	}
+	for _, a := range activities {
+		if a.Action == "add" && a.Success && a.TorrentID == torrentID && !a.CreatedAt.Before(since) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatDuplicateAddMessage builds the reply for a magnet that is already in the user's list
+func formatDuplicateAddMessage(torrentID string) string {
+	return fmt.Sprintf(
+		"<b>Torrent Already In Your List</b>\n\n"+
+			"<i>ID:</i> <code>%s</code>\n\n"+
+			"Use <code>/info %s</code> to check its status.",
+		torrentID, torrentID,
+	)
+}
+
+// shortHash truncates a torrent hash for compact display in lists
+func shortHash(hash string) string {
+	const shownChars = 12
+	if len(hash) <= shownChars {
+		return hash
+	}
+	return hash[:shownChars] + "…"
+}
+
+// inputTooLong reports whether input is longer than limit characters; a non-positive limit disables the check
+func inputTooLong(input string, limit int) bool {
+	return limit > 0 && utf8.RuneCountInString(input) > limit
+}
+
+// truncateRunes shortens s to at most n characters without splitting a multi-byte character
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// truncateName shortens a display name to at most n characters, ending in an ellipsis when cut.
+// It counts runes so multi-byte characters and emoji are never split; n <= 0 disables truncation.
+func truncateName(s string, n int) string {
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	if n == 1 {
+		return "…"
+	}
+	return truncateRunes(s, n-1) + "…"
+}
+
+// rejectLongInput replies with an error and logs the command if input exceeds app.max_input_length.
+// Only a bounded prefix of the message is logged. It returns true if the input was rejected.
+func (b *Bot) rejectLongInput(ctx context.Context, update *models.Update, user *db.User, chatID, chatPK int64, messageThreadID int, command, input string, startTime time.Time) bool {
+	limit := b.config.App.MaxInputLength
+	if !inputTooLong(input, limit) {
+		return false
+	}
+
+	text := fmt.Sprintf("<b>[ERROR]</b> Link is too long (%d characters, maximum is %d). Please check that it was pasted correctly.", utf8.RuneCountInString(input), limit)
+	b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+	b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, command, truncateRunes(update.Message.Text, limit), startTime, false, "Input too long", 0)
+	return true
+}
+
+// rejectBlockedHost replies with an error and logs the command if link is excluded by
+// app.blocked_hosts or app.allowed_hosts. It returns true if the link was rejected.
+func (b *Bot) rejectBlockedHost(ctx context.Context, update *models.Update, user *db.User, chatID, chatPK int64, messageThreadID int, command, link string, startTime time.Time) bool {
+	err := b.config.CheckLinkHost(link)
+	if err == nil {
+		return false
+	}
+
+	text := fmt.Sprintf("<b>[ERROR]</b> Cannot unrestrict this link: %s.", html.EscapeString(err.Error()))
+	b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+	b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, command, update.Message.Text, startTime, false, err.Error(), 0)
+	return true
+}
+
 func (b *Bot) sendHTMLMessage(ctx context.Context, chatID int64, messageThreadID int, text string, replyToMessageID int) {
+	b.sendHTMLMessageWithMarkup(ctx, chatID, messageThreadID, text, replyToMessageID, nil)
+}
+
+// sendHTMLMessageWithMarkup is sendHTMLMessage with reply markup such as inline buttons; nil sends none
+func (b *Bot) sendHTMLMessageWithMarkup(ctx context.Context, chatID int64, messageThreadID int, text string, replyToMessageID int, markup models.ReplyMarkup) {
 	params := &bot.SendMessageParams{
-		ChatID:    chatID,
-		Text:      text,
-		ParseMode: models.ParseModeHTML,
+		ChatID:      chatID,
+		Text:        b.withFooter(text),
+		ParseMode:   models.ParseModeHTML,
+		ReplyMarkup: markup,
 	}
 	if messageThreadID != 0 {
 		params.MessageThreadID = messageThreadID
@@ -894,7 +1390,7 @@ func (b *Bot) sendHTMLMessage(ctx context.Context, chatID int64, messageThreadID
 func (b *Bot) sendHTMLMessageWithErr(ctx context.Context, chatID int64, messageThreadID int, text string, replyToMessageID int) error {
 	params := &bot.SendMessageParams{
 		ChatID:    chatID,
-		Text:      text,
+		Text:      b.withFooter(text),
 		ParseMode: models.ParseModeHTML,
 	}
 	if messageThreadID != 0 {
@@ -919,9 +1415,15 @@ func (b *Bot) logCommandHelper(ctx context.Context, user *db.User, chatPK int64,
 	if user == nil {
 		return
 	}
-	if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, command, fullCommand, messageID, messageThreadID, time.Since(startTime).Milliseconds(), success, errorMsg, responseLength); err != nil {
+	duration := time.Since(startTime).Milliseconds()
+	if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, command, fullCommand, messageID, messageThreadID, duration, success, errorMsg, responseLength); err != nil {
 		log.Printf("Warning: failed to log command %s: %v", command, err)
 	}
+	b.audit.Emit(audit.Event{
+		Time: time.Now(), Type: "command", UserID: user.UserID, Username: user.Username, ChatPK: chatPK,
+		ThreadID: messageThreadID, MessageID: messageID, Command: command, Text: fullCommand,
+		Success: success, Error: errorMsg, DurationMs: duration,
+	})
 }
 
 // logActivityHelper logs a general activity to the activity repo
@@ -932,6 +1434,14 @@ func (b *Bot) logActivityHelper(ctx context.Context, user *db.User, chatPK int64
 	if err := b.activityRepo.LogActivity(ctx, "", user.ID, chatPK, user.Username, activityType, command, messageID, messageThreadID, success, errorMsg, metadata); err != nil {
 		log.Printf("Warning: failed to log activity %s: %v", activityType, err)
 	}
+	// Mirror only what app.activity_logging keeps in the database
+	if db.ActivityLogging(b.config.App.ActivityLogging).Persist(success) {
+		b.audit.Emit(audit.Event{
+			Time: time.Now(), Type: string(activityType), UserID: user.UserID, Username: user.Username, ChatPK: chatPK,
+			ThreadID: messageThreadID, MessageID: messageID, Command: command,
+			Success: success, Error: errorMsg, Metadata: metadata,
+		})
+	}
 }
 
 // sendKeptTorrentsList fetches and sends the list of kept torrents to the user.
@@ -966,7 +1476,7 @@ func (b *Bot) sendKeptTorrentsList(ctx context.Context, chatID int64, messageThr
 		if keptBy == "" {
 			keptBy = fmt.Sprintf("User #%d", kt.KeptByID)
 		}
-		item := fmt.Sprintf("<code>%s</code> - %s\n<i>Kept by %s on %s</i>\n\n", html.EscapeString(kt.TorrentID), html.EscapeString(kt.Filename), html.EscapeString(keptBy), keptAt)
+		item := fmt.Sprintf("<code>%s</code> - %s\n<i>Kept by %s on %s</i>\n\n", html.EscapeString(kt.TorrentID), html.EscapeString(truncateName(kt.Filename, b.config.App.MaxFilenameDisplay)), html.EscapeString(keptBy), keptAt)
 		if text.Len()+len(item) > 4000 {
 			b.sendHTMLMessage(ctx, chatID, messageThreadID, text.String(), messageID)
 			text.Reset()
@@ -986,25 +1496,21 @@ func (b *Bot) sendKeptTorrentsList(ctx context.Context, chatID int64, messageThr
 
 // handleKeepCommand handles the /keep command
 func (b *Bot) handleKeepCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
-	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, isSuperAdmin bool, user *db.User) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
 		startTime := time.Now()
 		b.middleware.LogCommand(update, "keep")
 
 		parts := strings.Fields(update.Message.Text)
 		if len(parts) < 2 {
 			b.sendKeptTorrentsList(ctx, chatID, messageThreadID, update.Message.ID, false)
-			if user != nil {
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "keep", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), true, "", 0); err != nil {
-					log.Printf("Warning: failed to log keep list: %v", err)
-				}
-			}
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "keep", update.Message.Text, startTime, true, "", 0)
 			return
 		}
 		torrentID := parts[1]
 
 		// Determine the keep limit (0 = unlimited for admins)
 		maxKept := 0
-		if !isSuperAdmin {
+		if !role.IsSuperAdmin() {
 			maxKept = b.config.App.MaxKeptTorrents
 		}
 
@@ -1012,76 +1518,48 @@ func (b *Bot) handleKeepCommand(ctx context.Context, _ *bot.Bot, update *models.
 		torrent, err := b.rdClient.GetTorrentInfo(torrentID)
 		if err != nil {
 			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Could not retrieve torrent info: %s", html.EscapeString(err.Error())), update.Message.ID)
-			if user != nil {
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "keep", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), false, err.Error(), 0); err != nil {
-					log.Printf("Warning: failed to log keep command error: %v", err)
-				}
-			}
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "keep", update.Message.Text, startTime, false, err.Error(), 0)
 			return
 		}
 
 		// Mark torrent as kept (limit is enforced atomically inside the transaction)
 		if err := b.keptRepo.KeepTorrent(ctx, torrentID, torrent.Filename, int64(user.UserID), maxKept); err != nil {
 			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Failed to keep torrent: %s", html.EscapeString(err.Error())), update.Message.ID)
-			if user != nil {
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "keep", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), false, err.Error(), 0); err != nil {
-					log.Printf("Warning: failed to log keep command error: %v", err)
-				}
-			}
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "keep", update.Message.Text, startTime, false, err.Error(), 0)
 			return
 		}
 
 		b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[OK]</b> Torrent <code>%s</code> has been marked as kept and will be excluded from auto-delete.", html.EscapeString(torrentID)), update.Message.ID)
 
-		if user != nil {
-			if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "keep", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), true, "", 0); err != nil {
-				log.Printf("Warning: failed to log keep command success: %v", err)
-			}
-			if err := b.activityRepo.LogActivity(ctx, "", user.ID, chatPK, user.Username, db.ActivityTypeTorrentKeep, "keep", int64(update.Message.ID), messageThreadID, true, "", map[string]any{"torrent_id": torrentID}); err != nil {
-				log.Printf("Warning: failed to log keep command activity: %v", err)
-			}
-		}
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "keep", update.Message.Text, startTime, true, "", 0)
+		b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeTorrentKeep, "keep", true, "", map[string]any{"torrent_id": torrentID})
 	})
 }
 
 // handleUnkeepCommand handles the /unkeep command
 func (b *Bot) handleUnkeepCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
-	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, isSuperAdmin bool, user *db.User) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
 		startTime := time.Now()
 		b.middleware.LogCommand(update, "unkeep")
 
 		parts := strings.Fields(update.Message.Text)
 		if len(parts) < 2 {
 			b.sendKeptTorrentsList(ctx, chatID, messageThreadID, update.Message.ID, true)
-			if user != nil {
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "unkeep", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), true, "", 0); err != nil {
-					log.Printf("Warning: failed to log unkeep list: %v", err)
-				}
-			}
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "unkeep", update.Message.Text, startTime, true, "", 0)
 			return
 		}
 		torrentID := parts[1]
 
 		// Remove keep mark from torrent
-		if err := b.keptRepo.UnkeepTorrent(ctx, torrentID, int64(user.UserID), isSuperAdmin); err != nil {
+		if err := b.keptRepo.UnkeepTorrent(ctx, torrentID, int64(user.UserID), role.IsSuperAdmin()); err != nil {
 			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Failed to unkeep torrent: %s", html.EscapeString(err.Error())), update.Message.ID)
-			if user != nil {
-				if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "unkeep", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), false, err.Error(), 0); err != nil {
-					log.Printf("Warning: failed to log unkeep command error: %v", err)
-				}
-			}
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "unkeep", update.Message.Text, startTime, false, err.Error(), 0)
 			return
 		}
 
 		b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[OK]</b> Torrent <code>%s</code> is no longer marked as kept and will be subject to auto-delete.", html.EscapeString(torrentID)), update.Message.ID)
 
-		if user != nil {
-			if err := b.commandRepo.LogCommand(ctx, user.ID, chatPK, user.Username, "unkeep", update.Message.Text, int64(update.Message.ID), messageThreadID, time.Since(startTime).Milliseconds(), true, "", 0); err != nil {
-				log.Printf("Warning: failed to log unkeep command success: %v", err)
-			}
-			if err := b.activityRepo.LogActivity(ctx, "", user.ID, chatPK, user.Username, db.ActivityTypeTorrentUnkeep, "unkeep", int64(update.Message.ID), messageThreadID, true, "", map[string]any{"torrent_id": torrentID}); err != nil {
-				log.Printf("Warning: failed to log unkeep command activity: %v", err)
-			}
-		}
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "unkeep", update.Message.Text, startTime, true, "", 0)
+		b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeTorrentUnkeep, "unkeep", true, "", map[string]any{"torrent_id": torrentID})
 	})
 }
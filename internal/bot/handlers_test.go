@@ -0,0 +1,434 @@
+package bot
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/crazyuploader/rdctl-bot/internal/config"
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/crazyuploader/rdctl-bot/internal/web"
+)
+
+// TestShortHash verifies hashes are truncated for /list and short values pass through.
+func TestShortHash(t *testing.T) {
+	tests := []struct {
+		name string
+		hash string
+		want string
+	}{
+		{"full sha1 hash", "c12fe1c06bba254a9dc9f519b335aa7c1367a88a", "c12fe1c06bba…"},
+		{"exactly twelve", "c12fe1c06bba", "c12fe1c06bba"},
+		{"short", "abc", "abc"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shortHash(tt.hash); got != tt.want {
+				t.Errorf("shortHash(%q) = %q, want %q", tt.hash, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestHasPriorAdd verifies an ID returned by AddMagnet is matched against the user's recent adds.
+func TestHasPriorAdd(t *testing.T) {
+	now := time.Now()
+	since := now.Add(-24 * time.Hour)
+	activities := []db.TorrentActivity{
+		{TorrentID: "KNOWN", Action: "add", Success: true, CreatedAt: now.Add(-time.Hour)},
+		{TorrentID: "OLD", Action: "add", Success: true, CreatedAt: now.Add(-48 * time.Hour)},
+		{TorrentID: "FAILED", Action: "add", Success: false, CreatedAt: now.Add(-time.Hour)},
+		{TorrentID: "DELETED", Action: "delete", Success: true, CreatedAt: now.Add(-time.Hour)},
+	}
+
+	tests := []struct {
+		name      string
+		torrentID string
+		want      bool
+	}{
+		{"known recent add", "KNOWN", true},
+		{"outside window", "OLD", false},
+		{"failed add", "FAILED", false},
+		{"non-add action", "DELETED", false},
+		{"new torrent", "NEW", false},
+		{"empty id", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasPriorAdd(activities, tt.torrentID, since); got != tt.want {
+				t.Errorf("hasPriorAdd(%q) = %v, want %v", tt.torrentID, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFormatGlobalStats verifies totals and the top command are rendered.
+func TestFormatGlobalStats(t *testing.T) {
+	text := formatGlobalStats(map[string]interface{}{
+		"total_users":      int64(3),
+		"total_commands":   int64(70),
+		"total_torrents":   int64(9),
+		"total_downloads":  int64(13),
+		"top_command":      "list",
+		"top_command_uses": int64(31),
+	})
+	for _, want := range []string{"<b>3</b>", "<b>70</b>", "<b>9</b>", "<b>13</b>", "<code>/list</code> (31 uses)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("formatGlobalStats() missing %q in %q", want, text)
+		}
+	}
+
+	empty := formatGlobalStats(map[string]interface{}{"top_command": ""})
+	if !strings.Contains(empty, "none yet") {
+		t.Errorf("formatGlobalStats() without commands = %q, want \"none yet\"", empty)
+	}
+}
+
+// TestFormatMetricsSummary verifies the /metrics text reflects the cached collector values.
+func TestFormatMetricsSummary(t *testing.T) {
+	now := time.Now()
+	text := formatMetricsSummary(web.MetricsSummary{
+		TorrentCount:   12,
+		ActiveCount:    2,
+		TotalSizeBytes: 3 << 30,
+		DownloadCount:  40,
+		FidelityPoints: 1500,
+		PremiumSeconds: int64((3*24*time.Hour + 5*time.Hour) / time.Second),
+		ScrapedAt:      now.Add(-2 * time.Minute),
+	}, now)
+	for _, want := range []string{"<b>12</b> (2 active)", realdebrid.FormatSize(3 << 30), "<b>40</b>", "<b>1500</b>", "3 days, 5 hours", "Cached 2 minutes ago"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("formatMetricsSummary() missing %q in %q", want, text)
+		}
+	}
+
+	if empty := formatMetricsSummary(web.MetricsSummary{}, now); !strings.Contains(empty, "<b>none</b>") || strings.Contains(empty, "Cached") {
+		t.Errorf("formatMetricsSummary() before any scrape = %q", empty)
+	}
+}
+
+// TestInputTooLong verifies the length guard counts characters and can be disabled.
+func TestInputTooLong(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		limit int
+		want  bool
+	}{
+		{"under limit", "magnet:?xt=urn:btih:abc", 64, false},
+		{"at limit", strings.Repeat("a", 10), 10, false},
+		{"over limit", strings.Repeat("a", 11), 10, true},
+		{"multi-byte counted as characters", strings.Repeat("é", 10), 10, false},
+		{"disabled", strings.Repeat("a", 100), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inputTooLong(tt.input, tt.limit); got != tt.want {
+				t.Errorf("inputTooLong(len %d, %d) = %v, want %v", len(tt.input), tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTruncateRunes verifies logged prefixes stay valid UTF-8.
+func TestTruncateRunes(t *testing.T) {
+	if got := truncateRunes("héllo", 2); got != "hé" {
+		t.Errorf("truncateRunes() = %q, want %q", got, "hé")
+	}
+	if got := truncateRunes("short", 10); got != "short" {
+		t.Errorf("truncateRunes() = %q, want %q", got, "short")
+	}
+}
+
+// TestFormatLinkCheck verifies supported and unsupported links are reported with their details.
+func TestFormatLinkCheck(t *testing.T) {
+	ok := formatLinkCheck(&realdebrid.LinkCheck{Host: "example.com", Filename: "a<b>.mkv", Filesize: 2048, Supported: 1})
+	for _, want := range []string{"[OK]", "a&lt;b&gt;.mkv", "2.00 KB", "example.com"} {
+		if !strings.Contains(ok, want) {
+			t.Errorf("formatLinkCheck() missing %q in %q", want, ok)
+		}
+	}
+
+	unsupported := formatLinkCheck(&realdebrid.LinkCheck{Host: "example.com"})
+	if !strings.Contains(unsupported, "not supported") || strings.Contains(unsupported, "Size") {
+		t.Errorf("formatLinkCheck() unsupported = %q", unsupported)
+	}
+}
+
+// TestTruncateName verifies names are cut to n characters with an ellipsis without splitting runes.
+func TestTruncateName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		n    int
+		want string
+	}{
+		{"short ascii", "movie.mkv", 80, "movie.mkv"},
+		{"exact length", "abcde", 5, "abcde"},
+		{"long ascii", "abcdefghij", 5, "abcd…"},
+		{"accented", "ééééééé", 4, "ééé…"},
+		{"cjk", "日本語のファイル名", 5, "日本語の…"},
+		{"emoji", "🎬🎬🎬🎬🎬🎬", 3, "🎬🎬…"},
+		{"width one", "abc", 1, "…"},
+		{"disabled", "abcdefghij", 0, "abcdefghij"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateName(tt.in, tt.n)
+			if got != tt.want {
+				t.Errorf("truncateName(%q, %d) = %q, want %q", tt.in, tt.n, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("truncateName(%q, %d) returned invalid UTF-8", tt.in, tt.n)
+			}
+		})
+	}
+}
+
+// blockingUserClient is a RealDebridClient whose GetUser blocks until released, counting upstream calls.
+type blockingUserClient struct {
+	RealDebridClient
+	calls   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (c *blockingUserClient) GetUser() (*realdebrid.User, error) {
+	if c.calls.Add(1) == 1 {
+		close(c.started)
+	}
+	<-c.release
+	return &realdebrid.User{Username: "rduser"}, nil
+}
+
+// TestGetRDUser_CoalescesConcurrentCalls verifies concurrent /status lookups share one upstream GetUser call.
+func TestGetRDUser_CoalescesConcurrentCalls(t *testing.T) {
+	client := &blockingUserClient{started: make(chan struct{}), release: make(chan struct{})}
+	b := &Bot{rdClient: client}
+
+	var wg sync.WaitGroup
+	results := make([]*realdebrid.User, 2)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			user, err := b.getRDUser()
+			if err != nil {
+				t.Errorf("getRDUser() error = %v", err)
+			}
+			results[i] = user
+		}()
+		if i == 0 {
+			<-client.started
+		}
+	}
+
+	// Give the second caller time to join the in-flight call before it completes
+	time.Sleep(50 * time.Millisecond)
+	close(client.release)
+	wg.Wait()
+
+	if got := client.calls.Load(); got != 1 {
+		t.Errorf("upstream GetUser calls = %d, want 1", got)
+	}
+	for i, user := range results {
+		if user == nil || user.Username != "rduser" {
+			t.Errorf("caller %d got %+v, want rduser", i, user)
+		}
+	}
+}
+
+// TestAppendFooter verifies the footer is appended after a separator and dropped when it would not fit.
+func TestAppendFooter(t *testing.T) {
+	if got := appendFooter("hello", ""); got != "hello" {
+		t.Errorf("appendFooter() with no footer = %q, want %q", got, "hello")
+	}
+	if got, want := appendFooter("hello", "Powered by MyGroup"), "hello"+messageFooterSeparator+"Powered by MyGroup"; got != want {
+		t.Errorf("appendFooter() = %q, want %q", got, want)
+	}
+
+	footer := "Powered by MyGroup"
+	fits := strings.Repeat("é", telegramMaxMessageLength-utf8.RuneCountInString(messageFooterSeparator+footer))
+	if got := appendFooter(fits, footer); utf8.RuneCountInString(got) != telegramMaxMessageLength || !strings.HasSuffix(got, footer) {
+		t.Errorf("footer not appended to a reply that fits exactly (%d runes)", utf8.RuneCountInString(got))
+	}
+	if got := appendFooter(fits+"x", footer); got != fits+"x" {
+		t.Error("footer appended past the message length limit")
+	}
+}
+
+// TestSendHTMLMessage_AppendsFooter verifies replies sent through the central helper carry the footer.
+func TestSendHTMLMessage_AppendsFooter(t *testing.T) {
+	api, requests := newTestTelegramAPI(t)
+	cfg := &config.Config{Telegram: config.TelegramConfig{MessageFooter: "Powered by MyGroup"}}
+	cfg.App.RateLimit = config.RateLimitConfig{MessagesPerSecond: 100, Burst: 10}
+	b := &Bot{api: api, config: cfg, middleware: NewMiddleware(cfg)}
+
+	b.sendHTMLMessage(context.Background(), 1, 0, "<b>[OK]</b> Done", 0)
+
+	reqs := requests()
+	if len(reqs) != 1 {
+		t.Fatalf("got %d requests, want 1", len(reqs))
+	}
+	if !strings.Contains(reqs[0], "Powered by MyGroup") || !strings.Contains(reqs[0], "Done") {
+		t.Errorf("sent message missing reply or footer: %s", reqs[0])
+	}
+}
+
+// TestDownloadDuration verifies the time from add to completion, and that unfinished torrents have none.
+func TestDownloadDuration(t *testing.T) {
+	added := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	ended := added.Add(90 * time.Minute)
+
+	took, ok := downloadDuration(&realdebrid.Torrent{Added: added, Ended: &ended})
+	if !ok || took != 90*time.Minute {
+		t.Errorf("downloadDuration() = (%s, %v), want (1h30m, true)", took, ok)
+	}
+	if _, ok := downloadDuration(&realdebrid.Torrent{Added: added}); ok {
+		t.Error("downloadDuration() ok for a torrent still downloading")
+	}
+	early := added.Add(-time.Second)
+	if took, _ := downloadDuration(&realdebrid.Torrent{Added: added, Ended: &early}); took != 0 {
+		t.Errorf("downloadDuration() with clock skew = %s, want 0", took)
+	}
+
+	if got := formatElapsed(90 * time.Minute); got != "1 hours 30 minutes" {
+		t.Errorf("formatElapsed(90m) = %q, want %q", got, "1 hours 30 minutes")
+	}
+	if got := formatElapsed(20 * time.Second); got != "under a minute" {
+		t.Errorf("formatElapsed(20s) = %q, want %q", got, "under a minute")
+	}
+}
+
+// TestDisplayTime verifies times are shown in the configured timezone, defaulting to UTC.
+func TestDisplayTime(t *testing.T) {
+	ts := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	if got := (&Bot{}).displayTime(ts); got != "2025-03-01 10:00 UTC" {
+		t.Errorf("displayTime() without location = %q", got)
+	}
+	loc := time.FixedZone("CET", 3600)
+	if got := (&Bot{location: loc}).displayTime(ts); got != "2025-03-01 11:00 CET" {
+		t.Errorf("displayTime() in CET = %q", got)
+	}
+}
+
+// TestFormatTorrentInfo_HostAndSplit verifies host and split size are shown only when RD reports them.
+func TestFormatTorrentInfo_HostAndSplit(t *testing.T) {
+	b := &Bot{}
+
+	text := b.formatTorrentInfo(&realdebrid.Torrent{ID: "ABC", Filename: "movie.mkv", Host: "real-debrid.com", Split: 2}, "")
+	for _, want := range []string{"<i>Host:</i> <code>real-debrid.com</code>", "<i>Split:</i> 2 GB parts"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("info missing %q:\n%s", want, text)
+		}
+	}
+
+	text = b.formatTorrentInfo(&realdebrid.Torrent{ID: "ABC", Filename: "movie.mkv"}, "")
+	if strings.Contains(text, "Host:") || strings.Contains(text, "Split:") {
+		t.Errorf("info shows host or split when unset:\n%s", text)
+	}
+}
+
+// TestFormatTorrentInfo_DisplayName verifies a /rename alias is shown as the name, keeping the filename.
+func TestFormatTorrentInfo_DisplayName(t *testing.T) {
+	b := &Bot{}
+	text := b.formatTorrentInfo(&realdebrid.Torrent{ID: "ABC", Filename: "Some.Release.2160p.mkv"}, "Holiday <film>")
+	for _, want := range []string{"<i>Name:</i> <code>Holiday &lt;film&gt;</code>", "<i>File:</i> <code>Some.Release.2160p.mkv</code>"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("info missing %q:\n%s", want, text)
+		}
+	}
+}
+
+// TestDedupeDownloads verifies repeats of a link collapse into the most recent entry at the first position.
+func TestDedupeDownloads(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 5, d, 0, 0, 0, 0, time.UTC) }
+	downloads := []realdebrid.Download{
+		{ID: "1", Link: "https://h.example/a", Filename: "a.mkv", Generated: day(3)},
+		{ID: "2", Link: "https://h.example/b", Filename: "b.mkv", Generated: day(2)},
+		{ID: "3", Link: "https://h.example/a", Filename: "a.mkv", Generated: day(5)},
+		{ID: "4", Filename: "c.mkv", Generated: day(1)},
+		{ID: "5", Filename: "c.mkv", Generated: day(4)},
+		{ID: "6", Link: "https://h.example/d", Filename: "b.mkv", Generated: day(1)},
+	}
+
+	var ids []string
+	for _, d := range dedupeDownloads(downloads) {
+		ids = append(ids, d.ID)
+	}
+	if want := []string{"3", "2", "5", "6"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("dedupeDownloads() IDs = %v, want %v", ids, want)
+	}
+}
+
+// TestParseDownloadsArgs verifies the /downloads arguments and their errors.
+func TestParseDownloadsArgs(t *testing.T) {
+	opts, err := parseDownloadsArgs([]string{"Host=RapidGator.net", "sort=SIZE"})
+	if err != nil {
+		t.Fatalf("parseDownloadsArgs() error = %v", err)
+	}
+	if opts != (downloadsOptions{host: "rapidgator.net", sortBy: "size"}) {
+		t.Errorf("parseDownloadsArgs() = %+v", opts)
+	}
+	if opts, err := parseDownloadsArgs(nil); err != nil || opts != (downloadsOptions{}) {
+		t.Errorf("parseDownloadsArgs(nil) = %+v, %v", opts, err)
+	}
+	for _, bad := range []string{"sort=name", "host=", "limit=5", "rapidgator"} {
+		if _, err := parseDownloadsArgs([]string{bad}); err == nil {
+			t.Errorf("parseDownloadsArgs(%q) succeeded", bad)
+		}
+	}
+}
+
+// TestApplyDownloadsOptions verifies the host filter is case-insensitive and the sorts
+// order by size and date.
+func TestApplyDownloadsOptions(t *testing.T) {
+	day := func(n int) time.Time { return time.Date(2026, 1, n, 0, 0, 0, 0, time.UTC) }
+	downloads := []realdebrid.Download{
+		{ID: "1", Host: "rapidgator.net", Filesize: 300, Generated: day(2)},
+		{ID: "2", Host: "1fichier.com", Filesize: 900, Generated: day(5)},
+		{ID: "3", Host: "RapidGator.net", Filesize: 700, Generated: day(3)},
+		{ID: "4", Host: "mega.nz", Filesize: 100, Generated: day(4)},
+	}
+	ids := func(ds []realdebrid.Download) []string {
+		var out []string
+		for _, d := range ds {
+			out = append(out, d.ID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		opts downloadsOptions
+		want []string
+	}{
+		{"default", downloadsOptions{}, []string{"1", "2", "3", "4"}},
+		{"host", downloadsOptions{host: "rapidgator"}, []string{"1", "3"}},
+		{"size", downloadsOptions{sortBy: "size"}, []string{"2", "3", "1", "4"}},
+		{"date", downloadsOptions{sortBy: "date"}, []string{"2", "4", "3", "1"}},
+		{"host and size", downloadsOptions{host: "rapidgator.net", sortBy: "size"}, []string{"3", "1"}},
+		{"no match", downloadsOptions{host: "uptobox"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ids(applyDownloadsOptions(downloads, tt.opts)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("applyDownloadsOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if downloads[0].ID != "1" || downloads[1].ID != "2" {
+		t.Error("applyDownloadsOptions() reordered its input")
+	}
+}
@@ -0,0 +1,117 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/web"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// healthState records when the bot's subsystems last did their work, for
+// GET /api/health/detailed. A nil healthState records nothing.
+type healthState struct {
+	mu         sync.Mutex
+	lastUpdate time.Time
+	proxy      web.ComponentHealth
+	workers    map[string]time.Time // zero until the worker's first run
+}
+
+// newHealthState creates an empty healthState
+func newHealthState() *healthState {
+	return &healthState{
+		proxy:   web.ComponentHealth{Status: web.HealthUnknown},
+		workers: make(map[string]time.Time),
+	}
+}
+
+// trackUpdates is a Telegram middleware noting when the last update arrived
+func (h *healthState) trackUpdates(next bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, api *bot.Bot, update *models.Update) {
+		h.mu.Lock()
+		h.lastUpdate = time.Now()
+		h.mu.Unlock()
+		next(ctx, api, update)
+	}
+}
+
+// recordIPTest keeps the outcome of the latest outbound IP test
+func (h *healthState) recordIPTest(result IPTestResult, testErr error) {
+	if h == nil {
+		return
+	}
+	state := web.ComponentHealth{Status: web.HealthOK, CheckedAt: time.Now()}
+	switch {
+	case testErr != nil:
+		state.Status, state.Detail = web.HealthDown, testErr.Error()
+	case result.PrimaryErr != nil:
+		state.Status, state.Detail = web.HealthDown, result.PrimaryErr.Error()
+	default:
+		state.Detail = "outbound IP " + result.PrimaryIP
+	}
+	h.mu.Lock()
+	h.proxy = state
+	h.mu.Unlock()
+}
+
+// registerWorker lists a background worker in the health report before its first run
+func (h *healthState) registerWorker(name string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.workers[name]; !ok {
+		h.workers[name] = time.Time{}
+	}
+}
+
+// markWorkerRun records that a background worker just completed a run
+func (h *healthState) markWorkerRun(name string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	h.workers[name] = time.Now()
+	h.mu.Unlock()
+}
+
+// TelegramHealth reports when the last Telegram update was received
+func (b *Bot) TelegramHealth() web.ComponentHealth {
+	b.health.mu.Lock()
+	last := b.health.lastUpdate
+	b.health.mu.Unlock()
+	if last.IsZero() {
+		return web.ComponentHealth{Status: web.HealthUnknown, Detail: "no update received yet"}
+	}
+	return web.ComponentHealth{
+		Status:    web.HealthOK,
+		CheckedAt: last,
+		Detail:    fmt.Sprintf("last update %s ago", formatElapsed(time.Since(last))),
+	}
+}
+
+// ProxyHealth reports the outcome of the latest outbound IP test, from startup or /proxytest
+func (b *Bot) ProxyHealth() web.ComponentHealth {
+	b.health.mu.Lock()
+	defer b.health.mu.Unlock()
+	return b.health.proxy
+}
+
+// WorkerHealth reports when each running background worker last completed a run
+func (b *Bot) WorkerHealth() map[string]web.ComponentHealth {
+	b.health.mu.Lock()
+	defer b.health.mu.Unlock()
+	workers := make(map[string]web.ComponentHealth, len(b.health.workers))
+	for name, ran := range b.health.workers {
+		if ran.IsZero() {
+			workers[name] = web.ComponentHealth{Status: web.HealthUnknown, Detail: "not run yet"}
+			continue
+		}
+		workers[name] = web.ComponentHealth{Status: web.HealthOK, CheckedAt: ran}
+	}
+	return workers
+}
@@ -0,0 +1,34 @@
+package bot
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/web"
+)
+
+// TestHealthState verifies workers are listed before their first run and IP test failures are reported.
+func TestHealthState(t *testing.T) {
+	b := &Bot{health: newHealthState()}
+
+	if got := b.ProxyHealth().Status; got != web.HealthUnknown {
+		t.Errorf("proxy before any test = %q, want unknown", got)
+	}
+	b.health.recordIPTest(IPTestResult{PrimaryErr: errors.New("timeout")}, nil)
+	if got := b.ProxyHealth(); got.Status != web.HealthDown || got.Detail != "timeout" {
+		t.Errorf("proxy after failed test = %+v", got)
+	}
+
+	b.health.registerWorker("auto_delete")
+	if got := b.WorkerHealth()["auto_delete"].Status; got != web.HealthUnknown {
+		t.Errorf("worker before first run = %q, want unknown", got)
+	}
+	b.health.markWorkerRun("auto_delete")
+	if got := b.WorkerHealth()["auto_delete"]; got.Status != web.HealthOK || got.CheckedAt.IsZero() {
+		t.Errorf("worker after run = %+v", got)
+	}
+
+	if got := b.TelegramHealth().Status; got != web.HealthUnknown {
+		t.Errorf("telegram before any update = %q, want unknown", got)
+	}
+}
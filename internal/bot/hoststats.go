@@ -0,0 +1,72 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// normalizeHost reduces a /hoststats argument to the hoster domain recorded with each
+// download, so a pasted link or a "www." prefix matches too
+func normalizeHost(arg string) string {
+	host := strings.ToLower(strings.TrimSpace(arg))
+	if strings.Contains(host, "://") {
+		if u, err := url.Parse(host); err == nil && u.Hostname() != "" {
+			host = u.Hostname()
+		}
+	}
+	host = strings.TrimSuffix(host, "/")
+	return strings.TrimPrefix(host, "www.")
+}
+
+// formatHostStats renders the /hoststats reply, formatting the last download with displayTime
+func formatHostStats(stats db.HostStats, displayTime func(time.Time) string) string {
+	host := html.EscapeString(stats.Host)
+	if stats.Downloads == 0 {
+		return fmt.Sprintf("You haven't unrestricted anything from <code>%s</code> yet.", host)
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "<b>Host Stats:</b> <code>%s</code>\n\n", host)
+	fmt.Fprintf(&text, "<i>Downloads:</i> %d\n", stats.Downloads)
+	fmt.Fprintf(&text, "<i>Total Size:</i> %s\n", realdebrid.FormatSize(stats.TotalBytes))
+	if !stats.LastDownloadAt.IsZero() {
+		fmt.Fprintf(&text, "<i>Last Download:</i> %s\n", html.EscapeString(displayTime(stats.LastDownloadAt)))
+	}
+	return text.String()
+}
+
+// handleHostStatsCommand handles /hoststats <host>, showing how often the user has
+// unrestricted from a hoster and the total size
+func (b *Bot) handleHostStatsCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "hoststats")
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) != 2 || normalizeHost(parts[1]) == "" {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /hoststats &lt;host&gt;", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "hoststats", update.Message.Text, startTime, false, "Missing arguments", 0)
+			return
+		}
+
+		stats, err := b.downloadRepo.GetUserHostStats(ctx, user.ID, normalizeHost(parts[1]))
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Failed to get host stats: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "hoststats", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := formatHostStats(stats, b.displayTime)
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "hoststats", update.Message.Text, startTime, true, "", len(text))
+	})
+}
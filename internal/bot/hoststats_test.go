@@ -0,0 +1,46 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+)
+
+// TestNormalizeHost verifies links, case and a "www." prefix reduce to the bare domain.
+func TestNormalizeHost(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"rapidgator.net", "rapidgator.net"},
+		{"RapidGator.NET", "rapidgator.net"},
+		{"www.1fichier.com", "1fichier.com"},
+		{"https://www.Mega.nz/file/abc#key", "mega.nz"},
+		{"uptobox.com/", "uptobox.com"},
+		{"  ", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeHost(tt.arg); got != tt.want {
+			t.Errorf("normalizeHost(%q) = %q, want %q", tt.arg, got, tt.want)
+		}
+	}
+}
+
+// TestFormatHostStats verifies the totals are shown and a host without downloads gets a note.
+func TestFormatHostStats(t *testing.T) {
+	display := func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04 MST") }
+
+	got := formatHostStats(db.HostStats{Host: "rapidgator.net", Downloads: 3, TotalBytes: 3 << 30, LastDownloadAt: time.Date(2026, 3, 4, 5, 6, 0, 0, time.UTC)}, display)
+	for _, w := range []string{"rapidgator.net", "<i>Downloads:</i> 3", "3.00 GB", "2026-03-04 05:06 UTC"} {
+		if !strings.Contains(got, w) {
+			t.Errorf("missing %q in %q", w, got)
+		}
+	}
+
+	got = formatHostStats(db.HostStats{Host: "mega.nz"}, display)
+	if !strings.Contains(got, "haven't unrestricted anything from <code>mega.nz</code>") {
+		t.Errorf("formatHostStats() without downloads = %q", got)
+	}
+}
@@ -0,0 +1,316 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultLocale is used for chats without a telegram.chat_locales entry and for
+// keys missing from a chat's locale
+const defaultLocale = "en"
+
+// messageCatalog maps a locale to its messages, keyed by message ID.
+// Values are fmt format strings; keys missing from a locale fall back to English.
+var messageCatalog = map[string]map[string]string{
+	"en": {
+		"start.welcome": "<b>Welcome to the Real-Debrid Telegram Bot</b>\n\n" +
+			"This bot helps you manage your Real-Debrid torrents and hoster links.\n\n" +
+			"Your Chat ID is: <code>%d</code>\n\n" +
+			"Use /help to see a list of all available commands.",
+
+		"help.title":                  "🧭 Available Commands",
+		"help.section.torrents":       "🎬 Torrent Management:",
+		"help.section.hoster":         "📦 Hoster Link Management:",
+		"help.section.keep":           "🔒 Keep Management:",
+		"help.section.general":        "⚙️ General Commands:",
+		"help.superadmin_only":        "superadmin only",
+		"help.others_superadmin_only": "other users: superadmin only",
+		"help.moderator_only":         "moderators and superadmins",
+		"help.others_moderator_only":  "other users: moderators and superadmins",
+		"help.list":                   "List all active torrents",
+		"help.add":                    "Add a new torrent via magnet link",
+		"help.inspect":                "Check a magnet link and whether it is cached, without adding it",
+		"help.info":                   "Get detailed information about a torrent",
+		"help.fileprogress":           "Show which selected files of a torrent are ready",
+		"help.selectall":              "Select all files of a torrent stuck waiting for file selection",
+		"help.reselect":               "Select only the video files or the largest file of a waiting torrent",
+		"help.copy":                   "Re-add a torrent from its hash as a fresh torrent",
+		"help.rename":                 "Show a torrent under a name of your choice in /list and /info",
+		"help.setpref":                "Set your own preference, e.g. which files are selected when you add a torrent",
+		"help.getpref":                "Show your preferences",
+		"help.links":                  "List a finished torrent's links that are still available",
+		"help.failed":                 "Show which selected files of a torrent got no link",
+		"help.retry":                  "Delete a failed torrent and add it again from its original magnet link",
+		"help.top":                    "Show the largest torrents (default 5, up to 20)",
+		"help.delete":                 "Delete one or more torrents",
+		"help.subscribe":              "Get notified here when a torrent completes",
+		"help.unsubscribe":            "Stop a completion notification",
+		"help.myqueue":                "List your magnets waiting for a free torrent slot",
+		"help.import":                 "Add every magnet of a .txt file sent with this caption, one per line",
+		"help.unrestrict":             "Unrestrict a hoster link",
+		"help.check":                  "Check if a hoster link is supported and its size, without unrestricting it",
+		"help.dryunrestrict":          "Preview what /unrestrict would give for a link, without using traffic or adding it to your downloads",
+		"help.fetch":                  "Unrestrict a link and send the file here (up to 50 MB)",
+		"help.downloads":              "List recent downloads",
+		"help.hoststats":              "Show how often you unrestricted from a hoster and the total size",
+		"help.removelink":             "Remove a download from history",
+		"help.keep":                   "Mark a torrent as kept (excluded from auto-delete)",
+		"help.unkeep":                 "Remove keep mark from a torrent",
+		"help.status":                 "Show your Real-Debrid account status",
+		"help.expiry":                 "Show a countdown to the end of the premium time",
+		"help.traffic":                "Show how much of each metered hoster's traffic is used",
+		"help.settings":               "Show the Real-Debrid account settings",
+		"help.setsetting":             "Change a Real-Debrid account setting",
+		"help.stats":                  "Show torrent/download counts and combined size",
+		"help.globalstats":            "Show usage totals across all users",
+		"help.metrics":                "Show the cached Real-Debrid metrics summary",
+		"help.refreshmetrics":         "Refresh the Real-Debrid metrics now instead of waiting for the cache",
+		"help.capacity":               "Show free torrent slots and whether links can be generated",
+		"help.limits":                 "Show the rate limit settings and current usage",
+		"help.security":               "Show recent unauthorized attempts under your ID",
+		"help.dashboard":              "Get a temporary link to the web dashboard",
+		"help.autodelete":             "Auto-delete torrents older than X days",
+		"help.autodelete_torrent":     "Delete a torrent after the given number of hours",
+		"help.canceldelete":           "Cancel a scheduled torrent deletion",
+		"help.purgeuser":              "Permanently delete all data stored about a user",
+		"help.perf":                   "Show average, p95 and max execution time of a command",
+		"help.notifytest":             "Send a test notification to check delivery to this chat",
+		"help.proxytest":              "Re-run the outbound IP and proxy checks",
+		"help.token":                  "Show the Real-Debrid token type and the scopes it holds",
+		"help.debug":                  "Turn debug logging on or off without restarting",
+		"help.pinstatus":              "Pin a live queue summary in this chat, or stop it",
+		"help.help":                   "Display this help message",
+		"error.unauthorized":          "[UNAUTHORIZED]\n\nYou are not authorized to use this bot.\n\nYour User ID is: <code>%d</code>\nChat ID: <code>%d</code>\n\nPlease contact the administrator to add your User ID to the super admin list or add this chat to the allowed chats list.",
+		"error.unauthorized_callback": "You are not authorized to use this bot.",
+		"error.superadmin_only":       "<b>[ERROR]</b> Access Denied. This command is for superadmins only.",
+		"error.moderator_only":        "<b>[ERROR]</b> Access Denied. This command is for moderators and superadmins only.",
+		"error.internal":              "<b>[ERROR]</b> Something went wrong while processing your request. Please try again later.",
+		"error.internal_callback":     "Something went wrong. Please try again later.",
+	},
+	"es": {
+		"start.welcome": "<b>Bienvenido al bot de Real-Debrid para Telegram</b>\n\n" +
+			"Este bot te ayuda a gestionar tus torrents y enlaces de hosters de Real-Debrid.\n\n" +
+			"El ID de tu chat es: <code>%d</code>\n\n" +
+			"Usa /help para ver la lista de todos los comandos disponibles.",
+
+		"help.title":                  "🧭 Comandos disponibles",
+		"help.section.torrents":       "🎬 Gestión de torrents:",
+		"help.section.hoster":         "📦 Gestión de enlaces de hosters:",
+		"help.section.keep":           "🔒 Gestión de torrents conservados:",
+		"help.section.general":        "⚙️ Comandos generales:",
+		"help.superadmin_only":        "solo superadmins",
+		"help.others_superadmin_only": "otros usuarios: solo superadmins",
+		"help.moderator_only":         "moderadores y superadmins",
+		"help.others_moderator_only":  "otros usuarios: moderadores y superadmins",
+		"help.list":                   "Lista todos los torrents activos",
+		"help.add":                    "Añade un torrent nuevo mediante un enlace magnet",
+		"help.inspect":                "Comprueba un enlace magnet y si está en caché, sin añadirlo",
+		"help.info":                   "Muestra información detallada de un torrent",
+		"help.fileprogress":           "Muestra qué archivos seleccionados de un torrent están listos",
+		"help.selectall":              "Selecciona todos los archivos de un torrent atascado esperando la selección",
+		"help.reselect":               "Selecciona solo los vídeos o el archivo más grande de un torrent en espera",
+		"help.copy":                   "Vuelve a añadir un torrent a partir de su hash como uno nuevo",
+		"help.rename":                 "Muestra un torrent con el nombre que elijas en /list y /info",
+		"help.setpref":                "Define una preferencia propia, p. ej. qué archivos se seleccionan al añadir un torrent",
+		"help.getpref":                "Muestra tus preferencias",
+		"help.links":                  "Lista los enlaces de un torrent terminado que siguen disponibles",
+		"help.failed":                 "Muestra qué archivos seleccionados de un torrent no tienen enlace",
+		"help.retry":                  "Elimina un torrent fallido y lo vuelve a añadir con su enlace magnet original",
+		"help.top":                    "Muestra los torrents más grandes (5 por defecto, hasta 20)",
+		"help.delete":                 "Elimina uno o varios torrents",
+		"help.subscribe":              "Recibe un aviso aquí cuando un torrent termine",
+		"help.unsubscribe":            "Cancela un aviso de finalización",
+		"help.myqueue":                "Muestra tus magnets a la espera de un hueco libre para torrents",
+		"help.import":                 "Añade todos los magnets de un archivo .txt enviado con este pie, uno por línea",
+		"help.unrestrict":             "Desbloquea un enlace de hoster",
+		"help.check":                  "Comprueba si un enlace de hoster es compatible y su tamaño, sin desbloquearlo",
+		"help.dryunrestrict":          "Muestra lo que daría /unrestrict para un enlace, sin gastar tráfico ni añadirlo a tus descargas",
+		"help.fetch":                  "Desbloquea un enlace y envía el archivo aquí (hasta 50 MB)",
+		"help.downloads":              "Lista las descargas recientes",
+		"help.hoststats":              "Muestra cuántas veces desbloqueaste de un servidor y el tamaño total",
+		"help.removelink":             "Elimina una descarga del historial",
+		"help.keep":                   "Marca un torrent como conservado (excluido del borrado automático)",
+		"help.unkeep":                 "Quita la marca de conservado de un torrent",
+		"help.status":                 "Muestra el estado de tu cuenta de Real-Debrid",
+		"help.expiry":                 "Muestra la cuenta atrás hasta el fin del tiempo premium",
+		"help.traffic":                "Muestra cuánto tráfico de cada hoster limitado se ha usado",
+		"help.settings":               "Muestra los ajustes de la cuenta de Real-Debrid",
+		"help.setsetting":             "Cambia un ajuste de la cuenta de Real-Debrid",
+		"help.stats":                  "Muestra el número de torrents y descargas y su tamaño total",
+		"help.globalstats":            "Muestra los totales de uso de todos los usuarios",
+		"help.metrics":                "Muestra el resumen de métricas de Real-Debrid en caché",
+		"help.refreshmetrics":         "Actualiza ahora las métricas de Real-Debrid sin esperar a la caché",
+		"help.capacity":               "Muestra los huecos de torrents libres y si se pueden generar enlaces",
+		"help.limits":                 "Muestra la configuración y el uso actual del límite de mensajes",
+		"help.security":               "Muestra los intentos no autorizados recientes con tu ID",
+		"help.dashboard":              "Obtén un enlace temporal al panel web",
+		"help.autodelete":             "Borra automáticamente los torrents con más de X días",
+		"help.autodelete_torrent":     "Elimina un torrent pasado el número de horas indicado",
+		"help.canceldelete":           "Cancela la eliminación programada de un torrent",
+		"help.purgeuser":              "Elimina de forma permanente todos los datos guardados de un usuario",
+		"help.perf":                   "Muestra el tiempo de ejecución medio, p95 y máximo de un comando",
+		"help.notifytest":             "Envía una notificación de prueba para comprobar la entrega en este chat",
+		"help.proxytest":              "Vuelve a comprobar la IP de salida y el proxy",
+		"help.token":                  "Muestra el tipo de token de Real-Debrid y sus permisos",
+		"help.debug":                  "Activa o desactiva los registros de depuración sin reiniciar",
+		"help.pinstatus":              "Fija un resumen de la cola en este chat, o lo detiene",
+		"help.help":                   "Muestra este mensaje de ayuda",
+		"error.unauthorized":          "[NO AUTORIZADO]\n\nNo estás autorizado para usar este bot.\n\nTu ID de usuario es: <code>%d</code>\nID del chat: <code>%d</code>\n\nPide al administrador que añada tu ID de usuario a la lista de superadmins o este chat a la lista de chats permitidos.",
+		"error.unauthorized_callback": "No estás autorizado para usar este bot.",
+		"error.superadmin_only":       "<b>[ERROR]</b> Acceso denegado. Este comando es solo para superadmins.",
+		"error.moderator_only":        "<b>[ERROR]</b> Acceso denegado. Este comando es solo para moderadores y superadmins.",
+		"error.internal":              "<b>[ERROR]</b> Algo salió mal al procesar tu solicitud. Inténtalo de nuevo más tarde.",
+		"error.internal_callback":     "Algo salió mal. Inténtalo de nuevo más tarde.",
+	},
+}
+
+// translate returns the message for key in locale, formatted with args.
+// Unknown locales and missing keys fall back to English; an unknown key is returned as-is.
+func translate(locale, key string, args ...any) string {
+	msg, ok := messageCatalog[strings.ToLower(locale)][key]
+	if !ok {
+		msg, ok = messageCatalog[defaultLocale][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// isKnownLocale reports whether the catalog has messages for locale
+func isKnownLocale(locale string) bool {
+	_, ok := messageCatalog[strings.ToLower(locale)]
+	return ok
+}
+
+// localeFor returns the locale configured for chatID, defaulting to English
+func (b *Bot) localeFor(chatID int64) string {
+	if b.config == nil {
+		return defaultLocale
+	}
+	if locale := b.config.ChatLocale(chatID); locale != "" {
+		return locale
+	}
+	return defaultLocale
+}
+
+// localize returns the message for key in chatID's language, formatted with args
+func (b *Bot) localize(chatID int64, key string, args ...any) string {
+	return translate(b.localeFor(chatID), key, args...)
+}
+
+// helpAccess marks which users a /help entry is restricted to
+type helpAccess int
+
+const (
+	helpEveryone helpAccess = iota
+	helpSuperadmin
+	helpOthersSuperadmin // usable by everyone, superadmins can also target other users
+	helpModerator
+	helpOthersModerator // usable by everyone, moderators and superadmins can also target other users
+)
+
+// helpEntry is one command line in /help
+type helpEntry struct {
+	usage  string // command and arguments, HTML-escaped
+	key    string // catalog key of the description
+	access helpAccess
+}
+
+// helpSections is the layout of /help: each section title key with its commands
+var helpSections = []struct {
+	title   string
+	entries []helpEntry
+}{
+	{"help.section.torrents", []helpEntry{
+		{"/list", "help.list", helpEveryone},
+		{"/add &lt;magnet&gt;", "help.add", helpEveryone},
+		{"/inspect &lt;magnet&gt;", "help.inspect", helpEveryone},
+		{"/info &lt;id&gt;", "help.info", helpEveryone},
+		{"/fileprogress &lt;id&gt;", "help.fileprogress", helpEveryone},
+		{"/selectall &lt;id&gt;", "help.selectall", helpEveryone},
+		{"/reselect &lt;id&gt; all|video|largest", "help.reselect", helpEveryone},
+		{"/copy &lt;id&gt;", "help.copy", helpEveryone},
+		{"/rename &lt;id&gt; &lt;name&gt;", "help.rename", helpEveryone},
+		{"/setpref fileselect all|video|largest|default", "help.setpref", helpEveryone},
+		{"/getpref", "help.getpref", helpEveryone},
+		{"/links &lt;id&gt;", "help.links", helpEveryone},
+		{"/failed &lt;id&gt;", "help.failed", helpEveryone},
+		{"/retry &lt;id&gt;", "help.retry", helpEveryone},
+		{"/top [count]", "help.top", helpEveryone},
+		{"/delete &lt;id&gt; [id...]", "help.delete", helpSuperadmin},
+		{"/subscribe &lt;id&gt;", "help.subscribe", helpEveryone},
+		{"/unsubscribe &lt;id&gt;", "help.unsubscribe", helpEveryone},
+		{"/myqueue", "help.myqueue", helpEveryone},
+		{"/import (caption of a .txt file)", "help.import", helpSuperadmin},
+	}},
+	{"help.section.hoster", []helpEntry{
+		{"/unrestrict &lt;link&gt;", "help.unrestrict", helpEveryone},
+		{"/check &lt;link&gt;", "help.check", helpEveryone},
+		{"/dryunrestrict &lt;link&gt;", "help.dryunrestrict", helpEveryone},
+		{"/fetch &lt;link&gt;", "help.fetch", helpEveryone},
+		{"/downloads [host=&lt;host&gt;] [sort=size|date]", "help.downloads", helpEveryone},
+		{"/hoststats &lt;host&gt;", "help.hoststats", helpEveryone},
+		{"/removelink &lt;id&gt;", "help.removelink", helpSuperadmin},
+	}},
+	{"help.section.keep", []helpEntry{
+		{"/keep &lt;id&gt;", "help.keep", helpEveryone},
+		{"/unkeep &lt;id&gt;", "help.unkeep", helpEveryone},
+	}},
+	{"help.section.general", []helpEntry{
+		{"/status", "help.status", helpEveryone},
+		{"/expiry", "help.expiry", helpEveryone},
+		{"/traffic", "help.traffic", helpEveryone},
+		{"/settings", "help.settings", helpModerator},
+		{"/setsetting &lt;name&gt; &lt;value&gt;", "help.setsetting", helpSuperadmin},
+		{"/stats", "help.stats", helpEveryone},
+		{"/globalstats", "help.globalstats", helpModerator},
+		{"/metrics", "help.metrics", helpModerator},
+		{"/refreshmetrics", "help.refreshmetrics", helpSuperadmin},
+		{"/capacity", "help.capacity", helpEveryone},
+		{"/limits", "help.limits", helpSuperadmin},
+		{"/security [user_id]", "help.security", helpOthersModerator},
+		{"/dashboard", "help.dashboard", helpEveryone},
+		{"/autodelete &lt;days&gt;", "help.autodelete", helpSuperadmin},
+		{"/autodelete &lt;id&gt; &lt;hours&gt;", "help.autodelete_torrent", helpSuperadmin},
+		{"/canceldelete &lt;id&gt;", "help.canceldelete", helpSuperadmin},
+		{"/purgeuser &lt;user_id&gt;", "help.purgeuser", helpSuperadmin},
+		{"/perf &lt;command&gt; [hours]", "help.perf", helpSuperadmin},
+		{"/notifytest", "help.notifytest", helpEveryone},
+		{"/proxytest", "help.proxytest", helpModerator},
+		{"/token", "help.token", helpSuperadmin},
+		{"/debug on|off", "help.debug", helpSuperadmin},
+		{"/pinstatus [off]", "help.pinstatus", helpSuperadmin},
+		{"/help", "help.help", helpEveryone},
+	}},
+}
+
+// renderHelp builds the /help message in locale
+func renderHelp(locale string) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "<b>%s</b>\n\n", translate(locale, "help.title"))
+	for i, section := range helpSections {
+		if i > 0 {
+			text.WriteString("\n")
+		}
+		fmt.Fprintf(&text, "<b>%s</b>\n", translate(locale, section.title))
+		for _, e := range section.entries {
+			fmt.Fprintf(&text, "• <code>%s</code> — %s", e.usage, translate(locale, e.key))
+			switch e.access {
+			case helpSuperadmin:
+				fmt.Fprintf(&text, " <i>(%s)</i>", translate(locale, "help.superadmin_only"))
+			case helpOthersSuperadmin:
+				fmt.Fprintf(&text, " <i>(%s)</i>", translate(locale, "help.others_superadmin_only"))
+			case helpModerator:
+				fmt.Fprintf(&text, " <i>(%s)</i>", translate(locale, "help.moderator_only"))
+			case helpOthersModerator:
+				fmt.Fprintf(&text, " <i>(%s)</i>", translate(locale, "help.others_moderator_only"))
+			}
+			text.WriteString("\n")
+		}
+	}
+	return strings.TrimRight(text.String(), "\n")
+}
@@ -0,0 +1,84 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/config"
+)
+
+// TestTranslate_Fallbacks verifies unknown locales and missing keys fall back to English,
+// and unknown keys are returned unchanged.
+func TestTranslate_Fallbacks(t *testing.T) {
+	if got := translate("es", "help.title"); got != "🧭 Comandos disponibles" {
+		t.Errorf("translate(es) = %q", got)
+	}
+	if got := translate("ES", "help.title"); got != "🧭 Comandos disponibles" {
+		t.Errorf("translate(ES) = %q, want locale matching to ignore case", got)
+	}
+	if got := translate("fr", "help.title"); got != "🧭 Available Commands" {
+		t.Errorf("translate(fr) = %q, want English fallback", got)
+	}
+	if got := translate("en", "no.such.key"); got != "no.such.key" {
+		t.Errorf("translate(missing key) = %q, want the key", got)
+	}
+	if got := translate("es", "start.welcome", int64(42)); !strings.Contains(got, "<code>42</code>") {
+		t.Errorf("translate(start.welcome) = %q, want formatted chat ID", got)
+	}
+}
+
+// TestCatalog_SampleLocaleComplete keeps the shipped sample locale in step with English.
+func TestCatalog_SampleLocaleComplete(t *testing.T) {
+	for key, en := range messageCatalog[defaultLocale] {
+		es, ok := messageCatalog["es"][key]
+		if !ok {
+			t.Errorf("es catalog missing %q", key)
+			continue
+		}
+		if strings.Count(es, "%") != strings.Count(en, "%") {
+			t.Errorf("es %q has different format verbs than English", key)
+		}
+	}
+	for _, section := range helpSections {
+		for _, e := range section.entries {
+			if _, ok := messageCatalog[defaultLocale][e.key]; !ok {
+				t.Errorf("help entry %s uses unknown key %q", e.usage, e.key)
+			}
+		}
+	}
+}
+
+// TestLocalize_PerChat verifies the chat's configured locale is used and other chats get English.
+func TestLocalize_PerChat(t *testing.T) {
+	b := &Bot{config: &config.Config{Telegram: config.TelegramConfig{
+		ChatLocales: map[string]string{"-100": "es"},
+	}}}
+
+	if got := b.localize(-100, "error.superadmin_only"); !strings.Contains(got, "Acceso denegado") {
+		t.Errorf("localize(-100) = %q, want Spanish", got)
+	}
+	if got := b.localize(-200, "error.superadmin_only"); !strings.Contains(got, "Access Denied") {
+		t.Errorf("localize(-200) = %q, want English", got)
+	}
+	if got := (&Bot{}).localize(-100, "error.superadmin_only"); !strings.Contains(got, "Access Denied") {
+		t.Errorf("localize without config = %q, want English", got)
+	}
+}
+
+// TestRenderHelp verifies /help is assembled from the catalog in the requested locale.
+func TestRenderHelp(t *testing.T) {
+	en := renderHelp("en")
+	for _, want := range []string{"<b>🧭 Available Commands</b>", "• <code>/delete &lt;id&gt; [id...]</code> — Delete one or more torrents <i>(superadmin only)</i>", "• <code>/help</code> — Display this help message"} {
+		if !strings.Contains(en, want) {
+			t.Errorf("English help missing %q", want)
+		}
+	}
+	if strings.HasSuffix(en, "\n") {
+		t.Error("help ends with a newline")
+	}
+
+	es := renderHelp("es")
+	if !strings.Contains(es, "• <code>/delete &lt;id&gt; [id...]</code> — Elimina uno o varios torrents <i>(solo superadmins)</i>") {
+		t.Errorf("Spanish help = %q", es)
+	}
+}
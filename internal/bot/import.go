@@ -0,0 +1,238 @@
+package bot
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// maxImportFileBytes is the largest magnet list /import downloads
+	maxImportFileBytes = 1 << 20
+
+	// importDetailLines is how many failures, and separately how many invalid lines,
+	// the /import summary lists, keeping it within Telegram's message length limit
+	importDetailLines = 10
+
+	// telegramFileTimeout bounds downloading a file sent to the bot
+	telegramFileTimeout = 30 * time.Second
+)
+
+// telegramFileClient downloads files sent to the bot
+var telegramFileClient = &http.Client{Timeout: telegramFileTimeout}
+
+// importAddInterval is the pause between adds of an /import, to stay clear of RD rate limits
+var importAddInterval = 500 * time.Millisecond
+
+// matchImportDocument matches a document sent with the /import caption
+func matchImportDocument(update *models.Update) bool {
+	return update.Message != nil && update.Message.Document != nil && isCommand(update.Message.Caption, "/import")
+}
+
+// importLineError is a line of a magnet list that is not a valid magnet
+type importLineError struct {
+	line int
+	err  error
+}
+
+// parseMagnetList reads one magnet per line, skipping blank lines and # comments.
+// Invalid lines are returned separately; more than max magnets is an error.
+func parseMagnetList(r io.Reader, max int) ([]string, []importLineError, error) {
+	var magnets []string
+	var invalid []importLineError
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportFileBytes)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, err := parseMagnet(line); err != nil {
+			invalid = append(invalid, importLineError{line: n, err: err})
+			continue
+		}
+		magnets = append(magnets, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read the file: %w", err)
+	}
+	if len(magnets) > max {
+		return nil, nil, fmt.Errorf("the file has %d magnets, the maximum is %d", len(magnets), max)
+	}
+	return magnets, invalid, nil
+}
+
+// importResult is the outcome of adding the magnets of an /import
+type importResult struct {
+	added    []string // IDs of the added torrents
+	failed   []string // error per magnet that could not be added
+	notTried int      // magnets skipped after the active torrent limit was reached
+}
+
+// importMagnets adds magnets one by one, selecting their files and logging each add.
+// It stops once Real-Debrid reports the active torrent limit, as every further add
+// would fail the same way.
+func (b *Bot) importMagnets(ctx context.Context, user *db.User, chatPK int64, magnets []string) importResult {
+	var result importResult
+	filter := b.fileSelectFor(ctx, userPKOf(user))
+	for i, magnet := range magnets {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				result.notTried = len(magnets) - i
+				return result
+			case <-time.After(importAddInterval):
+			}
+		}
+
+		response, err := b.rdClient.AddMagnet(magnet)
+		if err != nil {
+			result.failed = append(result.failed, fmt.Sprintf("<code>%s</code>: %s", html.EscapeString(truncateName(queuedMagnetName(magnet), b.config.App.MaxFilenameDisplay)), html.EscapeString(err.Error())))
+			if user != nil {
+				if logErr := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, "", "", "", magnet, "add", "error", 0, 0, false, err.Error(), map[string]interface{}{"source": "import"}); logErr != nil {
+					log.Printf("Warning: failed to log imported magnet error: %v", logErr)
+				}
+			}
+			if isActiveLimitReached(err) {
+				result.notTried = len(magnets) - i - 1
+				return result
+			}
+			continue
+		}
+
+		cached, err := b.selectAddedFiles(response.ID, magnet, filter)
+		if err != nil {
+			log.Printf("Error selecting files for imported torrent %s: %v", response.ID, err)
+		}
+		result.added = append(result.added, response.ID)
+		if user != nil {
+			if err := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, response.ID, "", "", magnet, "add", "waiting_files_selection", 0, 0, true, "", cached.metadata(map[string]interface{}{"source": "import"})); err != nil {
+				log.Printf("Warning: failed to log imported magnet: %v", err)
+			}
+		}
+	}
+	return result
+}
+
+// formatImportResult renders the /import summary
+func formatImportResult(result importResult, invalid []importLineError) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "<b>Magnet Import</b>\n\n<i>Added:</i> %d\n<i>Failed:</i> %d\n", len(result.added), len(result.failed))
+	if len(invalid) > 0 {
+		fmt.Fprintf(&text, "<i>Invalid lines:</i> %d\n", len(invalid))
+	}
+	if result.notTried > 0 {
+		fmt.Fprintf(&text, "<i>Not tried:</i> %d (active torrent limit reached)\n", result.notTried)
+	}
+	for i, line := range result.failed {
+		if i == importDetailLines {
+			fmt.Fprintf(&text, "\n…and %d more failures", len(result.failed)-i)
+			break
+		}
+		text.WriteString("\n❌ " + line)
+	}
+	for i, inv := range invalid {
+		if i == importDetailLines {
+			fmt.Fprintf(&text, "\n…and %d more invalid lines", len(invalid)-i)
+			break
+		}
+		fmt.Fprintf(&text, "\n⚠️ Line %d: %s", inv.line, html.EscapeString(inv.err.Error()))
+	}
+	return text.String()
+}
+
+// downloadTelegramFile fetches a file sent to the bot, refusing files over maxBytes
+func (b *Bot) downloadTelegramFile(ctx context.Context, fileID string, maxBytes int64) ([]byte, error) {
+	file, err := b.api.GetFile(ctx, &bot.GetFileParams{FileID: fileID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the file: %w", err)
+	}
+	if file.FileSize > maxBytes {
+		return nil, fmt.Errorf("the file is larger than %d bytes", maxBytes)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.api.FileDownloadLink(file), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := telegramFileClient.Do(req)
+	if err != nil {
+		// The download URL contains the bot token, so only the underlying cause is reported
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("failed to download the file: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download the file: HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download the file: %w", err)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("the file is larger than %d bytes", maxBytes)
+	}
+	return data, nil
+}
+
+// handleImportCommand handles /import (superadmin only): a .txt document sent with the
+// /import caption is read as a list of magnets, which are all added
+func (b *Bot) handleImportCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "import")
+		fullCommand := update.Message.Text + update.Message.Caption
+
+		if !role.IsSuperAdmin() {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, b.localize(chatID, "error.superadmin_only"), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "import", fullCommand, startTime, false, "Unauthorized - not superadmin", 0)
+			return
+		}
+
+		doc := update.Message.Document
+		if doc == nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> send a .txt file with one magnet per line and the caption /import", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "import", fullCommand, startTime, false, "Missing file", 0)
+			return
+		}
+		if !strings.HasSuffix(strings.ToLower(doc.FileName), ".txt") {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>[ERROR]</b> Please send a .txt file.", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "import", fullCommand, startTime, false, "Not a .txt file", 0)
+			return
+		}
+
+		data, err := b.downloadTelegramFile(ctx, doc.FileID, maxImportFileBytes)
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>[ERROR]</b> "+html.EscapeString(err.Error()), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "import", fullCommand, startTime, false, err.Error(), 0)
+			return
+		}
+		magnets, invalid, err := parseMagnetList(bytes.NewReader(data), b.config.App.MaxImportMagnets)
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>[ERROR]</b> "+html.EscapeString(err.Error()), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "import", fullCommand, startTime, false, err.Error(), 0)
+			return
+		}
+
+		reply := b.ackProcessing(ctx, chatID, messageThreadID, update.Message.ID, "import")
+		result := b.importMagnets(ctx, user, chatPK, magnets)
+		text := formatImportResult(result, invalid)
+		reply.finish(ctx, text)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "import", fullCommand, startTime, true, "", len(text))
+	})
+}
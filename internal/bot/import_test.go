@@ -0,0 +1,148 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/config"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+)
+
+const (
+	importMagnetA = "magnet:?xt=urn:btih:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa&dn=a"
+	importMagnetB = "magnet:?xt=urn:btih:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb&dn=b"
+	importMagnetC = "magnet:?xt=urn:btih:cccccccccccccccccccccccccccccccccccccccc&dn=c"
+)
+
+// TestParseMagnetList verifies blank and comment lines are skipped, invalid lines reported
+// with their line number, and the cap enforced.
+func TestParseMagnetList(t *testing.T) {
+	input := "# my list\n" + importMagnetA + "\n\n  " + importMagnetB + "  \nnot a magnet\n"
+	magnets, invalid, err := parseMagnetList(strings.NewReader(input), 2)
+	if err != nil {
+		t.Fatalf("parseMagnetList() error = %v", err)
+	}
+	if want := []string{importMagnetA, importMagnetB}; !reflect.DeepEqual(magnets, want) {
+		t.Errorf("magnets = %v, want %v", magnets, want)
+	}
+	if len(invalid) != 1 || invalid[0].line != 5 {
+		t.Errorf("invalid = %+v, want line 5", invalid)
+	}
+
+	if _, _, err := parseMagnetList(strings.NewReader(importMagnetA+"\n"+importMagnetB+"\n"+importMagnetC), 2); err == nil {
+		t.Error("expected an error for more magnets than the maximum")
+	}
+}
+
+// importClient is a RealDebridClient that adds magnets, refusing those listed in refuse.
+type importClient struct {
+	RealDebridClient
+	refuse   map[string]error
+	added    []string
+	selected []string
+}
+
+func (c *importClient) AddMagnet(magnet string) (*realdebrid.AddMagnetResponse, error) {
+	if err := c.refuse[magnet]; err != nil {
+		return nil, err
+	}
+	c.added = append(c.added, magnet)
+	return &realdebrid.AddMagnetResponse{ID: fmt.Sprintf("T%d", len(c.added))}, nil
+}
+
+func (c *importClient) SelectAllFiles(torrentID string) error {
+	c.selected = append(c.selected, torrentID)
+	return nil
+}
+
+// TestImportMagnets verifies every magnet is added with its files selected, failures are
+// reported, and the import stops at the active torrent limit.
+func TestImportMagnets(t *testing.T) {
+	interval := importAddInterval
+	importAddInterval = 0
+	t.Cleanup(func() { importAddInterval = interval })
+	cfg := &config.Config{}
+	cfg.App.MaxFilenameDisplay = 40
+
+	client := &importClient{refuse: map[string]error{importMagnetB: errors.New("invalid magnet")}}
+	b := &Bot{config: cfg, rdClient: client}
+	result := b.importMagnets(context.Background(), nil, 0, []string{importMagnetA, importMagnetB, importMagnetC})
+	if !reflect.DeepEqual(result.added, []string{"T1", "T2"}) || len(result.failed) != 1 || result.notTried != 0 {
+		t.Errorf("result = %+v, want 2 added and 1 failed", result)
+	}
+	if !reflect.DeepEqual(client.selected, result.added) {
+		t.Errorf("selected %v, want %v", client.selected, result.added)
+	}
+
+	client = &importClient{refuse: map[string]error{importMagnetA: &realdebrid.APIError{ErrorCode: rdErrorTooManyActive}}}
+	b.rdClient = client
+	result = b.importMagnets(context.Background(), nil, 0, []string{importMagnetA, importMagnetB, importMagnetC})
+	if len(result.added) != 0 || len(result.failed) != 1 || result.notTried != 2 {
+		t.Errorf("result = %+v, want to stop after the limit error", result)
+	}
+	if text := formatImportResult(result, nil); !strings.Contains(text, "Not tried:</i> 2") {
+		t.Errorf("summary missing skipped count:\n%s", text)
+	}
+}
+
+// TestFormatImportResult_Capped verifies long failure and invalid line lists are cut
+// short so the summary fits in one Telegram message.
+func TestFormatImportResult_Capped(t *testing.T) {
+	var result importResult
+	for i := 0; i < 100; i++ {
+		result.failed = append(result.failed, fmt.Sprintf("<code>%s</code>: %s", strings.Repeat("n", 60), strings.Repeat("e", 80)))
+	}
+	invalid := make([]importLineError, 5000)
+	for i := range invalid {
+		invalid[i] = importLineError{line: i + 1, err: errors.New("not a magnet link")}
+	}
+
+	text := formatImportResult(result, invalid)
+	if len(text) > 4096 {
+		t.Errorf("summary is %d characters, want at most 4096", len(text))
+	}
+	for _, want := range []string{"…and 90 more failures", "…and 4990 more invalid lines"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("summary missing %q", want)
+		}
+	}
+}
+
+// TestDownloadTelegramFile_HidesToken verifies a failed download does not report the
+// file URL, which contains the bot token.
+func TestDownloadTelegramFile_HidesToken(t *testing.T) {
+	const token = "123:secret-token"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/getFile") {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"file_id":"f","file_path":"documents/list.txt","file_size":10}}`))
+			return
+		}
+		// Drop the connection so the download fails with a network error
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	t.Cleanup(srv.Close)
+	api, err := bot.New(token, bot.WithSkipGetMe(), bot.WithServerURL(srv.URL))
+	if err != nil {
+		t.Fatalf("bot.New() error = %v", err)
+	}
+	b := &Bot{api: api}
+
+	_, err = b.downloadTelegramFile(context.Background(), "f", maxImportFileBytes)
+	if err == nil {
+		t.Fatal("downloadTelegramFile() error = nil, want a download failure")
+	}
+	if strings.Contains(err.Error(), token) {
+		t.Errorf("error leaks the bot token: %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// infoRefreshPrefix prefixes the /info refresh button's callback data, followed by the torrent ID
+	infoRefreshPrefix = "info_refresh:"
+
+	// infoRefreshCooldown is how soon the same /info message can be refreshed again
+	infoRefreshCooldown = 3 * time.Second
+
+	// maxCallbackDataLen is Telegram's limit on a button's callback data, in bytes
+	maxCallbackDataLen = 64
+)
+
+// infoRefreshKeyboard returns the refresh button of a torrent's /info message, or nil
+// if the torrent ID does not fit in the callback data
+func infoRefreshKeyboard(torrentID string) models.ReplyMarkup {
+	data := infoRefreshPrefix + torrentID
+	if torrentID == "" || len(data) > maxCallbackDataLen {
+		return nil
+	}
+	return &models.InlineKeyboardMarkup{
+		InlineKeyboard: [][]models.InlineKeyboardButton{{
+			{Text: "🔄 Refresh", CallbackData: data},
+		}},
+	}
+}
+
+// refreshKey identifies a message with a refresh button
+type refreshKey struct {
+	chatID    int64
+	messageID int
+}
+
+// refreshLimiter debounces refresh button presses, so tapping a button repeatedly
+// costs one Real-Debrid call per cooldown
+type refreshLimiter struct {
+	mu       sync.Mutex
+	cooldown time.Duration
+	last     map[refreshKey]time.Time
+}
+
+// newRefreshLimiter creates a refreshLimiter allowing one refresh per message per cooldown
+func newRefreshLimiter(cooldown time.Duration) *refreshLimiter {
+	return &refreshLimiter{cooldown: cooldown, last: make(map[refreshKey]time.Time)}
+}
+
+// allow reports whether the message may be refreshed at now, recording the refresh if so
+func (l *refreshLimiter) allow(chatID int64, messageID int, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	key := refreshKey{chatID, messageID}
+	if last, ok := l.last[key]; ok && now.Sub(last) < l.cooldown {
+		return false
+	}
+	l.last[key] = now
+	return true
+}
+
+// sweep forgets refreshes whose cooldown has passed and returns how many were dropped
+func (l *refreshLimiter) sweep(now time.Time) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	dropped := 0
+	for key, last := range l.last {
+		if now.Sub(last) >= l.cooldown {
+			delete(l.last, key)
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// handleInfoRefreshCallback handles the refresh button of /info, editing the message
+// with the torrent's current details. Every press is answered, with "Updated" even
+// when nothing changed.
+func (b *Bot) handleInfoRefreshCallback(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuthCallback(ctx, update, func(ctx context.Context, query *models.CallbackQuery, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "info_refresh")
+
+		msg := query.Message.Message
+		torrentID := strings.TrimPrefix(query.Data, infoRefreshPrefix)
+		if msg == nil || torrentID == "" {
+			b.answerCallback(ctx, query.ID, "This message can no longer be refreshed. Send /info again.", false)
+			return
+		}
+		if !b.infoRefreshes.allow(chatID, msg.ID, startTime) {
+			b.answerCallback(ctx, query.ID, "Just refreshed, please wait a moment.", false)
+			return
+		}
+
+		torrent, err := b.rdClient.GetTorrentInfo(torrentID)
+		if err != nil {
+			b.answerCallback(ctx, query.ID, "Could not refresh: "+err.Error(), true)
+			b.logCommandHelper(ctx, user, chatPK, int64(msg.ID), messageThreadID, "info", query.Data, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := b.formatTorrentInfo(torrent, b.lookupDisplayNames(ctx, torrent.ID)[torrent.ID])
+		if err := b.middleware.WaitForRateLimitWithContext(ctx); err != nil {
+			b.answerCallback(ctx, query.ID, "", false)
+			return
+		}
+		if _, err := b.api.EditMessageText(ctx, &bot.EditMessageTextParams{
+			ChatID:      chatID,
+			MessageID:   msg.ID,
+			Text:        b.withFooter(text),
+			ParseMode:   models.ParseModeHTML,
+			ReplyMarkup: infoRefreshKeyboard(torrent.ID),
+		}); err != nil && !strings.Contains(err.Error(), "message is not modified") {
+			log.Printf("Error refreshing /info of torrent %s: %v", torrentID, err)
+		}
+		b.answerCallback(ctx, query.ID, "Updated", false)
+		b.logCommandHelper(ctx, user, chatPK, int64(msg.ID), messageThreadID, "info", query.Data, startTime, true, "", len(text))
+	})
+}
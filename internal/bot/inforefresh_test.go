@@ -0,0 +1,55 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-telegram/bot/models"
+)
+
+// TestInfoRefreshKeyboard verifies the button carries the torrent ID and is left out
+// when the ID would not fit in the callback data.
+func TestInfoRefreshKeyboard(t *testing.T) {
+	markup, ok := infoRefreshKeyboard("ABCDEF123").(*models.InlineKeyboardMarkup)
+	if !ok {
+		t.Fatal("infoRefreshKeyboard() returned no inline keyboard")
+	}
+	if got := markup.InlineKeyboard[0][0].CallbackData; got != "info_refresh:ABCDEF123" {
+		t.Errorf("callback data = %q, want info_refresh:ABCDEF123", got)
+	}
+
+	if got := infoRefreshKeyboard(strings.Repeat("x", 60)); got != nil {
+		t.Errorf("infoRefreshKeyboard(long ID) = %v, want nil", got)
+	}
+	if got := infoRefreshKeyboard(""); got != nil {
+		t.Errorf("infoRefreshKeyboard(\"\") = %v, want nil", got)
+	}
+}
+
+// TestRefreshLimiter verifies presses on the same message within the cooldown are
+// rejected, other messages are independent, and sweep forgets expired refreshes.
+func TestRefreshLimiter(t *testing.T) {
+	l := newRefreshLimiter(3 * time.Second)
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	if !l.allow(1, 10, now) {
+		t.Fatal("first refresh rejected")
+	}
+	if l.allow(1, 10, now.Add(time.Second)) {
+		t.Error("refresh within the cooldown allowed")
+	}
+	if !l.allow(1, 11, now.Add(time.Second)) {
+		t.Error("refresh of another message rejected")
+	}
+	if !l.allow(1, 10, now.Add(3*time.Second)) {
+		t.Error("refresh after the cooldown rejected")
+	}
+
+	if got := l.sweep(now.Add(5 * time.Second)); got != 1 {
+		t.Errorf("sweep() = %d, want the refresh of message 11 dropped", got)
+	}
+	if got := l.sweep(now.Add(time.Minute)); got != 1 {
+		t.Errorf("sweep() = %d, want the refresh of message 10 dropped", got)
+	}
+}
@@ -0,0 +1,153 @@
+package bot
+
+import (
+	"context"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// magnetInfo is the metadata carried by a magnet link
+type magnetInfo struct {
+	hash     string // lowercase hex infohash
+	name     string // display name (dn), if any
+	size     int64  // exact length (xl) in bytes, 0 if absent
+	trackers int
+}
+
+// parseMagnet extracts the infohash and metadata of a magnet link. Both the hex and
+// the base32 form of the BitTorrent infohash are accepted; base32 is converted to hex.
+func parseMagnet(link string) (magnetInfo, error) {
+	u, err := url.Parse(link)
+	if err != nil || !strings.EqualFold(u.Scheme, "magnet") {
+		return magnetInfo{}, errors.New("not a magnet link")
+	}
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return magnetInfo{}, fmt.Errorf("malformed magnet link: %w", err)
+	}
+
+	info := magnetInfo{
+		name:     query.Get("dn"),
+		trackers: len(query["tr"]),
+	}
+	if xl := query.Get("xl"); xl != "" {
+		if size, err := strconv.ParseInt(xl, 10, 64); err == nil && size > 0 {
+			info.size = size
+		}
+	}
+
+	for _, xt := range query["xt"] {
+		if len(xt) < len("urn:btih:") || !strings.EqualFold(xt[:len("urn:btih:")], "urn:btih:") {
+			continue
+		}
+		hash := xt[len("urn:btih:"):]
+		switch len(hash) {
+		case 40:
+			if _, err := hex.DecodeString(hash); err == nil {
+				info.hash = strings.ToLower(hash)
+				return info, nil
+			}
+		case 32:
+			if raw, err := base32.StdEncoding.DecodeString(strings.ToUpper(hash)); err == nil {
+				info.hash = hex.EncodeToString(raw)
+				return info, nil
+			}
+		}
+		return magnetInfo{}, fmt.Errorf("invalid infohash %q", hash)
+	}
+	return magnetInfo{}, errors.New("magnet link has no BitTorrent infohash (xt=urn:btih:...)")
+}
+
+// isCached reports whether availability lists a cached Real-Debrid variant of hash.
+// Real-Debrid answers {"<hash>": {"rd": [...]}}, with an empty list or object when
+// nothing is cached.
+func isCached(availability realdebrid.InstantAvailability, hash string) bool {
+	for key, value := range availability {
+		if !strings.EqualFold(key, hash) {
+			continue
+		}
+		hosts, ok := value.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		variants, _ := hosts["rd"].([]interface{})
+		return len(variants) > 0
+	}
+	return false
+}
+
+// formatMagnetInspection renders the /inspect reply. availErr is set when the cache
+// check failed, in which case the cache state is shown as unknown.
+func formatMagnetInspection(info magnetInfo, cached bool, availErr error) string {
+	var text strings.Builder
+	text.WriteString("<b>Magnet Inspection</b>\n\n")
+	name := info.name
+	if name == "" {
+		name = "(no display name)"
+	}
+	fmt.Fprintf(&text, "<i>Name:</i> <code>%s</code>\n", html.EscapeString(name))
+	fmt.Fprintf(&text, "<i>Hash:</i> <code>%s</code>\n", info.hash)
+	if info.size > 0 {
+		fmt.Fprintf(&text, "<i>Size:</i> %s\n", realdebrid.FormatSize(info.size))
+	}
+	fmt.Fprintf(&text, "<i>Trackers:</i> %d\n", info.trackers)
+
+	switch {
+	case availErr != nil:
+		fmt.Fprintf(&text, "<i>Cached:</i> unknown (%s)\n", html.EscapeString(availErr.Error()))
+	case cached:
+		text.WriteString("<i>Cached:</i> ✅ yes, it should be ready right after adding\n")
+	default:
+		text.WriteString("<i>Cached:</i> ❌ no, Real-Debrid will have to download it\n")
+	}
+	text.WriteString("\nNothing was added. Use <code>/add</code> with the magnet to add it.")
+	return text.String()
+}
+
+// handleInspectCommand handles the /inspect command. It validates a magnet link and
+// checks whether Real-Debrid has it cached, without adding it.
+func (b *Bot) handleInspectCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "inspect")
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) < 2 {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /inspect &lt;magnet&gt;", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "inspect", update.Message.Text, startTime, false, "Missing arguments", 0)
+			return
+		}
+		magnetLink := parts[1]
+		if b.rejectLongInput(ctx, update, user, chatID, chatPK, messageThreadID, "inspect", magnetLink, startTime) {
+			return
+		}
+
+		info, err := parseMagnet(magnetLink)
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Invalid magnet link: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "inspect", update.Message.Text, startTime, false, err.Error(), 0)
+			b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeMagnetInspect, "inspect", false, err.Error(), nil)
+			return
+		}
+
+		availability, availErr := b.rdClient.CheckInstantAvailability([]string{info.hash})
+		cached := availErr == nil && isCached(availability, info.hash)
+
+		text := formatMagnetInspection(info, cached, availErr)
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "inspect", update.Message.Text, startTime, true, "", len(text))
+		b.logActivityHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, db.ActivityTypeMagnetInspect, "inspect", true, "", map[string]any{"hash": info.hash, "cached": cached})
+	})
+}
@@ -0,0 +1,85 @@
+package bot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// TestParseMagnet verifies hex and base32 infohashes, metadata extraction and invalid links.
+func TestParseMagnet(t *testing.T) {
+	const hexHash = "c12fe1c06bba254a9dc9f519b335aa7c1367a88a"
+
+	tests := []struct {
+		name    string
+		link    string
+		want    magnetInfo
+		wantErr bool
+	}{
+		{
+			name: "hex with metadata",
+			link: "magnet:?xt=urn:btih:C12FE1C06BBA254A9DC9F519B335AA7C1367A88A&dn=Some+File.mkv&xl=1048576&tr=udp%3A%2F%2Fa&tr=udp%3A%2F%2Fb",
+			want: magnetInfo{hash: hexHash, name: "Some File.mkv", size: 1048576, trackers: 2},
+		},
+		{
+			name: "base32",
+			link: "magnet:?xt=urn:btih:YEX6DQDLXISUVHOJ6UM3GNNKPQJWPKEK",
+			want: magnetInfo{hash: hexHash},
+		},
+		{name: "not a magnet", link: "https://example.com/file", wantErr: true},
+		{name: "no infohash", link: "magnet:?dn=name", wantErr: true},
+		{name: "bad infohash", link: "magnet:?xt=urn:btih:zzzz", wantErr: true},
+		{name: "non-hex 40 chars", link: "magnet:?xt=urn:btih:" + strings.Repeat("z", 40), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMagnet(tt.link)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseMagnet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseMagnet() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestIsCached verifies only a non-empty rd variant list counts as cached.
+func TestIsCached(t *testing.T) {
+	const hash = "c12fe1c06bba254a9dc9f519b335aa7c1367a88a"
+	tests := []struct {
+		name  string
+		avail realdebrid.InstantAvailability
+		want  bool
+	}{
+		{"cached", realdebrid.InstantAvailability{strings.ToUpper(hash): map[string]interface{}{"rd": []interface{}{map[string]interface{}{}}}}, true},
+		{"empty variants", realdebrid.InstantAvailability{hash: map[string]interface{}{"rd": []interface{}{}}}, false},
+		{"empty list", realdebrid.InstantAvailability{hash: []interface{}{}}, false},
+		{"missing", realdebrid.InstantAvailability{}, false},
+	}
+	for _, tt := range tests {
+		if got := isCached(tt.avail, hash); got != tt.want {
+			t.Errorf("%s: isCached() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestFormatMagnetInspection verifies the cache state and escaped metadata are shown.
+func TestFormatMagnetInspection(t *testing.T) {
+	info := magnetInfo{hash: "abc", name: "<b>x</b>", trackers: 1}
+
+	text := formatMagnetInspection(info, true, nil)
+	for _, want := range []string{"&lt;b&gt;x&lt;/b&gt;", "<code>abc</code>", "✅ yes"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("inspection missing %q:\n%s", want, text)
+		}
+	}
+	if text := formatMagnetInspection(info, false, nil); !strings.Contains(text, "❌ no") {
+		t.Errorf("uncached inspection = %s", text)
+	}
+	if text := formatMagnetInspection(info, false, errors.New("boom")); !strings.Contains(text, "unknown (boom)") {
+		t.Errorf("failed inspection = %s", text)
+	}
+}
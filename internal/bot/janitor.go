@@ -0,0 +1,82 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// sweeper is an in-memory store with entries that expire
+type sweeper interface {
+	// sweep drops entries expired at now and returns how many were dropped
+	sweep(now time.Time) int
+}
+
+// janitor periodically sweeps the bot's in-memory stores so entries nobody comes
+// back for (unanswered prompts, unconfirmed batches) don't accumulate. Stores still
+// check expiry on access; the janitor only bounds their memory.
+type janitor struct {
+	interval time.Duration
+	sweepers []sweeper
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// defaultJanitorInterval is used when no positive interval is configured
+const defaultJanitorInterval = time.Minute
+
+// newJanitor creates a janitor that sweeps every interval
+func newJanitor(interval time.Duration, sweepers ...sweeper) *janitor {
+	if interval <= 0 {
+		interval = defaultJanitorInterval
+	}
+	return &janitor{
+		interval: interval,
+		sweepers: sweepers,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// start runs the janitor in the background until Stop is called
+func (j *janitor) start() {
+	go func() {
+		defer close(j.done)
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-j.stop:
+				return
+			case now := <-ticker.C:
+				if dropped := j.sweepAll(now); dropped > 0 {
+					log.Printf("Janitor: dropped %d expired in-memory entries", dropped)
+				}
+			}
+		}
+	}()
+}
+
+// sweepAll sweeps every store and returns the total number of entries dropped
+func (j *janitor) sweepAll(now time.Time) int {
+	dropped := 0
+	for _, s := range j.sweepers {
+		dropped += s.sweep(now)
+	}
+	return dropped
+}
+
+// Stop stops the janitor and waits for it to exit, or for ctx to be done.
+// It is safe to call more than once.
+func (j *janitor) Stop(ctx context.Context) error {
+	j.stopOnce.Do(func() { close(j.stop) })
+	select {
+	case <-j.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,80 @@
+package bot
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestJanitor_SweepAllEvictsExpired verifies only expired entries are dropped from every store.
+func TestJanitor_SweepAllEvictsExpired(t *testing.T) {
+	now := time.Now()
+	// Live entries first: set also sweeps, so adding them after would hide the janitor's work
+	prompts := newPromptStore(time.Minute)
+	prompts.set(-100, 2, "add", 11, now)
+	prompts.set(-100, 1, "add", 10, now.Add(-2*time.Minute))
+	batches := newDeleteBatchStore(time.Minute)
+	batches.set(-100, 2, pendingDeleteBatch{ids: []string{"b"}, messageID: 21}, now)
+	batches.set(-100, 1, pendingDeleteBatch{ids: []string{"a"}, messageID: 20}, now.Add(-2*time.Minute))
+
+	j := newJanitor(time.Minute, prompts, batches)
+	if got := j.sweepAll(now); got != 2 {
+		t.Errorf("sweepAll() dropped %d entries, want 2", got)
+	}
+
+	if _, ok := prompts.prompts[promptKey{-100, 1}]; ok {
+		t.Error("expired prompt survived the sweep")
+	}
+	if !prompts.pending(-100, 2, 11, now) {
+		t.Error("live prompt was swept")
+	}
+	if _, ok := batches.batches[deleteBatchKey{-100, 1}]; ok {
+		t.Error("expired batch survived the sweep")
+	}
+	if _, ok := batches.take(-100, 2, 21, now); !ok {
+		t.Error("live batch was swept")
+	}
+
+	if got := j.sweepAll(now); got != 0 {
+		t.Errorf("second sweepAll() dropped %d entries, want 0", got)
+	}
+}
+
+// countingSweeper counts how often it is swept.
+type countingSweeper struct{ sweeps atomic.Int32 }
+
+func (s *countingSweeper) sweep(time.Time) int {
+	s.sweeps.Add(1)
+	return 0
+}
+
+// TestJanitor_RunsUntilStopped verifies the janitor sweeps on its interval and stops via its shutdown hook.
+func TestJanitor_RunsUntilStopped(t *testing.T) {
+	s := &countingSweeper{}
+	j := newJanitor(5*time.Millisecond, s)
+	j.start()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for s.sweeps.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if s.sweeps.Load() < 2 {
+		t.Fatalf("janitor swept %d times, want at least 2", s.sweeps.Load())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := j.Stop(ctx); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if err := j.Stop(ctx); err != nil {
+		t.Errorf("second Stop() error = %v", err)
+	}
+
+	after := s.sweeps.Load()
+	time.Sleep(30 * time.Millisecond)
+	if s.sweeps.Load() != after {
+		t.Error("janitor kept sweeping after Stop")
+	}
+}
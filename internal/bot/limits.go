@@ -0,0 +1,60 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// RateLimitStats is a snapshot of the outgoing message rate limiter
+type RateLimitStats struct {
+	MessagesPerSecond float64 // Sustained rate
+	Burst             int     // Messages that can be sent back to back
+	Available         float64 // Tokens available right now; at most Burst
+}
+
+// RateLimitStats returns the configured limits and current state of the rate limiter
+func (m *Middleware) RateLimitStats() RateLimitStats {
+	return RateLimitStats{
+		MessagesPerSecond: float64(m.limiter.Limit()),
+		Burst:             m.limiter.Burst(),
+		Available:         m.limiter.Tokens(),
+	}
+}
+
+// formatRateLimitStats renders the /limits report
+func formatRateLimitStats(s RateLimitStats) string {
+	var text strings.Builder
+	text.WriteString("<b>🚦 Rate Limits</b>\n\n")
+	fmt.Fprintf(&text, "<i>Messages per second:</i> %g\n", s.MessagesPerSecond)
+	fmt.Fprintf(&text, "<i>Burst:</i> %d\n", s.Burst)
+	available := max(s.Available, 0)
+	fmt.Fprintf(&text, "<i>Available now:</i> %.1f / %d", available, s.Burst)
+	if s.Available < 1 {
+		text.WriteString("\n\n<i>The limiter is saturated; outgoing messages are being delayed.</i>")
+	}
+	return text.String()
+}
+
+// handleLimitsCommand handles the /limits command (superadmin only)
+func (b *Bot) handleLimitsCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "limits")
+
+		if !role.IsSuperAdmin() {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, b.localize(chatID, "error.superadmin_only"), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "limits", update.Message.Text, startTime, false, "Unauthorized - not superadmin", 0)
+			return
+		}
+
+		text := formatRateLimitStats(b.middleware.RateLimitStats())
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "limits", update.Message.Text, startTime, true, "", len(text))
+	})
+}
@@ -0,0 +1,46 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/config"
+)
+
+// TestRateLimitStats verifies the accessor reports the configured rate and burst.
+func TestRateLimitStats(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.App.RateLimit = config.RateLimitConfig{MessagesPerSecond: 3, Burst: 7}
+	m := NewMiddleware(cfg)
+
+	s := m.RateLimitStats()
+	if s.MessagesPerSecond != 3 || s.Burst != 7 {
+		t.Errorf("RateLimitStats() = %+v, want 3 msg/s with burst 7", s)
+	}
+	if s.Available != 7 {
+		t.Errorf("Available = %v, want a full bucket of 7", s.Available)
+	}
+
+	if err := m.WaitForRateLimit(); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.RateLimitStats().Available; got >= 7 {
+		t.Errorf("Available = %v after one message, want fewer than 7", got)
+	}
+}
+
+// TestFormatRateLimitStats verifies the report and the saturation hint.
+func TestFormatRateLimitStats(t *testing.T) {
+	text := formatRateLimitStats(RateLimitStats{MessagesPerSecond: 0.5, Burst: 5, Available: 5})
+	for _, want := range []string{"<i>Messages per second:</i> 0.5", "<i>Burst:</i> 5", "5.0 / 5"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("report missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "saturated") {
+		t.Error("idle limiter reported as saturated")
+	}
+	if text := formatRateLimitStats(RateLimitStats{MessagesPerSecond: 1, Burst: 5, Available: -2}); !strings.Contains(text, "0.0 / 5") || !strings.Contains(text, "saturated") {
+		t.Errorf("saturated report = %q", text)
+	}
+}
@@ -0,0 +1,80 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// linkCheckConcurrency bounds the /unrestrict/check calls made by /links at once
+const linkCheckConcurrency = 4
+
+// formatTorrentLinks renders the /links reply for the still valid links of a torrent
+func formatTorrentLinks(t *realdebrid.Torrent, valid []realdebrid.LinkCheck, maxName int) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "<b>Links for</b> <code>%s</code>\n\n", html.EscapeString(truncateName(t.Filename, maxName)))
+	for _, l := range valid {
+		name := l.Filename
+		if name == "" {
+			name = l.Link
+		}
+		fmt.Fprintf(&text, "• <a href=\"%s\">%s</a> (%s)\n", html.EscapeString(l.Link), html.EscapeString(truncateName(name, maxName)), realdebrid.FormatSize(l.Filesize))
+	}
+	if len(valid) == 0 {
+		text.WriteString("None of the links are still available.\n")
+	}
+
+	total := 0
+	for _, l := range t.Links {
+		if l != "" {
+			total++
+		}
+	}
+	if dead := total - len(valid); dead > 0 {
+		fmt.Fprintf(&text, "\n<i>Skipped %d dead link(s). Use <code>/copy %s</code> to add the torrent again.</i>", dead, html.EscapeString(t.ID))
+	}
+	return strings.TrimRight(text.String(), "\n")
+}
+
+// handleLinksCommand handles the /links command. It lists the hoster links of a finished
+// torrent that can still be unrestricted, skipping the ones that expired.
+func (b *Bot) handleLinksCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "links")
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) < 2 {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /links &lt;torrent_id&gt;", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "links", update.Message.Text, startTime, false, "Missing arguments", 0)
+			return
+		}
+		torrentID := parts[1]
+
+		reply := b.ackProcessing(ctx, chatID, messageThreadID, update.Message.ID, "links")
+		torrent, err := b.rdClient.GetTorrentInfo(torrentID)
+		if err != nil {
+			reply.finish(ctx, fmt.Sprintf("<b>[ERROR]</b> Could not retrieve torrent info: %s", html.EscapeString(err.Error())))
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "links", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+		if len(torrent.Links) == 0 {
+			text := fmt.Sprintf("<b>[ERROR]</b> <code>%s</code> has no links yet (status: %s).", html.EscapeString(torrentID), realdebrid.FormatStatus(torrent.Status))
+			reply.finish(ctx, text)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "links", update.Message.Text, startTime, false, "No links", 0)
+			return
+		}
+
+		valid := realdebrid.ValidTorrentLinks(b.rdClient, torrent, linkCheckConcurrency)
+		text := formatTorrentLinks(torrent, valid, b.config.App.MaxFilenameDisplay)
+		reply.finish(ctx, text)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "links", update.Message.Text, startTime, true, "", len(text))
+	})
+}
@@ -0,0 +1,80 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// isActiveTorrent reports whether a torrent is still moving, so its speed and seeders change
+func isActiveTorrent(status string) bool {
+	switch status {
+	case "magnet_conversion", "queued", "downloading", "compressing", "uploading":
+		return true
+	}
+	return false
+}
+
+// enrichTorrents replaces the active entries of torrents with fresh details from fetch,
+// running at most concurrency fetches at once. A fetch that fails or takes longer than
+// timeout leaves the summary entry in place.
+func enrichTorrents(ctx context.Context, torrents []realdebrid.Torrent, concurrency int, timeout time.Duration, fetch func(id string) (*realdebrid.Torrent, error)) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i := range torrents {
+		if !isActiveTorrent(torrents[i].Status) {
+			continue
+		}
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+		wg.Add(1)
+		go func(t *realdebrid.Torrent) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			fresh, err := fetchWithTimeout(ctx, timeout, t.ID, fetch)
+			if err != nil {
+				log.Printf("List enrichment: keeping summary data for %s: %v", t.ID, err)
+				return
+			}
+			*t = *fresh
+		}(&torrents[i])
+	}
+	wg.Wait()
+}
+
+// fetchWithTimeout runs fetch(id) and gives up after timeout. The Real-Debrid client has
+// no per-call context, so an abandoned call finishes in the background; its slot is
+// released right away so a stuck request can't hold up the reply.
+func fetchWithTimeout(ctx context.Context, timeout time.Duration, id string, fetch func(id string) (*realdebrid.Torrent, error)) (*realdebrid.Torrent, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		torrent *realdebrid.Torrent
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		t, err := fetch(id)
+		done <- result{t, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.torrent, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
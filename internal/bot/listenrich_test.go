@@ -0,0 +1,73 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// TestEnrichTorrents_BoundedConcurrency verifies no more than the configured fetches run at once.
+func TestEnrichTorrents_BoundedConcurrency(t *testing.T) {
+	torrents := make([]realdebrid.Torrent, 10)
+	for i := range torrents {
+		torrents[i] = realdebrid.Torrent{ID: fmt.Sprint(i), Status: "downloading"}
+	}
+
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	fetch := func(id string) (*realdebrid.Torrent, error) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return &realdebrid.Torrent{ID: id, Status: "downloading", Seeders: 7}, nil
+	}
+
+	enrichTorrents(context.Background(), torrents, 3, time.Second, fetch)
+
+	if peak > 3 {
+		t.Errorf("peak concurrent fetches = %d, want at most 3", peak)
+	}
+	for _, tr := range torrents {
+		if tr.Seeders != 7 {
+			t.Errorf("torrent %s not enriched: %+v", tr.ID, tr)
+		}
+	}
+}
+
+// TestEnrichTorrents_Fallback verifies inactive torrents aren't fetched and failed or slow fetches keep the summary.
+func TestEnrichTorrents_Fallback(t *testing.T) {
+	torrents := []realdebrid.Torrent{
+		{ID: "done", Status: "downloaded", Seeders: 1},
+		{ID: "fails", Status: "downloading", Seeders: 2},
+		{ID: "slow", Status: "queued", Seeders: 3},
+	}
+	fetch := func(id string) (*realdebrid.Torrent, error) {
+		switch id {
+		case "fails":
+			return nil, errors.New("boom")
+		case "slow":
+			time.Sleep(200 * time.Millisecond)
+			return &realdebrid.Torrent{ID: id, Seeders: 99}, nil
+		}
+		t.Errorf("fetched inactive torrent %s", id)
+		return nil, errors.New("unexpected")
+	}
+
+	enrichTorrents(context.Background(), torrents, 2, 20*time.Millisecond, fetch)
+
+	for i, want := range []int{1, 2, 3} {
+		if torrents[i].Seeders != want {
+			t.Errorf("%s seeders = %d, want summary value %d", torrents[i].ID, torrents[i].Seeders, want)
+		}
+	}
+}
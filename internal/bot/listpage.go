@@ -0,0 +1,190 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// listPageSize is the most torrents shown on one /list page
+	listPageSize = 10
+
+	// listCallbackPrefix prefixes the /list page buttons' callback data, followed by the offset
+	listCallbackPrefix = "list_page:"
+
+	// listMaxMsgLen is the text budget of a /list page, below Telegram's 4096 characters
+	listMaxMsgLen = 4000
+)
+
+// listPage is one rendered /list page
+type listPage struct {
+	text   string
+	count  int                // Torrents shown
+	markup models.ReplyMarkup // Page buttons; nil when everything fits on one page
+}
+
+// parseListOffset returns the torrent offset in the callback data of a /list page button
+func parseListOffset(data string) (int, bool) {
+	offset, err := strconv.Atoi(strings.TrimPrefix(data, listCallbackPrefix))
+	if err != nil || offset < 0 {
+		return 0, false
+	}
+	return offset, true
+}
+
+// listPageKeyboard returns the Previous / Next buttons of the /list page starting at
+// offset and showing shown torrents. Previous is left out on the first page and Next
+// when there are no more torrents; with neither, it returns nil.
+func listPageKeyboard(offset, shown int, hasNext bool) models.ReplyMarkup {
+	var row []models.InlineKeyboardButton
+	if offset > 0 {
+		prev := max(offset-listPageSize, 0)
+		row = append(row, models.InlineKeyboardButton{Text: "« Previous", CallbackData: listCallbackPrefix + strconv.Itoa(prev)})
+	}
+	if hasNext {
+		row = append(row, models.InlineKeyboardButton{Text: "Next »", CallbackData: listCallbackPrefix + strconv.Itoa(offset+shown)})
+	}
+	if len(row) == 0 {
+		return nil
+	}
+	return &models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{row}}
+}
+
+// buildListPage fetches and renders the /list page starting at offset. One torrent past
+// the page is requested to tell whether a Next button is needed. When the page would
+// exceed the message length it is cut short, and Next continues from the first torrent left out.
+func (b *Bot) buildListPage(ctx context.Context, offset int) (listPage, error) {
+	torrents, err := b.rdClient.GetTorrents(listPageSize+1, offset)
+	if err != nil {
+		return listPage{}, err
+	}
+	if len(torrents) == 0 {
+		if offset == 0 {
+			return listPage{text: "No torrents found."}, nil
+		}
+		return listPage{text: "No more torrents.", markup: listPageKeyboard(offset, 0, false)}, nil
+	}
+
+	hasNext := len(torrents) > listPageSize
+	torrents = torrents[:min(len(torrents), listPageSize)]
+	if enrich := b.config.App.ListEnrich; enrich.Enabled {
+		enrichTorrents(ctx, torrents, enrich.Concurrency, time.Duration(enrich.TimeoutSeconds)*time.Second, b.rdClient.GetTorrentInfo)
+	}
+	ids := make([]string, len(torrents))
+	for i, t := range torrents {
+		ids[i] = t.ID
+	}
+	names := b.lookupDisplayNames(ctx, ids...)
+	var cachedFlags map[string]bool
+	if b.config.App.ListCachedBadge {
+		if cachedFlags, err = b.torrentRepo.GetCachedFlags(ctx, ids); err != nil {
+			log.Printf("Warning: failed to get cached flags for /list: %v", err)
+		}
+	}
+
+	var body strings.Builder
+	torrentsShown := 0
+	hitLengthLimit := false
+
+	for _, t := range torrents {
+		entry := strings.Builder{}
+		status := realdebrid.FormatStatus(t.Status)
+		size := realdebrid.FormatSize(t.Bytes)
+		progress := fmt.Sprintf("%.1f%%", t.Progress)
+		added := t.Added.Format("2006-01-02 15:04")
+
+		fmt.Fprintf(&entry, "<i>File:</i> <code>%s</code>\n", html.EscapeString(truncateName(displayName(names, t), b.config.App.MaxFilenameDisplay)))
+		fmt.Fprintf(&entry, "<i>ID:</i> <code>%s</code>\n", t.ID)
+		if b.config.App.ListShowHash && t.Hash != "" {
+			fmt.Fprintf(&entry, "<i>Hash:</i> <code>%s</code>\n", shortHash(t.Hash))
+		}
+		fmt.Fprintf(&entry, "<i>Status:</i> %s\n", status)
+		entry.WriteString(cachedBadge(cachedFlags, t.ID))
+		fmt.Fprintf(&entry, "<i>Size:</i> %s\n", size)
+		fmt.Fprintf(&entry, "<i>Progress:</i> %s\n", progress)
+		fmt.Fprintf(&entry, "<i>Added:</i> %s\n", added)
+
+		if t.Speed > 0 {
+			speed := realdebrid.FormatSize(t.Speed) + "/s"
+			fmt.Fprintf(&entry, "<i>Speed:</i> %s\n", speed)
+		}
+		if t.Seeders > 0 {
+			fmt.Fprintf(&entry, "<i>Seeders:</i> %d\n", t.Seeders)
+		}
+		entry.WriteString("\n")
+
+		if body.Len()+entry.Len() > listMaxMsgLen && torrentsShown > 0 {
+			hitLengthLimit = true
+			break
+		}
+		body.WriteString(entry.String())
+		torrentsShown++
+	}
+	if hitLengthLimit {
+		hasNext = true
+		fmt.Fprintf(&body, "<i>Showing %d torrents on this page to avoid exceeding message length limits.</i>\n\n", torrentsShown)
+	}
+
+	var text strings.Builder
+	text.WriteString("<b>Your Recent Torrents</b>")
+	if offset > 0 || hasNext {
+		fmt.Fprintf(&text, " <i>(%d–%d)</i>", offset+1, offset+torrentsShown)
+	}
+	text.WriteString("\n\n")
+	text.WriteString(body.String())
+	text.WriteString("Use <code>/info &lt;id&gt;</code> for more details on a specific torrent.")
+
+	return listPage{
+		text:   text.String(),
+		count:  torrentsShown,
+		markup: listPageKeyboard(offset, torrentsShown, hasNext),
+	}, nil
+}
+
+// handleListPageCallback handles the Previous / Next buttons of /list, editing the list
+// in place to show the requested page
+func (b *Bot) handleListPageCallback(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuthCallback(ctx, update, func(ctx context.Context, query *models.CallbackQuery, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "list_page")
+
+		msg := query.Message.Message
+		offset, ok := parseListOffset(query.Data)
+		if msg == nil || !ok {
+			b.answerCallback(ctx, query.ID, "This list can no longer be paged. Send /list again.", false)
+			return
+		}
+
+		page, err := b.buildListPage(ctx, offset)
+		if err != nil {
+			b.answerCallback(ctx, query.ID, "Failed to retrieve torrents: "+err.Error(), true)
+			b.logCommandHelper(ctx, user, chatPK, int64(msg.ID), messageThreadID, "list", query.Data, startTime, false, err.Error(), 0)
+			return
+		}
+		b.answerCallback(ctx, query.ID, "", false)
+
+		if err := b.middleware.WaitForRateLimitWithContext(ctx); err != nil {
+			return
+		}
+		if _, err := b.api.EditMessageText(ctx, &bot.EditMessageTextParams{
+			ChatID:      chatID,
+			MessageID:   msg.ID,
+			Text:        b.withFooter(page.text),
+			ParseMode:   models.ParseModeHTML,
+			ReplyMarkup: page.markup,
+		}); err != nil {
+			log.Printf("Error showing /list page at offset %d: %v", offset, err)
+		}
+		b.logCommandHelper(ctx, user, chatPK, int64(msg.ID), messageThreadID, "list", query.Data, startTime, true, "", len(page.text))
+	})
+}
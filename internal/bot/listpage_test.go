@@ -0,0 +1,67 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/go-telegram/bot/models"
+)
+
+// TestParseListOffset verifies page button data yields its offset and malformed data is rejected.
+func TestParseListOffset(t *testing.T) {
+	tests := []struct {
+		data   string
+		want   int
+		wantOK bool
+	}{
+		{"list_page:0", 0, true},
+		{"list_page:20", 20, true},
+		{"list_page:-10", 0, false},
+		{"list_page:", 0, false},
+		{"list_page:next", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseListOffset(tt.data)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseListOffset(%q) = %d, %v, want %d, %v", tt.data, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+// TestListPageKeyboard verifies Previous is absent on the first page, Next only appears
+// when more torrents follow, and Next continues after the torrents shown.
+func TestListPageKeyboard(t *testing.T) {
+	buttons := func(m models.ReplyMarkup) []models.InlineKeyboardButton {
+		if m == nil {
+			return nil
+		}
+		return m.(*models.InlineKeyboardMarkup).InlineKeyboard[0]
+	}
+
+	tests := []struct {
+		name    string
+		offset  int
+		shown   int
+		hasNext bool
+		want    []string // callback data of the buttons, in order
+	}{
+		{"single page", 0, 4, false, nil},
+		{"first page", 0, 10, true, []string{"list_page:10"}},
+		{"middle page", 10, 10, true, []string{"list_page:0", "list_page:20"}},
+		{"last page", 20, 3, false, []string{"list_page:10"}},
+		{"cut short", 10, 6, true, []string{"list_page:0", "list_page:16"}},
+		{"after a short page", 16, 10, false, []string{"list_page:6"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buttons(listPageKeyboard(tt.offset, tt.shown, tt.hasNext))
+			if len(got) != len(tt.want) {
+				t.Fatalf("buttons = %+v, want %v", got, tt.want)
+			}
+			for i, w := range tt.want {
+				if got[i].CallbackData != w {
+					t.Errorf("button %d data = %q, want %q", i, got[i].CallbackData, w)
+				}
+			}
+		})
+	}
+}
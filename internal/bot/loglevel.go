@@ -0,0 +1,105 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// logLevel is the live application log level. It starts from app.log_level and can be
+// switched between info and debug at runtime with /debug, without a restart.
+type logLevel struct {
+	debug atomic.Bool
+}
+
+// newLogLevel creates a logLevel starting at the configured level name
+func newLogLevel(name string) *logLevel {
+	l := &logLevel{}
+	l.debug.Store(name == "debug")
+	return l
+}
+
+// setDebug switches debug logging on or off
+func (l *logLevel) setDebug(enabled bool) {
+	l.debug.Store(enabled)
+}
+
+// isDebug reports whether debug logging is on; a nil logLevel is never in debug
+func (l *logLevel) isDebug() bool {
+	return l != nil && l.debug.Load()
+}
+
+// String returns the level name
+func (l *logLevel) String() string {
+	if l.isDebug() {
+		return "debug"
+	}
+	return "info"
+}
+
+// debugf logs like log.Printf when debug logging is on. It is also the Telegram client's
+// debug handler, so its request logging follows the live level.
+func (l *logLevel) debugf(format string, args ...any) {
+	if l.isDebug() {
+		log.Printf("Debug: "+format, args...)
+	}
+}
+
+// debugSetter is implemented by Real-Debrid clients whose request logging can be toggled
+type debugSetter interface {
+	SetDebug(enabled bool)
+}
+
+// setDebugLogging switches the bot and its Real-Debrid client between info and debug logging
+func (b *Bot) setDebugLogging(enabled bool) {
+	b.logLevel.setDebug(enabled)
+	if c, ok := b.rdClient.(debugSetter); ok {
+		c.SetDebug(enabled)
+	}
+	log.Printf("Log level set to %s", b.logLevel)
+}
+
+// handleDebugCommand handles the /debug command (superadmin only). It turns debug
+// logging on or off at runtime to diagnose a live issue without a redeploy.
+func (b *Bot) handleDebugCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "debug")
+
+		if !role.IsSuperAdmin() {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, b.localize(chatID, "error.superadmin_only"), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "debug", update.Message.Text, startTime, false, "Unauthorized - not superadmin", 0)
+			return
+		}
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) < 2 {
+			text := fmt.Sprintf("Log level: <b>%s</b>\n\n<b>Usage:</b> /debug on|off", b.logLevel)
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "debug", update.Message.Text, startTime, true, "", len(text))
+			return
+		}
+
+		switch strings.ToLower(parts[1]) {
+		case "on":
+			b.setDebugLogging(true)
+		case "off":
+			b.setDebugLogging(false)
+		default:
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /debug on|off", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "debug", update.Message.Text, startTime, false, "Invalid argument", 0)
+			return
+		}
+
+		text := fmt.Sprintf("<b>[OK]</b> Log level set to <b>%s</b>.", b.logLevel)
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "debug", update.Message.Text, startTime, true, "", len(text))
+	})
+}
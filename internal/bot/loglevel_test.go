@@ -0,0 +1,57 @@
+package bot
+
+import (
+	"bytes"
+	"log"
+	"testing"
+)
+
+// debugClient is a RealDebridClient recording the debug flag it was given.
+type debugClient struct {
+	RealDebridClient
+	debug bool
+}
+
+func (c *debugClient) SetDebug(enabled bool) { c.debug = enabled }
+
+// TestLogLevel_Toggle verifies the level switches at runtime and gates debug output.
+func TestLogLevel_Toggle(t *testing.T) {
+	var buf bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(prev) })
+
+	client := &debugClient{}
+	b := &Bot{rdClient: client, logLevel: newLogLevel("info")}
+
+	b.logLevel.debugf("hidden %d", 1)
+	if buf.Len() != 0 {
+		t.Errorf("debugf logged at info level: %q", buf.String())
+	}
+
+	b.setDebugLogging(true)
+	if b.logLevel.String() != "debug" || !client.debug {
+		t.Errorf("after on: level = %s, client debug = %v", b.logLevel, client.debug)
+	}
+	buf.Reset()
+	b.logLevel.debugf("shown %d", 2)
+	if !bytes.Contains(buf.Bytes(), []byte("Debug: shown 2")) {
+		t.Errorf("debugf output = %q, want the message", buf.String())
+	}
+
+	b.setDebugLogging(false)
+	if b.logLevel.String() != "info" || client.debug {
+		t.Errorf("after off: level = %s, client debug = %v", b.logLevel, client.debug)
+	}
+}
+
+// TestLogLevel_Initial verifies the configured level is the starting point and nil is info.
+func TestLogLevel_Initial(t *testing.T) {
+	if got := newLogLevel("debug").String(); got != "debug" {
+		t.Errorf("newLogLevel(debug) = %s", got)
+	}
+	var l *logLevel
+	if l.isDebug() || l.String() != "info" {
+		t.Errorf("nil logLevel = %s, want info", l)
+	}
+}
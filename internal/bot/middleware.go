@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/crazyuploader/rdctl-bot/internal/config"
@@ -16,6 +17,17 @@ import (
 type Middleware struct {
 	config  *config.Config
 	limiter *rate.Limiter
+
+	// allowMu guards fileAllowed, the live set loaded from telegram.allowlist_file
+	allowMu     sync.RWMutex
+	fileAllowed map[int64]struct{}
+
+	// stateMu guards the limiter state waiting to be saved to statePath, from
+	// app.rate_limit.state_file
+	stateMu    sync.Mutex
+	statePath  string
+	stateDirty bool
+	state      rateLimitState
 }
 
 // NewMiddleware creates a Middleware configured from cfg.
@@ -30,20 +42,67 @@ func NewMiddleware(cfg *config.Config) *Middleware {
 	}
 }
 
-// CheckAuthorization verifies if the user is allowed to use the bot
-func (m *Middleware) CheckAuthorization(chatID, userID int64) (bool, bool) {
-	// Check if user is superadmin - they can use bot anywhere
-	isSuperAdmin := m.config.IsSuperAdmin(userID)
+// Role is a user's access tier
+type Role int
+
+const (
+	// RoleUser can use the bot in allowed chats
+	RoleUser Role = iota
+	// RoleModerator can also run read-only admin commands, in allowed chats
+	RoleModerator
+	// RoleSuperAdmin can run every command, including destructive ones, anywhere
+	RoleSuperAdmin
+)
+
+// String returns the role name used in logs
+func (r Role) String() string {
+	switch r {
+	case RoleSuperAdmin:
+		return "superadmin"
+	case RoleModerator:
+		return "moderator"
+	default:
+		return "user"
+	}
+}
+
+// IsSuperAdmin reports whether the role may run destructive admin commands
+func (r Role) IsSuperAdmin() bool {
+	return r == RoleSuperAdmin
+}
+
+// CanModerate reports whether the role may run read-only admin commands
+func (r Role) CanModerate() bool {
+	return r >= RoleModerator
+}
+
+// RoleOf returns the configured role of a user. Superadmin takes precedence when a
+// user is listed as both.
+func (m *Middleware) RoleOf(userID int64) Role {
+	switch {
+	case m.config.IsSuperAdmin(userID):
+		return RoleSuperAdmin
+	case m.config.IsModerator(userID):
+		return RoleModerator
+	default:
+		return RoleUser
+	}
+}
+
+// CheckAuthorization verifies if the user is allowed to use the bot and returns their role
+func (m *Middleware) CheckAuthorization(chatID, userID int64) (bool, Role) {
+	// Only superadmins can use the bot anywhere
+	role := m.RoleOf(userID)
 
 	// Check if the chat itself is allowed
-	isChatAllowed := m.config.IsAllowedChat(chatID)
+	isChatAllowed := m.config.IsAllowedChat(chatID) || m.isFileAllowed(chatID)
 
 	// User is allowed if either:
 	// 1. They are a superadmin (can use anywhere), OR
-	// 2. The chat is in the allowed list
-	isAllowed := isSuperAdmin || isChatAllowed
+	// 2. The chat is in the allowed list or the allowlist file
+	isAllowed := role.IsSuperAdmin() || isChatAllowed
 
-	return isAllowed, isSuperAdmin
+	return isAllowed, role
 }
 
 // WaitForRateLimit waits if rate limit is exceeded
@@ -51,6 +110,7 @@ func (m *Middleware) WaitForRateLimit() error {
 	if err := m.limiter.Wait(context.Background()); err != nil {
 		return fmt.Errorf("rate limit error: %w", err)
 	}
+	m.noteSend(time.Now())
 	return nil
 }
 
@@ -59,6 +119,7 @@ func (m *Middleware) WaitForRateLimitWithContext(ctx context.Context) error {
 	if err := m.limiter.Wait(ctx); err != nil {
 		return fmt.Errorf("rate limit error: %w", err)
 	}
+	m.noteSend(time.Now())
 	return nil
 }
 
@@ -88,6 +149,8 @@ func (m *Middleware) LogCommand(update *models.Update, command string) {
 		if update.CallbackQuery.Message.Message != nil {
 			chatID = update.CallbackQuery.Message.Message.Chat.ID
 			messageThreadID = update.CallbackQuery.Message.Message.MessageThreadID
+		} else if update.CallbackQuery.Message.InaccessibleMessage != nil {
+			chatID = update.CallbackQuery.Message.InaccessibleMessage.Chat.ID
 		}
 	}
 
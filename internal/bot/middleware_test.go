@@ -117,11 +117,11 @@ func TestCheckAuthorization_SuperAdminAllowedAnywhere(t *testing.T) {
 	m := NewMiddleware(cfg)
 
 	// Super admin in an unauthorized chat — should still be allowed.
-	allowed, isSuperAdmin := m.CheckAuthorization(9999, 999)
+	allowed, role := m.CheckAuthorization(9999, 999)
 	if !allowed {
 		t.Error("super admin should be allowed in any chat")
 	}
-	if !isSuperAdmin {
+	if !role.IsSuperAdmin() {
 		t.Error("user 999 should be recognized as super admin")
 	}
 }
@@ -142,15 +142,59 @@ func TestCheckAuthorization_AllowedChat(t *testing.T) {
 	}
 	m := NewMiddleware(cfg)
 
-	allowed, isSuperAdmin := m.CheckAuthorization(100, 42)
+	allowed, role := m.CheckAuthorization(100, 42)
 	if !allowed {
 		t.Error("user in allowed chat should be permitted")
 	}
-	if isSuperAdmin {
+	if role.IsSuperAdmin() {
 		t.Error("regular user should not be identified as super admin")
 	}
 }
 
+// TestCheckAuthorization_Roles covers the user / moderator / superadmin matrix: which
+// chats each tier may use and which admin commands it may run.
+func TestCheckAuthorization_Roles(t *testing.T) {
+	cfg := &config.Config{
+		Telegram: config.TelegramConfig{
+			AllowedChatIDs: []int64{100},
+			SuperAdminIDs:  []int64{999, 555},
+			ModeratorIDs:   []int64{555, 777},
+		},
+	}
+	m := NewMiddleware(cfg)
+
+	tests := []struct {
+		name            string
+		chatID, userID  int64
+		wantAllowed     bool
+		wantRole        Role
+		wantModerate    bool
+		wantDestructive bool
+	}{
+		{"user in allowed chat", 100, 42, true, RoleUser, false, false},
+		{"user in other chat", 9999, 42, false, RoleUser, false, false},
+		{"moderator in allowed chat", 100, 777, true, RoleModerator, true, false},
+		{"moderator in other chat", 9999, 777, false, RoleModerator, true, false},
+		{"superadmin in other chat", 9999, 999, true, RoleSuperAdmin, true, true},
+		{"listed as both", 9999, 555, true, RoleSuperAdmin, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, role := m.CheckAuthorization(tt.chatID, tt.userID)
+			if allowed != tt.wantAllowed || role != tt.wantRole {
+				t.Errorf("CheckAuthorization() = (%v, %v), want (%v, %v)", allowed, role, tt.wantAllowed, tt.wantRole)
+			}
+			if role.CanModerate() != tt.wantModerate {
+				t.Errorf("CanModerate() = %v, want %v", role.CanModerate(), tt.wantModerate)
+			}
+			if role.IsSuperAdmin() != tt.wantDestructive {
+				t.Errorf("IsSuperAdmin() = %v, want %v", role.IsSuperAdmin(), tt.wantDestructive)
+			}
+		})
+	}
+}
+
 // TestCheckAuthorization_UnauthorizedUserInUnauthorizedChat verifies rejection.
 func TestCheckAuthorization_UnauthorizedUserInUnauthorizedChat(t *testing.T) {
 	cfg := &config.Config{
@@ -167,11 +211,11 @@ func TestCheckAuthorization_UnauthorizedUserInUnauthorizedChat(t *testing.T) {
 	}
 	m := NewMiddleware(cfg)
 
-	allowed, isSuperAdmin := m.CheckAuthorization(9999, 42)
+	allowed, role := m.CheckAuthorization(9999, 42)
 	if allowed {
 		t.Error("user in unauthorized chat should not be allowed")
 	}
-	if isSuperAdmin {
+	if role.IsSuperAdmin() {
 		t.Error("non-admin user should not be identified as super admin")
 	}
 }
@@ -0,0 +1,52 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// formatTestNotification renders the message sent by /notifytest
+func formatTestNotification(chatID int64, messageThreadID int, now time.Time) string {
+	var text strings.Builder
+	text.WriteString("<b>🔔 Test Notification</b>\n\n")
+	text.WriteString("This message was sent the same way as completion and auto-delete notifications.\n\n")
+	fmt.Fprintf(&text, "<i>Chat ID:</i> <code>%d</code>\n", chatID)
+	if messageThreadID != 0 {
+		fmt.Fprintf(&text, "<i>Topic ID:</i> <code>%d</code>\n", messageThreadID)
+	}
+	fmt.Fprintf(&text, "<i>Sent:</i> %s", now.UTC().Format("2006-01-02 15:04:05 UTC"))
+	return text.String()
+}
+
+// sendTestNotification delivers a test notification through the path background
+// notifications use: rate limited, routed to the thread, and not a reply to any message
+func (b *Bot) sendTestNotification(ctx context.Context, chatID int64, messageThreadID int) error {
+	return b.sendHTMLMessageWithErr(ctx, chatID, messageThreadID, formatTestNotification(chatID, messageThreadID, time.Now()), 0)
+}
+
+// handleNotifyTestCommand handles the /notifytest command. It sends a proactive
+// notification to the current chat and topic so users can confirm background
+// notifications will reach them.
+func (b *Bot) handleNotifyTestCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "notifytest")
+
+		if err := b.sendTestNotification(ctx, chatID, messageThreadID); err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Test notification could not be delivered: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "notifytest", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := fmt.Sprintf("<b>[OK]</b> Test notification delivered in %dms.", time.Since(startTime).Milliseconds())
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "notifytest", update.Message.Text, startTime, true, "", len(text))
+	})
+}
@@ -0,0 +1,48 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/config"
+)
+
+// TestFormatTestNotification verifies the topic is only shown for threaded chats.
+func TestFormatTestNotification(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	text := formatTestNotification(-100, 7, now)
+	for _, want := range []string{"<code>-100</code>", "<i>Topic ID:</i> <code>7</code>", "2025-03-01 12:00:00 UTC"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("notification missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(formatTestNotification(-100, 0, now), "Topic ID") {
+		t.Error("notification for an unthreaded chat mentions a topic")
+	}
+}
+
+// TestSendTestNotification verifies the notification is routed to the thread and not sent as a reply.
+func TestSendTestNotification(t *testing.T) {
+	api, requests := newTestTelegramAPI(t)
+	cfg := &config.Config{}
+	cfg.App.RateLimit = config.RateLimitConfig{MessagesPerSecond: 100, Burst: 10}
+	b := &Bot{api: api, config: cfg, middleware: NewMiddleware(cfg)}
+
+	if err := b.sendTestNotification(context.Background(), -100, 7); err != nil {
+		t.Fatalf("sendTestNotification() error = %v", err)
+	}
+
+	reqs := requests()
+	if len(reqs) != 1 || !strings.Contains(reqs[0], "/sendMessage") {
+		t.Fatalf("requests = %v, want one sendMessage", reqs)
+	}
+	if !strings.Contains(reqs[0], "name=\"message_thread_id\"\r\n\r\n7\r\n") {
+		t.Errorf("notification not routed to thread 7: %s", reqs[0])
+	}
+	if strings.Contains(reqs[0], "reply_parameters") {
+		t.Errorf("notification sent as a reply: %s", reqs[0])
+	}
+}
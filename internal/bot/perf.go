@@ -0,0 +1,110 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// defaultPerfWindowHours is the window /perf covers when no hours are given
+	defaultPerfWindowHours = 24
+
+	// maxPerfWindowHours is the longest window accepted by /perf <command> <hours>
+	maxPerfWindowHours = 720 // 30 days
+)
+
+// parsePerfArgs parses /perf <command> [hours] into the logged command name and window
+func parsePerfArgs(parts []string) (string, time.Duration, error) {
+	if len(parts) < 2 {
+		return "", 0, fmt.Errorf("missing command")
+	}
+	command := strings.ToLower(strings.TrimPrefix(parts[1], "/"))
+	if command == "" {
+		return "", 0, fmt.Errorf("missing command")
+	}
+	hours := defaultPerfWindowHours
+	if len(parts) > 2 {
+		h, err := strconv.Atoi(parts[2])
+		if err != nil || h < 1 || h > maxPerfWindowHours {
+			return "", 0, fmt.Errorf("please provide a valid number of hours (1 to %d)", maxPerfWindowHours)
+		}
+		hours = h
+	}
+	return command, time.Duration(hours) * time.Hour, nil
+}
+
+// formatMillis renders an execution time in milliseconds, switching to seconds from 1s up
+func formatMillis(ms float64) string {
+	if ms >= 1000 {
+		return fmt.Sprintf("%.2f s", ms/1000)
+	}
+	return fmt.Sprintf("%.0f ms", ms)
+}
+
+// formatLatencyStats renders the /perf reply for the given window
+func formatLatencyStats(stats db.LatencyStats, window time.Duration) string {
+	if stats.Samples == 0 {
+		return fmt.Sprintf("No timed runs of <code>/%s</code> in the last %s.", html.EscapeString(stats.Command), formatDuration(window))
+	}
+	return fmt.Sprintf(
+		"<b>Latency of /%s</b> (last %s)\n\n"+
+			"<i>Runs:</i> %d\n"+
+			"<i>Average:</i> %s\n"+
+			"<i>Min:</i> %s\n"+
+			"<i>p95:</i> %s\n"+
+			"<i>Max:</i> %s",
+		html.EscapeString(stats.Command), formatDuration(window),
+		stats.Samples,
+		formatMillis(stats.AvgMs),
+		formatMillis(float64(stats.MinMs)),
+		formatMillis(float64(stats.P95Ms)),
+		formatMillis(float64(stats.MaxMs)),
+	)
+}
+
+// handlePerfCommand handles the /perf command (superadmin only). It shows how long a
+// command took to run recently, which reveals slow commands and degrading RD latency.
+func (b *Bot) handlePerfCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "perf")
+
+		if !role.IsSuperAdmin() {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, b.localize(chatID, "error.superadmin_only"), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "perf", update.Message.Text, startTime, false, "Unauthorized - not superadmin", 0)
+			return
+		}
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) < 2 {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /perf &lt;command&gt; [hours]", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "perf", update.Message.Text, startTime, false, "Missing arguments", 0)
+			return
+		}
+		command, window, err := parsePerfArgs(parts)
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>[ERROR]</b> "+html.EscapeString(err.Error()), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "perf", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		stats, err := b.commandRepo.GetLatencyStats(ctx, command, time.Now().Add(-window))
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Failed to load latency stats: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "perf", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := formatLatencyStats(stats, window)
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "perf", update.Message.Text, startTime, true, "", len(text))
+	})
+}
@@ -0,0 +1,53 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+)
+
+// TestParsePerfArgs verifies the command name is normalised and the window validated.
+func TestParsePerfArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		wantCommand string
+		wantWindow  time.Duration
+		wantErr     bool
+	}{
+		{"default window", "/perf list", "list", 24 * time.Hour, false},
+		{"slash and case", "/perf /Info 6", "info", 6 * time.Hour, false},
+		{"zero hours", "/perf list 0", "", 0, true},
+		{"too many hours", "/perf list 721", "", 0, true},
+		{"not a number", "/perf list soon", "", 0, true},
+		{"bare slash", "/perf /", "", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command, window, err := parsePerfArgs(strings.Fields(tt.text))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePerfArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if command != tt.wantCommand || window != tt.wantWindow {
+				t.Errorf("parsePerfArgs() = %q, %v, want %q, %v", command, window, tt.wantCommand, tt.wantWindow)
+			}
+		})
+	}
+}
+
+// TestFormatLatencyStats verifies the figures are rendered and an empty window is explained.
+func TestFormatLatencyStats(t *testing.T) {
+	stats := db.LatencyStats{Command: "list", Samples: 20, AvgMs: 105.4, MinMs: 10, MaxMs: 2500, P95Ms: 190}
+	text := formatLatencyStats(stats, 24*time.Hour)
+	for _, want := range []string{"/list", "24 hours", "<i>Runs:</i> 20", "105 ms", "10 ms", "190 ms", "2.50 s"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("reply missing %q:\n%s", want, text)
+		}
+	}
+
+	if text := formatLatencyStats(db.LatencyStats{Command: "list"}, time.Hour); !strings.Contains(text, "No timed runs") {
+		t.Errorf("empty reply = %s", text)
+	}
+}
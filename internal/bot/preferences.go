@@ -0,0 +1,148 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/config"
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// fileSelectPref is the preference choosing which files of a user's adds are selected
+const fileSelectPref = "fileselect"
+
+// userPref is a preference users can set for themselves with /setpref
+type userPref struct {
+	key      string
+	values   []string
+	fallback func(*config.Config) string // value used when the user hasn't set one
+}
+
+// userPrefs lists the preferences accepted by /setpref, in /getpref order
+var userPrefs = []userPref{
+	{fileSelectPref, fileFilters, func(c *config.Config) string { return c.App.DefaultFileSelect }},
+}
+
+// findUserPref returns the preference named key
+func findUserPref(key string) (userPref, bool) {
+	for _, p := range userPrefs {
+		if p.key == key {
+			return p, true
+		}
+	}
+	return userPref{}, false
+}
+
+// resolveFileSelect returns the file filter applied to a user's adds: their own
+// preference if valid, else app.default_file_select if valid, else "all"
+func resolveFileSelect(userValue, configDefault string) string {
+	if slices.Contains(fileFilters, userValue) {
+		return userValue
+	}
+	if slices.Contains(fileFilters, configDefault) {
+		return configDefault
+	}
+	return "all"
+}
+
+// fileSelectFor returns the file filter for adds by the user (internal users.id, 0 if
+// unknown). Lookup failures are only logged, falling back to the configured default.
+func (b *Bot) fileSelectFor(ctx context.Context, userPK int64) string {
+	var value string
+	if b.prefRepo != nil && userPK != 0 {
+		var err error
+		if value, err = b.prefRepo.Get(ctx, userPK, fileSelectPref); err != nil {
+			log.Printf("Warning: failed to get file selection preference of user %d: %v", userPK, err)
+		}
+	}
+	return resolveFileSelect(value, b.config.App.DefaultFileSelect)
+}
+
+// handleSetPrefCommand handles /setpref <key> <value>, storing a preference of the
+// user. The value "default" clears it, so the bot's configured default applies again.
+func (b *Bot) handleSetPrefCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "setpref")
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) != 3 {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /setpref fileselect all|video|largest|default", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "setpref", update.Message.Text, startTime, false, "Missing arguments", 0)
+			return
+		}
+		key, value := strings.ToLower(parts[1]), strings.ToLower(parts[2])
+
+		pref, ok := findUserPref(key)
+		if !ok || (value != "default" && !slices.Contains(pref.values, value)) {
+			var text string
+			if !ok {
+				keys := make([]string, 0, len(userPrefs))
+				for _, p := range userPrefs {
+					keys = append(keys, p.key)
+				}
+				text = fmt.Sprintf("<b>[ERROR]</b> Unknown preference <code>%s</code>. Available: %s", html.EscapeString(key), strings.Join(keys, ", "))
+			} else {
+				text = fmt.Sprintf("<b>[ERROR]</b> Invalid value <code>%s</code> for %s. Use one of: %s, default", html.EscapeString(value), key, strings.Join(pref.values, ", "))
+			}
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "setpref", update.Message.Text, startTime, false, "Invalid preference", 0)
+			return
+		}
+
+		var err error
+		if value == "default" {
+			err = b.prefRepo.Clear(ctx, user.ID, key)
+		} else {
+			err = b.prefRepo.Set(ctx, user.ID, key, value)
+		}
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Failed to save preference: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "setpref", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := fmt.Sprintf("<b>[OK]</b> %s set to <code>%s</code>.", key, value)
+		if value == "default" {
+			text = fmt.Sprintf("<b>[OK]</b> %s reset to the default (<code>%s</code>).", key, html.EscapeString(pref.fallback(b.config)))
+		}
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "setpref", update.Message.Text, startTime, true, "", len(text))
+	})
+}
+
+// handleGetPrefCommand handles /getpref, listing the user's value of every preference
+// and whether it is their own or the bot's default
+func (b *Bot) handleGetPrefCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "getpref")
+
+		var text strings.Builder
+		text.WriteString("<b>Your Preferences</b>\n\n")
+		for _, pref := range userPrefs {
+			value, err := b.prefRepo.Get(ctx, user.ID, pref.key)
+			if err != nil {
+				b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Failed to get preferences: %s", html.EscapeString(err.Error())), update.Message.ID)
+				b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "getpref", update.Message.Text, startTime, false, err.Error(), 0)
+				return
+			}
+			source := "yours"
+			if value == "" {
+				value, source = pref.fallback(b.config), "default"
+			}
+			fmt.Fprintf(&text, "<i>%s:</i> <code>%s</code> (%s)\n", pref.key, html.EscapeString(value), source)
+		}
+		text.WriteString("\nChange one with <code>/setpref &lt;name&gt; &lt;value&gt;</code>.")
+
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text.String(), update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "getpref", update.Message.Text, startTime, true, "", text.Len())
+	})
+}
@@ -0,0 +1,40 @@
+package bot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/config"
+)
+
+// TestResolveFileSelect verifies a user's own preference wins over the configured
+// default, and that invalid values fall through to the next level.
+func TestResolveFileSelect(t *testing.T) {
+	tests := []struct {
+		name, userValue, configDefault, want string
+	}{
+		{"user preference", "video", "largest", "video"},
+		{"config default", "", "largest", "largest"},
+		{"neither set", "", "", "all"},
+		{"invalid user value", "subtitles", "largest", "largest"},
+		{"invalid config default", "", "bogus", "all"},
+		{"user all over config", "all", "video", "all"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveFileSelect(tt.userValue, tt.configDefault); got != tt.want {
+				t.Errorf("resolveFileSelect(%q, %q) = %q, want %q", tt.userValue, tt.configDefault, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFileSelectFor_NoUser verifies adds without a known user get the configured default.
+func TestFileSelectFor_NoUser(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.App.DefaultFileSelect = "largest"
+	b := &Bot{config: cfg}
+	if got := b.fileSelectFor(context.Background(), 0); got != "largest" {
+		t.Errorf("fileSelectFor() = %q, want largest", got)
+	}
+}
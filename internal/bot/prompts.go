@@ -0,0 +1,173 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// promptTTL is how long a force-reply prompt waits for the user's answer
+const promptTTL = 5 * time.Minute
+
+// promptKey identifies whose answer a prompt is waiting for
+type promptKey struct {
+	chatID int64
+	userID int64
+}
+
+// pendingPrompt is a command waiting for its missing argument
+type pendingPrompt struct {
+	command   string
+	messageID int
+	expiresAt time.Time
+}
+
+// promptStore tracks force-reply prompts sent for commands with missing arguments.
+// Each user has at most one pending prompt per chat; a newer prompt replaces the older one.
+type promptStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	prompts map[promptKey]pendingPrompt
+}
+
+// newPromptStore creates a promptStore whose prompts expire after ttl
+func newPromptStore(ttl time.Duration) *promptStore {
+	return &promptStore{ttl: ttl, prompts: make(map[promptKey]pendingPrompt)}
+}
+
+// set records that messageID in chatID is waiting for userID to answer command
+func (s *promptStore) set(chatID, userID int64, command string, messageID int, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweepLocked(now)
+	s.prompts[promptKey{chatID, userID}] = pendingPrompt{
+		command:   command,
+		messageID: messageID,
+		expiresAt: now.Add(s.ttl),
+	}
+}
+
+// pending reports whether replyToID is the user's current, unexpired prompt
+func (s *promptStore) pending(chatID, userID int64, replyToID int, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.prompts[promptKey{chatID, userID}]
+	return ok && p.messageID == replyToID && now.Before(p.expiresAt)
+}
+
+// take removes and returns the command for the user's prompt if replyToID answers it
+func (s *promptStore) take(chatID, userID int64, replyToID int, now time.Time) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := promptKey{chatID, userID}
+	p, ok := s.prompts[key]
+	if !ok || p.messageID != replyToID {
+		return "", false
+	}
+	delete(s.prompts, key)
+	if !now.Before(p.expiresAt) {
+		return "", false
+	}
+	return p.command, true
+}
+
+// sweep drops expired prompts and returns how many were dropped
+func (s *promptStore) sweep(now time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sweepLocked(now)
+}
+
+// sweepLocked drops expired prompts and returns how many were dropped; the caller must hold s.mu
+func (s *promptStore) sweepLocked(now time.Time) int {
+	dropped := 0
+	for key, p := range s.prompts {
+		if !now.Before(p.expiresAt) {
+			delete(s.prompts, key)
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// promptReplyKey extracts the chat, user and replied-to message of an update, if any
+func promptReplyKey(update *models.Update) (chatID, userID int64, replyToID int, ok bool) {
+	msg := update.Message
+	if msg == nil || msg.From == nil || msg.ReplyToMessage == nil || msg.Text == "" {
+		return 0, 0, 0, false
+	}
+	return msg.Chat.ID, msg.From.ID, msg.ReplyToMessage.ID, true
+}
+
+// matchPromptReply matches messages that answer a pending force-reply prompt
+func (b *Bot) matchPromptReply(update *models.Update) bool {
+	chatID, userID, replyToID, ok := promptReplyKey(update)
+	return ok && b.prompts.pending(chatID, userID, replyToID, time.Now())
+}
+
+// handlePromptReply re-dispatches a prompt answer as the original command with the argument filled in
+func (b *Bot) handlePromptReply(ctx context.Context, api *bot.Bot, update *models.Update) {
+	chatID, userID, replyToID, ok := promptReplyKey(update)
+	if !ok {
+		return
+	}
+	command, ok := b.prompts.take(chatID, userID, replyToID, time.Now())
+	if !ok {
+		return
+	}
+
+	msg := *update.Message
+	msg.Text = "/" + command + " " + strings.TrimSpace(msg.Text)
+	answered := *update
+	answered.Message = &msg
+
+	switch command {
+	case "add":
+		b.handleAddCommand(ctx, api, &answered)
+	case "unrestrict":
+		b.handleUnrestrictCommand(ctx, api, &answered)
+	}
+}
+
+// sendArgumentPrompt asks the user for a command's missing argument with a force-reply prompt.
+// It returns false if prompting is disabled or the prompt could not be sent, in which case the
+// caller should fall back to its usage message.
+func (b *Bot) sendArgumentPrompt(ctx context.Context, update *models.Update, messageThreadID int, command, text, placeholder string) bool {
+	if !b.config.App.PromptMissingArgs || update.Message == nil || update.Message.From == nil {
+		return false
+	}
+
+	params := &bot.SendMessageParams{
+		ChatID:    update.Message.Chat.ID,
+		Text:      text,
+		ParseMode: models.ParseModeHTML,
+		ReplyParameters: &models.ReplyParameters{
+			MessageID: update.Message.ID,
+		},
+		ReplyMarkup: &models.ForceReply{
+			ForceReply:            true,
+			InputFieldPlaceholder: placeholder,
+			Selective:             true,
+		},
+	}
+	if messageThreadID != 0 {
+		params.MessageThreadID = messageThreadID
+	}
+
+	if err := b.middleware.WaitForRateLimitWithContext(ctx); err != nil {
+		return false
+	}
+	sent, err := b.api.SendMessage(ctx, params)
+	if err != nil {
+		log.Printf("Error sending %s prompt: %v", command, err)
+		return false
+	}
+
+	b.prompts.set(update.Message.Chat.ID, update.Message.From.ID, command, sent.ID, time.Now())
+	return true
+}
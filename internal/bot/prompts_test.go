@@ -0,0 +1,104 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-telegram/bot/models"
+)
+
+// TestPromptStore_TakeAnswersPrompt verifies a reply to the prompt returns its command once.
+func TestPromptStore_TakeAnswersPrompt(t *testing.T) {
+	s := newPromptStore(time.Minute)
+	now := time.Now()
+	s.set(-100, 7, "add", 55, now)
+
+	if !s.pending(-100, 7, 55, now) {
+		t.Fatal("pending() = false, want true")
+	}
+	cmd, ok := s.take(-100, 7, 55, now)
+	if !ok || cmd != "add" {
+		t.Fatalf("take() = (%q, %v), want (%q, true)", cmd, ok, "add")
+	}
+	if _, ok := s.take(-100, 7, 55, now); ok {
+		t.Error("second take() succeeded, want prompt consumed")
+	}
+}
+
+// TestPromptStore_IgnoresOtherReplies verifies replies to other messages or by other users don't match.
+func TestPromptStore_IgnoresOtherReplies(t *testing.T) {
+	s := newPromptStore(time.Minute)
+	now := time.Now()
+	s.set(-100, 7, "add", 55, now)
+
+	if s.pending(-100, 7, 56, now) {
+		t.Error("pending() matched a different message")
+	}
+	if s.pending(-100, 8, 55, now) {
+		t.Error("pending() matched a different user")
+	}
+	if s.pending(-200, 7, 55, now) {
+		t.Error("pending() matched a different chat")
+	}
+	if _, ok := s.take(-100, 7, 56, now); ok {
+		t.Error("take() answered with a different message")
+	}
+	if !s.pending(-100, 7, 55, now) {
+		t.Error("unrelated take() removed the prompt")
+	}
+}
+
+// TestPromptStore_Expiry verifies stale prompts are neither answered nor retained.
+func TestPromptStore_Expiry(t *testing.T) {
+	s := newPromptStore(time.Minute)
+	now := time.Now()
+	s.set(-100, 7, "add", 55, now)
+
+	later := now.Add(2 * time.Minute)
+	if s.pending(-100, 7, 55, later) {
+		t.Error("pending() = true for an expired prompt")
+	}
+	if _, ok := s.take(-100, 7, 55, later); ok {
+		t.Error("take() answered an expired prompt")
+	}
+
+	s.set(-100, 1, "unrestrict", 60, now)
+	s.set(-100, 2, "add", 61, later) // sweeps the first prompt
+	if len(s.prompts) != 1 {
+		t.Errorf("len(prompts) = %d after sweep, want 1", len(s.prompts))
+	}
+}
+
+// TestPromptStore_NewerPromptReplacesOlder verifies only the latest prompt per user and chat is answerable.
+func TestPromptStore_NewerPromptReplacesOlder(t *testing.T) {
+	s := newPromptStore(time.Minute)
+	now := time.Now()
+	s.set(-100, 7, "add", 55, now)
+	s.set(-100, 7, "unrestrict", 60, now)
+
+	if s.pending(-100, 7, 55, now) {
+		t.Error("older prompt is still pending")
+	}
+	if cmd, ok := s.take(-100, 7, 60, now); !ok || cmd != "unrestrict" {
+		t.Errorf("take() = (%q, %v), want (%q, true)", cmd, ok, "unrestrict")
+	}
+}
+
+// TestPromptReplyKey verifies only text replies from a known user are considered answers.
+func TestPromptReplyKey(t *testing.T) {
+	reply := &models.Update{Message: &models.Message{
+		Text:           "magnet:?xt=urn:btih:abc",
+		Chat:           models.Chat{ID: -100},
+		From:           &models.User{ID: 7},
+		ReplyToMessage: &models.Message{ID: 55},
+	}}
+	chatID, userID, replyToID, ok := promptReplyKey(reply)
+	if !ok || chatID != -100 || userID != 7 || replyToID != 55 {
+		t.Errorf("promptReplyKey() = (%d, %d, %d, %v), want (-100, 7, 55, true)", chatID, userID, replyToID, ok)
+	}
+
+	notReply := &models.Update{Message: &models.Message{Text: "hi", From: &models.User{ID: 7}}}
+	if _, _, _, ok := promptReplyKey(notReply); ok {
+		t.Error("promptReplyKey() accepted a message that is not a reply")
+	}
+}
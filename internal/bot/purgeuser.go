@@ -0,0 +1,94 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// formatPurgeResult renders the per-table row counts removed by /purgeuser
+func formatPurgeResult(userID int64, purged []db.PurgedRows) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "<b>[OK]</b> All data of user <code>%d</code> was deleted.\n\n", userID)
+	var total int64
+	for _, p := range purged {
+		if p.Rows == 0 {
+			continue
+		}
+		fmt.Fprintf(&text, "<i>%s:</i> %d\n", p.Table, p.Rows)
+		total += p.Rows
+	}
+	fmt.Fprintf(&text, "\n<b>Total rows removed:</b> %d", total)
+	return text.String()
+}
+
+// handlePurgeUserCommand handles the /purgeuser command (superadmin only). It hard-deletes
+// every record stored about a user, e.g. to honour a GDPR erasure request. Without the
+// trailing "confirm" argument it only explains what would happen.
+func (b *Bot) handlePurgeUserCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "purgeuser")
+
+		if !role.IsSuperAdmin() {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, b.localize(chatID, "error.superadmin_only"), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "purgeuser", update.Message.Text, startTime, false, "Unauthorized - not superadmin", 0)
+			return
+		}
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) < 2 {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /purgeuser &lt;user_id&gt;", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "purgeuser", update.Message.Text, startTime, false, "Missing arguments", 0)
+			return
+		}
+		targetID, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || targetID == 0 {
+			// User ID 0 is the bot's own system user, which background jobs log under
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>[ERROR]</b> Please provide a valid Telegram user ID.", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "purgeuser", update.Message.Text, startTime, false, "Invalid user ID", 0)
+			return
+		}
+
+		if len(parts) < 3 || parts[2] != "confirm" {
+			text := fmt.Sprintf(
+				"<b>⚠️ Confirm Purge</b>\n\n"+
+					"This permanently deletes user <code>%d</code> and everything stored about them: "+
+					"messages, command and activity logs, kept torrents, subscriptions and statistics. "+
+					"It cannot be undone.\n\n"+
+					"Send <code>/purgeuser %d confirm</code> to proceed.",
+				targetID, targetID,
+			)
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "purgeuser", update.Message.Text, startTime, true, "", len(text))
+			return
+		}
+
+		purged, err := b.userRepo.PurgeUser(ctx, targetID)
+		if err != nil {
+			text := fmt.Sprintf("<b>[ERROR]</b> Failed to purge user: %s", html.EscapeString(err.Error()))
+			if errors.Is(err, db.ErrUserNotFound) {
+				text = fmt.Sprintf("<b>[ERROR]</b> No data is stored about user <code>%d</code>.", targetID)
+			}
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "purgeuser", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		if user != nil && user.UserID == targetID {
+			// The requester's own rows are gone; logging under them would break the foreign keys
+			user = nil
+		}
+		text := formatPurgeResult(targetID, purged)
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "purgeuser", update.Message.Text, startTime, true, "", len(text))
+	})
+}
@@ -0,0 +1,25 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+)
+
+// TestFormatPurgeResult verifies only tables with removed rows are listed and totalled.
+func TestFormatPurgeResult(t *testing.T) {
+	text := formatPurgeResult(42, []db.PurgedRows{
+		{Table: "messages", Rows: 5},
+		{Table: "kept_torrents", Rows: 0},
+		{Table: "users", Rows: 1},
+	})
+	for _, want := range []string{"<code>42</code>", "<i>messages:</i> 5", "<i>users:</i> 1", "Total rows removed:</b> 6"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("result missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "kept_torrents") {
+		t.Errorf("result lists a table with no removed rows:\n%s", text)
+	}
+}
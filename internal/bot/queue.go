@@ -0,0 +1,186 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// rdErrorTooManyActive is the RD error code for an add refused because the active torrent limit is reached
+const rdErrorTooManyActive = 21
+
+// isActiveLimitReached reports whether err is RD refusing a torrent because all active slots are in use
+func isActiveLimitReached(err error) bool {
+	var apiErr *realdebrid.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode == rdErrorTooManyActive
+}
+
+// freeSlots returns how many more torrents can be active right now
+func freeSlots(active *realdebrid.ActiveCount) int {
+	if active == nil || active.Nb >= active.Limit {
+		return 0
+	}
+	return active.Limit - active.Nb
+}
+
+// queuedMagnetName returns a short label for a queued magnet: its display name, else its hash
+func queuedMagnetName(magnet string) string {
+	info, err := parseMagnet(magnet)
+	switch {
+	case err != nil:
+		return magnet
+	case info.name != "":
+		return info.name
+	default:
+		return info.hash
+	}
+}
+
+// formatQueue renders the /myqueue reply
+func formatQueue(queue []db.QueuedMagnet, maxNameLen int, displayTime func(time.Time) string) string {
+	if len(queue) == 0 {
+		return "You have no magnets queued."
+	}
+	var text strings.Builder
+	fmt.Fprintf(&text, "<b>Your Queued Magnets (%d)</b>\n\n", len(queue))
+	for i, m := range queue {
+		fmt.Fprintf(&text, "%d. <code>%s</code>\n   <i>Queued:</i> %s\n",
+			i+1, html.EscapeString(truncateName(queuedMagnetName(m.Magnet), maxNameLen)), displayTime(m.CreatedAt))
+	}
+	text.WriteString("\nThey are added oldest first as active torrent slots free up.")
+	return text.String()
+}
+
+// queueMagnetIfFull queues magnetLink when addErr is RD's active limit error and queueing
+// is enabled, replying to the user. It returns true if the add was handled this way, in
+// which case the caller must not report addErr.
+func (b *Bot) queueMagnetIfFull(ctx context.Context, update *models.Update, user *db.User, chatID, chatPK int64, messageThreadID int, command, magnetLink string, addErr error, startTime time.Time) bool {
+	if !b.config.RealDebrid.QueueWhenFull || user == nil || !isActiveLimitReached(addErr) {
+		return false
+	}
+
+	queued, err := b.queueRepo.Enqueue(ctx, magnetLink, user.ID, chatPK, messageThreadID, b.config.RealDebrid.QueueMaxPerUser)
+	if err != nil {
+		text := fmt.Sprintf("<b>[ERROR]</b> The active torrent limit is reached and the magnet could not be queued: %s", html.EscapeString(err.Error()))
+		if errors.Is(err, db.ErrQueueFull) {
+			text = fmt.Sprintf("<b>[ERROR]</b> The active torrent limit is reached and you already have %d magnets queued. Try again once some have started.", b.config.RealDebrid.QueueMaxPerUser)
+		}
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, command, update.Message.Text, startTime, false, err.Error(), 0)
+		return true
+	}
+
+	text := fmt.Sprintf(
+		"<b>⏳ Magnet Queued</b>\n\n"+
+			"The active torrent limit is reached, so the magnet will be added as soon as a slot frees up. "+
+			"You will be notified when it starts.\n\n"+
+			"<i>Queued by you:</i> %d\n\n"+
+			"Use <code>/myqueue</code> to see your pending adds.",
+		queued,
+	)
+	b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+	if err := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, "", "", "", magnetLink, "add", "queued", 0, 0, true, "", nil); err != nil {
+		log.Printf("Warning: failed to log queued magnet: %v", err)
+	}
+	b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, command, update.Message.Text, startTime, true, "", len(text))
+	return true
+}
+
+// handleMyQueueCommand handles the /myqueue command, listing the magnets the user has queued
+func (b *Bot) handleMyQueueCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "myqueue")
+
+		if user == nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>[ERROR]</b> Could not identify you.", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "myqueue", update.Message.Text, startTime, false, "Unknown user", 0)
+			return
+		}
+
+		queue, err := b.queueRepo.ListByUser(ctx, user.ID)
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Failed to load your queue: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "myqueue", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := formatQueue(queue, b.config.App.MaxFilenameDisplay, b.displayTime)
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "myqueue", update.Message.Text, startTime, true, "", len(text))
+	})
+}
+
+// releaseQueuedMagnets adds queued magnets, oldest first, for as many active torrent slots
+// as are free, and notifies each user when theirs starts. Magnets RD still refuses for
+// lack of slots or a transient error stay queued for the next run.
+func (b *Bot) releaseQueuedMagnets(ctx context.Context) {
+	queue, err := b.queueRepo.List(ctx)
+	if err != nil {
+		log.Printf("Magnet queue: failed to list queued magnets: %v", err)
+		return
+	}
+	if len(queue) == 0 {
+		return
+	}
+
+	active, err := b.rdClient.GetActiveCount()
+	if err != nil {
+		log.Printf("Magnet queue: failed to get active torrent count: %v", err)
+		return
+	}
+	slots := freeSlots(active)
+
+	for _, m := range queue[:min(slots, len(queue))] {
+		if ctx.Err() != nil {
+			return
+		}
+
+		response, addErr := b.rdClient.AddMagnet(m.Magnet)
+		if addErr != nil && (isActiveLimitReached(addErr) || isRetryableHTTPError(addErr)) {
+			log.Printf("Magnet queue: could not add queued magnet %d yet: %v", m.ID, addErr)
+			return
+		}
+		if err := b.queueRepo.Remove(ctx, m.ID); err != nil {
+			log.Printf("Magnet queue: failed to remove queued magnet %d: %v", m.ID, err)
+		}
+
+		name := html.EscapeString(truncateName(queuedMagnetName(m.Magnet), b.config.App.MaxFilenameDisplay))
+		var text string
+		if addErr != nil {
+			text = fmt.Sprintf("<b>[ERROR]</b> Your queued magnet could not be added\n\n<i>Name:</i> <code>%s</code>\n<i>Error:</i> %s", name, html.EscapeString(addErr.Error()))
+			if err := b.torrentRepo.LogTorrentActivity(ctx, "", m.UserPK, m.ChatPK, "", "", "", m.Magnet, "add", "error", 0, 0, false, addErr.Error(), map[string]interface{}{"queued_at": m.CreatedAt}); err != nil {
+				log.Printf("Magnet queue: failed to log failed add: %v", err)
+			}
+		} else {
+			cached, err := b.selectAddedFiles(response.ID, m.Magnet, b.fileSelectFor(ctx, m.UserPK))
+			if err != nil {
+				log.Printf("Error selecting files for queued torrent %s: %v", response.ID, err)
+			}
+			text = fmt.Sprintf(
+				"<b>▶️ Queued Torrent Started</b>\n\n"+
+					"<i>Name:</i> <code>%s</code>\n"+
+					"<i>ID:</i> <code>%s</code>\n\n"+
+					"Use <code>/info %s</code> to check its status.",
+				name, html.EscapeString(response.ID), html.EscapeString(response.ID),
+			)
+			if err := b.torrentRepo.LogTorrentActivity(ctx, "", m.UserPK, m.ChatPK, response.ID, "", "", m.Magnet, "add", "waiting_files_selection", 0, 0, true, "", cached.metadata(map[string]interface{}{"queued_at": m.CreatedAt})); err != nil {
+				log.Printf("Magnet queue: failed to log released add: %v", err)
+			}
+			log.Printf("Magnet queue: released queued magnet %d as torrent %s", m.ID, response.ID)
+		}
+
+		if err := b.sendHTMLMessageWithErr(ctx, m.ChatID, b.notificationThread(m.ChatID, m.UserID, int(m.ThreadID)), text, 0); err != nil {
+			log.Printf("Magnet queue: failed to notify user %d: %v", m.UserID, err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// TestIsActiveLimitReached verifies only RD's too-many-active error counts, also when wrapped.
+func TestIsActiveLimitReached(t *testing.T) {
+	limit := &realdebrid.APIError{ErrorCode: rdErrorTooManyActive, ErrorMessage: "too_many_active_downloads"}
+	if !isActiveLimitReached(limit) {
+		t.Error("isActiveLimitReached(code 21) = false")
+	}
+	if !isActiveLimitReached(fmt.Errorf("add: %w", limit)) {
+		t.Error("isActiveLimitReached(wrapped code 21) = false")
+	}
+	if isActiveLimitReached(&realdebrid.APIError{ErrorCode: rdErrorUnknownResource}) {
+		t.Error("isActiveLimitReached(code 7) = true")
+	}
+	if isActiveLimitReached(errors.New("HTTP 503")) {
+		t.Error("isActiveLimitReached(plain error) = true")
+	}
+}
+
+// TestFreeSlots verifies free slots never go negative and a missing count frees none.
+func TestFreeSlots(t *testing.T) {
+	tests := []struct {
+		active *realdebrid.ActiveCount
+		want   int
+	}{
+		{&realdebrid.ActiveCount{Nb: 3, Limit: 5}, 2},
+		{&realdebrid.ActiveCount{Nb: 5, Limit: 5}, 0},
+		{&realdebrid.ActiveCount{Nb: 7, Limit: 5}, 0},
+		{nil, 0},
+	}
+	for _, tt := range tests {
+		if got := freeSlots(tt.active); got != tt.want {
+			t.Errorf("freeSlots(%+v) = %d, want %d", tt.active, got, tt.want)
+		}
+	}
+}
+
+// TestFormatQueue verifies queued magnets are listed by name, falling back to their hash.
+func TestFormatQueue(t *testing.T) {
+	if text := formatQueue(nil, 50, func(time.Time) string { return "" }); !strings.Contains(text, "no magnets queued") {
+		t.Errorf("empty queue = %s", text)
+	}
+
+	queue := []db.QueuedMagnet{
+		{Magnet: "magnet:?xt=urn:btih:c12fe1c06bba254a9dc9f519b335aa7c1367a88a&dn=<Show>.mkv"},
+		{Magnet: "magnet:?xt=urn:btih:c12fe1c06bba254a9dc9f519b335aa7c1367a88a"},
+	}
+	text := formatQueue(queue, 50, func(time.Time) string { return "today" })
+	for _, want := range []string{"(2)", "1. <code>&lt;Show&gt;.mkv</code>", "2. <code>c12fe1c06bba254a9dc9f519b335aa7c1367a88a</code>", "today"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("queue missing %q:\n%s", want, text)
+		}
+	}
+}
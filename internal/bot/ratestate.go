@@ -0,0 +1,137 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// rateLimitStateInterval is how often a changed limiter state is saved to
+// app.rate_limit.state_file
+const rateLimitStateInterval = time.Second
+
+// rateLimitState is the content of app.rate_limit.state_file: the limiter's tokens
+// right after the last send
+type rateLimitState struct {
+	Tokens float64   `json:"tokens"`
+	At     time.Time `json:"at"`
+}
+
+// loadRateLimitState reads the state saved at path. A missing file yields a zero state.
+func loadRateLimitState(path string) (rateLimitState, error) {
+	var state rateLimitState
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("failed to read rate limit state: %w", err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("failed to parse rate limit state %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// saveRateLimitState writes state to path through a temporary file and a rename, so
+// a crash mid-write never leaves a truncated file behind
+func saveRateLimitState(path string, state rateLimitState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save rate limit state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save rate limit state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save rate limit state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save rate limit state: %w", err)
+	}
+	return nil
+}
+
+// RestoreRateLimitState takes the tokens saved at path as spent at the time they were
+// saved, so a restart continues with roughly what the previous run had left rather than
+// a full burst, and keeps the state of later sends to be saved there by
+// RunRateLimitStateSaver. Partial tokens are rounded against the bot; a state from the
+// future is ignored.
+func (m *Middleware) RestoreRateLimitState(path string) error {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+
+	m.statePath = path
+	state, err := loadRateLimitState(path)
+	if err != nil {
+		return err
+	}
+	if state.At.IsZero() || state.At.After(time.Now()) {
+		return nil
+	}
+	burst := m.limiter.Burst()
+	spent := min(max(int(math.Ceil(float64(burst)-state.Tokens)), 0), burst)
+	if spent > 0 {
+		m.limiter.ReserveN(state.At, spent)
+	}
+	return nil
+}
+
+// noteSend records the limiter's tokens after a message sent at t when a state file is
+// configured. The state is written by the next flushRateLimitState.
+func (m *Middleware) noteSend(t time.Time) {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+
+	if m.statePath == "" {
+		return
+	}
+	m.state = rateLimitState{Tokens: m.limiter.TokensAt(t), At: t}
+	m.stateDirty = true
+}
+
+// flushRateLimitState saves the state recorded since the last flush, if any. Failing
+// to save only logs a warning; the state is saved again after the next send.
+func (m *Middleware) flushRateLimitState() {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+
+	if !m.stateDirty {
+		return
+	}
+	m.stateDirty = false
+	if err := saveRateLimitState(m.statePath, m.state); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+// RunRateLimitStateSaver saves the limiter state every interval while it changes, so
+// sending doesn't touch the disk, and a last time once ctx is cancelled. It blocks
+// until then.
+func (m *Middleware) RunRateLimitStateSaver(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	defer m.flushRateLimitState()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.flushRateLimitState()
+		}
+	}
+}
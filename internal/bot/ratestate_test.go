@@ -0,0 +1,142 @@
+package bot
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/config"
+)
+
+// newStateMiddleware returns a middleware allowing 1 message per second with a burst of 3
+func newStateMiddleware() *Middleware {
+	cfg := &config.Config{}
+	cfg.App.RateLimit = config.RateLimitConfig{MessagesPerSecond: 1, Burst: 3}
+	return NewMiddleware(cfg)
+}
+
+// TestRateLimitState_SaveAndRestore verifies the state of the last send is saved on a
+// flush and a new middleware restored from the file starts without the burst the first one used up.
+func TestRateLimitState_SaveAndRestore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rate-limit.json")
+
+	m := newStateMiddleware()
+	if err := m.RestoreRateLimitState(path); err != nil {
+		t.Fatalf("RestoreRateLimitState() with no file error = %v", err)
+	}
+	if got := m.RateLimitStats().Available; got != 3 {
+		t.Fatalf("Available = %v without a state file, want a full burst of 3", got)
+	}
+	for range 3 {
+		if err := m.WaitForRateLimit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m.flushRateLimitState()
+
+	state, err := loadRateLimitState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.At.IsZero() || state.Tokens >= 1 {
+		t.Fatalf("saved state = %+v, want the burst used up", state)
+	}
+
+	restored := newStateMiddleware()
+	if err := restored.RestoreRateLimitState(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := restored.RateLimitStats().Available; got >= 1 {
+		t.Errorf("Available = %v after restore, want under 1 since the burst was used up", got)
+	}
+}
+
+// TestRateLimitState_IgnoresFutureState verifies a state saved after now doesn't drain the limiter.
+func TestRateLimitState_IgnoresFutureState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rate-limit.json")
+	if err := saveRateLimitState(path, rateLimitState{Tokens: 0, At: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newStateMiddleware()
+	if err := m.RestoreRateLimitState(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.RateLimitStats().Available; got != 3 {
+		t.Errorf("Available = %v, want a full burst of 3", got)
+	}
+}
+
+// TestRateLimitState_Corrupt verifies an unreadable file is reported and later sends overwrite it.
+func TestRateLimitState_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rate-limit.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newStateMiddleware()
+	if err := m.RestoreRateLimitState(path); err == nil {
+		t.Fatal("RestoreRateLimitState() error = nil for a corrupt file")
+	}
+	if err := m.WaitForRateLimit(); err != nil {
+		t.Fatal(err)
+	}
+	m.flushRateLimitState()
+	if state, err := loadRateLimitState(path); err != nil || state.Tokens < 1 || state.Tokens >= 3 {
+		t.Errorf("loadRateLimitState() = %+v, %v, want 2 tokens left", state, err)
+	}
+}
+
+// TestRateLimitState_SavesOnlyWhenChanged verifies sends don't write the file themselves
+// and a flush without new sends leaves it alone.
+func TestRateLimitState_SavesOnlyWhenChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rate-limit.json")
+
+	m := newStateMiddleware()
+	if err := m.RestoreRateLimitState(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.WaitForRateLimit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("state file exists before a flush: %v", err)
+	}
+
+	m.flushRateLimitState()
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("state file missing after a flush: %v", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	m.flushRateLimitState()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("flush without a new send rewrote the state file: %v", err)
+	}
+}
+
+// TestRunRateLimitStateSaver_FlushesOnCancel verifies the saver writes the pending
+// state when its context is cancelled.
+func TestRunRateLimitStateSaver_FlushesOnCancel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rate-limit.json")
+
+	m := newStateMiddleware()
+	if err := m.RestoreRateLimitState(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.WaitForRateLimit(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	m.RunRateLimitStateSaver(ctx, time.Hour)
+
+	if state, err := loadRateLimitState(path); err != nil || state.At.IsZero() {
+		t.Errorf("loadRateLimitState() = %+v, %v, want the state of the send", state, err)
+	}
+}
@@ -0,0 +1,105 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-telegram/bot"
+)
+
+// telegramPollTimeout bounds each Telegram request, including the long poll, the same as
+// the library's default client
+const telegramPollTimeout = time.Minute
+
+// pollBackoff delays Telegram polling after failed getUpdates calls. The library retries
+// a failed poll on its own after at most 5 seconds; this adds a configurable exponential
+// backoff on top, so an outage or a revoked token doesn't hammer Telegram.
+// Other API calls pass through untouched.
+type pollBackoff struct {
+	client   bot.HttpClient
+	initial  time.Duration
+	maxDelay time.Duration
+	sleep    func(ctx context.Context, d time.Duration) error // waits d unless ctx ends first
+
+	mu       sync.Mutex
+	failures int // consecutive failed polls
+}
+
+// newPollBackoff wraps client, waiting initial after the first failed poll and doubling
+// the wait with each consecutive failure up to maxDelay
+func newPollBackoff(client bot.HttpClient, initial, maxDelay time.Duration) *pollBackoff {
+	return &pollBackoff{client: client, initial: initial, maxDelay: max(maxDelay, initial), sleep: sleepContext}
+}
+
+// sleepContext waits d or until ctx is done, whichever comes first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// delay returns the wait before the next poll after failures consecutive failures
+func (p *pollBackoff) delay(failures int) time.Duration {
+	if failures == 0 {
+		return 0
+	}
+	d := p.initial
+	for i := 1; i < failures && d < p.maxDelay; i++ {
+		d *= 2
+	}
+	return min(d, p.maxDelay)
+}
+
+// Do sends req, first waiting out the backoff when it is a poll following failed ones.
+// A poll fails when the request errors or Telegram answers with a non-200 status.
+func (p *pollBackoff) Do(req *http.Request) (*http.Response, error) {
+	if !strings.HasSuffix(req.URL.Path, "/getUpdates") {
+		return p.client.Do(req)
+	}
+
+	p.mu.Lock()
+	wait := p.delay(p.failures)
+	p.mu.Unlock()
+	if wait > 0 {
+		if err := p.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := p.client.Do(req)
+	if req.Context().Err() != nil {
+		return resp, err
+	}
+	failed := err
+	// The request URL carries the bot token, so only the cause is logged
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		failed = urlErr.Err
+	}
+	if err == nil && resp.StatusCode != http.StatusOK {
+		failed = fmt.Errorf("status %s", resp.Status)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if failed != nil {
+		p.failures++
+		log.Printf("Telegram polling failed (attempt %d): %v. Retrying in %s", p.failures, failed, p.delay(p.failures))
+	} else if p.failures > 0 {
+		log.Printf("Telegram polling recovered after %d failed attempts", p.failures)
+		p.failures = 0
+	}
+	return resp, err
+}
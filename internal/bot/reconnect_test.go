@@ -0,0 +1,159 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// flakyTelegram answers getUpdates with a network error for the first failures calls,
+// then with update, then with nothing. Other methods always succeed.
+type flakyTelegram struct {
+	mu       sync.Mutex
+	failures int
+	polls    int
+	update   string
+}
+
+func (f *flakyTelegram) Do(req *http.Request) (*http.Response, error) {
+	if _, err := io.Copy(io.Discard, req.Body); err != nil {
+		return nil, err
+	}
+	body := `{"ok":true,"result":true}`
+	if strings.HasSuffix(req.URL.Path, "/getUpdates") {
+		f.mu.Lock()
+		f.polls++
+		poll := f.polls
+		f.mu.Unlock()
+		switch {
+		case poll <= f.failures:
+			return nil, errors.New("connection refused")
+		case poll == f.failures+1 && f.update != "":
+			body = `{"ok":true,"result":[` + f.update + `]}`
+		default:
+			body = `{"ok":true,"result":[]}`
+		}
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// newPollRequest returns a request for method like the library sends it
+func newPollRequest(ctx context.Context, method string) *http.Request {
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.telegram.org/bot123:secret/"+method, strings.NewReader(""))
+	return req
+}
+
+// TestPollBackoff_FailsThenSucceeds verifies the waits between polls double with each
+// consecutive failure up to the maximum and reset once a poll succeeds.
+func TestPollBackoff_FailsThenSucceeds(t *testing.T) {
+	stub := &flakyTelegram{failures: 4}
+	p := newPollBackoff(stub, time.Second, 5*time.Second)
+	var waits []time.Duration
+	p.sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	var errs int
+	for range 6 {
+		resp, err := p.Do(newPollRequest(context.Background(), "getUpdates"))
+		if err != nil {
+			errs++
+			continue
+		}
+		resp.Body.Close()
+	}
+	if errs != 4 {
+		t.Errorf("got %d failed polls, want 4", errs)
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	if len(waits) != len(want) {
+		t.Fatalf("waits = %v, want %v", waits, want)
+	}
+	for i := range want {
+		if waits[i] != want[i] {
+			t.Errorf("waits = %v, want %v", waits, want)
+			break
+		}
+	}
+}
+
+// TestPollBackoff_OtherMethods verifies only getUpdates is delayed after failed polls.
+func TestPollBackoff_OtherMethods(t *testing.T) {
+	p := newPollBackoff(&flakyTelegram{failures: 1}, time.Second, time.Minute)
+	p.sleep = func(context.Context, time.Duration) error {
+		t.Error("sendMessage was delayed")
+		return nil
+	}
+	if _, err := p.Do(newPollRequest(context.Background(), "getUpdates")); err == nil {
+		t.Fatal("first poll succeeded, want the stub's failure")
+	}
+	resp, err := p.Do(newPollRequest(context.Background(), "sendMessage"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+}
+
+// TestPollBackoff_Cancelled verifies a wait ends with the request's context.
+func TestPollBackoff_Cancelled(t *testing.T) {
+	p := newPollBackoff(&flakyTelegram{failures: 1}, time.Hour, time.Hour)
+	if _, err := p.Do(newPollRequest(context.Background(), "getUpdates")); err == nil {
+		t.Fatal("first poll succeeded, want the stub's failure")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := p.Do(newPollRequest(ctx, "getUpdates")); !errors.Is(err, context.Canceled) {
+		t.Errorf("Do() error = %v, want context.Canceled", err)
+	}
+}
+
+// TestPollBackoff_Start verifies the bot keeps polling through failed getUpdates calls
+// and handles the update delivered once Telegram is reachable again.
+func TestPollBackoff_Start(t *testing.T) {
+	stub := &flakyTelegram{failures: 2, update: `{"update_id":1,"message":{"message_id":1,"date":0,"chat":{"id":5,"type":"private"},"text":"hi"}}`}
+	p := newPollBackoff(stub, time.Millisecond, 10*time.Millisecond)
+	handled := make(chan string, 1)
+	api, err := bot.New("123:test", bot.WithSkipGetMe(), bot.WithHTTPClient(time.Second, p),
+		bot.WithDefaultHandler(func(_ context.Context, _ *bot.Bot, update *models.Update) {
+			handled <- update.Message.Text
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		api.Start(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	select {
+	case text := <-handled:
+		if text != "hi" {
+			t.Errorf("handled %q, want %q", text, "hi")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("update not handled after failed polls")
+	}
+}
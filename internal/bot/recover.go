@@ -0,0 +1,49 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// recoverHandler wraps a handler so that a panic is logged with the command and user
+// context and answered with a generic error, instead of taking down the bot
+func (b *Bot) recoverHandler(command string, next bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, api *bot.Bot, update *models.Update) {
+		defer func() {
+			if r := recover(); r != nil {
+				info := getUserFromUpdate(update)
+				log.Printf("Panic in %s handler (user %d @%s, chat %d): %v\n%s", command, info.UserID, info.Username, info.ChatID, r, debug.Stack())
+				b.replyHandlerPanic(ctx, update, info)
+			}
+		}()
+		next(ctx, api, update)
+	}
+}
+
+// replyHandlerPanic tells the user their update failed. Errors (and panics) while
+// replying are only logged, since the update is already lost.
+func (b *Bot) replyHandlerPanic(ctx context.Context, update *models.Update, info UserInfo) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic while replying to failed update: %v", r)
+		}
+	}()
+
+	if update.CallbackQuery != nil {
+		b.answerCallback(ctx, update.CallbackQuery.ID, b.localize(info.ChatID, "error.internal_callback"), true)
+		return
+	}
+	if info.ChatID == 0 {
+		return
+	}
+
+	replyTo := 0
+	if update.Message != nil {
+		replyTo = update.Message.ID
+	}
+	b.sendHTMLMessage(ctx, info.ChatID, info.MessageThreadID, b.localize(info.ChatID, "error.internal"), replyTo)
+}
@@ -0,0 +1,106 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/config"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// newTestTelegramAPI returns a bot client backed by a mock Telegram server that
+// records the body of every request it receives.
+func newTestTelegramAPI(t *testing.T) (*bot.Bot, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, r.URL.Path+"\n"+string(body))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/answerCallbackQuery") {
+			_, _ = w.Write([]byte(`{"ok":true,"result":true}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1,"date":0,"chat":{"id":1,"type":"private"}}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	api, err := bot.New("123:test", bot.WithSkipGetMe(), bot.WithServerURL(srv.URL))
+	if err != nil {
+		t.Fatalf("bot.New() error = %v", err)
+	}
+	return api, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requests...)
+	}
+}
+
+// TestRecoverHandler_Panic verifies a panicking handler is logged and answered
+// with a generic error instead of crashing the caller.
+func TestRecoverHandler_Panic(t *testing.T) {
+	api, requests := newTestTelegramAPI(t)
+	b := &Bot{api: api, middleware: NewMiddleware(&config.Config{})}
+
+	var logs bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(prev) })
+
+	handler := b.recoverHandler("boom", func(context.Context, *bot.Bot, *models.Update) {
+		panic("handler exploded")
+	})
+	update := &models.Update{Message: &models.Message{
+		ID:   10,
+		Text: "/boom",
+		Chat: models.Chat{ID: 555, Type: models.ChatTypePrivate},
+		From: &models.User{ID: 77, Username: "alice"},
+	}}
+
+	handler(context.Background(), api, update)
+
+	out := logs.String()
+	for _, want := range []string{"Panic in boom handler", "user 77", "chat 555", "handler exploded"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log output missing %q:\n%s", want, out)
+		}
+	}
+
+	reqs := requests()
+	if len(reqs) != 1 || !strings.Contains(reqs[0], "sendMessage") || !strings.Contains(reqs[0], "Something went wrong") {
+		t.Fatalf("requests = %q, want one sendMessage with the generic error", reqs)
+	}
+	if !strings.Contains(reqs[0], "555") {
+		t.Errorf("reply not sent to chat 555: %q", reqs[0])
+	}
+}
+
+// TestRecoverHandler_NoPanic verifies the wrapper is transparent for well-behaved handlers.
+func TestRecoverHandler_NoPanic(t *testing.T) {
+	api, requests := newTestTelegramAPI(t)
+	b := &Bot{api: api, middleware: NewMiddleware(&config.Config{})}
+
+	called := false
+	handler := b.recoverHandler("ok", func(context.Context, *bot.Bot, *models.Update) {
+		called = true
+	})
+	handler(context.Background(), api, &models.Update{Message: &models.Message{Chat: models.Chat{ID: 1}}})
+
+	if !called {
+		t.Error("wrapped handler was not called")
+	}
+	if reqs := requests(); len(reqs) != 0 {
+		t.Errorf("requests = %q, want none", reqs)
+	}
+}
@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// maxDisplayNameLength bounds the alias set with /rename, in characters
+const maxDisplayNameLength = 100
+
+// displayName returns the alias of t from names, or its Real-Debrid filename if it has none
+func displayName(names map[string]string, t realdebrid.Torrent) string {
+	if name := names[t.ID]; name != "" {
+		return name
+	}
+	return t.Filename
+}
+
+// lookupDisplayNames fetches the aliases of ids. Failures are only logged, so torrents
+// are shown under their filename instead.
+func (b *Bot) lookupDisplayNames(ctx context.Context, ids ...string) map[string]string {
+	names, err := b.torrentRepo.GetDisplayNames(ctx, ids)
+	if err != nil {
+		log.Printf("Warning: failed to get torrent display names: %v", err)
+		return nil
+	}
+	return names
+}
+
+// handleRenameCommand handles /rename <id> <name>. Real-Debrid can't rename torrents, so
+// the name is stored by the bot and shown in place of the filename in /list and /info.
+func (b *Bot) handleRenameCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "rename")
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) < 3 {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /rename &lt;torrent_id&gt; &lt;name&gt;", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "rename", update.Message.Text, startTime, false, "Missing arguments", 0)
+			return
+		}
+		torrentID, name := parts[1], strings.Join(parts[2:], " ")
+		if utf8.RuneCountInString(name) > maxDisplayNameLength {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> The name can be at most %d characters.", maxDisplayNameLength), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "rename", update.Message.Text, startTime, false, "Name too long", 0)
+			return
+		}
+
+		torrent, err := b.rdClient.GetTorrentInfo(torrentID)
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Could not retrieve torrent info: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "rename", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		if err := b.torrentRepo.SetDisplayName(ctx, user.ID, chatPK, torrentID, torrent.Filename, name); err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Failed to rename torrent: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "rename", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := fmt.Sprintf("<b>[OK]</b> Torrent <code>%s</code> will be shown as <code>%s</code>.", html.EscapeString(torrentID), html.EscapeString(name))
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "rename", update.Message.Text, startTime, true, "", len(text))
+	})
+}
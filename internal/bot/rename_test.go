@@ -0,0 +1,28 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// TestDisplayName verifies an alias is preferred over the filename only when one is set.
+func TestDisplayName(t *testing.T) {
+	names := map[string]string{"A": "My Show", "B": ""}
+	tests := []struct {
+		torrent realdebrid.Torrent
+		want    string
+	}{
+		{realdebrid.Torrent{ID: "A", Filename: "my.show.s01.mkv"}, "My Show"},
+		{realdebrid.Torrent{ID: "B", Filename: "b.mkv"}, "b.mkv"},
+		{realdebrid.Torrent{ID: "C", Filename: "c.mkv"}, "c.mkv"},
+	}
+	for _, tt := range tests {
+		if got := displayName(names, tt.torrent); got != tt.want {
+			t.Errorf("displayName(%s) = %q, want %q", tt.torrent.ID, got, tt.want)
+		}
+	}
+	if got := displayName(nil, tests[0].torrent); got != "my.show.s01.mkv" {
+		t.Errorf("displayName without names = %q", got)
+	}
+}
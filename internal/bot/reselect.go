@@ -0,0 +1,117 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// fileFilters are the filters accepted by /reselect <id> <filter>
+var fileFilters = []string{"all", "video", "largest"}
+
+// videoExtensions are the file extensions the "video" filter keeps
+var videoExtensions = map[string]bool{
+	".mkv": true, ".mp4": true, ".avi": true, ".mov": true, ".wmv": true, ".m4v": true,
+	".webm": true, ".ts": true, ".m2ts": true, ".flv": true, ".mpg": true, ".mpeg": true,
+}
+
+// filterFileIDs returns the IDs of the files of a torrent kept by filter, in file order
+func filterFileIDs(files []realdebrid.File, filter string) ([]int, error) {
+	var ids []int
+	switch filter {
+	case "all":
+		for _, f := range files {
+			ids = append(ids, f.ID)
+		}
+	case "video":
+		for _, f := range files {
+			if videoExtensions[strings.ToLower(path.Ext(f.Path))] {
+				ids = append(ids, f.ID)
+			}
+		}
+	case "largest":
+		var largest *realdebrid.File
+		for i := range files {
+			if largest == nil || files[i].Bytes > largest.Bytes {
+				largest = &files[i]
+			}
+		}
+		if largest != nil {
+			ids = append(ids, largest.ID)
+		}
+	default:
+		return nil, fmt.Errorf("unknown filter %q, use one of: %s", filter, strings.Join(fileFilters, ", "))
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no files match the %q filter", filter)
+	}
+	return ids, nil
+}
+
+// handleReselectCommand handles the /reselect command. It selects the files of a torrent
+// waiting for a file selection with a filter, e.g. only the video files or the largest one.
+func (b *Bot) handleReselectCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "reselect")
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) < 3 {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /reselect &lt;torrent_id&gt; all|video|largest", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "reselect", update.Message.Text, startTime, false, "Missing arguments", 0)
+			return
+		}
+		torrentID, filter := parts[1], strings.ToLower(parts[2])
+
+		torrent, err := b.rdClient.GetTorrentInfo(torrentID)
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Could not retrieve torrent info: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "reselect", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		if err := checkSelectable(torrent); err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Cannot select files of <code>%s</code>: %s", html.EscapeString(torrentID), html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "reselect", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		fileIDs, err := filterFileIDs(torrent.Files, filter)
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>[ERROR]</b> "+html.EscapeString(err.Error()), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "reselect", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		metadata := map[string]interface{}{"filter": filter, "file_ids": fileIDs}
+		if err := b.rdClient.SelectFiles(torrentID, fileIDs); err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Failed to select files: %s", html.EscapeString(err.Error())), update.Message.ID)
+			if user != nil {
+				if logErr := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, torrentID, torrent.Hash, torrent.Filename, "", "reselect", "error", torrent.Bytes, torrent.Progress, false, err.Error(), metadata); logErr != nil {
+					log.Printf("Warning: failed to log reselect error: %v", logErr)
+				}
+			}
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "reselect", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := fmt.Sprintf("<b>[OK]</b> Selected %d of %d files of <code>%s</code> (filter: %s). The download will start shortly.",
+			len(fileIDs), len(torrent.Files), html.EscapeString(truncateName(torrent.Filename, b.config.App.MaxFilenameDisplay)), filter)
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		if user != nil {
+			if err := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, torrentID, torrent.Hash, torrent.Filename, "", "reselect", "files_selected", torrent.Bytes, torrent.Progress, true, "", metadata); err != nil {
+				log.Printf("Warning: failed to log reselect: %v", err)
+			}
+		}
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "reselect", update.Message.Text, startTime, true, "", len(text))
+	})
+}
@@ -0,0 +1,41 @@
+package bot
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// TestFilterFileIDs verifies each filter selects the right file IDs.
+func TestFilterFileIDs(t *testing.T) {
+	files := []realdebrid.File{
+		{ID: 1, Path: "/Show/S01E01.MKV", Bytes: 700},
+		{ID: 2, Path: "/Show/S01E02.mp4", Bytes: 900},
+		{ID: 3, Path: "/Show/sample.txt", Bytes: 1},
+		{ID: 4, Path: "/Show/extras.zip", Bytes: 2000},
+	}
+	tests := []struct {
+		filter  string
+		files   []realdebrid.File
+		want    []int
+		wantErr bool
+	}{
+		{"all", files, []int{1, 2, 3, 4}, false},
+		{"video", files, []int{1, 2}, false},
+		{"largest", files, []int{4}, false},
+		{"video", files[2:], nil, true},
+		{"largest", nil, nil, true},
+		{"subtitles", files, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := filterFileIDs(tt.files, tt.filter)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("filterFileIDs(%q) error = %v, wantErr %v", tt.filter, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("filterFileIDs(%q) = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// handleRetryCommand handles /retry <torrent_id>. A failed torrent (magnet error, error,
+// virus or dead) is deleted and re-added from the magnet link it was added with, as
+// recorded in its torrent activities, and its files are selected like after /add.
+// Without a recorded magnet the torrent is left alone and the user is asked to re-send
+// the link.
+func (b *Bot) handleRetryCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "retry")
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) != 2 {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /retry &lt;torrent_id&gt;", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "retry", update.Message.Text, startTime, false, "Missing arguments", 0)
+			return
+		}
+		torrentID := parts[1]
+
+		fail := func(text, errMsg string) {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "retry", update.Message.Text, startTime, false, errMsg, 0)
+		}
+
+		torrent, err := b.rdClient.GetTorrentInfo(torrentID)
+		if err != nil {
+			fail(fmt.Sprintf("<b>[ERROR]</b> Could not retrieve torrent info: %s", html.EscapeString(err.Error())), err.Error())
+			return
+		}
+		if classifySubscriptionStatus(torrent.Status) != subscriptionFailed {
+			fail(fmt.Sprintf("<b>[ERROR]</b> Torrent <code>%s</code> is %s. Only failed torrents can be retried.", html.EscapeString(torrentID), realdebrid.FormatStatus(torrent.Status)), "Torrent has not failed")
+			return
+		}
+
+		magnetLink, err := b.torrentRepo.GetTorrentMagnet(ctx, torrentID)
+		if err != nil {
+			fail(fmt.Sprintf("<b>[ERROR]</b> Failed to look up the magnet link: %s", html.EscapeString(err.Error())), err.Error())
+			return
+		}
+		if magnetLink == "" {
+			fail(fmt.Sprintf("<b>[ERROR]</b> No magnet link is recorded for torrent <code>%s</code>. Please send the magnet link again.", html.EscapeString(torrentID)), "No magnet link recorded")
+			return
+		}
+
+		if err := b.rdClient.DeleteTorrent(torrentID); err != nil {
+			fail(fmt.Sprintf("<b>[ERROR]</b> Failed to delete the failed torrent: %s", html.EscapeString(err.Error())), err.Error())
+			return
+		}
+
+		response, err := b.rdClient.AddMagnet(magnetLink)
+		if err != nil {
+			text := fmt.Sprintf("<b>[ERROR]</b> The failed torrent was deleted, but adding it again failed: %s\n\nSend this magnet link to try again:\n<code>%s</code>", html.EscapeString(err.Error()), html.EscapeString(magnetLink))
+			fail(text, err.Error())
+			if user != nil {
+				if err := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, torrentID, torrent.Hash, torrent.Filename, magnetLink, "retry", "error", torrent.Bytes, 0, false, err.Error(), nil); err != nil {
+					log.Printf("Warning: failed to log retry error: %v", err)
+				}
+			}
+			return
+		}
+
+		cached, err := b.selectAddedFiles(response.ID, magnetLink, b.fileSelectFor(ctx, userPKOf(user)))
+		if err != nil {
+			log.Printf("Error selecting files for retried torrent %s: %v", response.ID, err)
+		}
+
+		text := fmt.Sprintf(
+			"<b>[OK]</b> Torrent <code>%s</code> was re-added.\n\n"+
+				"<i>New ID:</i> <code>%s</code>\n\n"+
+				"Use <code>/info %s</code> to check its status.",
+			html.EscapeString(torrentID), response.ID, response.ID,
+		)
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+
+		if user != nil {
+			if err := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, response.ID, torrent.Hash, torrent.Filename, magnetLink, "retry", "waiting_files_selection", torrent.Bytes, 0, true, "", cached.metadata(map[string]interface{}{"retried_from": torrentID})); err != nil {
+				log.Printf("Warning: failed to log retry: %v", err)
+			}
+		}
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "retry", update.Message.Text, startTime, true, "", len(text))
+	})
+}
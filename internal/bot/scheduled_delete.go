@@ -0,0 +1,177 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// maxScheduledDeleteHours is the longest delay accepted by /autodelete <id> <hours>
+	maxScheduledDeleteHours = 720 // 30 days
+
+	// scheduledDeletionCheckInterval defines how often due deletions are executed
+	scheduledDeletionCheckInterval = 1 * time.Minute
+)
+
+// parseDeleteDelay parses the hours argument of /autodelete <id> <hours>
+func parseDeleteDelay(hoursStr string) (time.Duration, error) {
+	hours, err := strconv.Atoi(hoursStr)
+	if err != nil || hours < 1 || hours > maxScheduledDeleteHours {
+		return 0, fmt.Errorf("please provide a valid number of hours (1 to %d)", maxScheduledDeleteHours)
+	}
+	return time.Duration(hours) * time.Hour, nil
+}
+
+// scheduleTorrentDeletion handles /autodelete <id> <hours>: it schedules torrentID to be
+// deleted once the delay has passed, e.g. after users have had time to grab its links.
+// The caller has already checked that the user is a superadmin.
+func (b *Bot) scheduleTorrentDeletion(ctx context.Context, update *models.Update, user *db.User, chatID, chatPK int64, messageThreadID int, torrentID, hoursStr string, startTime time.Time) {
+	delay, err := parseDeleteDelay(hoursStr)
+	if err != nil {
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>[ERROR]</b> "+html.EscapeString(err.Error()), update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "autodelete", update.Message.Text, startTime, false, "Invalid hours value", 0)
+		return
+	}
+
+	torrent, err := b.rdClient.GetTorrentInfo(torrentID)
+	if err != nil {
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Could not retrieve torrent info: %s", html.EscapeString(err.Error())), update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "autodelete", update.Message.Text, startTime, false, err.Error(), 0)
+		return
+	}
+
+	deleteAt := time.Now().Add(delay)
+	if err := b.scheduledRepo.Schedule(ctx, torrentID, deleteAt, chatPK, messageThreadID, userPKOf(user)); err != nil {
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Failed to schedule deletion: %s", html.EscapeString(err.Error())), update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "autodelete", update.Message.Text, startTime, false, err.Error(), 0)
+		return
+	}
+
+	text := fmt.Sprintf(
+		"<b>⏳ Deletion Scheduled</b>\n\n"+
+			"<i>File:</i> <code>%s</code>\n"+
+			"<i>ID:</i> <code>%s</code>\n"+
+			"<i>Deletes at:</i> %s (in %s)\n\n"+
+			"Use <code>/canceldelete %s</code> to cancel.",
+		html.EscapeString(truncateName(torrent.Filename, b.config.App.MaxFilenameDisplay)),
+		html.EscapeString(torrentID),
+		deleteAt.UTC().Format("2006-01-02 15:04 UTC"),
+		formatDuration(delay),
+		html.EscapeString(torrentID),
+	)
+	b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+	b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "autodelete", update.Message.Text, startTime, true, "", len(text))
+}
+
+// handleCancelDeleteCommand handles the /canceldelete command (superadmin only)
+func (b *Bot) handleCancelDeleteCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "canceldelete")
+
+		if !role.IsSuperAdmin() {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, b.localize(chatID, "error.superadmin_only"), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "canceldelete", update.Message.Text, startTime, false, "Unauthorized - not superadmin", 0)
+			return
+		}
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) < 2 {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /canceldelete &lt;torrent_id&gt;", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "canceldelete", update.Message.Text, startTime, false, "Missing arguments", 0)
+			return
+		}
+		torrentID := parts[1]
+
+		if err := b.scheduledRepo.Cancel(ctx, torrentID); err != nil {
+			text := fmt.Sprintf("<b>[ERROR]</b> Failed to cancel deletion: %s", html.EscapeString(err.Error()))
+			if errors.Is(err, db.ErrNotScheduled) {
+				text = fmt.Sprintf("<b>[ERROR]</b> No deletion is scheduled for <code>%s</code>.", html.EscapeString(torrentID))
+			}
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "canceldelete", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := fmt.Sprintf("<b>[OK]</b> Scheduled deletion of <code>%s</code> cancelled.", html.EscapeString(torrentID))
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "canceldelete", update.Message.Text, startTime, true, "", len(text))
+	})
+}
+
+// startScheduledDeletionWorker periodically executes scheduled deletions that are due
+func (b *Bot) startScheduledDeletionWorker(ctx context.Context) {
+	ticker := time.NewTicker(scheduledDeletionCheckInterval)
+	defer ticker.Stop()
+
+	log.Printf("Scheduled deletion worker started (checking every %s)", formatDuration(scheduledDeletionCheckInterval))
+	b.health.registerWorker("scheduled_deletions")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Scheduled deletion worker stopped")
+			return
+		case <-ticker.C:
+			b.runScheduledDeletions(ctx)
+			b.health.markWorkerRun("scheduled_deletions")
+		}
+	}
+}
+
+// runScheduledDeletions deletes every torrent whose scheduled time has passed and reports
+// the outcome in the chat it was scheduled from. Deletions failing with a transient error
+// stay scheduled and are retried on the next run.
+func (b *Bot) runScheduledDeletions(ctx context.Context) {
+	schedules, err := b.scheduledRepo.ListDue(ctx)
+	if err != nil {
+		log.Printf("Scheduled deletion: failed to list schedules: %v", err)
+		return
+	}
+
+	for _, s := range schedules {
+		if ctx.Err() != nil {
+			return
+		}
+
+		deleteErr := b.rdClient.DeleteTorrent(s.TorrentID)
+		if deleteErr != nil && isRetryableHTTPError(deleteErr) {
+			log.Printf("Scheduled deletion: transient error deleting %s, will retry: %v", s.TorrentID, deleteErr)
+			continue
+		}
+		if err := b.scheduledRepo.Remove(ctx, s.TorrentID); err != nil {
+			log.Printf("Scheduled deletion: failed to remove schedule for %s: %v", s.TorrentID, err)
+		}
+
+		userPK := s.UserPK
+		if userPK == 0 {
+			userPK = b.systemUserID
+		}
+		status, errMsg := "scheduled_deleted", ""
+		text := fmt.Sprintf("<b>[OK]</b> Scheduled deletion of <code>%s</code> completed.", html.EscapeString(s.TorrentID))
+		if deleteErr != nil {
+			status, errMsg = "error", deleteErr.Error()
+			text = fmt.Sprintf("<b>[ERROR]</b> Scheduled deletion of <code>%s</code> failed: %s", html.EscapeString(s.TorrentID), html.EscapeString(deleteErr.Error()))
+			log.Printf("Scheduled deletion: failed to delete %s: %v", s.TorrentID, deleteErr)
+		} else {
+			log.Printf("Scheduled deletion: deleted torrent %s", s.TorrentID)
+		}
+		if err := b.torrentRepo.LogTorrentActivity(ctx, "", userPK, s.ChatPK, s.TorrentID, "", "", "", "delete", status, 0, 0, deleteErr == nil, errMsg, map[string]interface{}{"scheduled_for": s.DeleteAt}); err != nil {
+			log.Printf("Scheduled deletion: failed to log torrent deletion: %v", err)
+		}
+
+		if err := b.sendHTMLMessageWithErr(ctx, s.ChatID, int(s.ThreadID), text, 0); err != nil {
+			log.Printf("Scheduled deletion: failed to report deletion of %s: %v", s.TorrentID, err)
+		}
+	}
+}
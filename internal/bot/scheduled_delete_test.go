@@ -0,0 +1,30 @@
+package bot
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseDeleteDelay verifies the accepted range of /autodelete <id> <hours>.
+func TestParseDeleteDelay(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"1", time.Hour, false},
+		{"48", 48 * time.Hour, false},
+		{"720", 720 * time.Hour, false},
+		{"0", 0, true},
+		{"-3", 0, true},
+		{"721", 0, true},
+		{"1.5", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseDeleteDelay(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseDeleteDelay(%q) = (%s, %v), want (%s, err %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// securityReportLimit bounds how many unauthorized attempts /security lists
+const securityReportLimit = 10
+
+// formatSecurityReport renders the unauthorized attempts recorded for a Telegram user
+func formatSecurityReport(telegramUserID int64, attempts []db.ActivityEntry, limit int) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "<b>🛡 Security Report</b>\n\n<i>User ID:</i> <code>%d</code>\n\n", telegramUserID)
+
+	if len(attempts) == 0 {
+		text.WriteString("No unauthorized attempts have been recorded for this user.")
+		return text.String()
+	}
+
+	fmt.Fprintf(&text, "<b>Recent unauthorized attempts:</b>\n")
+	for _, a := range attempts {
+		chat := fmt.Sprintf("<code>%d</code>", a.ChatID)
+		if a.ChatTitle != "" {
+			chat += " (" + html.EscapeString(a.ChatTitle) + ")"
+		}
+		fmt.Fprintf(&text, "• %s — chat %s", a.CreatedAt.UTC().Format("2006-01-02 15:04 UTC"), chat)
+		if a.ThreadID != 0 {
+			fmt.Fprintf(&text, ", topic %d", a.ThreadID)
+		}
+		text.WriteString("\n")
+	}
+	if len(attempts) >= limit {
+		fmt.Fprintf(&text, "\n<i>Showing the %d most recent attempts.</i>", limit)
+	}
+	return strings.TrimRight(text.String(), "\n")
+}
+
+// handleSecurityCommand handles the /security command. Users see the unauthorized
+// attempts recorded under their own ID; moderators and superadmins may pass another user's ID.
+func (b *Bot) handleSecurityCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "security")
+
+		if update.Message.From == nil {
+			return
+		}
+		target := update.Message.From.ID
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) > 1 {
+			id, err := strconv.ParseInt(parts[1], 10, 64)
+			if err != nil {
+				b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /security [user_id]", update.Message.ID)
+				b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "security", update.Message.Text, startTime, false, "Invalid user ID", 0)
+				return
+			}
+			if id != target && !role.CanModerate() {
+				b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>[ERROR]</b> Access Denied. Only moderators and superadmins can view other users' security reports.", update.Message.ID)
+				b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "security", update.Message.Text, startTime, false, "Unauthorized - not moderator", 0)
+				return
+			}
+			target = id
+		}
+
+		attempts, err := b.activityRepo.ListUserActivities(ctx, target, db.ActivityTypeUnauthorized, securityReportLimit)
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Failed to load security report: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "security", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := formatSecurityReport(target, attempts, securityReportLimit)
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "security", update.Message.Text, startTime, true, "", len(text))
+	})
+}
@@ -0,0 +1,41 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+)
+
+// TestFormatSecurityReport_Empty verifies the empty case is stated plainly.
+func TestFormatSecurityReport_Empty(t *testing.T) {
+	text := formatSecurityReport(42, nil, securityReportLimit)
+	if !strings.Contains(text, "<code>42</code>") || !strings.Contains(text, "No unauthorized attempts") {
+		t.Errorf("unexpected empty report: %q", text)
+	}
+}
+
+// TestFormatSecurityReport_Attempts verifies each attempt lists its time, chat and topic,
+// and that a full page notes it only shows the most recent entries.
+func TestFormatSecurityReport_Attempts(t *testing.T) {
+	at := time.Date(2026, 3, 4, 5, 6, 0, 0, time.UTC)
+	attempts := []db.ActivityEntry{
+		{ChatID: -100, ChatTitle: "<Group>", ThreadID: 7, CreatedAt: at},
+		{ChatID: 42, CreatedAt: at.Add(-time.Hour)},
+	}
+
+	text := formatSecurityReport(42, attempts, 5)
+	for _, want := range []string{"2026-03-04 05:06 UTC", "<code>-100</code> (&lt;Group&gt;), topic 7", "2026-03-04 04:06 UTC — chat <code>42</code>"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("report missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "most recent") {
+		t.Errorf("partial page should not mention the limit:\n%s", text)
+	}
+
+	if full := formatSecurityReport(42, attempts, 2); !strings.Contains(full, "2 most recent") {
+		t.Errorf("full page should mention the limit:\n%s", full)
+	}
+}
@@ -0,0 +1,81 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// checkSelectable returns an error explaining why the files of t can't be selected.
+// Real-Debrid only accepts a file selection while the torrent waits for one; before
+// that the magnet is still being converted and afterwards the selection is fixed.
+func checkSelectable(t *realdebrid.Torrent) error {
+	switch {
+	case t.Status == "waiting_files_selection":
+		return nil
+	case t.Status == "magnet_conversion":
+		return fmt.Errorf("the magnet is still being converted, try again in a moment")
+	case classifySubscriptionStatus(t.Status) == subscriptionFailed:
+		return fmt.Errorf("the torrent failed (status: %s), use /retry %s to add it again", realdebrid.FormatStatus(t.Status), t.ID)
+	default:
+		return fmt.Errorf("files are already selected (status: %s)", realdebrid.FormatStatus(t.Status))
+	}
+}
+
+// handleSelectAllCommand handles the /selectall command. It selects every file of a
+// torrent left waiting for a file selection, e.g. when selection failed during /add.
+func (b *Bot) handleSelectAllCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "selectall")
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) < 2 {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /selectall &lt;torrent_id&gt;", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "selectall", update.Message.Text, startTime, false, "Missing arguments", 0)
+			return
+		}
+		torrentID := parts[1]
+
+		torrent, err := b.rdClient.GetTorrentInfo(torrentID)
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Could not retrieve torrent info: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "selectall", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		if err := checkSelectable(torrent); err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Cannot select files of <code>%s</code>: %s", html.EscapeString(torrentID), html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "selectall", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		if err := b.rdClient.SelectAllFiles(torrentID); err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Failed to select files: %s", html.EscapeString(err.Error())), update.Message.ID)
+			if user != nil {
+				if logErr := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, torrentID, torrent.Hash, torrent.Filename, "", "select_files", "error", torrent.Bytes, torrent.Progress, false, err.Error(), nil); logErr != nil {
+					log.Printf("Warning: failed to log select files error: %v", logErr)
+				}
+			}
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "selectall", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := fmt.Sprintf("<b>[OK]</b> Selected all %d files of <code>%s</code>. The download will start shortly.", len(torrent.Files), html.EscapeString(truncateName(torrent.Filename, b.config.App.MaxFilenameDisplay)))
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		if user != nil {
+			if err := b.torrentRepo.LogTorrentActivity(ctx, "", user.ID, chatPK, torrentID, torrent.Hash, torrent.Filename, "", "select_files", "files_selected", torrent.Bytes, torrent.Progress, true, "", nil); err != nil {
+				log.Printf("Warning: failed to log select files: %v", err)
+			}
+		}
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "selectall", update.Message.Text, startTime, true, "", len(text))
+	})
+}
@@ -0,0 +1,40 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// TestCheckSelectable verifies files can only be selected while the torrent waits for a selection.
+func TestCheckSelectable(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{"waiting_files_selection", true},
+		{"magnet_conversion", false},
+		{"queued", false},
+		{"downloading", false},
+		{"downloaded", false},
+		{"error", false},
+	}
+	for _, tt := range tests {
+		err := checkSelectable(&realdebrid.Torrent{Status: tt.status})
+		if (err == nil) != tt.want {
+			t.Errorf("checkSelectable(%q) error = %v, want selectable %v", tt.status, err, tt.want)
+		}
+	}
+}
+
+// TestCheckSelectable_Failed verifies failed torrents point to /retry instead of
+// claiming their files are already selected.
+func TestCheckSelectable_Failed(t *testing.T) {
+	for _, status := range []string{"error", "magnet_error", "virus", "dead"} {
+		err := checkSelectable(&realdebrid.Torrent{ID: "ABC", Status: status})
+		if err == nil || !strings.Contains(err.Error(), "/retry ABC") {
+			t.Errorf("checkSelectable(%q) error = %v, want a pointer to /retry ABC", status, err)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package bot
+
+import (
+	"context"
+	"log"
+)
+
+// RegisterShutdownHook adds a cleanup function to run when the bot stops.
+// Hooks run in registration order and share the shutdown context, so they must
+// return once ctx is done. Errors are logged and do not stop later hooks.
+func (b *Bot) RegisterShutdownHook(hook func(context.Context) error) {
+	b.shutdownMu.Lock()
+	defer b.shutdownMu.Unlock()
+	b.shutdownHooks = append(b.shutdownHooks, hook)
+}
+
+// runShutdownHooks runs all registered hooks in order
+func (b *Bot) runShutdownHooks(ctx context.Context) {
+	b.shutdownMu.Lock()
+	hooks := append([]func(context.Context) error(nil), b.shutdownHooks...)
+	b.shutdownMu.Unlock()
+
+	for i, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			log.Printf("Warning: shutdown hook %d failed: %v", i+1, err)
+		}
+	}
+}
+
+// stopWorkers cancels the bot context and waits for background workers to exit
+func (b *Bot) stopWorkers(ctx context.Context) error {
+	if b.cancel != nil {
+		b.cancel()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,73 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestStop_RunsShutdownHooksInOrder verifies every hook runs once, in registration order,
+// even when an earlier hook fails.
+func TestStop_RunsShutdownHooksInOrder(t *testing.T) {
+	b := &Bot{}
+	var order []string
+	b.RegisterShutdownHook(func(context.Context) error {
+		order = append(order, "first")
+		return errors.New("boom")
+	})
+	b.RegisterShutdownHook(func(context.Context) error {
+		order = append(order, "second")
+		return nil
+	})
+
+	b.Stop(context.Background())
+
+	if want := []string{"first", "second"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("hooks ran as %v, want %v", order, want)
+	}
+}
+
+// TestStop_HooksReceiveShutdownDeadline verifies hooks get the caller's shutdown budget.
+func TestStop_HooksReceiveShutdownDeadline(t *testing.T) {
+	b := &Bot{}
+	var hasDeadline bool
+	b.RegisterShutdownHook(func(ctx context.Context) error {
+		_, hasDeadline = ctx.Deadline()
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	b.Stop(ctx)
+
+	if !hasDeadline {
+		t.Error("hook context has no deadline")
+	}
+}
+
+// TestStopWorkers verifies workers are cancelled and awaited, and that a stuck worker
+// cannot block shutdown past the deadline.
+func TestStopWorkers(t *testing.T) {
+	b := &Bot{}
+	workerCtx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		<-workerCtx.Done()
+	}()
+
+	if err := b.stopWorkers(context.Background()); err != nil {
+		t.Fatalf("stopWorkers() error = %v", err)
+	}
+
+	stuck := &Bot{}
+	stuck.wg.Add(1) // never done
+	ctx, cancelTimeout := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelTimeout()
+	if err := stuck.stopWorkers(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("stopWorkers() with stuck worker error = %v, want deadline exceeded", err)
+	}
+}
@@ -0,0 +1,239 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// statusBoardMaxEntries bounds how many in-progress torrents a status board lists
+const statusBoardMaxEntries = 10
+
+// statusBoard is a pinned message that is periodically edited with the queue summary
+type statusBoard struct {
+	messageID int
+	threadID  int
+}
+
+// statusBoardStore tracks the pinned status board of each chat
+type statusBoardStore struct {
+	mu     sync.Mutex
+	boards map[int64]statusBoard
+}
+
+// newStatusBoardStore creates an empty statusBoardStore
+func newStatusBoardStore() *statusBoardStore {
+	return &statusBoardStore{boards: make(map[int64]statusBoard)}
+}
+
+// set records the board for chatID, replacing any previous one
+func (s *statusBoardStore) set(chatID int64, board statusBoard) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.boards[chatID] = board
+}
+
+// remove forgets the board for chatID and returns it, if there was one
+func (s *statusBoardStore) remove(chatID int64) (statusBoard, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	board, ok := s.boards[chatID]
+	delete(s.boards, chatID)
+	return board, ok
+}
+
+// snapshot returns a copy of all boards
+func (s *statusBoardStore) snapshot() map[int64]statusBoard {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	boards := make(map[int64]statusBoard, len(s.boards))
+	for chatID, board := range s.boards {
+		boards[chatID] = board
+	}
+	return boards
+}
+
+// formatStatusBoard renders the queue summary shown on a pinned status board
+func formatStatusBoard(torrents []realdebrid.Torrent, active *realdebrid.ActiveCount, now time.Time) string {
+	var text strings.Builder
+	text.WriteString("<b>📌 Torrent Queue</b>\n\n")
+	if active != nil {
+		fmt.Fprintf(&text, "<i>Active:</i> %d / %d\n\n", active.Nb, active.Limit)
+	}
+
+	shown, pending := 0, 0
+	for _, t := range torrents {
+		if classifySubscriptionStatus(t.Status) != subscriptionPending {
+			continue
+		}
+		pending++
+		if shown == statusBoardMaxEntries {
+			continue
+		}
+		shown++
+		fmt.Fprintf(&text, "• <code>%s</code>\n  %s, %.1f%%", html.EscapeString(t.Filename), realdebrid.FormatStatus(t.Status), t.Progress)
+		if t.Speed > 0 {
+			fmt.Fprintf(&text, ", %s/s", realdebrid.FormatSize(t.Speed))
+		}
+		text.WriteString("\n")
+	}
+	switch {
+	case pending == 0:
+		text.WriteString("Nothing in progress.\n")
+	case pending > shown:
+		fmt.Fprintf(&text, "<i>…and %d more</i>\n", pending-shown)
+	}
+
+	fmt.Fprintf(&text, "\n<i>Updated:</i> %s UTC", now.UTC().Format("2006-01-02 15:04"))
+	return text.String()
+}
+
+// isPinPermissionError reports whether err means the bot may not pin messages in the chat
+func isPinPermissionError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "not enough rights") || strings.Contains(msg, "chat_admin_required")
+}
+
+// buildStatusBoardText fetches the current queue and renders it
+func (b *Bot) buildStatusBoardText() (string, error) {
+	torrents, err := b.rdClient.GetTorrents(100, 0)
+	if err != nil {
+		return "", err
+	}
+	active, err := b.rdClient.GetActiveCount()
+	if err != nil {
+		log.Printf("Status board: failed to get active count: %v", err)
+	}
+	return formatStatusBoard(torrents, active, time.Now()), nil
+}
+
+// handlePinStatusCommand handles the /pinstatus command (superadmin only)
+func (b *Bot) handlePinStatusCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "pinstatus")
+
+		if !role.IsSuperAdmin() {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, b.localize(chatID, "error.superadmin_only"), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "pinstatus", update.Message.Text, startTime, false, "Unauthorized - not superadmin", 0)
+			return
+		}
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) > 1 && strings.EqualFold(parts[1], "off") {
+			text := "No status board is pinned in this chat."
+			if board, ok := b.statusBoards.remove(chatID); ok {
+				if _, err := b.api.UnpinChatMessage(ctx, &bot.UnpinChatMessageParams{ChatID: chatID, MessageID: board.messageID}); err != nil {
+					log.Printf("Status board: failed to unpin message %d in chat %d: %v", board.messageID, chatID, err)
+				}
+				text = "<b>[OK]</b> Status board stopped and unpinned."
+			}
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "pinstatus", update.Message.Text, startTime, true, "", len(text))
+			return
+		}
+
+		text, err := b.buildStatusBoardText()
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Failed to retrieve torrents: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "pinstatus", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		params := &bot.SendMessageParams{
+			ChatID:    chatID,
+			Text:      text,
+			ParseMode: models.ParseModeHTML,
+		}
+		if messageThreadID != 0 {
+			params.MessageThreadID = messageThreadID
+		}
+		if err := b.middleware.WaitForRateLimitWithContext(ctx); err != nil {
+			return
+		}
+		sent, err := b.api.SendMessage(ctx, params)
+		if err != nil {
+			log.Printf("Status board: failed to send board in chat %d: %v", chatID, err)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "pinstatus", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		if _, err := b.api.PinChatMessage(ctx, &bot.PinChatMessageParams{ChatID: chatID, MessageID: sent.ID, DisableNotification: true}); err != nil {
+			errText := fmt.Sprintf("<b>[ERROR]</b> Could not pin the status board: %s", html.EscapeString(err.Error()))
+			if isPinPermissionError(err) {
+				errText = "<b>[ERROR]</b> Could not pin the status board. Make the bot an admin with the <i>Pin messages</i> permission and try again."
+			}
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, errText, update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "pinstatus", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		b.statusBoards.set(chatID, statusBoard{messageID: sent.ID, threadID: messageThreadID})
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "pinstatus", update.Message.Text, startTime, true, "", len(text))
+	})
+}
+
+// startStatusBoardWorker refreshes pinned status boards on app.pin_status_refresh_minutes
+func (b *Bot) startStatusBoardWorker(ctx context.Context) {
+	interval := time.Duration(b.config.App.PinStatusRefreshMinutes) * time.Minute
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Printf("Status board worker started (refreshing every %s)", formatDuration(interval))
+	b.health.registerWorker("status_board")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Status board worker stopped")
+			return
+		case <-ticker.C:
+			b.refreshStatusBoards(ctx)
+			b.health.markWorkerRun("status_board")
+		}
+	}
+}
+
+// refreshStatusBoards edits every pinned board in place with the current queue.
+// Boards whose message was deleted are forgotten.
+func (b *Bot) refreshStatusBoards(ctx context.Context) {
+	boards := b.statusBoards.snapshot()
+	if len(boards) == 0 {
+		return
+	}
+
+	text, err := b.buildStatusBoardText()
+	if err != nil {
+		log.Printf("Status board: failed to build board: %v", err)
+		return
+	}
+
+	for chatID, board := range boards {
+		if err := b.middleware.WaitForRateLimitWithContext(ctx); err != nil {
+			return
+		}
+		_, err := b.api.EditMessageText(ctx, &bot.EditMessageTextParams{
+			ChatID:    chatID,
+			MessageID: board.messageID,
+			Text:      text,
+			ParseMode: models.ParseModeHTML,
+		})
+		switch {
+		case err == nil, strings.Contains(err.Error(), "message is not modified"):
+		case strings.Contains(err.Error(), "message to edit not found"):
+			log.Printf("Status board: message %d in chat %d was deleted, stopping updates", board.messageID, chatID)
+			b.statusBoards.remove(chatID)
+		default:
+			log.Printf("Status board: failed to update chat %d: %v", chatID, err)
+		}
+	}
+}
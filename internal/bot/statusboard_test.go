@@ -0,0 +1,79 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// TestFormatStatusBoard verifies only in-progress torrents are listed, with the active count and timestamp.
+func TestFormatStatusBoard(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)
+	torrents := []realdebrid.Torrent{
+		{Filename: "done.mkv", Status: "downloaded", Progress: 100},
+		{Filename: "busy<1>.mkv", Status: "downloading", Progress: 42.5, Speed: 2048},
+		{Filename: "waiting.iso", Status: "queued"},
+	}
+
+	text := formatStatusBoard(torrents, &realdebrid.ActiveCount{Nb: 2, Limit: 25}, now)
+
+	for _, want := range []string{"2 / 25", "busy&lt;1&gt;.mkv", "42.5%", "2.00 KB/s", "waiting.iso", "2026-01-02 03:04 UTC"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("formatStatusBoard() missing %q in %q", want, text)
+		}
+	}
+	if strings.Contains(text, "done.mkv") {
+		t.Error("formatStatusBoard() lists a finished torrent")
+	}
+}
+
+// TestFormatStatusBoard_EmptyAndOverflow verifies the empty state and the overflow note.
+func TestFormatStatusBoard_EmptyAndOverflow(t *testing.T) {
+	empty := formatStatusBoard(nil, nil, time.Now())
+	if !strings.Contains(empty, "Nothing in progress") {
+		t.Errorf("formatStatusBoard(nil) = %q, want empty state", empty)
+	}
+
+	var torrents []realdebrid.Torrent
+	for i := range statusBoardMaxEntries + 3 {
+		torrents = append(torrents, realdebrid.Torrent{Filename: fmt.Sprintf("t%d", i), Status: "downloading"})
+	}
+	full := formatStatusBoard(torrents, nil, time.Now())
+	if !strings.Contains(full, "and 3 more") {
+		t.Errorf("formatStatusBoard() overflow = %q, want \"and 3 more\"", full)
+	}
+}
+
+// TestStatusBoardStore verifies boards can be replaced, listed and removed per chat.
+func TestStatusBoardStore(t *testing.T) {
+	s := newStatusBoardStore()
+	s.set(-100, statusBoard{messageID: 1})
+	s.set(-100, statusBoard{messageID: 2, threadID: 5})
+	s.set(-200, statusBoard{messageID: 3})
+
+	boards := s.snapshot()
+	if len(boards) != 2 || boards[-100].messageID != 2 || boards[-100].threadID != 5 {
+		t.Fatalf("snapshot() = %+v", boards)
+	}
+
+	if board, ok := s.remove(-100); !ok || board.messageID != 2 {
+		t.Errorf("remove(-100) = (%+v, %v), want message 2", board, ok)
+	}
+	if _, ok := s.remove(-100); ok {
+		t.Error("remove(-100) twice reported a board")
+	}
+}
+
+// TestIsPinPermissionError verifies Telegram's missing-rights errors are recognised.
+func TestIsPinPermissionError(t *testing.T) {
+	if !isPinPermissionError(errors.New("bad request, Bad Request: not enough rights to manage pinned messages in the chat")) {
+		t.Error("not enough rights error not recognised")
+	}
+	if isPinPermissionError(errors.New("bad request, Bad Request: message to pin not found")) {
+		t.Error("unrelated error recognised as a permission error")
+	}
+}
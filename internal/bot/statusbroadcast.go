@@ -0,0 +1,123 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// nextStatusBroadcast returns the first time after now that falls on the HH:MM time of
+// day at in loc
+func nextStatusBroadcast(now time.Time, at time.Time, loc *time.Location) time.Time {
+	now = now.In(loc)
+	next := time.Date(now.Year(), now.Month(), now.Day(), at.Hour(), at.Minute(), 0, 0, loc)
+	if !next.After(now) {
+		next = time.Date(now.Year(), now.Month(), now.Day()+1, at.Hour(), at.Minute(), 0, 0, loc)
+	}
+	return next
+}
+
+// runDaily calls fire every day at the HH:MM time of day at in loc until ctx is done
+func runDaily(ctx context.Context, at time.Time, loc *time.Location, now func() time.Time, fire func(context.Context)) {
+	for {
+		timer := time.NewTimer(nextStatusBroadcast(now(), at, loc).Sub(now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			fire(ctx)
+		}
+	}
+}
+
+// buildStatusBroadcastText renders the /status summary followed by the active torrent
+// count and the combined size of all torrents
+func (b *Bot) buildStatusBroadcastText() (string, error) {
+	rdUser, err := b.getRDUser()
+	if err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	text.WriteString(formatAccountStatus(rdUser, b.displayTime))
+	if active, err := b.rdClient.GetActiveCount(); err != nil {
+		log.Printf("Status broadcast: failed to get active count: %v", err)
+	} else {
+		fmt.Fprintf(&text, "<i>Active Torrents:</i> %d / %d\n", active.Nb, active.Limit)
+	}
+
+	const pageSize = 2500
+	var totalBytes int64
+	for offset := 0; ; offset += pageSize {
+		page, err := b.rdClient.GetTorrents(pageSize, offset)
+		if err != nil {
+			log.Printf("Status broadcast: failed to get torrents at offset %d: %v", offset, err)
+			return text.String(), nil
+		}
+		for _, t := range page {
+			totalBytes += t.Bytes
+		}
+		if len(page) < pageSize {
+			break
+		}
+	}
+	fmt.Fprintf(&text, "<i>Total Size:</i> %s\n", realdebrid.FormatSize(totalBytes))
+	return text.String(), nil
+}
+
+// sendStatusBroadcast posts the account status to telegram.status_broadcast_chat
+func (b *Bot) sendStatusBroadcast(ctx context.Context) {
+	text, err := b.buildStatusBroadcastText()
+	if err != nil {
+		log.Printf("Status broadcast: failed to get account status: %v", err)
+		return
+	}
+
+	params := &bot.SendMessageParams{
+		ChatID:    b.config.Telegram.StatusBroadcastChat,
+		Text:      text,
+		ParseMode: models.ParseModeHTML,
+	}
+	if thread := b.config.Telegram.StatusBroadcastThread; thread != 0 {
+		params.MessageThreadID = thread
+	}
+	if err := b.middleware.WaitForRateLimitWithContext(ctx); err != nil {
+		return
+	}
+	if _, err := b.api.SendMessage(ctx, params); err != nil {
+		log.Printf("Status broadcast: failed to send to chat %d: %v", params.ChatID, err)
+	}
+}
+
+// startStatusBroadcastWorker posts the account status daily at telegram.status_broadcast_time.
+// It returns immediately when telegram.status_broadcast_chat is not set.
+func (b *Bot) startStatusBroadcastWorker(ctx context.Context) {
+	chatID := b.config.Telegram.StatusBroadcastChat
+	if chatID == 0 {
+		return
+	}
+	at, err := time.Parse("15:04", b.config.Telegram.StatusBroadcastTime)
+	if err != nil {
+		log.Printf("Status broadcast disabled: invalid time %q: %v", b.config.Telegram.StatusBroadcastTime, err)
+		return
+	}
+
+	loc := b.location
+	if loc == nil {
+		loc = time.UTC
+	}
+	log.Printf("Status broadcast worker started (posting to chat %d daily at %s %s)", chatID, at.Format("15:04"), loc)
+	b.health.registerWorker("status_broadcast")
+	runDaily(ctx, at, loc, time.Now, func(ctx context.Context) {
+		b.sendStatusBroadcast(ctx)
+		b.health.markWorkerRun("status_broadcast")
+	})
+	log.Println("Status broadcast worker stopped")
+}
@@ -0,0 +1,76 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestNextStatusBroadcast verifies the post is scheduled later today, or tomorrow once the time has passed.
+func TestNextStatusBroadcast(t *testing.T) {
+	at, _ := time.Parse("15:04", "09:30")
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"before", time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC), time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)},
+		{"exactly at", time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC), time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)},
+		{"after", time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC), time.Date(2026, 4, 1, 9, 30, 0, 0, time.UTC)},
+		{"other zone", time.Date(2026, 3, 1, 10, 0, 0, 0, time.FixedZone("CET", 3600)), time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextStatusBroadcast(tt.now, at, time.UTC); !got.Equal(tt.want) {
+				t.Errorf("nextStatusBroadcast() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNextStatusBroadcast_Timezone verifies the time of day is read in app.timezone,
+// including across a daylight saving change.
+func TestNextStatusBroadcast_Timezone(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	at, _ := time.Parse("15:04", "09:00")
+
+	// 07:30 UTC is 08:30 in Berlin in winter, so 09:00 local is still ahead today
+	now := time.Date(2026, 1, 10, 7, 30, 0, 0, time.UTC)
+	if got, want := nextStatusBroadcast(now, at, loc), time.Date(2026, 1, 10, 8, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("nextStatusBroadcast() = %v, want %v", got, want)
+	}
+
+	// Clocks go forward on 2026-03-29; the next post is still at 09:00 local
+	now = time.Date(2026, 3, 28, 9, 0, 0, 0, time.UTC)
+	if got, want := nextStatusBroadcast(now, at, loc), time.Date(2026, 3, 29, 7, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("nextStatusBroadcast() across DST = %v, want %v", got, want)
+	}
+}
+
+// TestRunDaily_Fires verifies the scheduled function runs once the time of day is reached.
+func TestRunDaily_Fires(t *testing.T) {
+	at, _ := time.Parse("15:04", "12:00")
+	start := time.Now()
+	fake := time.Date(2026, 3, 1, 11, 59, 59, 950_000_000, time.UTC)
+	now := func() time.Time { return fake.Add(time.Since(start)) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fired := make(chan struct{}, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runDaily(ctx, at, time.UTC, now, func(context.Context) { fired <- struct{}{} })
+	}()
+
+	select {
+	case <-fired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduled function did not run")
+	}
+	cancel()
+	<-done
+}
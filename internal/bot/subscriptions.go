@@ -0,0 +1,247 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// subscriptionCheckInterval defines how often subscribed torrents are polled
+	subscriptionCheckInterval = 2 * time.Minute
+
+	// rdErrorUnknownResource is the RD error code for a torrent that no longer exists
+	rdErrorUnknownResource = 7
+)
+
+// subscriptionOutcome is the result of checking a subscribed torrent
+type subscriptionOutcome int
+
+const (
+	subscriptionPending subscriptionOutcome = iota
+	subscriptionCompleted
+	subscriptionFailed
+)
+
+// classifySubscriptionStatus maps a raw RD torrent status to a subscription outcome
+func classifySubscriptionStatus(status string) subscriptionOutcome {
+	switch status {
+	case "downloaded":
+		return subscriptionCompleted
+	case "error", "magnet_error", "virus", "dead":
+		return subscriptionFailed
+	default:
+		return subscriptionPending
+	}
+}
+
+// groupSubscriptionsByTorrent groups subscriptions so each torrent is fetched once per cycle.
+// The returned order follows the first subscription for each torrent.
+func groupSubscriptionsByTorrent(subs []db.TorrentSubscription) ([]string, map[string][]db.TorrentSubscription) {
+	var order []string
+	grouped := make(map[string][]db.TorrentSubscription)
+	for _, s := range subs {
+		if _, ok := grouped[s.TorrentID]; !ok {
+			order = append(order, s.TorrentID)
+		}
+		grouped[s.TorrentID] = append(grouped[s.TorrentID], s)
+	}
+	return order, grouped
+}
+
+// isTorrentNotFound reports whether err is RD's "unknown resource" error
+func isTorrentNotFound(err error) bool {
+	var apiErr *realdebrid.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode == rdErrorUnknownResource
+}
+
+// handleSubscribeCommand handles the /subscribe command
+func (b *Bot) handleSubscribeCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "subscribe")
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) < 2 {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /subscribe &lt;torrent_id&gt;", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "subscribe", update.Message.Text, startTime, false, "Missing arguments", 0)
+			return
+		}
+		torrentID := parts[1]
+
+		if user == nil || chatPK == 0 {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>[ERROR]</b> Subscriptions are unavailable for this chat right now. Please try again later.", update.Message.ID)
+			return
+		}
+
+		torrent, err := b.rdClient.GetTorrentInfo(torrentID)
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Could not retrieve torrent info: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "subscribe", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		if classifySubscriptionStatus(torrent.Status) != subscriptionPending {
+			text := fmt.Sprintf("Torrent <code>%s</code> has already finished with status %s. Use <code>/info %s</code> for details.",
+				html.EscapeString(torrentID), realdebrid.FormatStatus(torrent.Status), html.EscapeString(torrentID))
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "subscribe", update.Message.Text, startTime, true, "", len(text))
+			return
+		}
+
+		if err := b.subscriptionRepo.Subscribe(ctx, user.ID, chatPK, torrentID, messageThreadID); err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Failed to subscribe: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "subscribe", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := fmt.Sprintf("<b>[OK]</b> You will be notified here when <code>%s</code> finishes downloading.\n\n<i>File:</i> <code>%s</code>",
+			html.EscapeString(torrentID), html.EscapeString(torrent.Filename))
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "subscribe", update.Message.Text, startTime, true, "", len(text))
+	})
+}
+
+// handleUnsubscribeCommand handles the /unsubscribe command
+func (b *Bot) handleUnsubscribeCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "unsubscribe")
+
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) < 2 {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>Usage:</b> /unsubscribe &lt;torrent_id&gt;", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "unsubscribe", update.Message.Text, startTime, false, "Missing arguments", 0)
+			return
+		}
+		torrentID := parts[1]
+
+		if user == nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>[ERROR]</b> Subscriptions are unavailable right now. Please try again later.", update.Message.ID)
+			return
+		}
+
+		if err := b.subscriptionRepo.Unsubscribe(ctx, user.ID, torrentID); err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Failed to unsubscribe: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "unsubscribe", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+
+		text := fmt.Sprintf("<b>[OK]</b> You will no longer be notified about <code>%s</code>.", html.EscapeString(torrentID))
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "unsubscribe", update.Message.Text, startTime, true, "", len(text))
+	})
+}
+
+// startSubscriptionWorker polls subscribed torrents and notifies subscribers on completion.
+// Each cycle also releases queued magnets into active torrent slots freed meanwhile.
+func (b *Bot) startSubscriptionWorker(ctx context.Context) {
+	ticker := time.NewTicker(subscriptionCheckInterval)
+	defer ticker.Stop()
+
+	log.Printf("Subscription worker started (checking every %s)", formatDuration(subscriptionCheckInterval))
+	b.health.registerWorker("subscriptions")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Subscription worker stopped")
+			return
+		case <-ticker.C:
+			b.runSubscriptionCheck(ctx)
+			b.releaseQueuedMagnets(ctx)
+			b.health.markWorkerRun("subscriptions")
+		}
+	}
+}
+
+// runSubscriptionCheck performs a single subscription check cycle.
+// Only torrents with at least one subscriber are fetched from RD.
+func (b *Bot) runSubscriptionCheck(ctx context.Context) {
+	subs, err := b.subscriptionRepo.ListSubscriptions(ctx)
+	if err != nil {
+		log.Printf("Subscription check: failed to list subscriptions: %v", err)
+		return
+	}
+	if len(subs) == 0 {
+		return
+	}
+
+	order, grouped := groupSubscriptionsByTorrent(subs)
+	for _, torrentID := range order {
+		if ctx.Err() != nil {
+			return
+		}
+
+		var text string
+		torrent, err := b.rdClient.GetTorrentInfo(torrentID)
+		switch {
+		case err != nil && isTorrentNotFound(err):
+			text = fmt.Sprintf("<b>[ERROR]</b> Torrent <code>%s</code> no longer exists on Real-Debrid. Your subscription has been removed.", html.EscapeString(torrentID))
+		case err != nil:
+			log.Printf("Subscription check: failed to get torrent %s: %v", torrentID, err)
+			continue
+		default:
+			switch classifySubscriptionStatus(torrent.Status) {
+			case subscriptionPending:
+				continue
+			case subscriptionCompleted:
+				text = fmt.Sprintf("<b>✅ Torrent Completed</b>\n\n<i>File:</i> <code>%s</code>\n<i>ID:</i> <code>%s</code>\n<i>Size:</i> %s\n\nUse <code>/info %s</code> to get the links.",
+					html.EscapeString(torrent.Filename), html.EscapeString(torrentID), realdebrid.FormatSize(torrent.Bytes), html.EscapeString(torrentID))
+			case subscriptionFailed:
+				text = fmt.Sprintf("<b>[ERROR]</b> Torrent Failed\n\n<i>File:</i> <code>%s</code>\n<i>ID:</i> <code>%s</code>\n<i>Status:</i> %s",
+					html.EscapeString(torrent.Filename), html.EscapeString(torrentID), realdebrid.FormatStatus(torrent.Status))
+			}
+		}
+
+		if b.config.Telegram.MentionAdder {
+			text += b.adderMention(ctx, torrentID)
+		}
+
+		for _, sub := range grouped[torrentID] {
+			if err := b.sendHTMLMessageWithErr(ctx, sub.ChatID, b.notificationThread(sub.ChatID, sub.UserID, int(sub.ThreadID)), text, 0); err != nil {
+				log.Printf("Subscription check: failed to notify user %d about %s: %v", sub.UserID, torrentID, err)
+			}
+		}
+
+		if err := b.subscriptionRepo.ClearTorrent(ctx, torrentID); err != nil {
+			log.Printf("Subscription check: failed to clear subscriptions for %s: %v", torrentID, err)
+		}
+	}
+}
+
+// formatMention renders a mention of a Telegram user: their @username, or a link to
+// their profile named after them when they have none
+func formatMention(user *db.User) string {
+	if user.Username != "" {
+		return "@" + html.EscapeString(user.Username)
+	}
+	name := user.FirstName
+	if name == "" {
+		name = fmt.Sprintf("user %d", user.UserID)
+	}
+	return fmt.Sprintf(`<a href="tg://user?id=%d">%s</a>`, user.UserID, html.EscapeString(name))
+}
+
+// adderMention returns the "Added by" line of a notification about torrentID, or ""
+// when who added it is unknown. Lookup failures are only logged.
+func (b *Bot) adderMention(ctx context.Context, torrentID string) string {
+	adder, err := b.torrentRepo.GetTorrentAdder(ctx, torrentID)
+	if err != nil {
+		log.Printf("Subscription check: failed to look up who added %s: %v", torrentID, err)
+		return ""
+	}
+	if adder == nil {
+		return ""
+	}
+	return "\n\n<i>Added by:</i> " + formatMention(adder)
+}
@@ -0,0 +1,90 @@
+package bot
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// TestClassifySubscriptionStatus verifies which RD statuses end a subscription.
+func TestClassifySubscriptionStatus(t *testing.T) {
+	tests := []struct {
+		status string
+		want   subscriptionOutcome
+	}{
+		{"downloaded", subscriptionCompleted},
+		{"error", subscriptionFailed},
+		{"magnet_error", subscriptionFailed},
+		{"virus", subscriptionFailed},
+		{"dead", subscriptionFailed},
+		{"downloading", subscriptionPending},
+		{"queued", subscriptionPending},
+		{"waiting_files_selection", subscriptionPending},
+		{"magnet_conversion", subscriptionPending},
+		{"compressing", subscriptionPending},
+		{"uploading", subscriptionPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			if got := classifySubscriptionStatus(tt.status); got != tt.want {
+				t.Errorf("classifySubscriptionStatus(%q) = %v, want %v", tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGroupSubscriptionsByTorrent verifies each torrent is fetched once and keeps all subscribers.
+func TestGroupSubscriptionsByTorrent(t *testing.T) {
+	subs := []db.TorrentSubscription{
+		{ID: 1, TorrentID: "AAA", UserID: 10},
+		{ID: 2, TorrentID: "BBB", UserID: 10},
+		{ID: 3, TorrentID: "AAA", UserID: 20},
+	}
+
+	order, grouped := groupSubscriptionsByTorrent(subs)
+
+	if want := []string{"AAA", "BBB"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+	if len(grouped["AAA"]) != 2 || len(grouped["BBB"]) != 1 {
+		t.Errorf("grouped sizes = (%d, %d), want (2, 1)", len(grouped["AAA"]), len(grouped["BBB"]))
+	}
+}
+
+// TestIsTorrentNotFound verifies only RD's unknown-resource error counts as a missing torrent.
+func TestIsTorrentNotFound(t *testing.T) {
+	notFound := fmt.Errorf("failed to get torrent info: %w", &realdebrid.APIError{ErrorCode: rdErrorUnknownResource, ErrorMessage: "unknown_ressource"})
+	if !isTorrentNotFound(notFound) {
+		t.Error("isTorrentNotFound(unknown_ressource) = false, want true")
+	}
+	if isTorrentNotFound(&realdebrid.APIError{ErrorCode: 8, ErrorMessage: "bad_token"}) {
+		t.Error("isTorrentNotFound(bad_token) = true, want false")
+	}
+	if isTorrentNotFound(fmt.Errorf("request failed: timeout")) {
+		t.Error("isTorrentNotFound(network error) = true, want false")
+	}
+}
+
+// TestFormatMention verifies users are mentioned by username, falling back to a profile link.
+func TestFormatMention(t *testing.T) {
+	tests := []struct {
+		name string
+		user db.User
+		want string
+	}{
+		{"username", db.User{UserID: 42, Username: "alice", FirstName: "Alice"}, "@alice"},
+		{"first name", db.User{UserID: 42, FirstName: "Bob <3"}, `<a href="tg://user?id=42">Bob &lt;3</a>`},
+		{"no name", db.User{UserID: 42}, `<a href="tg://user?id=42">user 42</a>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatMention(&tt.user); got != tt.want {
+				t.Errorf("formatMention() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
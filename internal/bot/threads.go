@@ -0,0 +1,85 @@
+package bot
+
+import (
+	"sync"
+	"time"
+)
+
+// threadMemoryTTL is how long a user's last forum topic is remembered without activity
+const threadMemoryTTL = 30 * 24 * time.Hour
+
+// threadKey identifies a user within a chat
+type threadKey struct {
+	chatID int64
+	userID int64
+}
+
+// rememberedThread is the last forum topic a user wrote in
+type rememberedThread struct {
+	threadID int
+	seenAt   time.Time
+}
+
+// threadMemory remembers the forum topic each user last used per chat, so messages
+// sent later without a reply context (notifications) land in the same topic. A nil
+// *threadMemory is valid and remembers nothing.
+type threadMemory struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	threads map[threadKey]rememberedThread
+}
+
+// newThreadMemory creates a threadMemory that forgets a user's topic after ttl of inactivity
+func newThreadMemory(ttl time.Duration) *threadMemory {
+	return &threadMemory{ttl: ttl, threads: make(map[threadKey]rememberedThread)}
+}
+
+// remember records that userID last wrote in threadID of chatID. Messages outside a
+// topic don't overwrite what is remembered.
+func (m *threadMemory) remember(chatID, userID int64, threadID int, now time.Time) {
+	if m == nil || userID == 0 || threadID == 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.threads[threadKey{chatID, userID}] = rememberedThread{threadID: threadID, seenAt: now}
+}
+
+// lookup returns the topic userID last used in chatID, or 0 if none is remembered
+func (m *threadMemory) lookup(chatID, userID int64, now time.Time) int {
+	if m == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.threads[threadKey{chatID, userID}]
+	if !ok || now.Sub(t.seenAt) >= m.ttl {
+		return 0
+	}
+	return t.threadID
+}
+
+// sweep forgets topics not used within the TTL and returns how many were dropped
+func (m *threadMemory) sweep(now time.Time) int {
+	if m == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	dropped := 0
+	for key, t := range m.threads {
+		if now.Sub(t.seenAt) >= m.ttl {
+			delete(m.threads, key)
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// notificationThread returns threadID, or when it is unset the topic userID last used in chatID
+func (b *Bot) notificationThread(chatID, userID int64, threadID int) int {
+	if threadID != 0 {
+		return threadID
+	}
+	return b.threads.lookup(chatID, userID, time.Now())
+}
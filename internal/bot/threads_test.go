@@ -0,0 +1,85 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/config"
+)
+
+// TestThreadMemory verifies topics are remembered per user and chat, and messages outside a topic keep them.
+func TestThreadMemory(t *testing.T) {
+	m := newThreadMemory(time.Hour)
+	now := time.Now()
+
+	m.remember(-100, 7, 42, now)
+	m.remember(-100, 7, 0, now)
+	m.remember(-100, 8, 43, now)
+
+	if got := m.lookup(-100, 7, now); got != 42 {
+		t.Errorf("lookup(user 7) = %d, want 42", got)
+	}
+	if got := m.lookup(-100, 8, now); got != 43 {
+		t.Errorf("lookup(user 8) = %d, want 43", got)
+	}
+	if got := m.lookup(-200, 7, now); got != 0 {
+		t.Errorf("lookup(other chat) = %d, want 0", got)
+	}
+}
+
+// TestThreadMemory_Expiry verifies stale topics are neither returned nor kept by sweep.
+func TestThreadMemory_Expiry(t *testing.T) {
+	m := newThreadMemory(time.Hour)
+	now := time.Now()
+	m.remember(-100, 7, 42, now.Add(-2*time.Hour))
+	m.remember(-100, 8, 43, now)
+
+	if got := m.lookup(-100, 7, now); got != 0 {
+		t.Errorf("lookup(expired) = %d, want 0", got)
+	}
+	if dropped := m.sweep(now); dropped != 1 {
+		t.Errorf("sweep() = %d, want 1", dropped)
+	}
+	if _, ok := m.threads[threadKey{-100, 8}]; !ok {
+		t.Error("sweep() dropped a live topic")
+	}
+}
+
+// TestThreadMemory_Disabled verifies a nil threadMemory remembers nothing.
+func TestThreadMemory_Disabled(t *testing.T) {
+	var m *threadMemory
+	m.remember(-100, 7, 42, time.Now())
+	if got := m.lookup(-100, 7, time.Now()); got != 0 {
+		t.Errorf("lookup() = %d, want 0", got)
+	}
+	b := &Bot{}
+	if got := b.notificationThread(-100, 7, 0); got != 0 {
+		t.Errorf("notificationThread() = %d, want 0", got)
+	}
+}
+
+// TestNotificationThread_Remembered verifies an async message without a topic is sent to the remembered one.
+func TestNotificationThread_Remembered(t *testing.T) {
+	api, requests := newTestTelegramAPI(t)
+	cfg := &config.Config{}
+	cfg.App.RateLimit = config.RateLimitConfig{MessagesPerSecond: 100, Burst: 10}
+	b := &Bot{api: api, config: cfg, middleware: NewMiddleware(cfg), threads: newThreadMemory(time.Hour)}
+	b.threads.remember(-100, 7, 42, time.Now())
+
+	if got := b.notificationThread(-100, 7, 5); got != 5 {
+		t.Errorf("notificationThread() with a known topic = %d, want 5", got)
+	}
+
+	if err := b.sendHTMLMessageWithErr(context.Background(), -100, b.notificationThread(-100, 7, 0), "done", 0); err != nil {
+		t.Fatalf("sendHTMLMessageWithErr() error = %v", err)
+	}
+	reqs := requests()
+	if len(reqs) != 1 {
+		t.Fatalf("requests = %v, want one sendMessage", reqs)
+	}
+	if !strings.Contains(reqs[0], "name=\"message_thread_id\"\r\n\r\n42\r\n") {
+		t.Errorf("notification not routed to remembered thread 42: %s", reqs[0])
+	}
+}
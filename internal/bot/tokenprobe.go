@@ -0,0 +1,143 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// rdErrorBadToken is the RD error code for an invalid or expired token
+	rdErrorBadToken = 8
+
+	// rdErrorPermissionDenied is the RD error code for a token lacking the scope of an endpoint
+	rdErrorPermissionDenied = 9
+)
+
+// scopeAccess is what a probe request revealed about one token scope
+type scopeAccess int
+
+const (
+	scopeGranted scopeAccess = iota
+	scopeDenied
+	scopeBadToken
+	scopeUnknown // the probe failed for an unrelated reason, e.g. a network error
+)
+
+// classifyProbe interprets the error of a probe request
+func classifyProbe(err error) scopeAccess {
+	if err == nil {
+		return scopeGranted
+	}
+	var apiErr *realdebrid.APIError
+	if !errors.As(err, &apiErr) {
+		return scopeUnknown
+	}
+	switch apiErr.ErrorCode {
+	case rdErrorBadToken:
+		return scopeBadToken
+	case rdErrorPermissionDenied:
+		return scopeDenied
+	default:
+		return scopeUnknown
+	}
+}
+
+// scopeProbe is the outcome of probing one scope with a read-only request
+type scopeProbe struct {
+	scope  string
+	access scopeAccess
+	err    error
+}
+
+// probeTokenScopes calls a read-only endpoint for each scope a token can hold. The
+// settings endpoint stands for full account access: the private API token can read it,
+// while OAuth tokens issued to apps are limited to the scopes they were granted.
+func (b *Bot) probeTokenScopes() []scopeProbe {
+	calls := []struct {
+		scope string
+		call  func() error
+	}{
+		{"user", func() error { _, err := b.rdClient.GetUser(); return err }},
+		{"torrents", func() error { _, err := b.rdClient.GetTorrents(1, 0); return err }},
+		{"downloads", func() error { _, err := b.rdClient.GetDownloads(1, 0); return err }},
+		{"settings", func() error { _, err := b.rdClient.GetSettings(); return err }},
+	}
+	probes := make([]scopeProbe, 0, len(calls))
+	for _, c := range calls {
+		err := c.call()
+		probes = append(probes, scopeProbe{scope: c.scope, access: classifyProbe(err), err: err})
+	}
+	return probes
+}
+
+// inferTokenType summarises probe outcomes as the kind of token in use
+func inferTokenType(probes []scopeProbe) string {
+	granted, denied := 0, 0
+	for _, p := range probes {
+		switch p.access {
+		case scopeBadToken:
+			return "Invalid or expired token"
+		case scopeGranted:
+			granted++
+		case scopeDenied:
+			denied++
+		}
+	}
+	switch {
+	case granted == len(probes):
+		return "Private API token (full access)"
+	case denied > 0:
+		return "OAuth token with limited scopes"
+	default:
+		return "Unknown (some checks could not be completed)"
+	}
+}
+
+// formatTokenReport renders the /token reply
+func formatTokenReport(probes []scopeProbe) string {
+	var text strings.Builder
+	text.WriteString("<b>Real-Debrid Token</b>\n\n")
+	fmt.Fprintf(&text, "<i>Type:</i> %s\n\n", inferTokenType(probes))
+	for _, p := range probes {
+		switch p.access {
+		case scopeGranted:
+			fmt.Fprintf(&text, "✅ <code>%s</code>\n", p.scope)
+		case scopeDenied:
+			fmt.Fprintf(&text, "❌ <code>%s</code>: permission denied\n", p.scope)
+		case scopeBadToken:
+			fmt.Fprintf(&text, "❌ <code>%s</code>: token rejected\n", p.scope)
+		default:
+			fmt.Fprintf(&text, "❔ <code>%s</code>: %s\n", p.scope, html.EscapeString(p.err.Error()))
+		}
+	}
+	return text.String()
+}
+
+// handleTokenCommand handles the /token command (superadmin only). It reports which kind
+// of Real-Debrid token the bot uses and which scopes it holds, to diagnose permission
+// errors such as failing deletions.
+func (b *Bot) handleTokenCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "token")
+
+		if !role.IsSuperAdmin() {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, b.localize(chatID, "error.superadmin_only"), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "token", update.Message.Text, startTime, false, "Unauthorized - not superadmin", 0)
+			return
+		}
+
+		text := formatTokenReport(b.probeTokenScopes())
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "token", update.Message.Text, startTime, true, "", len(text))
+	})
+}
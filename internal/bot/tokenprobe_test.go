@@ -0,0 +1,54 @@
+package bot
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// TestClassifyProbe verifies RD error codes map to the scope they reveal.
+func TestClassifyProbe(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want scopeAccess
+	}{
+		{"ok", nil, scopeGranted},
+		{"permission denied", &realdebrid.APIError{ErrorCode: 9, ErrorMessage: "permission_denied"}, scopeDenied},
+		{"bad token", &realdebrid.APIError{ErrorCode: 8, ErrorMessage: "bad_token"}, scopeBadToken},
+		{"other api error", &realdebrid.APIError{ErrorCode: 5, ErrorMessage: "slow_down"}, scopeUnknown},
+		{"network", errors.New("request failed: timeout"), scopeUnknown},
+	}
+	for _, tt := range tests {
+		if got := classifyProbe(tt.err); got != tt.want {
+			t.Errorf("%s: classifyProbe() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestInferTokenType verifies full, scoped, rejected and inconclusive probe sets.
+func TestInferTokenType(t *testing.T) {
+	probes := func(access ...scopeAccess) []scopeProbe {
+		out := make([]scopeProbe, len(access))
+		for i, a := range access {
+			out[i] = scopeProbe{scope: "s", access: a, err: errors.New("x")}
+		}
+		return out
+	}
+	tests := []struct {
+		name   string
+		probes []scopeProbe
+		want   string
+	}{
+		{"full", probes(scopeGranted, scopeGranted, scopeGranted), "Private API token (full access)"},
+		{"scoped", probes(scopeGranted, scopeGranted, scopeDenied), "OAuth token with limited scopes"},
+		{"rejected", probes(scopeBadToken, scopeBadToken, scopeBadToken), "Invalid or expired token"},
+		{"inconclusive", probes(scopeGranted, scopeUnknown, scopeGranted), "Unknown (some checks could not be completed)"},
+	}
+	for _, tt := range tests {
+		if got := inferTokenType(tt.probes); got != tt.want {
+			t.Errorf("%s: inferTokenType() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
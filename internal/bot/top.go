@@ -0,0 +1,107 @@
+package bot
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"html"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// defaultTopTorrents is how many torrents /top shows without an argument
+	defaultTopTorrents = 5
+
+	// maxTopTorrents is the largest count accepted by /top
+	maxTopTorrents = 20
+
+	// topPageSize is how many torrents /top fetches per Real-Debrid call
+	topPageSize = 2500
+)
+
+// parseTopCount parses the optional count argument of /top
+func parseTopCount(parts []string) (int, error) {
+	if len(parts) < 2 {
+		return defaultTopTorrents, nil
+	}
+	n, err := strconv.Atoi(parts[1])
+	if err != nil || n < 1 || n > maxTopTorrents {
+		return 0, fmt.Errorf("please provide a count between 1 and %d", maxTopTorrents)
+	}
+	return n, nil
+}
+
+// largestTorrents returns the n largest torrents by size, largest first. Torrents of
+// equal size keep their listing order.
+func largestTorrents(torrents []realdebrid.Torrent, n int) []realdebrid.Torrent {
+	sorted := slices.Clone(torrents)
+	slices.SortStableFunc(sorted, func(a, b realdebrid.Torrent) int {
+		return cmp.Compare(b.Bytes, a.Bytes)
+	})
+	return sorted[:min(n, len(sorted))]
+}
+
+// fetchAllTorrents pages through every torrent on the account
+func (b *Bot) fetchAllTorrents() ([]realdebrid.Torrent, error) {
+	var all []realdebrid.Torrent
+	for offset := 0; ; offset += topPageSize {
+		page, err := b.rdClient.GetTorrents(topPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < topPageSize {
+			return all, nil
+		}
+	}
+}
+
+// handleTopCommand handles the /top command. It lists the largest torrents on the
+// account, to find what takes up the most space.
+func (b *Bot) handleTopCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "top")
+
+		n, err := parseTopCount(strings.Fields(update.Message.Text))
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, "<b>[ERROR]</b> "+html.EscapeString(err.Error())+"\n\n<b>Usage:</b> /top [count]", update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "top", update.Message.Text, startTime, false, "Invalid count", 0)
+			return
+		}
+
+		reply := b.ackProcessing(ctx, chatID, messageThreadID, update.Message.ID, "top")
+		torrents, err := b.fetchAllTorrents()
+		if err != nil {
+			text := fmt.Sprintf("<b>[ERROR]</b> Failed to retrieve torrents: %s", html.EscapeString(err.Error()))
+			reply.finish(ctx, text)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "top", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+		if len(torrents) == 0 {
+			reply.finish(ctx, "No torrents found.")
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "top", update.Message.Text, startTime, true, "", 0)
+			return
+		}
+
+		top := largestTorrents(torrents, n)
+		var text strings.Builder
+		fmt.Fprintf(&text, "<b>Largest Torrents</b> (top %d of %d)\n\n", len(top), len(torrents))
+		for i, t := range top {
+			fmt.Fprintf(&text, "%d. <code>%s</code>\n", i+1, html.EscapeString(truncateName(t.Filename, b.config.App.MaxFilenameDisplay)))
+			fmt.Fprintf(&text, "    %s • <code>%s</code>\n", realdebrid.FormatSize(t.Bytes), t.ID)
+		}
+		text.WriteString("\nUse <code>/info &lt;id&gt;</code> for more details on a specific torrent.")
+
+		reply.finish(ctx, text.String())
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "top", update.Message.Text, startTime, true, "", text.Len())
+	})
+}
@@ -0,0 +1,55 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// TestLargestTorrents verifies the N largest torrents are returned largest first.
+func TestLargestTorrents(t *testing.T) {
+	torrents := []realdebrid.Torrent{
+		{ID: "a", Bytes: 10}, {ID: "b", Bytes: 500}, {ID: "c", Bytes: 30},
+		{ID: "d", Bytes: 500}, {ID: "e", Bytes: 1},
+	}
+
+	top := largestTorrents(torrents, 3)
+	var ids string
+	for _, tr := range top {
+		ids += tr.ID
+	}
+	if ids != "bdc" {
+		t.Errorf("largestTorrents(3) = %s, want bdc", ids)
+	}
+	if torrents[0].ID != "a" {
+		t.Error("largestTorrents reordered its input")
+	}
+	if got := largestTorrents(torrents[:2], 5); len(got) != 2 {
+		t.Errorf("largestTorrents with fewer torrents than n = %d entries, want 2", len(got))
+	}
+	if got := largestTorrents(nil, 5); len(got) != 0 {
+		t.Errorf("largestTorrents(nil) = %v, want empty", got)
+	}
+}
+
+// TestParseTopCount verifies the default, valid counts and the bounds.
+func TestParseTopCount(t *testing.T) {
+	tests := []struct {
+		parts   []string
+		want    int
+		wantErr bool
+	}{
+		{[]string{"/top"}, 5, false},
+		{[]string{"/top", "12"}, 12, false},
+		{[]string{"/top", "20"}, 20, false},
+		{[]string{"/top", "21"}, 0, true},
+		{[]string{"/top", "0"}, 0, true},
+		{[]string{"/top", "many"}, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseTopCount(tt.parts)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("parseTopCount(%v) = %d, %v; want %d, error %v", tt.parts, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package bot
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/db"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// trafficCacheTTL is how long fetched traffic quotas are reused before asking RD again
+const trafficCacheTTL = 5 * time.Minute
+
+// trafficCache keeps the last traffic quotas fetched from Real-Debrid
+type trafficCache struct {
+	mu      sync.Mutex
+	fetched time.Time
+	traffic realdebrid.Traffic
+}
+
+// cachedTraffic returns the account's traffic quotas, fetching them at most once per trafficCacheTTL
+func (b *Bot) cachedTraffic() (realdebrid.Traffic, error) {
+	b.traffic.mu.Lock()
+	defer b.traffic.mu.Unlock()
+	if b.traffic.traffic != nil && time.Since(b.traffic.fetched) < trafficCacheTTL {
+		return b.traffic.traffic, nil
+	}
+	traffic, err := b.rdClient.GetTraffic()
+	if err != nil {
+		return nil, err
+	}
+	b.traffic.traffic, b.traffic.fetched = traffic, time.Now()
+	return traffic, nil
+}
+
+// hostTraffic finds the quota of the hoster serving link. A quota applies to its domain
+// and all of its subdomains.
+func hostTraffic(traffic realdebrid.Traffic, link string) (string, realdebrid.HostTraffic, bool) {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || u.Hostname() == "" {
+		return "", realdebrid.HostTraffic{}, false
+	}
+	host := strings.ToLower(u.Hostname())
+	for domain, quota := range traffic {
+		domain = strings.ToLower(domain)
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return domain, quota, true
+		}
+	}
+	return "", realdebrid.HostTraffic{}, false
+}
+
+// trafficWarning returns a warning when the hoster of link has used at least threshold
+// percent of its quota, or "" if it has not or is not metered
+func trafficWarning(traffic realdebrid.Traffic, link string, threshold int) string {
+	if threshold <= 0 {
+		return ""
+	}
+	domain, quota, ok := hostTraffic(traffic, link)
+	if !ok {
+		return ""
+	}
+	used := quota.UsedPercent()
+	if used < float64(threshold) {
+		return ""
+	}
+	warning := fmt.Sprintf("⚠️ You've used %.0f%% of your %s traffic", used, html.EscapeString(domain))
+	if quota.Reset != "" {
+		warning += fmt.Sprintf(" (resets %s)", html.EscapeString(quota.Reset))
+	}
+	return warning + "."
+}
+
+// trafficWarningFor checks the cached quotas for the hoster of link against
+// realdebrid.traffic_warn_percent. Failures to fetch the quotas are only logged.
+func (b *Bot) trafficWarningFor(link string) string {
+	threshold := b.config.RealDebrid.TrafficWarnPercent
+	if threshold <= 0 {
+		return ""
+	}
+	traffic, err := b.cachedTraffic()
+	if err != nil {
+		log.Printf("Warning: failed to fetch traffic for quota warning: %v", err)
+		return ""
+	}
+	return trafficWarning(traffic, link, threshold)
+}
+
+// formatTraffic renders the /traffic reply, most used hosters first. Hosters at or over
+// threshold percent are flagged.
+func formatTraffic(traffic realdebrid.Traffic, threshold int) string {
+	if len(traffic) == 0 {
+		return "<b>Hoster Traffic</b>\n\nNo metered hosters on this account."
+	}
+	domains := make([]string, 0, len(traffic))
+	for domain := range traffic {
+		domains = append(domains, domain)
+	}
+	slices.SortFunc(domains, func(a, b string) int {
+		return cmp.Or(cmp.Compare(traffic[b].UsedPercent(), traffic[a].UsedPercent()), cmp.Compare(a, b))
+	})
+
+	var text strings.Builder
+	text.WriteString("<b>Hoster Traffic</b>\n\n")
+	for _, domain := range domains {
+		quota := traffic[domain]
+		used := quota.UsedPercent()
+		mark := ""
+		if threshold > 0 && used >= float64(threshold) {
+			mark = " ⚠️"
+		}
+		var left string
+		if quota.Type == "links" {
+			left = fmt.Sprintf("%d links left", quota.Left)
+		} else {
+			left = realdebrid.FormatSize(quota.Left) + " left"
+		}
+		fmt.Fprintf(&text, "<code>%s</code>: %.0f%% used, %s", html.EscapeString(domain), used, left)
+		if quota.Reset != "" {
+			fmt.Fprintf(&text, ", resets %s", html.EscapeString(quota.Reset))
+		}
+		text.WriteString(mark + "\n")
+	}
+	return text.String()
+}
+
+// handleTrafficCommand handles the /traffic command. It shows how much of each metered
+// hoster's quota the account has used.
+func (b *Bot) handleTrafficCommand(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.withAuth(ctx, update, func(ctx context.Context, chatID int64, chatPK int64, messageThreadID int, role Role, user *db.User) {
+		startTime := time.Now()
+		b.middleware.LogCommand(update, "traffic")
+
+		traffic, err := b.rdClient.GetTraffic()
+		if err != nil {
+			b.sendHTMLMessage(ctx, chatID, messageThreadID, fmt.Sprintf("<b>[ERROR]</b> Failed to get traffic: %s", html.EscapeString(err.Error())), update.Message.ID)
+			b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "traffic", update.Message.Text, startTime, false, err.Error(), 0)
+			return
+		}
+		b.traffic.mu.Lock()
+		b.traffic.traffic, b.traffic.fetched = traffic, time.Now()
+		b.traffic.mu.Unlock()
+
+		text := formatTraffic(traffic, b.config.RealDebrid.TrafficWarnPercent)
+		b.sendHTMLMessage(ctx, chatID, messageThreadID, text, update.Message.ID)
+		b.logCommandHelper(ctx, user, chatPK, int64(update.Message.ID), messageThreadID, "traffic", update.Message.Text, startTime, true, "", len(text))
+	})
+}
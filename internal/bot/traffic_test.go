@@ -0,0 +1,54 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// sampleTraffic has one hoster 90% used by bytes and one 50% used by links.
+var sampleTraffic = realdebrid.Traffic{
+	"uptobox.com":  {Bytes: 90, Left: 10, Type: "gigabytes", Reset: "daily"},
+	"1fichier.com": {Links: 10, Limit: 20, Type: "links"},
+}
+
+// TestTrafficWarning verifies warnings start at the threshold, match subdomains and skip unmetered hosts.
+func TestTrafficWarning(t *testing.T) {
+	tests := []struct {
+		name      string
+		link      string
+		threshold int
+		want      string
+	}{
+		{"over threshold", "https://uptobox.com/abc", 90, "used 90% of your uptobox.com traffic (resets daily)"},
+		{"subdomain", "https://www.UPTOBOX.com/abc", 80, "uptobox.com"},
+		{"under threshold", "https://1fichier.com/?x", 60, ""},
+		{"links quota over threshold", "https://1fichier.com/?x", 50, "used 50% of your 1fichier.com traffic."},
+		{"unmetered host", "https://example.com/f", 10, ""},
+		{"disabled", "https://uptobox.com/abc", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := trafficWarning(sampleTraffic, tt.link, tt.threshold)
+			if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+				t.Errorf("trafficWarning() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFormatTraffic verifies hosters are listed most used first and flagged at the threshold.
+func TestFormatTraffic(t *testing.T) {
+	text := formatTraffic(sampleTraffic, 90)
+	up, fi := strings.Index(text, "uptobox.com"), strings.Index(text, "1fichier.com")
+	if up < 0 || fi < 0 || up > fi {
+		t.Fatalf("hosters missing or out of order:\n%s", text)
+	}
+	if !strings.Contains(text, "90% used, 10 B left, resets daily ⚠️") || strings.Contains(text, "10 links left ⚠️") {
+		t.Errorf("threshold flags wrong:\n%s", text)
+	}
+	if text := formatTraffic(nil, 90); !strings.Contains(text, "No metered hosters") {
+		t.Errorf("empty traffic = %s", text)
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"net/url"
 	"slices"
 	"strings"
+	"time"
 
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
 	"github.com/spf13/viper"
 )
 
@@ -21,12 +23,21 @@ type Config struct {
 
 // WebConfig holds all web server configuration
 type WebConfig struct {
-	ListenAddr         string        `mapstructure:"listen_addr"`
-	APIKey             string        `mapstructure:"api_key"`
-	DashboardURL       string        `mapstructure:"dashboard_url"`
-	TokenExpiryMinutes int           `mapstructure:"token_expiry_minutes"`
-	Limiter            LimiterConfig `mapstructure:"limiter"`
-	Metrics            MetricsConfig `mapstructure:"metrics"`
+	ListenAddr         string            `mapstructure:"listen_addr"`
+	APIKey             string            `mapstructure:"api_key"`
+	DashboardURL       string            `mapstructure:"dashboard_url"`
+	TokenExpiryMinutes int               `mapstructure:"token_expiry_minutes"`
+	MaxPageSize        int               `mapstructure:"max_page_size"`  // Upper bound for the limit query parameter on paginated endpoints
+	MaxBodyBytes       int               `mapstructure:"max_body_bytes"` // Largest accepted request body; larger ones get 413
+	SelectFiles        SelectFilesConfig `mapstructure:"select_files"`
+	Limiter            LimiterConfig     `mapstructure:"limiter"`
+	Metrics            MetricsConfig     `mapstructure:"metrics"`
+}
+
+// SelectFilesConfig controls how dashboard-added torrents get their files selected
+type SelectFilesConfig struct {
+	TimeoutSeconds int `mapstructure:"timeout_seconds"` // How long to keep retrying while the magnet converts
+	Concurrency    int `mapstructure:"concurrency"`     // Max add requests waiting on file selection at once
 }
 
 // LimiterConfig holds web server rate limiting settings
@@ -52,18 +63,72 @@ type TelegramConfig struct {
 	BotToken        string             `mapstructure:"bot_token"`
 	AllowedChatIDs  []int64            `mapstructure:"allowed_chat_ids"`
 	SuperAdminIDs   []int64            `mapstructure:"super_admin_ids"`
+	ModeratorIDs    []int64            `mapstructure:"moderator_ids"`     // users who can run read-only admin commands but not destructive ones
 	AllowedTopicIDs map[string][]int64 `mapstructure:"allowed_topic_ids"` // map[chatID][]topicID; if set, bot only responds in listed topics
+	AllowlistFile   string             `mapstructure:"allowlist_file"`    // optional file of extra allowed chat IDs, reloaded when it changes
+	ChatLocales     map[string]string  `mapstructure:"chat_locales"`      // map[chatID]locale for bot replies; chats not listed use English
+	MessageFooter   string             `mapstructure:"message_footer"`    // optional HTML appended to bot replies, e.g. "Powered by MyGroup"
+	RememberThreads bool               `mapstructure:"remember_threads"`  // send notifications without a known topic to the one the user last wrote in
+	CaptionLinks    bool               `mapstructure:"caption_links"`     // process magnet and hoster links found in media captions, e.g. forwarded posts
+	EntityLinks     bool               `mapstructure:"entity_links"`      // process hoster links Telegram detected anywhere in a text message, not only at its start
+	MentionAdder    bool               `mapstructure:"mention_adder"`     // mention the user who added a torrent in its completion notifications
+	Reconnect       ReconnectConfig    `mapstructure:"reconnect"`
+
+	StatusBroadcastChat   int64  `mapstructure:"status_broadcast_chat"`   // chat that gets the account status posted daily; 0 disables
+	StatusBroadcastThread int    `mapstructure:"status_broadcast_thread"` // optional forum topic of status_broadcast_chat
+	StatusBroadcastTime   string `mapstructure:"status_broadcast_time"`   // time of day (HH:MM, in app.timezone) of the daily status post
+}
+
+// ReconnectConfig controls how long Telegram polling waits after failed getUpdates calls
+type ReconnectConfig struct {
+	InitialDelaySeconds int `mapstructure:"initial_delay_seconds"` // Wait after the first failed poll; doubled after each further failure
+	MaxDelaySeconds     int `mapstructure:"max_delay_seconds"`     // Upper bound for the wait between polls
 }
 
 // RealDebridConfig holds Real-Debrid API settings
 type RealDebridConfig struct {
-	APIToken      string `mapstructure:"api_token"`
-	BaseURL       string `mapstructure:"base_url"`
-	Timeout       int    `mapstructure:"timeout"`
-	Proxy         string `mapstructure:"proxy"`
-	IPTestURL     string `mapstructure:"ip_test_url"`
-	StremThruURL  string `mapstructure:"stremthru_url"`
-	StremThruAuth string `mapstructure:"stremthru_auth"`
+	APIToken           string                  `mapstructure:"api_token"`
+	BaseURL            string                  `mapstructure:"base_url"`
+	AllowInsecure      bool                    `mapstructure:"allow_insecure_base_url"` // Accept an http:// base_url, e.g. for a local mock API
+	Timeout            int                     `mapstructure:"timeout"`
+	Proxy              string                  `mapstructure:"proxy"`
+	IPTestURL          string                  `mapstructure:"ip_test_url"`
+	StremThruURL       string                  `mapstructure:"stremthru_url"`
+	StremThruAuth      string                  `mapstructure:"stremthru_auth"`
+	SlowThreshold      int                     `mapstructure:"slow_threshold_ms"` // Log RD calls slower than this; negative disables
+	Transport          RDTransportConfig       `mapstructure:"transport"`
+	QueueWhenFull      bool                    `mapstructure:"queue_when_full"`      // Queue magnets while the active torrent limit is reached
+	QueueMaxPerUser    int                     `mapstructure:"queue_max_per_user"`   // Magnets a user may have queued at once
+	SelectCachedOnly   bool                    `mapstructure:"select_cached_only"`   // Select only the cached files of added magnets
+	TrafficWarnPercent int                     `mapstructure:"traffic_warn_percent"` // Warn when a metered hoster's quota is this much used; 0 disables
+	AutoSelectSweeper  AutoSelectSweeperConfig `mapstructure:"auto_select_sweeper"`
+}
+
+// AutoSelectSweeperConfig controls the worker selecting all files of torrents left
+// waiting for a file selection
+type AutoSelectSweeperConfig struct {
+	Enabled         bool `mapstructure:"enabled"`
+	MinAgeMinutes   int  `mapstructure:"min_age_minutes"`  // How long a torrent must have waited before its files are selected
+	IntervalMinutes int  `mapstructure:"interval_minutes"` // Time between sweeps
+}
+
+// RDTransportConfig tunes the HTTP connection pool used for Real-Debrid API calls.
+// Unset values fall back to Go's defaults.
+type RDTransportConfig struct {
+	MaxIdleConns           int  `mapstructure:"max_idle_conns"`            // Idle connections kept across all hosts
+	MaxIdleConnsPerHost    int  `mapstructure:"max_idle_conns_per_host"`   // Idle connections kept per host
+	IdleConnTimeoutSeconds int  `mapstructure:"idle_conn_timeout_seconds"` // How long an idle connection is kept open
+	DisableHTTP2           bool `mapstructure:"disable_http2"`             // Use HTTP/1.1 only, for proxies that misbehave with HTTP/2
+}
+
+// Options converts the transport settings for realdebrid.NewClientWithTransport
+func (t RDTransportConfig) Options() realdebrid.TransportOptions {
+	return realdebrid.TransportOptions{
+		MaxIdleConns:        t.MaxIdleConns,
+		MaxIdleConnsPerHost: t.MaxIdleConnsPerHost,
+		IdleConnTimeout:     time.Duration(t.IdleConnTimeoutSeconds) * time.Second,
+		DisableHTTP2:        t.DisableHTTP2,
+	}
 }
 
 // AppConfig holds application settings
@@ -74,6 +139,49 @@ type AppConfig struct {
 	AutoDeleteDays               int                     `mapstructure:"auto_delete_days"`                 // Fallback when not set in DB
 	AutoDeleteCheckIntervalHours int                     `mapstructure:"auto_delete_check_interval_hours"` // Hours between cleanup runs
 	AutoDeleteWarning            AutoDeleteWarningConfig `mapstructure:"auto_delete_warning"`
+	ListShowHash                 bool                    `mapstructure:"list_show_hash"`    // Include the truncated hash per entry in /list
+	ListCachedBadge              bool                    `mapstructure:"list_cached_badge"` // Record whether added magnets were cached and mark them in /list
+	DownloadsDedupe              bool                    `mapstructure:"downloads_dedupe"`  // Show each link only once in /downloads, at its most recent unrestrict
+	ListEnrich                   ListEnrichConfig        `mapstructure:"list_enrich"`
+	DuplicateAddWindowHours      int                     `mapstructure:"duplicate_add_window_hours"` // How far back a re-added torrent ID counts as a duplicate
+	PromptMissingArgs            bool                    `mapstructure:"prompt_missing_args"`        // Ask for missing /add and /unrestrict arguments with a force-reply prompt
+	MaxInputLength               int                     `mapstructure:"max_input_length"`           // Longest magnet or hoster link accepted, in characters
+	PinStatusRefreshMinutes      int                     `mapstructure:"pin_status_refresh_minutes"` // How often /pinstatus boards are edited with the current queue
+	MaxFilenameDisplay           int                     `mapstructure:"max_filename_display"`       // Filenames in /list, /downloads and the kept list are cut to this many characters
+	SizeUnits                    string                  `mapstructure:"size_units"`                 // "binary" (KiB, 1024) or "decimal" (KB, 1000); empty keeps 1024-based sizes labelled KB
+	JanitorIntervalSeconds       int                     `mapstructure:"janitor_interval_seconds"`   // How often expired prompts and pending confirmations are dropped from memory
+	Timezone                     string                  `mapstructure:"timezone"`                   // IANA zone used for times shown in replies, e.g. "Europe/Berlin"; empty means UTC
+	BlockedHosts                 []string                `mapstructure:"blocked_hosts"`              // Hoster domains never unrestricted; subdomains included
+	AllowedHosts                 []string                `mapstructure:"allowed_hosts"`              // If set, only these hoster domains (and subdomains) are unrestricted
+	ProcessingAck                ProcessingAckConfig     `mapstructure:"processing_ack"`
+	ActivityLogging              string                  `mapstructure:"activity_logging"` // "all", "errors_only" or "off": which successful activity rows are stored
+	BulkUnrestrict               BulkUnrestrictConfig    `mapstructure:"bulk_unrestrict"`
+	MaxImportMagnets             int                     `mapstructure:"max_import_magnets"`  // Most magnets /import adds from one file
+	DefaultFileSelect            string                  `mapstructure:"default_file_select"` // "all", "video" or "largest": files selected on add unless a user set /setpref fileselect
+	AuditSink                    AuditSinkConfig         `mapstructure:"audit_sink"`
+}
+
+// AuditSinkConfig mirrors command and activity logs to an external system
+type AuditSinkConfig struct {
+	Type           string `mapstructure:"type"`            // "file" or "http"; empty disables the sink
+	Path           string `mapstructure:"path"`            // File the "file" sink appends JSON lines to
+	URL            string `mapstructure:"url"`             // Endpoint the "http" sink POSTs each event to
+	Token          string `mapstructure:"token"`           // Optional bearer token sent by the "http" sink
+	BufferSize     int    `mapstructure:"buffer_size"`     // Events held while the sink is slow; further ones are dropped
+	TimeoutSeconds int    `mapstructure:"timeout_seconds"` // Per-request timeout of the "http" sink
+}
+
+// BulkUnrestrictConfig controls messages carrying several hoster links
+type BulkUnrestrictConfig struct {
+	MaxPerMessage   int `mapstructure:"max_per_message"`  // Links unrestricted right away; the rest are queued
+	MaxQueued       int `mapstructure:"max_queued"`       // Links a user may have waiting in the queue
+	IntervalSeconds int `mapstructure:"interval_seconds"` // Pause between queued links
+}
+
+// ProcessingAckConfig controls the acknowledgment sent before slow commands produce their result
+type ProcessingAckConfig struct {
+	Commands []string `mapstructure:"commands"` // Commands, without the slash, that are acknowledged right away
+	Message  string   `mapstructure:"message"`  // HTML text of the acknowledgment, replaced by the result once ready
 }
 
 // AutoDeleteWarningConfig holds settings for auto-delete warning notifications
@@ -83,10 +191,18 @@ type AutoDeleteWarningConfig struct {
 	HoursBefore int   `mapstructure:"hours_before"` // Hours before deletion to send warning (default: 6)
 }
 
+// ListEnrichConfig controls refreshing active torrents in /list with their live details
+type ListEnrichConfig struct {
+	Enabled        bool `mapstructure:"enabled"`         // Fetch each active torrent's info for current speed and seeders
+	Concurrency    int  `mapstructure:"concurrency"`     // Max info requests in flight at once
+	TimeoutSeconds int  `mapstructure:"timeout_seconds"` // Per-request limit before falling back to the summary data
+}
+
 // RateLimitConfig holds rate limiting settings
 type RateLimitConfig struct {
-	MessagesPerSecond int `mapstructure:"messages_per_second"`
-	Burst             int `mapstructure:"burst"`
+	MessagesPerSecond int    `mapstructure:"messages_per_second"`
+	Burst             int    `mapstructure:"burst"`
+	StateFile         string `mapstructure:"state_file"` // Optional; persists the remaining burst so a restart doesn't grant a fresh burst
 }
 
 // DatabaseConfig holds database configuration
@@ -98,6 +214,11 @@ type DatabaseConfig struct {
 	Password string `mapstructure:"password"`
 	DBName   string `mapstructure:"dbname"`
 	SSLMode  string `mapstructure:"sslmode"`
+
+	LogLevel           string `mapstructure:"log_level"`            // Query logging: silent, error, warn or info
+	SlowThresholdMs    int    `mapstructure:"slow_threshold_ms"`    // Queries slower than this are logged at warn level
+	AutoMigrate        bool   `mapstructure:"auto_migrate"`         // Apply schema migrations on startup (default true)
+	HealthCheckSeconds int    `mapstructure:"health_check_seconds"` // How often the connection is checked and recovered after loss; negative disables
 }
 
 var cfg *Config
@@ -125,6 +246,19 @@ func (d *DatabaseConfig) Validate() error {
 	if d.SSLMode == "" {
 		d.SSLMode = "disable"
 	}
+	switch d.LogLevel {
+	case "":
+		d.LogLevel = "warn"
+	case "silent", "error", "warn", "info":
+	default:
+		return fmt.Errorf("invalid database log_level %q (must be silent, error, warn or info)", d.LogLevel)
+	}
+	if d.SlowThresholdMs <= 0 {
+		d.SlowThresholdMs = 200
+	}
+	if d.HealthCheckSeconds == 0 {
+		d.HealthCheckSeconds = 30
+	}
 	return nil
 }
 
@@ -159,6 +293,12 @@ func Load(cfgFile string) (*Config, error) {
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
 
+	// Defaults that cannot be inferred from a zero value
+	viper.SetDefault("database.auto_migrate", true)
+	viper.SetDefault("telegram.remember_threads", true)
+	viper.SetDefault("telegram.caption_links", true)
+	viper.SetDefault("telegram.entity_links", false)
+
 	// Read configuration
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
@@ -195,6 +335,20 @@ func (c *Config) Validate(webOnly bool) error {
 		if len(c.Telegram.SuperAdminIDs) == 0 {
 			return fmt.Errorf("at least one super admin ID is required")
 		}
+
+		if c.Telegram.Reconnect.InitialDelaySeconds <= 0 {
+			c.Telegram.Reconnect.InitialDelaySeconds = 1
+		}
+		if c.Telegram.Reconnect.MaxDelaySeconds < c.Telegram.Reconnect.InitialDelaySeconds {
+			c.Telegram.Reconnect.MaxDelaySeconds = max(60, c.Telegram.Reconnect.InitialDelaySeconds)
+		}
+
+		if c.Telegram.StatusBroadcastTime == "" {
+			c.Telegram.StatusBroadcastTime = "09:00"
+		}
+		if _, err := time.Parse("15:04", c.Telegram.StatusBroadcastTime); err != nil {
+			return fmt.Errorf("invalid telegram.status_broadcast_time %q: expected HH:MM", c.Telegram.StatusBroadcastTime)
+		}
 	}
 
 	if c.RealDebrid.APIToken == "" || c.RealDebrid.APIToken == "YOUR_REAL_DEBRID_API_TOKEN" {
@@ -205,11 +359,43 @@ func (c *Config) Validate(webOnly bool) error {
 	if c.RealDebrid.BaseURL == "" {
 		c.RealDebrid.BaseURL = "https://api.real-debrid.com/rest/1.0"
 	}
+	if err := validateBaseURL(c.RealDebrid.BaseURL, c.RealDebrid.AllowInsecure); err != nil {
+		return fmt.Errorf("invalid realdebrid.base_url: %w", err)
+	}
 
 	if c.RealDebrid.Timeout == 0 {
 		c.RealDebrid.Timeout = 30
 	}
 
+	if c.RealDebrid.SlowThreshold == 0 {
+		c.RealDebrid.SlowThreshold = 2000
+	}
+
+	if c.RealDebrid.TrafficWarnPercent < 0 || c.RealDebrid.TrafficWarnPercent > 100 {
+		return fmt.Errorf("realdebrid.traffic_warn_percent must be between 0 and 100")
+	}
+
+	if c.RealDebrid.QueueMaxPerUser <= 0 {
+		c.RealDebrid.QueueMaxPerUser = 5
+	}
+
+	if c.RealDebrid.AutoSelectSweeper.MinAgeMinutes <= 0 {
+		c.RealDebrid.AutoSelectSweeper.MinAgeMinutes = 15
+	}
+	if c.RealDebrid.AutoSelectSweeper.IntervalMinutes <= 0 {
+		c.RealDebrid.AutoSelectSweeper.IntervalMinutes = 5
+	}
+
+	if c.RealDebrid.Transport.MaxIdleConns <= 0 {
+		c.RealDebrid.Transport.MaxIdleConns = 100
+	}
+	if c.RealDebrid.Transport.MaxIdleConnsPerHost <= 0 {
+		c.RealDebrid.Transport.MaxIdleConnsPerHost = 2
+	}
+	if c.RealDebrid.Transport.IdleConnTimeoutSeconds <= 0 {
+		c.RealDebrid.Transport.IdleConnTimeoutSeconds = 90
+	}
+
 	if c.RealDebrid.Proxy != "" {
 		if _, err := url.Parse(c.RealDebrid.Proxy); err != nil {
 			return fmt.Errorf("invalid real-debrid proxy URL: %w", err)
@@ -247,6 +433,101 @@ func (c *Config) Validate(webOnly bool) error {
 		c.App.AutoDeleteWarning.HoursBefore = 6
 	}
 
+	if c.App.DuplicateAddWindowHours <= 0 {
+		c.App.DuplicateAddWindowHours = 24
+	}
+
+	if c.App.MaxInputLength <= 0 {
+		c.App.MaxInputLength = 2048
+	}
+
+	if c.App.PinStatusRefreshMinutes <= 0 {
+		c.App.PinStatusRefreshMinutes = 5
+	}
+
+	if c.App.MaxFilenameDisplay <= 0 {
+		c.App.MaxFilenameDisplay = 80
+	}
+
+	if c.App.ListEnrich.Concurrency <= 0 {
+		c.App.ListEnrich.Concurrency = 4
+	}
+	if c.App.ListEnrich.TimeoutSeconds <= 0 {
+		c.App.ListEnrich.TimeoutSeconds = 5
+	}
+
+	if c.App.ProcessingAck.Message == "" {
+		c.App.ProcessingAck.Message = "⏳ Queued, processing..."
+	}
+
+	if c.App.BulkUnrestrict.MaxPerMessage <= 0 {
+		c.App.BulkUnrestrict.MaxPerMessage = 5
+	}
+	if c.App.BulkUnrestrict.MaxQueued <= 0 {
+		c.App.BulkUnrestrict.MaxQueued = 50
+	}
+	if c.App.BulkUnrestrict.IntervalSeconds <= 0 {
+		c.App.BulkUnrestrict.IntervalSeconds = 2
+	}
+
+	if c.App.MaxImportMagnets <= 0 {
+		c.App.MaxImportMagnets = 100
+	}
+
+	switch c.App.AuditSink.Type {
+	case "":
+	case "file":
+		if c.App.AuditSink.Path == "" {
+			return fmt.Errorf("app.audit_sink.path is required for the file audit sink")
+		}
+	case "http":
+		if c.App.AuditSink.URL == "" {
+			return fmt.Errorf("app.audit_sink.url is required for the http audit sink")
+		}
+	default:
+		return fmt.Errorf("invalid app.audit_sink.type %q (must be file or http)", c.App.AuditSink.Type)
+	}
+	if c.App.AuditSink.BufferSize <= 0 {
+		c.App.AuditSink.BufferSize = 1000
+	}
+	if c.App.AuditSink.TimeoutSeconds <= 0 {
+		c.App.AuditSink.TimeoutSeconds = 5
+	}
+
+	switch c.App.DefaultFileSelect {
+	case "":
+		c.App.DefaultFileSelect = "all"
+	case "all", "video", "largest":
+	default:
+		return fmt.Errorf("invalid app.default_file_select %q (must be all, video or largest)", c.App.DefaultFileSelect)
+	}
+
+	if c.App.JanitorIntervalSeconds <= 0 {
+		c.App.JanitorIntervalSeconds = 60
+	}
+
+	if c.App.Timezone == "" {
+		c.App.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(c.App.Timezone); err != nil {
+		return fmt.Errorf("invalid app.timezone %q: %w", c.App.Timezone, err)
+	}
+
+	switch strings.ToLower(c.App.SizeUnits) {
+	case "", "binary", "decimal":
+	default:
+		return fmt.Errorf("invalid app.size_units %q (must be binary or decimal)", c.App.SizeUnits)
+	}
+
+	c.App.ActivityLogging = strings.ToLower(c.App.ActivityLogging)
+	switch c.App.ActivityLogging {
+	case "":
+		c.App.ActivityLogging = "all"
+	case "all", "errors_only", "off":
+	default:
+		return fmt.Errorf("invalid app.activity_logging %q (must be all, errors_only or off)", c.App.ActivityLogging)
+	}
+
 	// Database validation
 	if err := c.Database.Validate(); err != nil {
 		return err
@@ -264,6 +545,18 @@ func (c *Config) Validate(webOnly bool) error {
 	if c.Web.TokenExpiryMinutes == 0 {
 		c.Web.TokenExpiryMinutes = 60 // Default 1 hour
 	}
+	if c.Web.MaxPageSize <= 0 {
+		c.Web.MaxPageSize = 200
+	}
+	if c.Web.MaxBodyBytes <= 0 {
+		c.Web.MaxBodyBytes = 25 << 20 // 25 MB, room for .torrent uploads
+	}
+	if c.Web.SelectFiles.TimeoutSeconds <= 0 {
+		c.Web.SelectFiles.TimeoutSeconds = 30
+	}
+	if c.Web.SelectFiles.Concurrency <= 0 {
+		c.Web.SelectFiles.Concurrency = 4
+	}
 
 	// Limiter defaults
 	if c.Web.Limiter.Max == 0 {
@@ -293,6 +586,31 @@ func (c *Config) Validate(webOnly bool) error {
 	return nil
 }
 
+// validateBaseURL checks that raw is an absolute https URL with a host. Plain http is
+// only accepted with allowInsecure, since the API token would be sent in the clear.
+func validateBaseURL(raw string, allowInsecure bool) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "https":
+	case "http":
+		if !allowInsecure {
+			return fmt.Errorf("%q uses plain http; set realdebrid.allow_insecure_base_url to allow it", raw)
+		}
+	default:
+		return fmt.Errorf("%q must be an https URL", raw)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%q has no host", raw)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("%q must not have a query or fragment", raw)
+	}
+	return nil
+}
+
 // Get returns the loaded configuration
 func Get() *Config {
 	return cfg
@@ -308,6 +626,11 @@ func (c *Config) IsSuperAdmin(userID int64) bool {
 	return slices.Contains(c.Telegram.SuperAdminIDs, userID)
 }
 
+// IsModerator checks if a user ID belongs to a moderator
+func (c *Config) IsModerator(userID int64) bool {
+	return slices.Contains(c.Telegram.ModeratorIDs, userID)
+}
+
 // IsAllowedTopic checks if the given topic is allowed for the given chat.
 // If AllowedTopicIDs is not configured (nil), it returns true (all topics allowed).
 // If the chat is not in the map, it returns true (topic restriction not configured for this chat).
@@ -331,3 +654,34 @@ func (c *Config) IsAllowedTopic(chatID int64, topicID int) bool {
 	}
 	return slices.Contains(allowedTopics, int64(topicID))
 }
+
+// ChatLocale returns the locale configured for chatID, or "" if none is set
+func (c *Config) ChatLocale(chatID int64) string {
+	return c.Telegram.ChatLocales[fmt.Sprintf("%d", chatID)]
+}
+
+// CheckLinkHost returns an error if link may not be unrestricted under the configured
+// app.blocked_hosts and app.allowed_hosts. A host matches a listed domain exactly or as
+// a subdomain of it, ignoring case; blocked hosts win over allowed ones.
+func (c *Config) CheckLinkHost(link string) error {
+	if len(c.App.BlockedHosts) == 0 && len(c.App.AllowedHosts) == 0 {
+		return nil
+	}
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || u.Hostname() == "" {
+		return errors.New("link has no valid host")
+	}
+	host := strings.ToLower(u.Hostname())
+	matches := func(domain string) bool {
+		domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "."))
+		return domain != "" && (host == domain || strings.HasSuffix(host, "."+domain))
+	}
+
+	if slices.ContainsFunc(c.App.BlockedHosts, matches) {
+		return fmt.Errorf("links from %s are blocked", host)
+	}
+	if len(c.App.AllowedHosts) > 0 && !slices.ContainsFunc(c.App.AllowedHosts, matches) {
+		return fmt.Errorf("links from %s are not allowed", host)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package config
+
+import "testing"
+
+// minimalConfig returns the smallest Config that passes Validate(true)
+func minimalConfig() *Config {
+	cfg := &Config{}
+	cfg.RealDebrid.APIToken = "token"
+	cfg.App.RateLimit = RateLimitConfig{MessagesPerSecond: 25, Burst: 5}
+	cfg.Web.APIKey = "key"
+	cfg.Database.Host, cfg.Database.User, cfg.Database.DBName = "localhost", "rdctl", "rdctl"
+	return cfg
+}
+
+// TestValidateBaseURL verifies only well-formed https URLs pass unless http is allowed.
+func TestValidateBaseURL(t *testing.T) {
+	tests := []struct {
+		name          string
+		url           string
+		allowInsecure bool
+		wantErr       bool
+	}{
+		{"default", "https://api.real-debrid.com/rest/1.0", false, false},
+		{"uppercase scheme", "HTTPS://api.real-debrid.com/rest/1.0", false, false},
+		{"http rejected", "http://api.real-debrid.com/rest/1.0", false, true},
+		{"http allowed", "http://localhost:8081/rest/1.0", true, false},
+		{"no scheme", "api.real-debrid.com/rest/1.0", false, true},
+		{"other scheme", "ftp://api.real-debrid.com", true, true},
+		{"no host", "https:///rest/1.0", false, true},
+		{"query", "https://api.real-debrid.com/rest/1.0?x=1", false, true},
+		{"malformed", "https://api.real-debrid.com:port/", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBaseURL(tt.url, tt.allowInsecure)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateBaseURL(%q, %v) error = %v, wantErr %v", tt.url, tt.allowInsecure, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestValidate_BaseURL verifies Validate fills in the default base URL and rejects a bad one.
+func TestValidate_BaseURL(t *testing.T) {
+	cfg := minimalConfig()
+
+	cfg.RealDebrid.BaseURL = "http://api.real-debrid.com/rest/1.0"
+	if err := cfg.Validate(true); err == nil {
+		t.Error("Validate() accepted a plain http base URL")
+	}
+
+	cfg.RealDebrid.BaseURL = ""
+	if err := cfg.Validate(true); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if cfg.RealDebrid.BaseURL != "https://api.real-debrid.com/rest/1.0" {
+		t.Errorf("BaseURL = %q, want the default", cfg.RealDebrid.BaseURL)
+	}
+}
+
+// TestValidate_ActivityLogging verifies the activity logging modes and their default.
+func TestValidate_ActivityLogging(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"", "all", false},
+		{"all", "all", false},
+		{"Errors_Only", "errors_only", false},
+		{"off", "off", false},
+		{"some", "", true},
+	}
+	for _, tt := range tests {
+		cfg := minimalConfig()
+		cfg.App.ActivityLogging = tt.value
+
+		err := cfg.Validate(true)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate(activity_logging=%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && cfg.App.ActivityLogging != tt.want {
+			t.Errorf("activity_logging %q = %q, want %q", tt.value, cfg.App.ActivityLogging, tt.want)
+		}
+	}
+}
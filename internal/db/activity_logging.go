@@ -0,0 +1,42 @@
+package db
+
+// ActivityLogging controls which successful activity, torrent activity and download
+// activity rows are persisted. Failures, command logs and the rows features read back
+// (see keepTorrentActivity and keepDownloadActivity) are kept in every mode.
+type ActivityLogging string
+
+const (
+	ActivityLoggingAll        ActivityLogging = "all"         // Persist every activity
+	ActivityLoggingErrorsOnly ActivityLogging = "errors_only" // Persist failed activities only
+	ActivityLoggingOff        ActivityLogging = "off"         // Like errors_only, and skip the torrent and download counters
+)
+
+// Persist reports whether an activity with the given outcome is stored. Failures always
+// are; the zero value behaves like ActivityLoggingAll.
+func (l ActivityLogging) Persist(success bool) bool {
+	switch l {
+	case ActivityLoggingErrorsOnly, ActivityLoggingOff:
+		return !success
+	default:
+		return true
+	}
+}
+
+// Count reports whether successful torrent adds and downloads increment the per-user and
+// daily counters.
+func (l ActivityLogging) Count() bool {
+	return l != ActivityLoggingOff
+}
+
+// keepTorrentActivity reports whether a torrent activity is stored regardless of the
+// mode. Successful adds are: /retry reads their magnet link, completion notifications
+// their adder and the cached badge their metadata.
+func keepTorrentActivity(action string, success bool) bool {
+	return success && action == "add"
+}
+
+// keepDownloadActivity reports whether a download activity is stored regardless of the
+// mode. Successful unrestricts and fetches are, since /hoststats sums them up.
+func keepDownloadActivity(action string, success bool) bool {
+	return success && (action == "unrestrict" || action == "fetch")
+}
@@ -0,0 +1,81 @@
+package db
+
+import "testing"
+
+// TestActivityLogging_Persist verifies which outcomes each mode stores and counts.
+func TestActivityLogging_Persist(t *testing.T) {
+	tests := []struct {
+		mode        ActivityLogging
+		wantSuccess bool
+		wantFailure bool
+		wantCount   bool
+	}{
+		{ActivityLoggingAll, true, true, true},
+		{"", true, true, true},
+		{ActivityLoggingErrorsOnly, false, true, true},
+		{ActivityLoggingOff, false, true, false},
+	}
+	for _, tt := range tests {
+		if got := tt.mode.Persist(true); got != tt.wantSuccess {
+			t.Errorf("%q.Persist(success) = %v, want %v", tt.mode, got, tt.wantSuccess)
+		}
+		if got := tt.mode.Persist(false); got != tt.wantFailure {
+			t.Errorf("%q.Persist(failure) = %v, want %v", tt.mode, got, tt.wantFailure)
+		}
+		if got := tt.mode.Count(); got != tt.wantCount {
+			t.Errorf("%q.Count() = %v, want %v", tt.mode, got, tt.wantCount)
+		}
+	}
+}
+
+// TestLogActivity_Skipped verifies a skipped activity never reaches the database, which
+// is nil here and would panic if used. With off, a successful download isn't counted either.
+func TestLogActivity_Skipped(t *testing.T) {
+	activities := &ActivityRepository{logging: ActivityLoggingOff}
+	if err := activities.LogActivity(t.Context(), "", 1, 1, "u", ActivityTypeTorrentInfo, "info", 0, 0, true, "", nil); err != nil {
+		t.Errorf("LogActivity() error = %v", err)
+	}
+	torrents := &TorrentRepository{logging: ActivityLoggingErrorsOnly}
+	if err := torrents.LogTorrentActivity(t.Context(), "", 1, 1, "ID", "", "", "", "info", "", 0, 0, true, "", nil); err != nil {
+		t.Errorf("LogTorrentActivity() error = %v", err)
+	}
+	downloads := &DownloadRepository{logging: ActivityLoggingOff}
+	if err := downloads.LogDownloadActivity(t.Context(), "", 1, 1, "ID", "", "", "", "delete", 0, true, "", nil, nil); err != nil {
+		t.Errorf("LogDownloadActivity() error = %v", err)
+	}
+}
+
+// TestKeepActivity verifies only the successful rows features read back bypass the mode.
+func TestKeepActivity(t *testing.T) {
+	torrents := []struct {
+		action  string
+		success bool
+		want    bool
+	}{
+		{"add", true, true},
+		{"add", false, false},
+		{"delete", true, false},
+		{"add_duplicate", true, false},
+	}
+	for _, tt := range torrents {
+		if got := keepTorrentActivity(tt.action, tt.success); got != tt.want {
+			t.Errorf("keepTorrentActivity(%q, %v) = %v, want %v", tt.action, tt.success, got, tt.want)
+		}
+	}
+
+	downloads := []struct {
+		action  string
+		success bool
+		want    bool
+	}{
+		{"unrestrict", true, true},
+		{"fetch", true, true},
+		{"unrestrict", false, false},
+		{"delete", true, false},
+	}
+	for _, tt := range downloads {
+		if got := keepDownloadActivity(tt.action, tt.success); got != tt.want {
+			t.Errorf("keepDownloadActivity(%q, %v) = %v, want %v", tt.action, tt.success, got, tt.want)
+		}
+	}
+}
@@ -65,3 +65,67 @@ func (q *Queries) InsertActivityLog(ctx context.Context, arg InsertActivityLogPa
 	)
 	return err
 }
+
+const listActivitiesByTelegramUser = `-- name: ListActivitiesByTelegramUser :many
+SELECT
+    a.id,
+    a.activity_type,
+    a.command,
+    a.message_thread_id,
+    a.error_message,
+    a.created_at,
+    c.chat_id     AS chat_chat_id,
+    c.title       AS chat_title
+FROM activity_logs a
+JOIN users u ON u.id = a.user_id
+JOIN chats c ON c.id = a.chat_id
+WHERE u.user_id = $1 AND a.activity_type = $2
+ORDER BY a.created_at DESC
+LIMIT $3
+`
+
+type ListActivitiesByTelegramUserParams struct {
+	UserID       int64  `json:"user_id"`
+	ActivityType string `json:"activity_type"`
+	Limit        int32  `json:"limit"`
+}
+
+type ListActivitiesByTelegramUserRow struct {
+	ID              int64              `json:"id"`
+	ActivityType    string             `json:"activity_type"`
+	Command         *string            `json:"command"`
+	MessageThreadID *int64             `json:"message_thread_id"`
+	ErrorMessage    *string            `json:"error_message"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	ChatChatID      int64              `json:"chat_chat_id"`
+	ChatTitle       *string            `json:"chat_title"`
+}
+
+func (q *Queries) ListActivitiesByTelegramUser(ctx context.Context, arg ListActivitiesByTelegramUserParams) ([]ListActivitiesByTelegramUserRow, error) {
+	rows, err := q.db.Query(ctx, listActivitiesByTelegramUser, arg.UserID, arg.ActivityType, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListActivitiesByTelegramUserRow
+	for rows.Next() {
+		var i ListActivitiesByTelegramUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ActivityType,
+			&i.Command,
+			&i.MessageThreadID,
+			&i.ErrorMessage,
+			&i.CreatedAt,
+			&i.ChatChatID,
+			&i.ChatTitle,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -16,6 +16,7 @@ const (
 	ActivityTypeCommandStatus      ActivityType = "command_status"
 	ActivityTypeMagnetLink         ActivityType = "magnet_link"
 	ActivityTypeHosterLink         ActivityType = "hoster_link"
+	ActivityTypeMagnetInspect      ActivityType = "magnet_inspect"
 	ActivityTypeCommandDashboard   ActivityType = "command_dashboard"
 	ActivityTypeTorrentKeep        ActivityType = "torrent_keep"
 	ActivityTypeTorrentUnkeep      ActivityType = "torrent_unkeep"
@@ -23,6 +23,7 @@ func TestActivityTypeValues(t *testing.T) {
 		{"CommandStatus", ActivityTypeCommandStatus, "command_status"},
 		{"MagnetLink", ActivityTypeMagnetLink, "magnet_link"},
 		{"HosterLink", ActivityTypeHosterLink, "hoster_link"},
+		{"MagnetInspect", ActivityTypeMagnetInspect, "magnet_inspect"},
 		{"CommandDashboard", ActivityTypeCommandDashboard, "command_dashboard"},
 		{"TorrentKeep", ActivityTypeTorrentKeep, "torrent_keep"},
 		{"TorrentUnkeep", ActivityTypeTorrentUnkeep, "torrent_unkeep"},
@@ -62,6 +63,7 @@ func TestActivityTypeDistinct(t *testing.T) {
 		ActivityTypeCommandStatus,
 		ActivityTypeMagnetLink,
 		ActivityTypeHosterLink,
+		ActivityTypeMagnetInspect,
 		ActivityTypeCommandDashboard,
 		ActivityTypeTorrentKeep,
 		ActivityTypeTorrentUnkeep,
@@ -80,7 +82,7 @@ func TestActivityTypeDistinct(t *testing.T) {
 // TestActivityTypeCount ensures the expected total number of constants is present,
 // catching accidental removal.
 func TestActivityTypeCount(t *testing.T) {
-	const expected = 17
+	const expected = 18
 	all := []ActivityType{
 		ActivityTypeTorrentAdd,
 		ActivityTypeTorrentDelete,
@@ -94,6 +96,7 @@ func TestActivityTypeCount(t *testing.T) {
 		ActivityTypeCommandStatus,
 		ActivityTypeMagnetLink,
 		ActivityTypeHosterLink,
+		ActivityTypeMagnetInspect,
 		ActivityTypeCommandDashboard,
 		ActivityTypeTorrentKeep,
 		ActivityTypeTorrentUnkeep,
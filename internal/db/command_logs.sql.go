@@ -55,3 +55,58 @@ func (q *Queries) InsertCommandLog(ctx context.Context, arg InsertCommandLogPara
 	)
 	return err
 }
+
+const getCommandLatency = `-- name: GetCommandLatency :one
+SELECT
+    COUNT(execution_time)::bigint                  AS samples,
+    COALESCE(AVG(execution_time), 0)::float8       AS avg_ms,
+    COALESCE(MIN(execution_time), 0)::bigint       AS min_ms,
+    COALESCE(MAX(execution_time), 0)::bigint       AS max_ms
+FROM command_logs
+WHERE command = $1 AND created_at >= $2 AND execution_time IS NOT NULL
+`
+
+type GetCommandLatencyParams struct {
+	Command   string             `json:"command"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type GetCommandLatencyRow struct {
+	Samples int64   `json:"samples"`
+	AvgMs   float64 `json:"avg_ms"`
+	MinMs   int64   `json:"min_ms"`
+	MaxMs   int64   `json:"max_ms"`
+}
+
+func (q *Queries) GetCommandLatency(ctx context.Context, arg GetCommandLatencyParams) (GetCommandLatencyRow, error) {
+	row := q.db.QueryRow(ctx, getCommandLatency, arg.Command, arg.CreatedAt)
+	var i GetCommandLatencyRow
+	err := row.Scan(
+		&i.Samples,
+		&i.AvgMs,
+		&i.MinMs,
+		&i.MaxMs,
+	)
+	return i, err
+}
+
+const getCommandLatencyAtOffset = `-- name: GetCommandLatencyAtOffset :one
+SELECT execution_time
+FROM command_logs
+WHERE command = $1 AND created_at >= $2 AND execution_time IS NOT NULL
+ORDER BY execution_time
+LIMIT 1 OFFSET $3
+`
+
+type GetCommandLatencyAtOffsetParams struct {
+	Command   string             `json:"command"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	Offset    int32              `json:"offset"`
+}
+
+func (q *Queries) GetCommandLatencyAtOffset(ctx context.Context, arg GetCommandLatencyAtOffsetParams) (*int64, error) {
+	row := q.db.QueryRow(ctx, getCommandLatencyAtOffset, arg.Command, arg.CreatedAt, arg.Offset)
+	var execution_time *int64
+	err := row.Scan(&execution_time)
+	return execution_time, err
+}
@@ -23,6 +23,33 @@ func (q *Queries) CountDownloadsByUser(ctx context.Context, userID int64) (int64
 	return count, err
 }
 
+const getUserHostStats = `-- name: GetUserHostStats :one
+SELECT
+    COUNT(*) AS downloads,
+    COALESCE(SUM(file_size), 0)::bigint AS total_bytes,
+    MAX(created_at)::timestamptz AS last_download_at
+FROM download_activities
+WHERE user_id = $1 AND action IN ('unrestrict', 'fetch') AND success AND lower(host) = $2
+`
+
+type GetUserHostStatsParams struct {
+	UserID int64   `json:"user_id"`
+	Host   *string `json:"host"`
+}
+
+type GetUserHostStatsRow struct {
+	Downloads      int64              `json:"downloads"`
+	TotalBytes     int64              `json:"total_bytes"`
+	LastDownloadAt pgtype.Timestamptz `json:"last_download_at"`
+}
+
+func (q *Queries) GetUserHostStats(ctx context.Context, arg GetUserHostStatsParams) (GetUserHostStatsRow, error) {
+	row := q.db.QueryRow(ctx, getUserHostStats, arg.UserID, arg.Host)
+	var i GetUserHostStatsRow
+	err := row.Scan(&i.Downloads, &i.TotalBytes, &i.LastDownloadAt)
+	return i, err
+}
+
 const insertDownloadActivity = `-- name: InsertDownloadActivity :exec
 INSERT INTO download_activities (
     request_id, user_id, chat_id, download_id, original_link, file_name,
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+const (
+	// healthPingTimeout bounds a single health-check ping
+	healthPingTimeout = 5 * time.Second
+
+	// healthResetAfter is how many consecutive failed pings trigger a pool reset
+	healthResetAfter = 3
+
+	// healthMaxBackoff caps the wait between checks while the database is down
+	healthMaxBackoff = 2 * time.Minute
+)
+
+// healthPool is the part of *pgxpool.Pool used by the health monitor
+type healthPool interface {
+	Ping(ctx context.Context) error
+	Reset()
+}
+
+// healthMonitor pings the pool periodically. pgxpool dials new connections on demand,
+// but connections broken by a server restart are only noticed when used; after
+// repeated failures the monitor resets the pool so every stale connection is dropped
+// and the next query dials fresh. While the database stays down it backs off.
+type healthMonitor struct {
+	pool       healthPool
+	interval   time.Duration
+	resetAfter int
+	maxBackoff time.Duration
+
+	failures int
+}
+
+// newHealthMonitor creates a monitor that checks pool every interval
+func newHealthMonitor(pool healthPool, interval time.Duration) *healthMonitor {
+	return &healthMonitor{
+		pool:       pool,
+		interval:   interval,
+		resetAfter: healthResetAfter,
+		maxBackoff: healthMaxBackoff,
+	}
+}
+
+// run checks the pool until ctx is cancelled
+func (m *healthMonitor) run(ctx context.Context) {
+	timer := time.NewTimer(m.interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			m.check(ctx)
+			timer.Reset(m.nextDelay())
+		}
+	}
+}
+
+// check performs one ping and updates the failure count, resetting the pool once
+// resetAfter consecutive pings have failed
+func (m *healthMonitor) check(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, healthPingTimeout)
+	defer cancel()
+
+	err := m.pool.Ping(pingCtx)
+	if err == nil {
+		if m.failures > 0 {
+			log.Printf("Database connection restored after %d failed health checks", m.failures)
+		}
+		m.failures = 0
+		return
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	m.failures++
+	if m.failures == 1 {
+		log.Printf("Database health check failed: %v", err)
+	}
+	if m.failures%m.resetAfter == 0 {
+		log.Printf("Database unreachable for %d health checks (%v); resetting connection pool", m.failures, err)
+		m.pool.Reset()
+	}
+}
+
+// nextDelay returns the wait before the next check: the normal interval while healthy,
+// doubling per failure up to maxBackoff while the database is down
+func (m *healthMonitor) nextDelay() time.Duration {
+	delay := m.interval
+	for i := 0; i < m.failures && delay < m.maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, max(m.maxBackoff, m.interval))
+}
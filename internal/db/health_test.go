@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeHealthPool simulates a pool whose connection can be dropped and restored.
+type fakeHealthPool struct {
+	mu     sync.Mutex
+	down   bool
+	pings  int
+	resets int
+}
+
+func (p *fakeHealthPool) Ping(context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pings++
+	if p.down {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (p *fakeHealthPool) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.resets++
+}
+
+func (p *fakeHealthPool) setDown(down bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.down = down
+}
+
+func TestHealthMonitor_DroppedConnection(t *testing.T) {
+	pool := &fakeHealthPool{}
+	m := newHealthMonitor(pool, time.Second)
+	ctx := context.Background()
+
+	m.check(ctx)
+	if m.failures != 0 || pool.resets != 0 {
+		t.Fatalf("healthy check: failures=%d resets=%d, want 0, 0", m.failures, pool.resets)
+	}
+
+	// Postgres goes away: the pool is reset after resetAfter consecutive failures
+	pool.setDown(true)
+	for i := 1; i < healthResetAfter; i++ {
+		m.check(ctx)
+	}
+	if pool.resets != 0 {
+		t.Fatalf("pool reset after %d failures, want none before %d", healthResetAfter-1, healthResetAfter)
+	}
+	m.check(ctx)
+	if pool.resets != 1 {
+		t.Fatalf("resets = %d after %d failures, want 1", pool.resets, healthResetAfter)
+	}
+
+	// Postgres is back: the failure count clears and checks return to the normal interval
+	pool.setDown(false)
+	m.check(ctx)
+	if m.failures != 0 {
+		t.Errorf("failures = %d after recovery, want 0", m.failures)
+	}
+	if got := m.nextDelay(); got != time.Second {
+		t.Errorf("nextDelay() after recovery = %v, want %v", got, time.Second)
+	}
+}
+
+func TestHealthMonitor_Backoff(t *testing.T) {
+	m := newHealthMonitor(&fakeHealthPool{}, 10*time.Second)
+	m.maxBackoff = time.Minute
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 10 * time.Second},
+		{1, 20 * time.Second},
+		{2, 40 * time.Second},
+		{3, time.Minute},
+		{10, time.Minute},
+	}
+	for _, tt := range tests {
+		m.failures = tt.failures
+		if got := m.nextDelay(); got != tt.want {
+			t.Errorf("nextDelay() with %d failures = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestHealthMonitor_RunStopsOnCancel(t *testing.T) {
+	pool := &fakeHealthPool{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		newHealthMonitor(pool, 5*time.Millisecond).run(ctx)
+		close(done)
+	}()
+
+	time.Sleep(30 * time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("run did not return after cancel")
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	if pool.pings == 0 {
+		t.Error("run never pinged the pool")
+	}
+}
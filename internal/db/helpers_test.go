@@ -451,6 +451,120 @@ func TestToKeptTorrentPublic_NilFilenameAndUser(t *testing.T) {
 	}
 }
 
+func TestToTorrentSubscriptionPublic_FullRow(t *testing.T) {
+	username := "bob"
+	threadID := int64(12)
+	now := time.Now().UTC().Truncate(time.Second)
+	row := ListTorrentSubscriptionsRow{
+		ID:           4,
+		TorrentID:    "abc123",
+		ThreadID:     &threadID,
+		CreatedAt:    pgtype.Timestamptz{Time: now, Valid: true},
+		ChatChatID:   -100123,
+		UserIDPk:     3,
+		UserUserID:   1001,
+		UserUsername: &username,
+	}
+	pub := toTorrentSubscriptionPublic(row)
+	if pub.TorrentID != "abc123" {
+		t.Errorf("toTorrentSubscriptionPublic TorrentID: got %q, want %q", pub.TorrentID, "abc123")
+	}
+	if pub.ChatID != -100123 {
+		t.Errorf("toTorrentSubscriptionPublic ChatID: got %d, want -100123", pub.ChatID)
+	}
+	if pub.ThreadID != 12 {
+		t.Errorf("toTorrentSubscriptionPublic ThreadID: got %d, want 12", pub.ThreadID)
+	}
+	if pub.UserPK != 3 || pub.UserID != 1001 {
+		t.Errorf("toTorrentSubscriptionPublic user: got (%d, %d), want (3, 1001)", pub.UserPK, pub.UserID)
+	}
+	if !pub.CreatedAt.Equal(now) {
+		t.Errorf("toTorrentSubscriptionPublic CreatedAt: got %v, want %v", pub.CreatedAt, now)
+	}
+}
+
+func TestToTorrentSubscriptionPublic_FallsBackToFirstName(t *testing.T) {
+	firstName := "Bob"
+	row := ListTorrentSubscriptionsRow{TorrentID: "xyz", UserFirstName: &firstName}
+	pub := toTorrentSubscriptionPublic(row)
+	if pub.Username != "Bob" {
+		t.Errorf("toTorrentSubscriptionPublic Username: got %q, want %q", pub.Username, "Bob")
+	}
+	if pub.ThreadID != 0 {
+		t.Errorf("toTorrentSubscriptionPublic nil ThreadID: got %d, want 0", pub.ThreadID)
+	}
+}
+
+// ─────────────────────────────────────────────────────────────
+// toInt64FromAny / buildGlobalStats
+// ─────────────────────────────────────────────────────────────
+
+func TestToInt64FromAny(t *testing.T) {
+	var numeric pgtype.Numeric
+	if err := numeric.Scan("1234"); err != nil {
+		t.Fatalf("numeric.Scan: %v", err)
+	}
+	tests := []struct {
+		name string
+		in   interface{}
+		want int64
+	}{
+		{"int64", int64(7), 7},
+		{"int32", int32(8), 8},
+		{"numeric", numeric, 1234},
+		{"invalid numeric", pgtype.Numeric{}, 0},
+		{"nil", nil, 0},
+		{"string", "12", 0},
+	}
+	for _, tt := range tests {
+		if got := toInt64FromAny(tt.in); got != tt.want {
+			t.Errorf("toInt64FromAny(%s) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBuildGlobalStats_SeededDataset(t *testing.T) {
+	// Three users: 40 + 25 + 5 commands, 6 + 3 + 0 torrents, 10 + 2 + 1 downloads.
+	var commands, torrents, downloads pgtype.Numeric
+	for n, v := range map[*pgtype.Numeric]string{&commands: "70", &torrents: "9", &downloads: "13"} {
+		if err := n.Scan(v); err != nil {
+			t.Fatalf("numeric.Scan(%s): %v", v, err)
+		}
+	}
+	summary := GetGlobalSummaryStatsRow{
+		TotalUsers:     3,
+		TotalCommands:  commands,
+		TotalTorrents:  torrents,
+		TotalDownloads: downloads,
+	}
+	top := []GetCommandPopularityAllTimeRow{{Command: "list", Total: 31, SuccessCount: 30}}
+
+	stats := buildGlobalStats(summary, top)
+	want := map[string]interface{}{
+		"total_users":      int64(3),
+		"total_commands":   int64(70),
+		"total_torrents":   int64(9),
+		"total_downloads":  int64(13),
+		"top_command":      "list",
+		"top_command_uses": int64(31),
+	}
+	for key, v := range want {
+		if stats[key] != v {
+			t.Errorf("buildGlobalStats[%q] = %v, want %v", key, stats[key], v)
+		}
+	}
+}
+
+func TestBuildGlobalStats_EmptyDataset(t *testing.T) {
+	stats := buildGlobalStats(GetGlobalSummaryStatsRow{TotalCommands: int64(0)}, nil)
+	if stats["top_command"] != "" {
+		t.Errorf("buildGlobalStats top_command: got %v, want empty", stats["top_command"])
+	}
+	if stats["total_commands"] != int64(0) || stats["total_users"] != int64(0) {
+		t.Errorf("buildGlobalStats totals: got %v, want zeros", stats)
+	}
+}
+
 // ─────────────────────────────────────────────────────────────
 // Regression: strPtr must not mutate the original string
 // ─────────────────────────────────────────────────────────────
@@ -489,3 +603,40 @@ func TestMetadataJSONRoundTrip(t *testing.T) {
 		t.Errorf("metadata round-trip key: got %v, want %q", out["key"], "value")
 	}
 }
+
+// ─────────────────────────────────────────────────────────────
+// toActivityEntryPublic
+// ─────────────────────────────────────────────────────────────
+
+func TestToActivityEntryPublic(t *testing.T) {
+	cmd := "list"
+	errMsg := "Unauthorized access attempt"
+	title := "Group"
+	thread := int64(4)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	entry := toActivityEntryPublic(ListActivitiesByTelegramUserRow{
+		ID:              3,
+		ActivityType:    string(ActivityTypeUnauthorized),
+		Command:         &cmd,
+		MessageThreadID: &thread,
+		ErrorMessage:    &errMsg,
+		CreatedAt:       pgtype.Timestamptz{Time: now, Valid: true},
+		ChatChatID:      -100,
+		ChatTitle:       &title,
+	})
+	if entry.ID != 3 || entry.ActivityType != "unauthorized" || entry.Command != "list" || entry.ErrorMessage != errMsg {
+		t.Errorf("toActivityEntryPublic fields: got %+v", entry)
+	}
+	if entry.ChatID != -100 || entry.ChatTitle != "Group" || entry.ThreadID != 4 {
+		t.Errorf("toActivityEntryPublic chat: got %+v", entry)
+	}
+	if !entry.CreatedAt.Equal(now) {
+		t.Errorf("toActivityEntryPublic CreatedAt: got %v, want %v", entry.CreatedAt, now)
+	}
+
+	empty := toActivityEntryPublic(ListActivitiesByTelegramUserRow{ID: 1, ChatChatID: 5})
+	if empty.Command != "" || empty.ChatTitle != "" || empty.ThreadID != 0 || !empty.CreatedAt.IsZero() {
+		t.Errorf("toActivityEntryPublic nil fields: got %+v", empty)
+	}
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// hostStatsDBTX answers GetUserHostStats from rows of per-host totals, as the
+// database would after aggregating, and records the arguments it was queried with.
+type hostStatsDBTX struct {
+	totals map[string]GetUserHostStatsRow // keyed by lowercased host
+	args   []interface{}
+}
+
+func (h *hostStatsDBTX) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errors.New("unexpected exec")
+}
+
+func (h *hostStatsDBTX) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return nil, errors.New("unexpected query")
+}
+
+func (h *hostStatsDBTX) QueryRow(_ context.Context, _ string, args ...interface{}) pgx.Row {
+	h.args = args
+	return hostStatsRow(h.totals[*args[1].(*string)])
+}
+
+// hostStatsRow scans a GetUserHostStatsRow
+type hostStatsRow GetUserHostStatsRow
+
+func (r hostStatsRow) Scan(dest ...interface{}) error {
+	*dest[0].(*int64) = r.Downloads
+	*dest[1].(*int64) = r.TotalBytes
+	*dest[2].(*pgtype.Timestamptz) = r.LastDownloadAt
+	return nil
+}
+
+// TestGetUserHostStats verifies hosts are looked up lowercased and each host's totals
+// are returned, with no downloads reported as zero.
+func TestGetUserHostStats(t *testing.T) {
+	last := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	dbtx := &hostStatsDBTX{totals: map[string]GetUserHostStatsRow{
+		"rapidgator.net": {Downloads: 3, TotalBytes: 3 << 30, LastDownloadAt: toPgtypeTimestamptz(last)},
+		"1fichier.com":   {Downloads: 1, TotalBytes: 512, LastDownloadAt: toPgtypeTimestamptz(last.Add(-time.Hour))},
+	}}
+	repo := &DownloadRepository{queries: New(dbtx)}
+
+	tests := []struct {
+		host string
+		want HostStats
+	}{
+		{" RapidGator.NET ", HostStats{Host: "rapidgator.net", Downloads: 3, TotalBytes: 3 << 30, LastDownloadAt: last}},
+		{"1fichier.com", HostStats{Host: "1fichier.com", Downloads: 1, TotalBytes: 512, LastDownloadAt: last.Add(-time.Hour)}},
+		{"mega.nz", HostStats{Host: "mega.nz"}},
+	}
+	for _, tt := range tests {
+		got, err := repo.GetUserHostStats(t.Context(), 42, tt.host)
+		if err != nil {
+			t.Fatalf("GetUserHostStats(%q) error = %v", tt.host, err)
+		}
+		if got != tt.want {
+			t.Errorf("GetUserHostStats(%q) = %+v, want %+v", tt.host, got, tt.want)
+		}
+		if dbtx.args[0] != int64(42) {
+			t.Errorf("queried user %v, want 42", dbtx.args[0])
+		}
+	}
+}
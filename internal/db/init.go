@@ -18,58 +18,96 @@ import (
 //go:embed migrations/*.sql
 var migrationsFS embed.FS
 
-// It returns an error if migrations fail, if the DSN cannot be parsed, if the pool cannot be created, or if the initial ping fails (the pool is closed on ping failure).
-func Init(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
-	if err := RunMigrations(dsn); err != nil {
-		return nil, fmt.Errorf("migrations failed: %w", err)
+// runMigrations is the migration step used by Init; tests replace it to observe calls
+var runMigrations = RunMigrations
+
+// Options configures Init
+type Options struct {
+	LogLevel      string        // Query log level: silent, error, warn or info
+	SlowThreshold time.Duration // Queries at least this slow are logged at warn level; 0 disables
+	AutoMigrate   bool          // Apply embedded migrations on startup; when false the schema must already exist
+	HealthCheck   time.Duration // How often the pool is pinged to detect and recover from connection loss; 0 disables
+}
+
+// Init runs migrations (unless opts.AutoMigrate is false) and returns a connected pool whose queries are logged according to opts.
+// It returns an error if the log level is invalid, if migrations fail, if the DSN cannot be parsed, if the pool cannot be created, or if the initial ping fails (the pool is closed on ping failure).
+func Init(ctx context.Context, dsn string, opts Options) (*pgxpool.Pool, error) {
+	level, err := ParseLogLevel(opts.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.AutoMigrate {
+		if err := runMigrations(dsn); err != nil {
+			return nil, fmt.Errorf("migrations failed: %w", err)
+		}
+	} else {
+		log.Println("Skipping database migrations (database.auto_migrate is false); the schema must already be up to date")
 	}
 
 	cfg, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse DSN: %w", err)
 	}
+	if level > LogLevelSilent {
+		cfg.ConnConfig.Tracer = &queryLogger{level: level, slowThreshold: opts.SlowThreshold}
+	}
 	cfg.MaxConns = 20
 	cfg.MinConns = 2
 	cfg.MaxConnLifetime = time.Hour
 	cfg.MaxConnIdleTime = 30 * time.Minute
 	cfg.HealthCheckPeriod = time.Minute
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	connectCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	pool, err := pgxpool.NewWithConfig(connectCtx, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create pool: %w", err)
 	}
-	if err := pool.Ping(ctx); err != nil {
+	if err := pool.Ping(connectCtx); err != nil {
 		pool.Close()
 		return nil, fmt.Errorf("failed to ping: %w", err)
 	}
-	log.Println("Database connected and migrations completed successfully!")
-
-	// Start periodic pool stats logging; stops when ctx is cancelled
-	go func() {
-		ticker := time.NewTicker(30 * time.Second)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				stat := pool.Stat()
-				log.Printf("db pool: acquired=%d idle=%d waiting=%d max=%d",
-					stat.AcquiredConns(),
-					stat.IdleConns(),
-					stat.TotalConns()-stat.AcquiredConns(),
-					stat.MaxConns(),
-				)
-			}
-		}
-	}()
+	if opts.AutoMigrate {
+		log.Println("Database connected and migrations completed successfully!")
+	} else {
+		log.Println("Database connected successfully!")
+	}
+
+	// Watch for connection loss (e.g. a Postgres restart); stops when ctx is cancelled
+	if opts.HealthCheck > 0 {
+		go newHealthMonitor(pool, opts.HealthCheck).run(ctx)
+	}
+
+	// At info level, log pool stats periodically; stops when ctx is cancelled
+	if level == LogLevelInfo {
+		go logPoolStats(ctx, pool)
+	}
 
 	return pool, nil
 }
 
+// logPoolStats logs the pool's connection counts every 30 seconds until ctx is cancelled
+func logPoolStats(ctx context.Context, pool *pgxpool.Pool) {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stat := pool.Stat()
+			log.Printf("db pool: acquired=%d idle=%d waiting=%d max=%d",
+				stat.AcquiredConns(),
+				stat.IdleConns(),
+				stat.TotalConns()-stat.AcquiredConns(),
+				stat.MaxConns(),
+			)
+		}
+	}
+}
+
 // Close closes the given connection pool. Calling Close with a nil pool is a no-op.
 func Close(pool *pgxpool.Pool) {
 	if pool != nil {
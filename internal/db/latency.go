@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetLatencyStats returns the average, minimum, maximum and 95th percentile execution
+// time of command over the logs created since the given time. Both queries run in one
+// REPEATABLE READ transaction so the percentile is taken from the same rows.
+func (r *CommandRepository) GetLatencyStats(ctx context.Context, command string, since time.Time) (LatencyStats, error) {
+	var stats LatencyStats
+	err := withReadTx(ctx, r.pool, func(tx pgx.Tx) error {
+		var err error
+		stats, err = latencyStats(ctx, New(tx), command, since)
+		return err
+	})
+	return stats, err
+}
+
+// latencyStats computes the figures of GetLatencyStats with q. The aggregates are cast to
+// float8/bigint in SQL because AVG over a bigint yields numeric, which pgx would otherwise
+// decode into pgtype.Numeric; p95 is read with an ordered OFFSET query rather than
+// percentile_disc so it stays a plain index-friendly lookup.
+func latencyStats(ctx context.Context, q *Queries, command string, since time.Time) (LatencyStats, error) {
+	from := toPgtypeTimestamptz(since)
+	agg, err := q.GetCommandLatency(ctx, GetCommandLatencyParams{Command: command, CreatedAt: from})
+	if err != nil {
+		return LatencyStats{}, fmt.Errorf("failed to aggregate latency: %w", err)
+	}
+	stats := LatencyStats{Command: command, Samples: agg.Samples, AvgMs: agg.AvgMs, MinMs: agg.MinMs, MaxMs: agg.MaxMs}
+	if agg.Samples == 0 {
+		return stats, nil
+	}
+
+	p95, err := q.GetCommandLatencyAtOffset(ctx, GetCommandLatencyAtOffsetParams{
+		Command:   command,
+		CreatedAt: from,
+		Offset:    int32(percentileOffset(agg.Samples, 0.95)),
+	})
+	if err != nil {
+		return LatencyStats{}, fmt.Errorf("failed to read p95 latency: %w", err)
+	}
+	if p95 != nil {
+		stats.P95Ms = *p95
+	}
+	return stats, nil
+}
+
+// percentileOffset returns the zero-based position of the nearest-rank p-th percentile
+// among n ascending samples.
+func percentileOffset(n int64, p float64) int64 {
+	rank := int64(math.Ceil(p * float64(n)))
+	if rank < 1 {
+		rank = 1
+	}
+	return rank - 1
+}
@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// latencyDBTX is a DBTX that answers the latency queries from seeded execution times.
+type latencyDBTX struct {
+	timings []int64
+	offsets []int32
+}
+
+func (l *latencyDBTX) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errors.New("unexpected exec")
+}
+
+func (l *latencyDBTX) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return nil, errors.New("unexpected query")
+}
+
+func (l *latencyDBTX) QueryRow(_ context.Context, sql string, args ...interface{}) pgx.Row {
+	sorted := append([]int64(nil), l.timings...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	if strings.Contains(sql, "OFFSET") {
+		offset := args[2].(int32)
+		l.offsets = append(l.offsets, offset)
+		if int(offset) >= len(sorted) {
+			return latencyRow{err: pgx.ErrNoRows}
+		}
+		v := sorted[offset]
+		return latencyRow{values: []interface{}{&v}}
+	}
+
+	var sum, lo, hi int64
+	for i, v := range sorted {
+		sum += v
+		if i == 0 || v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	avg := 0.0
+	if len(sorted) > 0 {
+		avg = float64(sum) / float64(len(sorted))
+	}
+	return latencyRow{values: []interface{}{int64(len(sorted)), avg, lo, hi}}
+}
+
+// latencyRow is a pgx.Row scanning fixed values into pointers of the same type.
+type latencyRow struct {
+	values []interface{}
+	err    error
+}
+
+func (r latencyRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, d := range dest {
+		switch d := d.(type) {
+		case *int64:
+			*d = r.values[i].(int64)
+		case *float64:
+			*d = r.values[i].(float64)
+		case **int64:
+			*d = r.values[i].(*int64)
+		}
+	}
+	return nil
+}
+
+// TestLatencyStats verifies avg/min/max and the nearest-rank p95 on seeded timings.
+func TestLatencyStats(t *testing.T) {
+	fake := &latencyDBTX{}
+	for ms := int64(1); ms <= 20; ms++ {
+		fake.timings = append(fake.timings, ms*10)
+	}
+
+	stats, err := latencyStats(context.Background(), New(fake), "list", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("latencyStats() error = %v", err)
+	}
+	want := LatencyStats{Command: "list", Samples: 20, AvgMs: 105, MinMs: 10, MaxMs: 200, P95Ms: 190}
+	if stats != want {
+		t.Errorf("latencyStats() = %+v, want %+v", stats, want)
+	}
+	if len(fake.offsets) != 1 || fake.offsets[0] != 18 {
+		t.Errorf("p95 offsets = %v, want [18]", fake.offsets)
+	}
+}
+
+// TestLatencyStats_NoSamples verifies a command without logs yields zeros and skips the p95 lookup.
+func TestLatencyStats_NoSamples(t *testing.T) {
+	fake := &latencyDBTX{}
+	stats, err := latencyStats(context.Background(), New(fake), "list", time.Now())
+	if err != nil {
+		t.Fatalf("latencyStats() error = %v", err)
+	}
+	if stats != (LatencyStats{Command: "list"}) {
+		t.Errorf("latencyStats() = %+v, want zero stats", stats)
+	}
+	if len(fake.offsets) != 0 {
+		t.Errorf("p95 looked up %d times without samples", len(fake.offsets))
+	}
+}
+
+// TestPercentileOffset verifies the nearest-rank position for small and large sample sets.
+func TestPercentileOffset(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want int64
+	}{
+		{1, 0},
+		{2, 1},
+		{19, 18},
+		{20, 18},
+		{100, 94},
+	}
+	for _, tt := range tests {
+		if got := percentileOffset(tt.n, 0.95); got != tt.want {
+			t.Errorf("percentileOffset(%d) = %d, want %d", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,5 @@
+-- 000003_torrent_subscriptions.down.sql
+
+SET search_path = public;
+
+DROP TABLE IF EXISTS torrent_subscriptions;
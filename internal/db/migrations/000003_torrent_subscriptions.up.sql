@@ -0,0 +1,19 @@
+-- 000003_torrent_subscriptions.up.sql
+-- Per-torrent completion subscriptions created with /subscribe.
+
+SET search_path = public;
+
+CREATE TABLE IF NOT EXISTS torrent_subscriptions (
+    id         bigint      GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id    bigint      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    torrent_id text        NOT NULL,
+    chat_id    bigint      NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    thread_id  bigint,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT uq_torrent_subscriptions_user_torrent UNIQUE (user_id, torrent_id)
+);
+
+-- ── torrent_subscriptions ──────────────────────────────────────────────────
+-- The watcher looks up and clears subscriptions by torrent
+CREATE INDEX IF NOT EXISTS idx_torrent_subscriptions_torrent_id ON torrent_subscriptions (torrent_id);
+CREATE INDEX IF NOT EXISTS idx_torrent_subscriptions_chat_id    ON torrent_subscriptions (chat_id);
@@ -0,0 +1,5 @@
+-- 000004_scheduled_deletions.down.sql
+
+SET search_path = public;
+
+DROP TABLE IF EXISTS scheduled_deletions;
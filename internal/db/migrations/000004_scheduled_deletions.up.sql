@@ -0,0 +1,19 @@
+-- 000004_scheduled_deletions.up.sql
+-- Delayed torrent deletions scheduled with /autodelete <id> <hours>.
+
+SET search_path = public;
+
+CREATE TABLE IF NOT EXISTS scheduled_deletions (
+    id         bigint      GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    torrent_id text        NOT NULL,
+    delete_at  timestamptz NOT NULL,
+    chat_id    bigint      NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    thread_id  bigint,
+    user_id    bigint      REFERENCES users(id) ON DELETE SET NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT uq_scheduled_deletions_torrent UNIQUE (torrent_id)
+);
+
+-- ── scheduled_deletions ────────────────────────────────────────────────────
+-- The worker picks up due deletions in delete_at order
+CREATE INDEX IF NOT EXISTS idx_scheduled_deletions_delete_at ON scheduled_deletions (delete_at);
@@ -0,0 +1,5 @@
+-- 000005_queued_magnets.down.sql
+
+SET search_path = public;
+
+DROP TABLE IF EXISTS queued_magnets;
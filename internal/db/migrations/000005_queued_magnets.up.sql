@@ -0,0 +1,18 @@
+-- 000005_queued_magnets.up.sql
+-- Magnets queued by /add while the active torrent limit was reached.
+
+SET search_path = public;
+
+CREATE TABLE IF NOT EXISTS queued_magnets (
+    id         bigint      GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    magnet     text        NOT NULL,
+    user_id    bigint      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    chat_id    bigint      NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    thread_id  bigint,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+-- ── queued_magnets ─────────────────────────────────────────────────────────
+-- Queued magnets are released oldest first and listed per user with /myqueue
+CREATE INDEX IF NOT EXISTS idx_queued_magnets_created_at ON queued_magnets (created_at);
+CREATE INDEX IF NOT EXISTS idx_queued_magnets_user_id    ON queued_magnets (user_id);
@@ -0,0 +1,6 @@
+-- 000006_torrent_display_names.down.sql
+
+SET search_path = public;
+
+DROP INDEX IF EXISTS idx_torrent_activities_display_name;
+ALTER TABLE torrent_activities DROP COLUMN IF EXISTS display_name;
@@ -0,0 +1,11 @@
+-- 000006_torrent_display_names.up.sql
+-- Aliases set with /rename, shown in place of the Real-Debrid filename.
+
+SET search_path = public;
+
+ALTER TABLE torrent_activities ADD COLUMN IF NOT EXISTS display_name text;
+
+-- ── torrent_activities ─────────────────────────────────────────────────────
+-- The latest alias of each torrent is looked up when listing torrents
+CREATE INDEX IF NOT EXISTS idx_torrent_activities_display_name
+    ON torrent_activities (torrent_id, created_at DESC) WHERE display_name IS NOT NULL;
@@ -0,0 +1,5 @@
+-- 000007_user_preferences.down.sql
+
+SET search_path = public;
+
+DROP TABLE IF EXISTS user_preferences;
@@ -0,0 +1,12 @@
+-- 000007_user_preferences.up.sql
+-- Per-user preferences set with /setpref, e.g. which files are selected on add.
+
+SET search_path = public;
+
+CREATE TABLE IF NOT EXISTS user_preferences (
+    user_id    bigint      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    key        text        NOT NULL,
+    value      text        NOT NULL,
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, key)
+);
@@ -115,6 +115,25 @@ type Messages struct {
 	SentAt      pgtype.Timestamptz `json:"sent_at"`
 }
 
+type QueuedMagnets struct {
+	ID        int64              `json:"id"`
+	Magnet    string             `json:"magnet"`
+	UserID    int64              `json:"user_id"`
+	ChatID    int64              `json:"chat_id"`
+	ThreadID  *int64             `json:"thread_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type ScheduledDeletions struct {
+	ID        int64              `json:"id"`
+	TorrentID string             `json:"torrent_id"`
+	DeleteAt  pgtype.Timestamptz `json:"delete_at"`
+	ChatID    int64              `json:"chat_id"`
+	ThreadID  *int64             `json:"thread_id"`
+	UserID    *int64             `json:"user_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
 type SettingAudits struct {
 	ID        int64              `json:"id"`
 	Key       string             `json:"key"`
@@ -150,6 +169,16 @@ type TorrentActivities struct {
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 	CreatedDate   pgtype.Date        `json:"created_date"`
 	SelectedFiles json.RawMessage    `json:"selected_files"`
+	DisplayName   *string            `json:"display_name"`
+}
+
+type TorrentSubscriptions struct {
+	ID        int64              `json:"id"`
+	UserID    int64              `json:"user_id"`
+	TorrentID string             `json:"torrent_id"`
+	ChatID    int64              `json:"chat_id"`
+	ThreadID  *int64             `json:"thread_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
 type UserChatMemberships struct {
@@ -170,6 +199,13 @@ type UserDailyStats struct {
 	Downloads     int64       `json:"downloads"`
 }
 
+type UserPreferences struct {
+	UserID    int64              `json:"user_id"`
+	Key       string             `json:"key"`
+	Value     string             `json:"value"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
 type Users struct {
 	ID                 int64              `json:"id"`
 	UserID             int64              `json:"user_id"`
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// userDataTables lists every table holding rows that belong to a user, with the column
+// referencing users.id. Most of them cascade on user deletion, but messages only set
+// their user to NULL and keep the text, and databases upgraded from the GORM schema
+// may lack some constraints; deleting explicitly also yields per-table counts.
+// scheduled_deletions is deliberately absent: it holds no personal data and its
+// reference is cleared so scheduled cleanups still run.
+var userDataTables = []struct {
+	table  string
+	column string
+}{
+	{"activity_logs", "user_id"},
+	{"torrent_activities", "user_id"},
+	{"download_activities", "user_id"},
+	{"command_logs", "user_id"},
+	{"kept_torrents", "kept_by_id"},
+	{"kept_torrent_actions", "user_id"},
+	{"setting_audits", "changed_by"},
+	{"messages", "user_id"},
+	{"user_chat_memberships", "user_id"},
+	{"user_daily_stats", "user_id"},
+	{"torrent_subscriptions", "user_id"},
+	{"queued_magnets", "user_id"},
+	{"user_preferences", "user_id"},
+}
+
+// PurgeUser hard-deletes every record of the Telegram user userID across all tables in
+// one transaction, including soft-deleted user rows. It returns the rows removed per
+// table, ending with users, or ErrUserNotFound if no such user exists.
+func (r *UserRepository) PurgeUser(ctx context.Context, userID int64) ([]PurgedRows, error) {
+	var purged []PurgedRows
+	err := withTx(ctx, r.pool, func(tx pgx.Tx) error {
+		var err error
+		purged, err = purgeUser(ctx, tx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return purged, nil
+}
+
+// purgeUser runs the deletions of PurgeUser on db, which should be a transaction
+func purgeUser(ctx context.Context, db DBTX, userID int64) ([]PurgedRows, error) {
+	purged := make([]PurgedRows, 0, len(userDataTables)+1)
+	for _, t := range userDataTables {
+		sql := fmt.Sprintf("DELETE FROM %s WHERE %s IN (SELECT id FROM users WHERE user_id = $1)", t.table, t.column)
+		tag, err := db.Exec(ctx, sql, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to purge %s: %w", t.table, err)
+		}
+		purged = append(purged, PurgedRows{Table: t.table, Rows: tag.RowsAffected()})
+	}
+
+	tag, err := db.Exec(ctx, "DELETE FROM users WHERE user_id = $1", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge users: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrUserNotFound
+	}
+	return append(purged, PurgedRows{Table: "users", Rows: tag.RowsAffected()}), nil
+}
@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// purgeDBTX is a DBTX that records executed statements and reports rows deleted per table.
+type purgeDBTX struct {
+	rows  map[string]int64
+	execs []string
+}
+
+func (p *purgeDBTX) Exec(_ context.Context, sql string, _ ...interface{}) (pgconn.CommandTag, error) {
+	p.execs = append(p.execs, sql)
+	table := strings.Fields(sql)[2]
+	return pgconn.NewCommandTag("DELETE " + strconv.FormatInt(p.rows[table], 10)), nil
+}
+
+func (p *purgeDBTX) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return nil, errors.New("unexpected query")
+}
+
+func (p *purgeDBTX) QueryRow(context.Context, string, ...interface{}) pgx.Row { return nil }
+
+// TestPurgeUser verifies every user table is purged, users last, with per-table counts.
+func TestPurgeUser(t *testing.T) {
+	fake := &purgeDBTX{rows: map[string]int64{"activity_logs": 3, "messages": 2, "users": 1}}
+
+	purged, err := purgeUser(context.Background(), fake, 42)
+	if err != nil {
+		t.Fatalf("purgeUser() error = %v", err)
+	}
+	if len(purged) != len(userDataTables)+1 {
+		t.Fatalf("purged %d tables, want %d", len(purged), len(userDataTables)+1)
+	}
+	if last := purged[len(purged)-1]; last.Table != "users" || last.Rows != 1 {
+		t.Errorf("last purged = %+v, want users row deleted last", last)
+	}
+	counts := make(map[string]int64)
+	for _, p := range purged {
+		counts[p.Table] = p.Rows
+	}
+	if counts["activity_logs"] != 3 || counts["messages"] != 2 {
+		t.Errorf("counts = %v", counts)
+	}
+	for _, sql := range fake.execs[:len(fake.execs)-1] {
+		if !strings.Contains(sql, "IN (SELECT id FROM users WHERE user_id = $1)") {
+			t.Errorf("statement does not select the user's rows by Telegram ID: %s", sql)
+		}
+	}
+}
+
+// TestPurgeUser_NotFound verifies purging an unknown user fails so the transaction rolls back.
+func TestPurgeUser_NotFound(t *testing.T) {
+	if _, err := purgeUser(context.Background(), &purgeDBTX{}, 42); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("purgeUser() error = %v, want ErrUserNotFound", err)
+	}
+}
+
+// TestUserDataTables_CoverSchema verifies every table referencing users is purged, so a
+// new table with user data can't be missed.
+func TestUserDataTables_CoverSchema(t *testing.T) {
+	purged := map[string]bool{"scheduled_deletions": true} // no personal data; reference set to NULL
+	for _, u := range userDataTables {
+		purged[u.table] = true
+	}
+
+	createTable := regexp.MustCompile(`(?s)CREATE TABLE IF NOT EXISTS (\w+) \((.*?)\n\);`)
+	files, err := fs.Glob(migrationsFS, "migrations/*.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		sql, err := fs.ReadFile(migrationsFS, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range createTable.FindAllStringSubmatch(string(sql), -1) {
+			if strings.Contains(m[2], "REFERENCES users(id)") && !purged[m[1]] {
+				t.Errorf("%s: table %s references users but is not in userDataTables", f, m[1])
+			}
+		}
+	}
+}
@@ -9,3 +9,20 @@ INSERT INTO activity_logs (
 
 -- name: CountActivitiesByUser :one
 SELECT COUNT(*) FROM activity_logs WHERE user_id = $1;
+
+-- name: ListActivitiesByTelegramUser :many
+SELECT
+    a.id,
+    a.activity_type,
+    a.command,
+    a.message_thread_id,
+    a.error_message,
+    a.created_at,
+    c.chat_id     AS chat_chat_id,
+    c.title       AS chat_title
+FROM activity_logs a
+JOIN users u ON u.id = a.user_id
+JOIN chats c ON c.id = a.chat_id
+WHERE u.user_id = $1 AND a.activity_type = $2
+ORDER BY a.created_at DESC
+LIMIT $3;
@@ -8,3 +8,19 @@ INSERT INTO command_logs (
     $6, $7,
     $8, $9, $10, $11, $12
 );
+
+-- name: GetCommandLatency :one
+SELECT
+    COUNT(execution_time)::bigint                  AS samples,
+    COALESCE(AVG(execution_time), 0)::float8       AS avg_ms,
+    COALESCE(MIN(execution_time), 0)::bigint       AS min_ms,
+    COALESCE(MAX(execution_time), 0)::bigint       AS max_ms
+FROM command_logs
+WHERE command = $1 AND created_at >= $2 AND execution_time IS NOT NULL;
+
+-- name: GetCommandLatencyAtOffset :one
+SELECT execution_time
+FROM command_logs
+WHERE command = $1 AND created_at >= $2 AND execution_time IS NOT NULL
+ORDER BY execution_time
+LIMIT 1 OFFSET $3;
@@ -11,3 +11,11 @@ INSERT INTO download_activities (
 
 -- name: CountDownloadsByUser :one
 SELECT COUNT(*) FROM download_activities WHERE user_id = $1 AND action = 'unrestrict';
+
+-- name: GetUserHostStats :one
+SELECT
+    COUNT(*) AS downloads,
+    COALESCE(SUM(file_size), 0)::bigint AS total_bytes,
+    MAX(created_at)::timestamptz AS last_download_at
+FROM download_activities
+WHERE user_id = $1 AND action IN ('unrestrict', 'fetch') AND success AND lower(host) = $2;
@@ -0,0 +1,30 @@
+-- name: InsertQueuedMagnet :exec
+INSERT INTO queued_magnets (magnet, user_id, chat_id, thread_id, created_at)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: CountQueuedMagnetsByUser :one
+SELECT COUNT(*) FROM queued_magnets WHERE user_id = $1;
+
+-- name: DeleteQueuedMagnet :execrows
+DELETE FROM queued_magnets WHERE id = $1;
+
+-- name: ListQueuedMagnets :many
+SELECT
+    q.id,
+    q.magnet,
+    q.user_id,
+    q.chat_id,
+    q.thread_id,
+    q.created_at,
+    c.chat_id AS chat_chat_id,
+    u.user_id AS user_user_id
+FROM queued_magnets q
+JOIN chats c ON c.id = q.chat_id
+JOIN users u ON u.id = q.user_id
+ORDER BY q.created_at, q.id;
+
+-- name: ListQueuedMagnetsByUser :many
+SELECT id, magnet, created_at
+FROM queued_magnets
+WHERE user_id = $1
+ORDER BY created_at, id;
@@ -0,0 +1,27 @@
+-- name: UpsertScheduledDeletion :exec
+INSERT INTO scheduled_deletions (torrent_id, delete_at, chat_id, thread_id, user_id, created_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (torrent_id) DO UPDATE SET
+    delete_at  = EXCLUDED.delete_at,
+    chat_id    = EXCLUDED.chat_id,
+    thread_id  = EXCLUDED.thread_id,
+    user_id    = EXCLUDED.user_id,
+    created_at = EXCLUDED.created_at;
+
+-- name: DeleteScheduledDeletion :execrows
+DELETE FROM scheduled_deletions WHERE torrent_id = $1;
+
+-- name: ListDueScheduledDeletions :many
+SELECT
+    s.id,
+    s.torrent_id,
+    s.delete_at,
+    s.chat_id,
+    s.thread_id,
+    s.user_id,
+    s.created_at,
+    c.chat_id AS chat_chat_id
+FROM scheduled_deletions s
+JOIN chats c ON c.id = s.chat_id
+WHERE s.delete_at <= now()
+ORDER BY s.delete_at;
@@ -22,3 +22,34 @@ LIMIT $1;
 
 -- name: CountTorrentAddsByUser :one
 SELECT COUNT(*) FROM torrent_activities WHERE user_id = $1 AND action = 'add';
+
+-- name: GetCachedFlags :many
+SELECT DISTINCT ON (torrent_id) torrent_id, (metadata->>'cached')::boolean AS cached
+FROM torrent_activities
+WHERE torrent_id = ANY(@torrent_ids::text[]) AND action = 'add' AND metadata->>'cached' IS NOT NULL
+ORDER BY torrent_id, created_at DESC;
+
+-- name: InsertTorrentRename :exec
+INSERT INTO torrent_activities (user_id, chat_id, torrent_id, torrent_name, action, display_name, created_at)
+VALUES ($1, $2, $3, $4, 'rename', $5, $6);
+
+-- name: GetDisplayNames :many
+SELECT DISTINCT ON (torrent_id) torrent_id, display_name
+FROM torrent_activities
+WHERE torrent_id = ANY(@torrent_ids::text[]) AND display_name IS NOT NULL
+ORDER BY torrent_id, created_at DESC;
+
+-- name: GetTorrentAdder :one
+SELECT u.user_id, u.username, u.first_name
+FROM torrent_activities ta
+JOIN users u ON u.id = ta.user_id
+WHERE ta.torrent_id = $1 AND ta.action = 'add' AND ta.success AND u.user_id <> 0
+ORDER BY ta.created_at DESC
+LIMIT 1;
+
+-- name: GetTorrentMagnet :one
+SELECT magnet_link
+FROM torrent_activities
+WHERE torrent_id = $1 AND magnet_link IS NOT NULL AND magnet_link <> ''
+ORDER BY created_at DESC
+LIMIT 1;
@@ -0,0 +1,29 @@
+-- name: UpsertTorrentSubscription :exec
+INSERT INTO torrent_subscriptions (user_id, torrent_id, chat_id, thread_id, created_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, torrent_id) DO UPDATE SET
+    chat_id    = EXCLUDED.chat_id,
+    thread_id  = EXCLUDED.thread_id,
+    created_at = EXCLUDED.created_at;
+
+-- name: DeleteTorrentSubscription :execrows
+DELETE FROM torrent_subscriptions WHERE user_id = $1 AND torrent_id = $2;
+
+-- name: DeleteTorrentSubscriptionsByTorrent :exec
+DELETE FROM torrent_subscriptions WHERE torrent_id = $1;
+
+-- name: ListTorrentSubscriptions :many
+SELECT
+    s.id,
+    s.torrent_id,
+    s.thread_id,
+    s.created_at,
+    c.chat_id     AS chat_chat_id,
+    u.id          AS user_id_pk,
+    u.user_id     AS user_user_id,
+    u.username    AS user_username,
+    u.first_name  AS user_first_name
+FROM torrent_subscriptions s
+JOIN chats c ON c.id = s.chat_id
+JOIN users u ON u.id = s.user_id
+ORDER BY s.created_at;
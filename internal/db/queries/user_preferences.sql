@@ -0,0 +1,12 @@
+-- name: GetUserPreference :one
+SELECT value FROM user_preferences WHERE user_id = $1 AND key = $2;
+
+-- name: UpsertUserPreference :exec
+INSERT INTO user_preferences (user_id, key, value, updated_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, key) DO UPDATE SET
+    value      = EXCLUDED.value,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: DeleteUserPreference :execrows
+DELETE FROM user_preferences WHERE user_id = $1 AND key = $2;
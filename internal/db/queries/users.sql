@@ -47,3 +47,15 @@ WHERE user_id = $1 AND deleted_at IS NULL;
 -- name: UnbanUser :exec
 UPDATE users SET is_allowed = true, ban_reason = NULL, banned_at = NULL, updated_at = $2
 WHERE user_id = $1 AND deleted_at IS NULL;
+
+-- name: CountUsers :one
+SELECT COUNT(*) FROM users WHERE deleted_at IS NULL AND user_id <> 0;
+
+-- name: ListUsers :many
+SELECT * FROM users
+WHERE deleted_at IS NULL AND user_id <> 0
+ORDER BY
+    CASE WHEN @sort_by::text = 'total_commands' THEN total_commands END DESC,
+    CASE WHEN @sort_by::text = 'last_seen' THEN last_seen_at END DESC NULLS LAST,
+    id
+LIMIT @row_limit OFFSET @row_offset;
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// LogLevel controls which queries the query logger reports
+type LogLevel int
+
+const (
+	// LogLevelSilent disables query logging
+	LogLevelSilent LogLevel = iota
+	// LogLevelError logs failed queries
+	LogLevelError
+	// LogLevelWarn additionally logs queries slower than the slow threshold
+	LogLevelWarn
+	// LogLevelInfo logs every query
+	LogLevelInfo
+)
+
+// maxLoggedQueryLen bounds how much of a query's SQL is written to the log
+const maxLoggedQueryLen = 300
+
+// ParseLogLevel converts a database.log_level value (silent, error, warn, info) to a LogLevel
+func ParseLogLevel(s string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "silent":
+		return LogLevelSilent, nil
+	case "error":
+		return LogLevelError, nil
+	case "warn", "warning":
+		return LogLevelWarn, nil
+	case "info":
+		return LogLevelInfo, nil
+	default:
+		return LogLevelSilent, fmt.Errorf("invalid database log level %q (want silent, error, warn or info)", s)
+	}
+}
+
+// queryLogger is a pgx.QueryTracer that logs failed and slow queries
+type queryLogger struct {
+	level         LogLevel
+	slowThreshold time.Duration
+}
+
+// queryStartKey is the context key under which TraceQueryStart stores the query being timed
+type queryStartKey struct{}
+
+type queryStart struct {
+	sql   string
+	start time.Time
+}
+
+// TraceQueryStart records the query and its start time in the returned context
+func (l *queryLogger) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{sql: data.SQL, start: time.Now()})
+}
+
+// TraceQueryEnd logs the query according to the configured level
+func (l *queryLogger) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	qs, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(qs.start)
+
+	switch {
+	case data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows):
+		if l.level >= LogLevelError {
+			log.Printf("db: query failed after %s: %v: %s", elapsed, data.Err, compactSQL(qs.sql))
+		}
+	case l.slowThreshold > 0 && elapsed >= l.slowThreshold:
+		if l.level >= LogLevelWarn {
+			log.Printf("db: slow query took %s (threshold %s): %s", elapsed, l.slowThreshold, compactSQL(qs.sql))
+		}
+	default:
+		if l.level >= LogLevelInfo {
+			log.Printf("db: query took %s: %s", elapsed, compactSQL(qs.sql))
+		}
+	}
+}
+
+// compactSQL collapses whitespace in a query and truncates it for logging.
+// sqlc's leading "-- name: X" comment is kept since it identifies the query.
+func compactSQL(sql string) string {
+	compact := strings.Join(strings.Fields(sql), " ")
+	if len(compact) > maxLoggedQueryLen {
+		return compact[:maxLoggedQueryLen] + "..."
+	}
+	return compact
+}
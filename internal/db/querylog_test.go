@@ -0,0 +1,93 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    LogLevel
+		wantErr bool
+	}{
+		{"silent", LogLevelSilent, false},
+		{"error", LogLevelError, false},
+		{"warn", LogLevelWarn, false},
+		{"INFO", LogLevelInfo, false},
+		{"debug", LogLevelSilent, true},
+		{"", LogLevelSilent, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLogLevel(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLogLevel(%q) = (%v, %v), want (%v, err=%v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// traceQuery runs a query of the given duration and outcome through l and returns what was logged.
+func traceQuery(t *testing.T, l *queryLogger, elapsed time.Duration, queryErr error) string {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(prev)
+
+	ctx := context.WithValue(context.Background(), queryStartKey{}, queryStart{
+		sql:   "-- name: GetUserByUserID :one\nSELECT id\nFROM users WHERE user_id = $1",
+		start: time.Now().Add(-elapsed),
+	})
+	l.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: queryErr})
+	return buf.String()
+}
+
+func TestQueryLogger_Levels(t *testing.T) {
+	failure := errors.New("relation does not exist")
+
+	tests := []struct {
+		name    string
+		level   LogLevel
+		elapsed time.Duration
+		err     error
+		want    string // substring expected in the log, or "" for no output
+	}{
+		{"silent hides failures", LogLevelSilent, 0, failure, ""},
+		{"error logs failures", LogLevelError, 0, failure, "query failed"},
+		{"error hides slow queries", LogLevelError, time.Second, nil, ""},
+		{"warn logs slow queries", LogLevelWarn, time.Second, nil, "slow query"},
+		{"warn hides fast queries", LogLevelWarn, 0, nil, ""},
+		{"no rows is not a failure", LogLevelError, 0, pgx.ErrNoRows, ""},
+		{"info logs every query", LogLevelInfo, 0, nil, "query took"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &queryLogger{level: tt.level, slowThreshold: 200 * time.Millisecond}
+			out := traceQuery(t, l, tt.elapsed, tt.err)
+			if tt.want == "" {
+				if out != "" {
+					t.Errorf("unexpected log output: %q", out)
+				}
+				return
+			}
+			if !strings.Contains(out, tt.want) || !strings.Contains(out, "-- name: GetUserByUserID :one SELECT id FROM users") {
+				t.Errorf("log output %q, want %q with the compacted query", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompactSQL_Truncates(t *testing.T) {
+	got := compactSQL(strings.Repeat("SELECT 1 ", 100))
+	if len(got) != maxLoggedQueryLen+len("...") || !strings.HasSuffix(got, "...") {
+		t.Errorf("compactSQL() length = %d, want %d with ellipsis", len(got), maxLoggedQueryLen+3)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -16,6 +18,9 @@ import (
 var (
 	ErrUserNotFound   = errors.New("user not found")
 	ErrTorrentNotKept = errors.New("torrent is not kept or you don't have permission to unkeep it")
+	ErrNotSubscribed  = errors.New("you are not subscribed to this torrent")
+	ErrNotScheduled   = errors.New("no deletion is scheduled for this torrent")
+	ErrQueueFull      = errors.New("your queue is full")
 )
 
 // toPgtypeTimestamptz converts t to a pgtype.Timestamptz with the time normalized to UTC and Valid set to true.
@@ -49,14 +54,16 @@ func int64Ptr(n int64) *int64 {
 // It converts nullable string fields using derefStr and copies nullable timestamp fields into the corresponding time.Time fields only when they are valid, returning a pointer to the constructed User.
 func toUserPublic(u Users) *User {
 	pub := &User{
-		ID:            u.ID,
-		UserID:        u.UserID,
-		Username:      derefStr(u.Username),
-		FirstName:     derefStr(u.FirstName),
-		LastName:      derefStr(u.LastName),
-		IsSuperAdmin:  u.IsSuperAdmin,
-		IsAllowed:     u.IsAllowed,
-		TotalCommands: u.TotalCommands,
+		ID:             u.ID,
+		UserID:         u.UserID,
+		Username:       derefStr(u.Username),
+		FirstName:      derefStr(u.FirstName),
+		LastName:       derefStr(u.LastName),
+		IsSuperAdmin:   u.IsSuperAdmin,
+		IsAllowed:      u.IsAllowed,
+		TotalCommands:  u.TotalCommands,
+		TotalTorrents:  u.TotalTorrentsAdded,
+		TotalDownloads: u.TotalDownloads,
 	}
 	if u.FirstSeenAt.Valid {
 		pub.FirstSeenAt = u.FirstSeenAt.Time
@@ -128,6 +135,43 @@ func toKeptTorrentPublic(row ListKeptTorrentsRow) KeptTorrent {
 	return kt
 }
 
+// toTorrentSubscriptionPublic converts a ListTorrentSubscriptionsRow to the public TorrentSubscription type.
+func toTorrentSubscriptionPublic(row ListTorrentSubscriptionsRow) TorrentSubscription {
+	sub := TorrentSubscription{
+		ID:        row.ID,
+		TorrentID: row.TorrentID,
+		ChatID:    row.ChatChatID,
+		ThreadID:  derefInt64(row.ThreadID),
+		UserPK:    row.UserIDPk,
+		UserID:    row.UserUserID,
+		Username:  derefStr(row.UserUsername),
+	}
+	if sub.Username == "" {
+		sub.Username = derefStr(row.UserFirstName)
+	}
+	if row.CreatedAt.Valid {
+		sub.CreatedAt = row.CreatedAt.Time
+	}
+	return sub
+}
+
+// toActivityEntryPublic converts a ListActivitiesByTelegramUserRow to the public ActivityEntry type.
+func toActivityEntryPublic(row ListActivitiesByTelegramUserRow) ActivityEntry {
+	entry := ActivityEntry{
+		ID:           row.ID,
+		ActivityType: row.ActivityType,
+		Command:      derefStr(row.Command),
+		ErrorMessage: derefStr(row.ErrorMessage),
+		ChatID:       row.ChatChatID,
+		ChatTitle:    derefStr(row.ChatTitle),
+		ThreadID:     derefInt64(row.MessageThreadID),
+	}
+	if row.CreatedAt.Valid {
+		entry.CreatedAt = row.CreatedAt.Time
+	}
+	return entry
+}
+
 // toFloat64FromNumeric converts a pgtype.Numeric to a float64 and returns 0 when the numeric is not valid.
 func toFloat64FromNumeric(n pgtype.Numeric) float64 {
 	if !n.Valid {
@@ -146,6 +190,50 @@ func toNumericFromFloat64(f float64) (pgtype.Numeric, error) {
 	return n, nil
 }
 
+// toInt64FromAny converts an untyped aggregate (e.g. SUM over bigint, which Postgres
+// returns as numeric) to an int64. Unknown or NULL values yield 0.
+func toInt64FromAny(v interface{}) int64 {
+	switch n := v.(type) {
+	case int64:
+		return n
+	case int32:
+		return int64(n)
+	case int:
+		return int64(n)
+	case float64:
+		return int64(n)
+	case pgtype.Numeric:
+		if !n.Valid {
+			return 0
+		}
+		i, err := n.Int64Value()
+		if err != nil {
+			return 0
+		}
+		return i.Int64
+	default:
+		return 0
+	}
+}
+
+// buildGlobalStats assembles the global stats map from the summary row and the
+// most-used command (if any commands have been logged).
+func buildGlobalStats(summary GetGlobalSummaryStatsRow, top []GetCommandPopularityAllTimeRow) map[string]interface{} {
+	stats := map[string]interface{}{
+		"total_users":      summary.TotalUsers,
+		"total_commands":   toInt64FromAny(summary.TotalCommands),
+		"total_torrents":   toInt64FromAny(summary.TotalTorrents),
+		"total_downloads":  toInt64FromAny(summary.TotalDownloads),
+		"top_command":      "",
+		"top_command_uses": int64(0),
+	}
+	if len(top) > 0 {
+		stats["top_command"] = top[0].Command
+		stats["top_command_uses"] = top[0].Total
+	}
+	return stats
+}
+
 // ─────────────────────────────────────────────────────────────
 // UserRepository
 // ─────────────────────────────────────────────────────────────
@@ -184,6 +272,45 @@ func (r *UserRepository) GetOrCreateUser(ctx context.Context, userID int64, user
 	return toUserPublic(u), nil
 }
 
+// UserSortFields are the orders ListUsers accepts: the most commands or the most
+// recently seen first
+var UserSortFields = []string{"total_commands", "last_seen"}
+
+// ListUsers returns a page of users with the total number of users, both from one
+// snapshot. The system user is left out. sortBy must be one of UserSortFields or empty,
+// which lists users in the order they were first recorded; it is passed to the query as
+// a parameter, never spliced into the SQL.
+func (r *UserRepository) ListUsers(ctx context.Context, limit, offset int, sortBy string) ([]User, int64, error) {
+	var users []User
+	var total int64
+	err := withReadTx(ctx, r.pool, func(tx pgx.Tx) error {
+		var err error
+		users, total, err = listUsersPage(ctx, New(tx), limit, offset, sortBy)
+		return err
+	})
+	return users, total, err
+}
+
+// listUsersPage implements ListUsers on q
+func listUsersPage(ctx context.Context, q *Queries, limit, offset int, sortBy string) ([]User, int64, error) {
+	if sortBy != "" && !slices.Contains(UserSortFields, sortBy) {
+		return nil, 0, fmt.Errorf("invalid user sort field %q", sortBy)
+	}
+	total, err := q.CountUsers(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows, err := q.ListUsers(ctx, ListUsersParams{SortBy: sortBy, RowLimit: int32(limit), RowOffset: int32(offset)})
+	if err != nil {
+		return nil, 0, err
+	}
+	users := make([]User, len(rows))
+	for i, u := range rows {
+		users[i] = *toUserPublic(u)
+	}
+	return users, total, nil
+}
+
 // ─────────────────────────────────────────────────────────────
 // ChatRepository
 // ─────────────────────────────────────────────────────────────
@@ -225,6 +352,7 @@ func (r *ChatRepository) GetOrCreateChat(ctx context.Context, chatID int64, titl
 type ActivityRepository struct {
 	pool    *pgxpool.Pool
 	queries *Queries
+	logging ActivityLogging
 }
 
 // NewActivityRepository returns an ActivityRepository that uses the provided pgxpool.Pool for database access.
@@ -232,8 +360,16 @@ func NewActivityRepository(pool *pgxpool.Pool) *ActivityRepository {
 	return &ActivityRepository{pool: pool, queries: New(pool)}
 }
 
-// LogActivity logs a general activity.
+// SetActivityLogging sets which activities LogActivity persists.
+func (r *ActivityRepository) SetActivityLogging(l ActivityLogging) {
+	r.logging = l
+}
+
+// LogActivity logs a general activity, unless the activity logging mode skips it.
 func (r *ActivityRepository) LogActivity(ctx context.Context, requestID string, userID int64, chatID int64, username string, activityType ActivityType, command string, messageID int64, messageThreadID int, success bool, errorMsg string, metadata map[string]interface{}) error {
+	if !r.logging.Persist(success) {
+		return nil
+	}
 	if metadata == nil {
 		metadata = make(map[string]interface{})
 	}
@@ -263,6 +399,27 @@ func (r *ActivityRepository) LogActivity(ctx context.Context, requestID string,
 	})
 }
 
+// ListUserActivities returns the most recent activities of the given type for a Telegram user, newest first.
+func (r *ActivityRepository) ListUserActivities(ctx context.Context, telegramUserID int64, activityType ActivityType, limit int) ([]ActivityEntry, error) {
+	lim := int32(limit)
+	if lim <= 0 {
+		lim = 10
+	}
+	rows, err := r.queries.ListActivitiesByTelegramUser(ctx, ListActivitiesByTelegramUserParams{
+		UserID:       telegramUserID,
+		ActivityType: string(activityType),
+		Limit:        lim,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]ActivityEntry, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toActivityEntryPublic(row))
+	}
+	return result, nil
+}
+
 // ─────────────────────────────────────────────────────────────
 // TorrentRepository
 // ─────────────────────────────────────────────────────────────
@@ -271,6 +428,7 @@ func (r *ActivityRepository) LogActivity(ctx context.Context, requestID string,
 type TorrentRepository struct {
 	pool    *pgxpool.Pool
 	queries *Queries
+	logging ActivityLogging
 }
 
 // NewTorrentRepository creates a TorrentRepository backed by the given pgxpool.Pool.
@@ -278,8 +436,14 @@ func NewTorrentRepository(pool *pgxpool.Pool) *TorrentRepository {
 	return &TorrentRepository{pool: pool, queries: New(pool)}
 }
 
+// SetActivityLogging sets which torrent activities LogTorrentActivity persists.
+func (r *TorrentRepository) SetActivityLogging(l ActivityLogging) {
+	r.logging = l
+}
+
 // LogTorrentActivity logs a torrent-specific activity.
-// When action=="add" and success==true, also increments daily and user torrent counters.
+// When action=="add" and success==true, the row is stored in every activity logging mode
+// and, unless the mode is off, the daily and user torrent counters are incremented.
 func (r *TorrentRepository) LogTorrentActivity(ctx context.Context, requestID string, userID int64, chatID int64, torrentID, torrentHash, torrentName, magnetLink, action, status string, fileSize int64, progress float64, success bool, errorMsg string, metadata map[string]interface{}) error {
 	if metadata == nil {
 		metadata = make(map[string]interface{})
@@ -293,6 +457,10 @@ func (r *TorrentRepository) LogTorrentActivity(ctx context.Context, requestID st
 	if err != nil {
 		return fmt.Errorf("LogTorrentActivity: %w", err)
 	}
+	countAdd := action == "add" && success && r.logging.Count()
+	if !r.logging.Persist(success) && !keepTorrentActivity(action, success) {
+		return nil
+	}
 	return withTx(ctx, r.pool, func(tx pgx.Tx) error {
 		q := New(tx)
 		if err := q.InsertTorrentActivity(ctx, InsertTorrentActivityParams{
@@ -315,19 +483,38 @@ func (r *TorrentRepository) LogTorrentActivity(ctx context.Context, requestID st
 		}); err != nil {
 			return err
 		}
-		if action == "add" && success {
-			if err := q.IncrementUserTorrents(ctx, userID); err != nil {
-				return err
-			}
-			if err := q.IncrementDailyTorrent(ctx, today); err != nil {
-				return err
-			}
-			return q.IncrementUserDailyTorrent(ctx, IncrementUserDailyTorrentParams{StatDate: today, UserID: userID})
+		if countAdd {
+			return incrementTorrentCounters(ctx, q, userID, today)
 		}
 		return nil
 	})
 }
 
+// incrementTorrentCounters counts a successful torrent add for the user and the day.
+func incrementTorrentCounters(ctx context.Context, q *Queries, userID int64, today pgtype.Date) error {
+	if err := q.IncrementUserTorrents(ctx, userID); err != nil {
+		return err
+	}
+	if err := q.IncrementDailyTorrent(ctx, today); err != nil {
+		return err
+	}
+	return q.IncrementUserDailyTorrent(ctx, IncrementUserDailyTorrentParams{StatDate: today, UserID: userID})
+}
+
+// GetCachedFlags returns, for those of torrentIDs whose add recorded it, whether the
+// torrent was cached on Real-Debrid when it was added.
+func (r *TorrentRepository) GetCachedFlags(ctx context.Context, torrentIDs []string) (map[string]bool, error) {
+	rows, err := r.queries.GetCachedFlags(ctx, torrentIDs)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		result[row.TorrentID] = row.Cached
+	}
+	return result, nil
+}
+
 // GetTorrentActivities retrieves torrent activities.  If userID == 0, all activities are returned.
 func (r *TorrentRepository) GetTorrentActivities(ctx context.Context, userID int64, limit int) ([]TorrentActivity, error) {
 	lim := int32(limit)
@@ -369,6 +556,7 @@ func (r *TorrentRepository) GetTorrentActivities(ctx context.Context, userID int
 			ErrorMessage:  derefStr(row.ErrorMessage),
 			Metadata:      string(row.Metadata),
 			SelectedFiles: string(row.SelectedFiles),
+			DisplayName:   derefStr(row.DisplayName),
 		}
 		if row.CreatedAt.Valid {
 			ta.CreatedAt = row.CreatedAt.Time
@@ -378,6 +566,59 @@ func (r *TorrentRepository) GetTorrentActivities(ctx context.Context, userID int
 	return result, nil
 }
 
+// SetDisplayName records displayName as the alias of torrentID, replacing any earlier one.
+// The rename is stored as an activity regardless of the activity logging mode, since
+// it is data the bot shows rather than a log entry.
+func (r *TorrentRepository) SetDisplayName(ctx context.Context, userID, chatID int64, torrentID, torrentName, displayName string) error {
+	return r.queries.InsertTorrentRename(ctx, InsertTorrentRenameParams{
+		UserID:      userID,
+		ChatID:      chatID,
+		TorrentID:   torrentID,
+		TorrentName: strPtr(torrentName),
+		DisplayName: &displayName,
+		CreatedAt:   toPgtypeTimestamptz(time.Now().UTC()),
+	})
+}
+
+// GetDisplayNames returns the latest alias of each of torrentIDs that has one.
+func (r *TorrentRepository) GetDisplayNames(ctx context.Context, torrentIDs []string) (map[string]string, error) {
+	rows, err := r.queries.GetDisplayNames(ctx, torrentIDs)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(rows))
+	for _, row := range rows {
+		result[row.TorrentID] = derefStr(row.DisplayName)
+	}
+	return result, nil
+}
+
+// GetTorrentAdder returns the user who last added torrentID successfully, or nil if
+// that was not recorded. Only UserID, Username and FirstName are set.
+func (r *TorrentRepository) GetTorrentAdder(ctx context.Context, torrentID string) (*User, error) {
+	row, err := r.queries.GetTorrentAdder(ctx, torrentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &User{UserID: row.UserID, Username: derefStr(row.Username), FirstName: derefStr(row.FirstName)}, nil
+}
+
+// GetTorrentMagnet returns the magnet link torrentID was last added from, or "" if
+// none was recorded
+func (r *TorrentRepository) GetTorrentMagnet(ctx context.Context, torrentID string) (string, error) {
+	magnet, err := r.queries.GetTorrentMagnet(ctx, torrentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	return derefStr(magnet), nil
+}
+
 // derefInt64 returns 0 when n is nil and otherwise the value pointed to by n.
 func derefInt64(n *int64) int64 {
 	if n == nil {
@@ -394,6 +635,7 @@ func derefInt64(n *int64) int64 {
 type DownloadRepository struct {
 	pool    *pgxpool.Pool
 	queries *Queries
+	logging ActivityLogging
 }
 
 // NewDownloadRepository constructs a DownloadRepository backed by the provided pgxpool.Pool and initialized SQLC Queries.
@@ -401,8 +643,15 @@ func NewDownloadRepository(pool *pgxpool.Pool) *DownloadRepository {
 	return &DownloadRepository{pool: pool, queries: New(pool)}
 }
 
+// SetActivityLogging sets which download activities LogDownloadActivity persists.
+func (r *DownloadRepository) SetActivityLogging(l ActivityLogging) {
+	r.logging = l
+}
+
 // LogDownloadActivity logs a download/unrestrict activity.
-// When success==true, also increments daily and user download counters.
+// When success==true, also increments daily and user download counters unless the
+// activity logging mode is off, even if the mode skips the activity row itself.
+// Successful unrestricts and fetches are stored in every mode.
 func (r *DownloadRepository) LogDownloadActivity(ctx context.Context, requestID string, userID int64, chatID int64, downloadID, originalLink, fileName, host, action string, fileSize int64, success bool, errorMsg string, metadata map[string]interface{}, torrentActivityID *int64) error {
 	if metadata == nil {
 		metadata = make(map[string]interface{})
@@ -413,8 +662,16 @@ func (r *DownloadRepository) LogDownloadActivity(ctx context.Context, requestID
 	}
 	raw := json.RawMessage(metaJSON)
 	today := toPgtypeDate(time.Now())
+	persist := r.logging.Persist(success) || keepDownloadActivity(action, success)
+	count := success && r.logging.Count()
+	if !persist && !count {
+		return nil
+	}
 	return withTx(ctx, r.pool, func(tx pgx.Tx) error {
 		q := New(tx)
+		if !persist {
+			return incrementDownloadCounters(ctx, q, userID, today)
+		}
 		if err := q.InsertDownloadActivity(ctx, InsertDownloadActivityParams{
 			RequestID:         strPtr(requestID),
 			UserID:            userID,
@@ -433,19 +690,39 @@ func (r *DownloadRepository) LogDownloadActivity(ctx context.Context, requestID
 		}); err != nil {
 			return err
 		}
-		if success {
-			if err := q.IncrementUserDownloads(ctx, userID); err != nil {
-				return err
-			}
-			if err := q.IncrementDailyDownload(ctx, today); err != nil {
-				return err
-			}
-			return q.IncrementUserDailyDownload(ctx, IncrementUserDailyDownloadParams{StatDate: today, UserID: userID})
+		if count {
+			return incrementDownloadCounters(ctx, q, userID, today)
 		}
 		return nil
 	})
 }
 
+// GetUserHostStats sums up the successful unrestricts of the user (internal users.id)
+// from host, matched case-insensitively.
+func (r *DownloadRepository) GetUserHostStats(ctx context.Context, userID int64, host string) (HostStats, error) {
+	host = strings.ToLower(strings.TrimSpace(host))
+	row, err := r.queries.GetUserHostStats(ctx, GetUserHostStatsParams{UserID: userID, Host: &host})
+	if err != nil {
+		return HostStats{}, err
+	}
+	stats := HostStats{Host: host, Downloads: row.Downloads, TotalBytes: row.TotalBytes}
+	if row.LastDownloadAt.Valid {
+		stats.LastDownloadAt = row.LastDownloadAt.Time
+	}
+	return stats, nil
+}
+
+// incrementDownloadCounters counts a successful download for the user and the day.
+func incrementDownloadCounters(ctx context.Context, q *Queries, userID int64, today pgtype.Date) error {
+	if err := q.IncrementUserDownloads(ctx, userID); err != nil {
+		return err
+	}
+	if err := q.IncrementDailyDownload(ctx, today); err != nil {
+		return err
+	}
+	return q.IncrementUserDailyDownload(ctx, IncrementUserDailyDownloadParams{StatDate: today, UserID: userID})
+}
+
 // ─────────────────────────────────────────────────────────────
 // CommandRepository
 // ─────────────────────────────────────────────────────────────
@@ -549,6 +826,29 @@ func (r *CommandRepository) GetUserStats(ctx context.Context, telegramUserID int
 	return stats, err
 }
 
+// GetGlobalStats returns usage totals across all users: users, commands, torrents
+// added, downloads and the most-used command. Both queries share one REPEATABLE READ
+// snapshot.
+func (r *CommandRepository) GetGlobalStats(ctx context.Context) (map[string]interface{}, error) {
+	var stats map[string]interface{}
+	err := withReadTx(ctx, r.pool, func(tx pgx.Tx) error {
+		q := New(tx)
+
+		summary, err := q.GetGlobalSummaryStats(ctx)
+		if err != nil {
+			return err
+		}
+		top, err := q.GetCommandPopularityAllTime(ctx, 1)
+		if err != nil {
+			return err
+		}
+
+		stats = buildGlobalStats(summary, top)
+		return nil
+	})
+	return stats, err
+}
+
 // ─────────────────────────────────────────────────────────────
 // SettingRepository
 // ─────────────────────────────────────────────────────────────
@@ -815,6 +1115,279 @@ func (r *KeptTorrentRepository) CountKeptByUser(ctx context.Context, userID int6
 	return r.queries.CountKeptByUser(ctx, u.ID)
 }
 
+// ─────────────────────────────────────────────────────────────
+// SubscriptionRepository
+// ─────────────────────────────────────────────────────────────
+
+// SubscriptionRepository handles per-torrent completion subscriptions.
+type SubscriptionRepository struct {
+	pool    *pgxpool.Pool
+	queries *Queries
+}
+
+// NewSubscriptionRepository creates a SubscriptionRepository backed by the provided pgxpool.Pool.
+func NewSubscriptionRepository(pool *pgxpool.Pool) *SubscriptionRepository {
+	return &SubscriptionRepository{pool: pool, queries: New(pool)}
+}
+
+// Subscribe registers userPK for a completion notification on torrentID in the given
+// chat (internal chats.id) and thread. Re-subscribing moves the notification target.
+func (r *SubscriptionRepository) Subscribe(ctx context.Context, userPK, chatPK int64, torrentID string, threadID int) error {
+	return r.queries.UpsertTorrentSubscription(ctx, UpsertTorrentSubscriptionParams{
+		UserID:    userPK,
+		TorrentID: torrentID,
+		ChatID:    chatPK,
+		ThreadID:  int64Ptr(int64(threadID)),
+		CreatedAt: toPgtypeTimestamptz(time.Now()),
+	})
+}
+
+// Unsubscribe removes userPK's subscription to torrentID, returning ErrNotSubscribed if none existed.
+func (r *SubscriptionRepository) Unsubscribe(ctx context.Context, userPK int64, torrentID string) error {
+	n, err := r.queries.DeleteTorrentSubscription(ctx, DeleteTorrentSubscriptionParams{
+		UserID:    userPK,
+		TorrentID: torrentID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotSubscribed
+	}
+	return nil
+}
+
+// ListSubscriptions returns all active subscriptions, oldest first.
+func (r *SubscriptionRepository) ListSubscriptions(ctx context.Context) ([]TorrentSubscription, error) {
+	rows, err := r.queries.ListTorrentSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]TorrentSubscription, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toTorrentSubscriptionPublic(row))
+	}
+	return result, nil
+}
+
+// ClearTorrent removes every subscription to torrentID once it has been notified.
+func (r *SubscriptionRepository) ClearTorrent(ctx context.Context, torrentID string) error {
+	return r.queries.DeleteTorrentSubscriptionsByTorrent(ctx, torrentID)
+}
+
+// ─────────────────────────────────────────────────────────────
+// ScheduledDeletionRepository
+// ─────────────────────────────────────────────────────────────
+
+// ScheduledDeletionRepository handles torrent deletions scheduled for a later time.
+type ScheduledDeletionRepository struct {
+	pool    *pgxpool.Pool
+	queries *Queries
+}
+
+// NewScheduledDeletionRepository creates a ScheduledDeletionRepository backed by the provided pgxpool.Pool.
+func NewScheduledDeletionRepository(pool *pgxpool.Pool) *ScheduledDeletionRepository {
+	return &ScheduledDeletionRepository{pool: pool, queries: New(pool)}
+}
+
+// Schedule records that torrentID is to be deleted at deleteAt, reporting to the given
+// chat (internal chats.id) and thread. Rescheduling a torrent replaces its schedule.
+func (r *ScheduledDeletionRepository) Schedule(ctx context.Context, torrentID string, deleteAt time.Time, chatPK int64, threadID int, userPK int64) error {
+	return r.queries.UpsertScheduledDeletion(ctx, UpsertScheduledDeletionParams{
+		TorrentID: torrentID,
+		DeleteAt:  toPgtypeTimestamptz(deleteAt),
+		ChatID:    chatPK,
+		ThreadID:  int64Ptr(int64(threadID)),
+		UserID:    int64Ptr(userPK),
+		CreatedAt: toPgtypeTimestamptz(time.Now()),
+	})
+}
+
+// Cancel removes the scheduled deletion of torrentID, returning ErrNotScheduled if none existed.
+func (r *ScheduledDeletionRepository) Cancel(ctx context.Context, torrentID string) error {
+	n, err := r.queries.DeleteScheduledDeletion(ctx, torrentID)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotScheduled
+	}
+	return nil
+}
+
+// ListDue returns the scheduled deletions whose time has come, soonest first.
+func (r *ScheduledDeletionRepository) ListDue(ctx context.Context) ([]ScheduledDeletion, error) {
+	rows, err := r.queries.ListDueScheduledDeletions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]ScheduledDeletion, 0, len(rows))
+	for _, row := range rows {
+		s := ScheduledDeletion{
+			ID:        row.ID,
+			TorrentID: row.TorrentID,
+			ChatID:    row.ChatChatID,
+			ChatPK:    row.ChatID,
+			ThreadID:  derefInt64(row.ThreadID),
+			UserPK:    derefInt64(row.UserID),
+		}
+		if row.DeleteAt.Valid {
+			s.DeleteAt = row.DeleteAt.Time
+		}
+		if row.CreatedAt.Valid {
+			s.CreatedAt = row.CreatedAt.Time
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// Remove drops the schedule of torrentID once it has been handled; a missing schedule is not an error.
+func (r *ScheduledDeletionRepository) Remove(ctx context.Context, torrentID string) error {
+	_, err := r.queries.DeleteScheduledDeletion(ctx, torrentID)
+	return err
+}
+
+// ─────────────────────────────────────────────────────────────
+// QueueRepository
+// ─────────────────────────────────────────────────────────────
+
+// QueueRepository handles magnets queued until an active torrent slot frees up.
+type QueueRepository struct {
+	pool    *pgxpool.Pool
+	queries *Queries
+}
+
+// NewQueueRepository creates a QueueRepository backed by the provided pgxpool.Pool.
+func NewQueueRepository(pool *pgxpool.Pool) *QueueRepository {
+	return &QueueRepository{pool: pool, queries: New(pool)}
+}
+
+// Enqueue queues magnet for the user (internal users.id), reporting to the given chat
+// (internal chats.id) and thread once it starts. It returns the user's queue length
+// including the new magnet, or ErrQueueFull if they already have maxPerUser queued
+// (0 = unlimited).
+func (r *QueueRepository) Enqueue(ctx context.Context, magnet string, userPK, chatPK int64, threadID int, maxPerUser int) (int64, error) {
+	var queued int64
+	err := withTx(ctx, r.pool, func(tx pgx.Tx) error {
+		q := New(tx)
+		// Serialise enqueues of the same user so two adds can't both pass the cap
+		if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", userPK); err != nil {
+			return err
+		}
+		n, err := q.CountQueuedMagnetsByUser(ctx, userPK)
+		if err != nil {
+			return err
+		}
+		if maxPerUser > 0 && n >= int64(maxPerUser) {
+			return ErrQueueFull
+		}
+		if err := q.InsertQueuedMagnet(ctx, InsertQueuedMagnetParams{
+			Magnet:    magnet,
+			UserID:    userPK,
+			ChatID:    chatPK,
+			ThreadID:  int64Ptr(int64(threadID)),
+			CreatedAt: toPgtypeTimestamptz(time.Now()),
+		}); err != nil {
+			return err
+		}
+		queued = n + 1
+		return nil
+	})
+	return queued, err
+}
+
+// List returns every queued magnet, oldest first.
+func (r *QueueRepository) List(ctx context.Context) ([]QueuedMagnet, error) {
+	rows, err := r.queries.ListQueuedMagnets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]QueuedMagnet, 0, len(rows))
+	for _, row := range rows {
+		m := QueuedMagnet{
+			ID:       row.ID,
+			Magnet:   row.Magnet,
+			ChatPK:   row.ChatID,
+			ChatID:   row.ChatChatID,
+			ThreadID: derefInt64(row.ThreadID),
+			UserPK:   row.UserID,
+			UserID:   row.UserUserID,
+		}
+		if row.CreatedAt.Valid {
+			m.CreatedAt = row.CreatedAt.Time
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
+
+// ListByUser returns the magnets queued by the user (internal users.id), oldest first.
+func (r *QueueRepository) ListByUser(ctx context.Context, userPK int64) ([]QueuedMagnet, error) {
+	rows, err := r.queries.ListQueuedMagnetsByUser(ctx, userPK)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]QueuedMagnet, 0, len(rows))
+	for _, row := range rows {
+		m := QueuedMagnet{ID: row.ID, Magnet: row.Magnet, UserPK: userPK}
+		if row.CreatedAt.Valid {
+			m.CreatedAt = row.CreatedAt.Time
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
+
+// Remove drops a queued magnet once it has been released; a missing entry is not an error.
+func (r *QueueRepository) Remove(ctx context.Context, id int64) error {
+	_, err := r.queries.DeleteQueuedMagnet(ctx, id)
+	return err
+}
+
+// ─────────────────────────────────────────────────────────────
+// PreferenceRepository
+// ─────────────────────────────────────────────────────────────
+
+// PreferenceRepository handles per-user preferences set with /setpref.
+type PreferenceRepository struct {
+	pool    *pgxpool.Pool
+	queries *Queries
+}
+
+// NewPreferenceRepository creates a PreferenceRepository backed by the provided pgxpool.Pool.
+func NewPreferenceRepository(pool *pgxpool.Pool) *PreferenceRepository {
+	return &PreferenceRepository{pool: pool, queries: New(pool)}
+}
+
+// Get returns the user's (internal users.id) value for key, or "" if they haven't set one.
+func (r *PreferenceRepository) Get(ctx context.Context, userPK int64, key string) (string, error) {
+	value, err := r.queries.GetUserPreference(ctx, GetUserPreferenceParams{UserID: userPK, Key: key})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	return value, nil
+}
+
+// Set creates or updates the user's (internal users.id) value for key.
+func (r *PreferenceRepository) Set(ctx context.Context, userPK int64, key, value string) error {
+	return r.queries.UpsertUserPreference(ctx, UpsertUserPreferenceParams{
+		UserID:    userPK,
+		Key:       key,
+		Value:     value,
+		UpdatedAt: toPgtypeTimestamptz(time.Now().UTC()),
+	})
+}
+
+// Clear removes the user's (internal users.id) value for key; an unset key is not an error.
+func (r *PreferenceRepository) Clear(ctx context.Context, userPK int64, key string) error {
+	_, err := r.queries.DeleteUserPreference(ctx, DeleteUserPreferenceParams{UserID: userPK, Key: key})
+	return err
+}
+
 // ─────────────────────────────────────────────────────────────
 // transaction helper
 // withTx begins a transaction on the provided pool, executes fn with the started transaction, rolls back if fn returns an error, and commits on success.
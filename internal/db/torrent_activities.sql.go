@@ -24,7 +24,7 @@ func (q *Queries) CountTorrentAddsByUser(ctx context.Context, userID int64) (int
 }
 
 const getAllTorrentActivities = `-- name: GetAllTorrentActivities :many
-SELECT id, request_id, user_id, chat_id, torrent_id, torrent_hash, torrent_name, magnet_link, action, status, file_size, progress, success, error_message, metadata, created_at, created_date, selected_files FROM torrent_activities
+SELECT id, request_id, user_id, chat_id, torrent_id, torrent_hash, torrent_name, magnet_link, action, status, file_size, progress, success, error_message, metadata, created_at, created_date, selected_files, display_name FROM torrent_activities
 ORDER BY created_at DESC
 LIMIT $1
 `
@@ -57,6 +57,7 @@ func (q *Queries) GetAllTorrentActivities(ctx context.Context, limit int32) ([]T
 			&i.CreatedAt,
 			&i.CreatedDate,
 			&i.SelectedFiles,
+			&i.DisplayName,
 		); err != nil {
 			return nil, err
 		}
@@ -68,8 +69,72 @@ func (q *Queries) GetAllTorrentActivities(ctx context.Context, limit int32) ([]T
 	return items, nil
 }
 
+const getCachedFlags = `-- name: GetCachedFlags :many
+SELECT DISTINCT ON (torrent_id) torrent_id, (metadata->>'cached')::boolean AS cached
+FROM torrent_activities
+WHERE torrent_id = ANY($1::text[]) AND action = 'add' AND metadata->>'cached' IS NOT NULL
+ORDER BY torrent_id, created_at DESC
+`
+
+type GetCachedFlagsRow struct {
+	TorrentID string `json:"torrent_id"`
+	Cached    bool   `json:"cached"`
+}
+
+func (q *Queries) GetCachedFlags(ctx context.Context, torrentIds []string) ([]GetCachedFlagsRow, error) {
+	rows, err := q.db.Query(ctx, getCachedFlags, torrentIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCachedFlagsRow
+	for rows.Next() {
+		var i GetCachedFlagsRow
+		if err := rows.Scan(&i.TorrentID, &i.Cached); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDisplayNames = `-- name: GetDisplayNames :many
+SELECT DISTINCT ON (torrent_id) torrent_id, display_name
+FROM torrent_activities
+WHERE torrent_id = ANY($1::text[]) AND display_name IS NOT NULL
+ORDER BY torrent_id, created_at DESC
+`
+
+type GetDisplayNamesRow struct {
+	TorrentID   string  `json:"torrent_id"`
+	DisplayName *string `json:"display_name"`
+}
+
+func (q *Queries) GetDisplayNames(ctx context.Context, torrentIds []string) ([]GetDisplayNamesRow, error) {
+	rows, err := q.db.Query(ctx, getDisplayNames, torrentIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDisplayNamesRow
+	for rows.Next() {
+		var i GetDisplayNamesRow
+		if err := rows.Scan(&i.TorrentID, &i.DisplayName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTorrentActivities = `-- name: GetTorrentActivities :many
-SELECT id, request_id, user_id, chat_id, torrent_id, torrent_hash, torrent_name, magnet_link, action, status, file_size, progress, success, error_message, metadata, created_at, created_date, selected_files FROM torrent_activities
+SELECT id, request_id, user_id, chat_id, torrent_id, torrent_hash, torrent_name, magnet_link, action, status, file_size, progress, success, error_message, metadata, created_at, created_date, selected_files, display_name FROM torrent_activities
 WHERE user_id = $1
 ORDER BY created_at DESC
 LIMIT $2
@@ -108,6 +173,7 @@ func (q *Queries) GetTorrentActivities(ctx context.Context, arg GetTorrentActivi
 			&i.CreatedAt,
 			&i.CreatedDate,
 			&i.SelectedFiles,
+			&i.DisplayName,
 		); err != nil {
 			return nil, err
 		}
@@ -119,6 +185,43 @@ func (q *Queries) GetTorrentActivities(ctx context.Context, arg GetTorrentActivi
 	return items, nil
 }
 
+const getTorrentAdder = `-- name: GetTorrentAdder :one
+SELECT u.user_id, u.username, u.first_name
+FROM torrent_activities ta
+JOIN users u ON u.id = ta.user_id
+WHERE ta.torrent_id = $1 AND ta.action = 'add' AND ta.success AND u.user_id <> 0
+ORDER BY ta.created_at DESC
+LIMIT 1
+`
+
+type GetTorrentAdderRow struct {
+	UserID    int64   `json:"user_id"`
+	Username  *string `json:"username"`
+	FirstName *string `json:"first_name"`
+}
+
+func (q *Queries) GetTorrentAdder(ctx context.Context, torrentID string) (GetTorrentAdderRow, error) {
+	row := q.db.QueryRow(ctx, getTorrentAdder, torrentID)
+	var i GetTorrentAdderRow
+	err := row.Scan(&i.UserID, &i.Username, &i.FirstName)
+	return i, err
+}
+
+const getTorrentMagnet = `-- name: GetTorrentMagnet :one
+SELECT magnet_link
+FROM torrent_activities
+WHERE torrent_id = $1 AND magnet_link IS NOT NULL AND magnet_link <> ''
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetTorrentMagnet(ctx context.Context, torrentID string) (*string, error) {
+	row := q.db.QueryRow(ctx, getTorrentMagnet, torrentID)
+	var magnet_link *string
+	err := row.Scan(&magnet_link)
+	return magnet_link, err
+}
+
 const insertTorrentActivity = `-- name: InsertTorrentActivity :exec
 INSERT INTO torrent_activities (
     request_id, user_id, chat_id, torrent_id, torrent_hash, torrent_name,
@@ -171,3 +274,29 @@ func (q *Queries) InsertTorrentActivity(ctx context.Context, arg InsertTorrentAc
 	)
 	return err
 }
+
+const insertTorrentRename = `-- name: InsertTorrentRename :exec
+INSERT INTO torrent_activities (user_id, chat_id, torrent_id, torrent_name, action, display_name, created_at)
+VALUES ($1, $2, $3, $4, 'rename', $5, $6)
+`
+
+type InsertTorrentRenameParams struct {
+	UserID      int64              `json:"user_id"`
+	ChatID      int64              `json:"chat_id"`
+	TorrentID   string             `json:"torrent_id"`
+	TorrentName *string            `json:"torrent_name"`
+	DisplayName *string            `json:"display_name"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) InsertTorrentRename(ctx context.Context, arg InsertTorrentRenameParams) error {
+	_, err := q.db.Exec(ctx, insertTorrentRename,
+		arg.UserID,
+		arg.ChatID,
+		arg.TorrentID,
+		arg.TorrentName,
+		arg.DisplayName,
+		arg.CreatedAt,
+	)
+	return err
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// magnetDBTX answers GetTorrentMagnet with the recorded magnet of each torrent
+type magnetDBTX struct {
+	mockDBTX
+	magnets map[string]*string
+}
+
+func (m *magnetDBTX) QueryRow(_ context.Context, _ string, args ...interface{}) pgx.Row {
+	magnet, ok := m.magnets[args[0].(string)]
+	return magnetRow{magnet: magnet, found: ok}
+}
+
+// magnetRow scans one magnet_link column, or fails with pgx.ErrNoRows when not found
+type magnetRow struct {
+	magnet *string
+	found  bool
+}
+
+func (r magnetRow) Scan(dest ...interface{}) error {
+	if !r.found {
+		return pgx.ErrNoRows
+	}
+	*dest[0].(**string) = r.magnet
+	return nil
+}
+
+// TestGetTorrentMagnet verifies the recorded magnet is returned and a torrent without
+// one yields an empty string rather than an error.
+func TestGetTorrentMagnet(t *testing.T) {
+	magnet := "magnet:?xt=urn:btih:abc"
+	repo := &TorrentRepository{queries: New(&magnetDBTX{magnets: map[string]*string{"ABC": &magnet}})}
+
+	got, err := repo.GetTorrentMagnet(t.Context(), "ABC")
+	if err != nil || got != magnet {
+		t.Errorf("GetTorrentMagnet(ABC) = %q, %v, want %q", got, err, magnet)
+	}
+	got, err = repo.GetTorrentMagnet(t.Context(), "MISSING")
+	if err != nil || got != "" {
+		t.Errorf("GetTorrentMagnet(MISSING) = %q, %v, want no magnet and no error", got, err)
+	}
+}
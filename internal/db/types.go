@@ -6,18 +6,20 @@ import "time"
 // Field types are chosen to match the old GORM model API so that
 // callers (handlers.go, autodelete.go, etc.) compile without changes.
 type User struct {
-	ID            int64
-	UserID        int64
-	Username      string
-	FirstName     string
-	LastName      string
-	IsSuperAdmin  bool
-	IsAllowed     bool
-	FirstSeenAt   time.Time
-	LastSeenAt    time.Time
-	TotalCommands int64
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	ID             int64
+	UserID         int64
+	Username       string
+	FirstName      string
+	LastName       string
+	IsSuperAdmin   bool
+	IsAllowed      bool
+	FirstSeenAt    time.Time
+	LastSeenAt     time.Time
+	TotalCommands  int64
+	TotalTorrents  int64 // Successful torrent adds
+	TotalDownloads int64 // Successful unrestricts
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 // Chat is the public-facing chat type returned by repositories.
@@ -67,6 +69,7 @@ type TorrentActivity struct {
 	Metadata      string
 	CreatedAt     time.Time
 	SelectedFiles string
+	DisplayName   string
 }
 
 // KeptTorrentUser holds the minimal user info embedded in a KeptTorrent record.
@@ -89,6 +92,85 @@ type KeptTorrent struct {
 	User      KeptTorrentUser
 }
 
+// TorrentSubscription is a user's request to be notified when a torrent completes.
+// ChatID and UserID are Telegram IDs, resolved for delivery.
+type TorrentSubscription struct {
+	ID        int64
+	TorrentID string
+	ChatID    int64
+	ThreadID  int64
+	UserPK    int64
+	UserID    int64
+	Username  string
+	CreatedAt time.Time
+}
+
+// ScheduledDeletion is a torrent deletion scheduled for a later time.
+// ChatID is the Telegram chat ID the result is reported to and ChatPK its internal
+// ID; UserPK is the internal ID of the user who scheduled it (0 if they were since removed).
+type ScheduledDeletion struct {
+	ID        int64
+	TorrentID string
+	DeleteAt  time.Time
+	ChatID    int64
+	ChatPK    int64
+	ThreadID  int64
+	UserPK    int64
+	CreatedAt time.Time
+}
+
+// QueuedMagnet is a magnet waiting for a free active torrent slot.
+// ChatID and UserID are Telegram IDs; ChatPK and UserPK are the internal IDs of the
+// chat it was queued from and the user who queued it.
+type QueuedMagnet struct {
+	ID        int64
+	Magnet    string
+	ChatPK    int64
+	ChatID    int64
+	ThreadID  int64
+	UserPK    int64
+	UserID    int64
+	CreatedAt time.Time
+}
+
+// HostStats sums up a user's successful unrestricts from one hoster.
+// LastDownloadAt is zero when there were none.
+type HostStats struct {
+	Host           string
+	Downloads      int64
+	TotalBytes     int64
+	LastDownloadAt time.Time
+}
+
+// PurgedRows is how many rows a user purge removed from one table.
+type PurgedRows struct {
+	Table string
+	Rows  int64
+}
+
+// LatencyStats summarises the execution times of a command, in milliseconds.
+// P95 is the nearest-rank 95th percentile; all values are zero when Samples is 0.
+type LatencyStats struct {
+	Command string
+	Samples int64
+	AvgMs   float64
+	MinMs   int64
+	MaxMs   int64
+	P95Ms   int64
+}
+
+// ActivityEntry is a logged activity of a user, with the Telegram chat it happened in.
+type ActivityEntry struct {
+	ID           int64
+	ActivityType string
+	Command      string
+	ErrorMessage string
+	ChatID       int64
+	ChatTitle    string
+	ThreadID     int64
+	CreatedAt    time.Time
+}
+
 // derefStr returns the string value pointed to by s, or the empty string if s is nil.
 func derefStr(s *string) string {
 	if s == nil {
@@ -33,6 +33,17 @@ func (q *Queries) BanUser(ctx context.Context, arg BanUserParams) error {
 	return err
 }
 
+const countUsers = `-- name: CountUsers :one
+SELECT COUNT(*) FROM users WHERE deleted_at IS NULL AND user_id <> 0
+`
+
+func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countUsers)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const getUserByID = `-- name: GetUserByID :one
 SELECT id, user_id, username, first_name, last_name, language_code, is_bot, is_premium, is_super_admin, is_allowed, ban_reason, banned_at, first_seen_at, last_seen_at, total_commands, total_torrents_added, total_downloads, created_at, updated_at, deleted_at FROM users WHERE id = $1 AND deleted_at IS NULL
 `
@@ -124,6 +135,63 @@ func (q *Queries) IncrementUserTorrents(ctx context.Context, userID int64) error
 	return err
 }
 
+const listUsers = `-- name: ListUsers :many
+SELECT id, user_id, username, first_name, last_name, language_code, is_bot, is_premium, is_super_admin, is_allowed, ban_reason, banned_at, first_seen_at, last_seen_at, total_commands, total_torrents_added, total_downloads, created_at, updated_at, deleted_at FROM users
+WHERE deleted_at IS NULL AND user_id <> 0
+ORDER BY
+    CASE WHEN $1::text = 'total_commands' THEN total_commands END DESC,
+    CASE WHEN $1::text = 'last_seen' THEN last_seen_at END DESC NULLS LAST,
+    id
+LIMIT $2 OFFSET $3
+`
+
+type ListUsersParams struct {
+	SortBy    string `json:"sort_by"`
+	RowLimit  int32  `json:"row_limit"`
+	RowOffset int32  `json:"row_offset"`
+}
+
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]Users, error) {
+	rows, err := q.db.Query(ctx, listUsers, arg.SortBy, arg.RowLimit, arg.RowOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Users
+	for rows.Next() {
+		var i Users
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Username,
+			&i.FirstName,
+			&i.LastName,
+			&i.LanguageCode,
+			&i.IsBot,
+			&i.IsPremium,
+			&i.IsSuperAdmin,
+			&i.IsAllowed,
+			&i.BanReason,
+			&i.BannedAt,
+			&i.FirstSeenAt,
+			&i.LastSeenAt,
+			&i.TotalCommands,
+			&i.TotalTorrentsAdded,
+			&i.TotalDownloads,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const lockUserForUpdate = `-- name: LockUserForUpdate :one
 SELECT id, user_id, username, first_name, last_name, language_code, is_bot, is_premium, is_super_admin, is_allowed, ban_reason, banned_at, first_seen_at, last_seen_at, total_commands, total_torrents_added, total_downloads, created_at, updated_at, deleted_at FROM users WHERE user_id = $1 AND deleted_at IS NULL FOR UPDATE
 `
@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// usersDBTX answers CountUsers with total and ListUsers with rows, recording the
+// arguments ListUsers was queried with
+type usersDBTX struct {
+	mockDBTX
+	total     int64
+	rows      []Users
+	queryArgs []interface{}
+}
+
+func (u *usersDBTX) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	return countRow(u.total)
+}
+
+func (u *usersDBTX) Query(_ context.Context, _ string, args ...interface{}) (pgx.Rows, error) {
+	u.queryArgs = args
+	return &usersRows{rows: u.rows, pos: -1}, nil
+}
+
+// countRow scans a single COUNT(*)
+type countRow int64
+
+func (r countRow) Scan(dest ...interface{}) error {
+	*dest[0].(*int64) = int64(r)
+	return nil
+}
+
+// usersRows iterates Users rows, scanning their fields in column order
+type usersRows struct {
+	rows []Users
+	pos  int
+}
+
+func (r *usersRows) Close()                                       {}
+func (r *usersRows) Err() error                                   { return nil }
+func (r *usersRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (r *usersRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *usersRows) Values() ([]interface{}, error)               { return nil, nil }
+func (r *usersRows) RawValues() [][]byte                          { return nil }
+func (r *usersRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *usersRows) Next() bool {
+	r.pos++
+	return r.pos < len(r.rows)
+}
+
+func (r *usersRows) Scan(dest ...interface{}) error {
+	row := reflect.ValueOf(r.rows[r.pos])
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(row.Field(i))
+	}
+	return nil
+}
+
+// TestListUsersPage verifies the page and sort order are passed to the query, the
+// total comes from the count, and rows map to users with their counters.
+func TestListUsersPage(t *testing.T) {
+	seen := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	alice, bob := "alice", "bob"
+	dbtx := &usersDBTX{total: 7, rows: []Users{
+		{ID: 3, UserID: 300, Username: &alice, TotalCommands: 40, TotalTorrentsAdded: 5, TotalDownloads: 9, LastSeenAt: toPgtypeTimestamptz(seen)},
+		{ID: 4, UserID: 400, Username: &bob, TotalCommands: 12, IsAllowed: true},
+	}}
+
+	users, total, err := listUsersPage(t.Context(), New(dbtx), 2, 2, "last_seen")
+	if err != nil {
+		t.Fatalf("listUsersPage() error = %v", err)
+	}
+	if want := []interface{}{"last_seen", int32(2), int32(2)}; !reflect.DeepEqual(dbtx.queryArgs, want) {
+		t.Errorf("ListUsers args = %v, want %v", dbtx.queryArgs, want)
+	}
+	if total != 7 {
+		t.Errorf("total = %d, want 7", total)
+	}
+	if len(users) != 2 {
+		t.Fatalf("got %d users, want 2", len(users))
+	}
+	want := User{ID: 3, UserID: 300, Username: "alice", TotalCommands: 40, TotalTorrents: 5, TotalDownloads: 9, LastSeenAt: seen}
+	if users[0] != want {
+		t.Errorf("users[0] = %+v, want %+v", users[0], want)
+	}
+	if users[1].Username != "bob" || !users[1].IsAllowed {
+		t.Errorf("users[1] = %+v, want allowed bob", users[1])
+	}
+
+	for _, sortBy := range []string{"", "total_commands"} {
+		if _, _, err := listUsersPage(t.Context(), New(dbtx), 10, 0, sortBy); err != nil {
+			t.Errorf("listUsersPage(sort %q) error = %v", sortBy, err)
+		}
+		if dbtx.queryArgs[0] != sortBy {
+			t.Errorf("sort_by = %v, want %q", dbtx.queryArgs[0], sortBy)
+		}
+	}
+}
+
+// TestListUsersPage_InvalidSort verifies a sort outside UserSortFields is rejected
+// before anything is queried.
+func TestListUsersPage_InvalidSort(t *testing.T) {
+	dbtx := &usersDBTX{}
+	if _, _, err := listUsersPage(t.Context(), New(dbtx), 10, 0, "id; DROP TABLE users"); err == nil {
+		t.Error("listUsersPage() error = nil for an invalid sort")
+	}
+	if dbtx.queryArgs != nil {
+		t.Error("ListUsers was queried despite the invalid sort")
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -21,6 +22,12 @@ type Client struct {
 	apiToken   string
 	httpClient *http.Client
 
+	// slowThreshold logs a warning for requests that take longer (0 = disabled)
+	slowThreshold time.Duration
+
+	// debug logs every request with its status and duration; toggled at runtime
+	debug atomic.Bool
+
 	domainsCache struct {
 		mu      sync.RWMutex
 		domains []string
@@ -42,9 +49,86 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("RD API error %d: %s", e.ErrorCode, e.ErrorMessage)
 }
 
-// NewClient creates a new Real-Debrid API client
+// maxErrorSnippet is how much of an unexpected response body is quoted in errors
+const maxErrorSnippet = 200
+
+// NonJSONResponseError is returned when Real-Debrid answers with something other than
+// JSON, typically an HTML challenge or error page served by Cloudflare, or a response
+// cut off mid-body
+type NonJSONResponseError struct {
+	StatusCode  int
+	ContentType string
+	Snippet     string // start of the body, whitespace collapsed
+}
+
+func (e *NonJSONResponseError) Error() string {
+	return fmt.Sprintf("HTTP %d: unexpected non-JSON response (%s), RD may be behind a challenge: %q", e.StatusCode, e.ContentType, e.Snippet)
+}
+
+// newNonJSONResponseError builds a NonJSONResponseError quoting the start of body
+func newNonJSONResponseError(resp *http.Response, body []byte) *NonJSONResponseError {
+	snippet := strings.Join(strings.Fields(string(body)), " ")
+	if len(snippet) > maxErrorSnippet {
+		snippet = strings.ToValidUTF8(snippet[:maxErrorSnippet], "") + "…"
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "no content type"
+	}
+	return &NonJSONResponseError{StatusCode: resp.StatusCode, ContentType: contentType, Snippet: snippet}
+}
+
+// checkResponse turns an RD response into an error: an *APIError for JSON error replies
+// and a *NonJSONResponseError for bodies that aren't JSON. Empty successful bodies, as
+// returned by the 204 endpoints, are accepted.
+func checkResponse(resp *http.Response, body []byte) error {
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr APIError
+		if err := json.Unmarshal(body, &apiErr); err != nil {
+			return newNonJSONResponseError(resp, body)
+		}
+		return &apiErr
+	}
+	if len(bytes.TrimSpace(body)) > 0 && !json.Valid(body) {
+		return newNonJSONResponseError(resp, body)
+	}
+	return nil
+}
+
+// TransportOptions tunes connection pooling of the client's HTTP transport
+type TransportOptions struct {
+	MaxIdleConns        int           // Idle connections kept across all hosts; 0 means no limit
+	MaxIdleConnsPerHost int           // Idle connections kept per host
+	IdleConnTimeout     time.Duration // How long an idle connection is kept; 0 means no limit
+	DisableHTTP2        bool          // Speak HTTP/1.1 only, for proxies that mishandle HTTP/2
+}
+
+// DefaultTransportOptions returns the pooling settings of http.DefaultTransport
+func DefaultTransportOptions() TransportOptions {
+	return TransportOptions{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: http.DefaultMaxIdleConnsPerHost,
+		IdleConnTimeout:     90 * time.Second,
+	}
+}
+
+// NewClient creates a new Real-Debrid API client with the default transport settings
 func NewClient(baseURL, apiToken, proxyURL string, timeout time.Duration) *Client {
-	transport := &http.Transport{}
+	return NewClientWithTransport(baseURL, apiToken, proxyURL, timeout, DefaultTransportOptions())
+}
+
+// NewClientWithTransport creates a new Real-Debrid API client whose connection pooling
+// and HTTP version are set by opts
+func NewClientWithTransport(baseURL, apiToken, proxyURL string, timeout time.Duration, opts TransportOptions) *Client {
+	transport := &http.Transport{
+		MaxIdleConns:        opts.MaxIdleConns,
+		MaxIdleConnsPerHost: opts.MaxIdleConnsPerHost,
+		IdleConnTimeout:     opts.IdleConnTimeout,
+	}
+	if opts.DisableHTTP2 {
+		transport.Protocols = new(http.Protocols)
+		transport.Protocols.SetHTTP1(true)
+	}
 	if proxyURL != "" {
 		parsedProxyURL, err := url.Parse(proxyURL)
 		if err != nil {
@@ -63,6 +147,35 @@ func NewClient(baseURL, apiToken, proxyURL string, timeout time.Duration) *Clien
 	}
 }
 
+// SetSlowThreshold enables a warning log for requests slower than d (0 disables it)
+func (c *Client) SetSlowThreshold(d time.Duration) {
+	c.slowThreshold = d
+}
+
+// SetDebug turns per-request debug logging on or off; safe to call while requests run
+func (c *Client) SetDebug(enabled bool) {
+	c.debug.Store(enabled)
+}
+
+// logDebug logs a finished request when debug logging is on. Like logIfSlow, it never
+// includes the token or query parameters.
+func (c *Client) logDebug(method, endpoint string, status int, start time.Time) {
+	if c.debug.Load() {
+		log.Printf("Debug: Real-Debrid %s %s -> HTTP %d in %s", method, endpoint, status, time.Since(start).Round(time.Millisecond))
+	}
+}
+
+// logIfSlow logs a warning when a request started at start exceeded the slow threshold.
+// Only the method and endpoint path are logged; the token and query are never included.
+func (c *Client) logIfSlow(method, endpoint string, start time.Time) {
+	if c.slowThreshold <= 0 {
+		return
+	}
+	if elapsed := time.Since(start); elapsed > c.slowThreshold {
+		log.Printf("Warning: slow Real-Debrid call %s %s took %s (threshold %s)", method, endpoint, elapsed.Round(time.Millisecond), c.slowThreshold)
+	}
+}
+
 // validTorrentID matches Real-Debrid torrent/download IDs (alphanumeric)
 var validTorrentID = regexp.MustCompile(`^[A-Za-z0-9]+$`)
 
@@ -113,10 +226,13 @@ func (c *Client) doRequestWithHeaders(method, endpoint string, body interface{},
 	}
 
 	// Perform request
+	start := time.Now()
+	defer c.logIfSlow(method, endpoint, start)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, nil, fmt.Errorf("request failed: %w", err)
 	}
+	c.logDebug(method, endpoint, resp.StatusCode, start)
 	defer func() {
 		if cerr := resp.Body.Close(); cerr != nil {
 			log.Printf("Warning: failed to close response body: %v", cerr)
@@ -130,12 +246,8 @@ func (c *Client) doRequestWithHeaders(method, endpoint string, body interface{},
 	}
 
 	// Check for errors
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		var apiErr APIError
-		if err := json.Unmarshal(respBody, &apiErr); err != nil {
-			return nil, nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(respBody))
-		}
-		return nil, nil, &apiErr
+	if err := checkResponse(resp, respBody); err != nil {
+		return nil, nil, err
 	}
 
 	return respBody, resp.Header, nil
@@ -163,6 +275,33 @@ func (c *Client) GETWithTotalCount(endpoint string, queryParams map[string]strin
 	return respBody, totalCount, nil
 }
 
+// listWithCount fetches one page of a paginated list endpoint and the total number
+// of items from its X-Total-Count header. what names the items in error messages.
+// An empty body, which RD sends with 204 No Content for an empty list, yields no items.
+func listWithCount[T any](c *Client, endpoint, what string, limit, offset int) ([]T, int, error) {
+	params := make(map[string]string)
+	if limit > 0 {
+		params["limit"] = strconv.Itoa(limit)
+	}
+	if offset > 0 {
+		params["offset"] = strconv.Itoa(offset)
+	}
+
+	data, totalCount, err := c.GETWithTotalCount(endpoint, params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get %s: %w", what, err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, totalCount, nil
+	}
+
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse %s: %w", what, err)
+	}
+	return items, totalCount, nil
+}
+
 // POST performs a POST request
 func (c *Client) POST(endpoint string, body interface{}) ([]byte, error) {
 	return c.doRequest(http.MethodPost, endpoint, body, nil)
@@ -190,10 +329,13 @@ func (c *Client) POSTForm(endpoint string, formData map[string]string) ([]byte,
 	req.Header.Set("Authorization", "Bearer "+c.apiToken)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
+	start := time.Now()
+	defer c.logIfSlow(http.MethodPost, endpoint, start)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
+	c.logDebug(http.MethodPost, endpoint, resp.StatusCode, start)
 	defer func() {
 		if cerr := resp.Body.Close(); cerr != nil {
 			log.Printf("Warning: failed to close form response body: %v", cerr)
@@ -205,12 +347,8 @@ func (c *Client) POSTForm(endpoint string, formData map[string]string) ([]byte,
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		var apiErr APIError
-		if err := json.Unmarshal(respBody, &apiErr); err != nil {
-			return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(respBody))
-		}
-		return nil, &apiErr
+	if err := checkResponse(resp, respBody); err != nil {
+		return nil, err
 	}
 
 	return respBody, nil
@@ -0,0 +1,532 @@
+package realdebrid
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// captureLog redirects the standard logger into a buffer for the duration of the test.
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prevOut, prevFlags := log.Writer(), log.Flags()
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	t.Cleanup(func() {
+		log.SetOutput(prevOut)
+		log.SetFlags(prevFlags)
+	})
+	return &buf
+}
+
+// newSlowServer returns a mock RD API that waits delay before answering with an empty object.
+func newSlowServer(t *testing.T, delay time.Duration) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(delay)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// TestSlowThreshold_LogsSlowCall verifies a warning with method, endpoint and duration is
+// logged for slow calls, without leaking the API token.
+func TestSlowThreshold_LogsSlowCall(t *testing.T) {
+	srv := newSlowServer(t, 50*time.Millisecond)
+	buf := captureLog(t)
+
+	c := NewClient(srv.URL, "secret-token", "", 5*time.Second)
+	c.SetSlowThreshold(10 * time.Millisecond)
+
+	if _, err := c.GET("/user", nil); err != nil {
+		t.Fatalf("GET() error = %v", err)
+	}
+	if _, err := c.POSTForm("/torrents/addMagnet", map[string]string{"magnet": "magnet:?xt=urn:btih:abc"}); err != nil {
+		t.Fatalf("POSTForm() error = %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "slow Real-Debrid call GET /user") {
+		t.Errorf("missing GET warning in log: %q", out)
+	}
+	if !strings.Contains(out, "slow Real-Debrid call POST /torrents/addMagnet") {
+		t.Errorf("missing POSTForm warning in log: %q", out)
+	}
+	if strings.Contains(out, "secret-token") {
+		t.Errorf("log leaks the API token: %q", out)
+	}
+}
+
+// TestSlowThreshold_FastOrDisabled verifies nothing is logged under the threshold or when disabled.
+func TestSlowThreshold_FastOrDisabled(t *testing.T) {
+	srv := newSlowServer(t, 20*time.Millisecond)
+	buf := captureLog(t)
+
+	fast := NewClient(srv.URL, "token", "", 5*time.Second)
+	fast.SetSlowThreshold(5 * time.Second)
+	if _, err := fast.GET("/user", nil); err != nil {
+		t.Fatalf("GET() error = %v", err)
+	}
+
+	disabled := NewClient(srv.URL, "token", "", 5*time.Second)
+	disabled.SetSlowThreshold(-1)
+	if _, err := disabled.GET("/user", nil); err != nil {
+		t.Fatalf("GET() error = %v", err)
+	}
+
+	if strings.Contains(buf.String(), "slow Real-Debrid call") {
+		t.Errorf("unexpected slow-call warning: %q", buf.String())
+	}
+}
+
+// TestCheckLink verifies the check endpoint is called with the link and its response parsed.
+func TestCheckLink(t *testing.T) {
+	var gotPath, gotLink string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_ = r.ParseForm()
+		gotLink = r.PostForm.Get("link")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"host":"example.com","link":"https://example.com/f/1","filename":"movie.mkv","filesize":1073741824,"supported":1}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "token", "", 5*time.Second)
+	check, err := c.CheckLink("https://example.com/f/1")
+	if err != nil {
+		t.Fatalf("CheckLink() error = %v", err)
+	}
+	if gotPath != "/unrestrict/check" || gotLink != "https://example.com/f/1" {
+		t.Errorf("request = %s link=%q, want /unrestrict/check with the link", gotPath, gotLink)
+	}
+	if !check.IsSupported() || check.Host != "example.com" || check.Filename != "movie.mkv" || check.Filesize != 1073741824 {
+		t.Errorf("CheckLink() = %+v", check)
+	}
+}
+
+// TestCheckLink_Unavailable verifies RD errors are returned as APIError.
+func TestCheckLink_Unavailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"error":"hoster_unavailable","error_code":19}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "token", "", 5*time.Second)
+	_, err := c.CheckLink("https://example.com/f/1")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode != 19 {
+		t.Fatalf("CheckLink() error = %v, want APIError 19", err)
+	}
+}
+
+// TestGetSettings verifies the settings response is decoded.
+func TestGetSettings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/settings" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"download_ports":["normal","secured"],"download_port":"secured","locales":{"en":"English","fr":"Français"},"locale":"en","streaming_qualities":["original","1080"],"streaming_quality":"1080","mobile_streaming_quality":"original","streaming_language_preference":"eng"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "token", "", 5*time.Second)
+	settings, err := c.GetSettings()
+	if err != nil {
+		t.Fatalf("GetSettings() error = %v", err)
+	}
+	if settings.DownloadPort != "secured" || settings.Locale != "en" || settings.StreamingQuality != "1080" || settings.Locales["fr"] != "Français" {
+		t.Errorf("GetSettings() = %+v", settings)
+	}
+}
+
+// TestGetTraffic verifies per-host quotas are decoded and their usage computed per quota type.
+func TestGetTraffic(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/traffic" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"uptobox.com":{"left":1073741824,"bytes":9663676416,"links":3,"limit":10,"type":"gigabytes","extra":0,"reset":"daily"},"1fichier.com":{"left":5,"bytes":0,"links":15,"limit":20,"type":"links","extra":0,"reset":"daily"}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "token", "", 5*time.Second)
+	traffic, err := c.GetTraffic()
+	if err != nil {
+		t.Fatalf("GetTraffic() error = %v", err)
+	}
+	if got := traffic["uptobox.com"].UsedPercent(); got != 90 {
+		t.Errorf("uptobox.com UsedPercent() = %v, want 90", got)
+	}
+	if got := traffic["1fichier.com"].UsedPercent(); got != 75 {
+		t.Errorf("1fichier.com UsedPercent() = %v, want 75", got)
+	}
+	if got := (HostTraffic{}).UsedPercent(); got != 0 {
+		t.Errorf("unmetered UsedPercent() = %v, want 0", got)
+	}
+}
+
+// TestUpdateSettings verifies the update is posted as setting_name/setting_value and unknown names are rejected locally.
+func TestUpdateSettings(t *testing.T) {
+	var gotName, gotValue string
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_ = r.ParseForm()
+		gotName, gotValue = r.PostForm.Get("setting_name"), r.PostForm.Get("setting_value")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "token", "", 5*time.Second)
+	if err := c.UpdateSettings("streaming_quality", "720"); err != nil {
+		t.Fatalf("UpdateSettings() error = %v", err)
+	}
+	if gotName != "streaming_quality" || gotValue != "720" {
+		t.Errorf("posted %q=%q, want streaming_quality=720", gotName, gotValue)
+	}
+
+	if err := c.UpdateSettings("password", "x"); err == nil {
+		t.Error("UpdateSettings(unknown) error = nil, want error")
+	}
+	if calls != 1 {
+		t.Errorf("server calls = %d, want 1", calls)
+	}
+}
+
+// TestFormatSizeUnits checks each mode at the unit boundaries.
+func TestFormatSizeUnits(t *testing.T) {
+	tests := []struct {
+		name  string
+		bytes int64
+		units SizeUnits
+		want  string
+	}{
+		{"legacy below KB", 1023, SizeUnitsLegacy, "1023 B"},
+		{"legacy KB", 1024, SizeUnitsLegacy, "1.00 KB"},
+		{"legacy GB", 1 << 30, SizeUnitsLegacy, "1.00 GB"},
+		{"binary below KiB", 1023, SizeUnitsBinary, "1023 B"},
+		{"binary KiB", 1024, SizeUnitsBinary, "1.00 KiB"},
+		{"binary MiB edge", 1<<20 - 1, SizeUnitsBinary, "1024.00 KiB"},
+		{"binary MiB", 1 << 20, SizeUnitsBinary, "1.00 MiB"},
+		{"binary GiB", 1 << 30, SizeUnitsBinary, "1.00 GiB"},
+		{"decimal below KB", 999, SizeUnitsDecimal, "999 B"},
+		{"decimal KB", 1000, SizeUnitsDecimal, "1.00 KB"},
+		{"decimal 1024 bytes", 1024, SizeUnitsDecimal, "1.02 KB"},
+		{"decimal MB", 1_000_000, SizeUnitsDecimal, "1.00 MB"},
+		{"decimal GB", 1_500_000_000, SizeUnitsDecimal, "1.50 GB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatSizeUnits(tt.bytes, tt.units); got != tt.want {
+				t.Errorf("FormatSizeUnits(%d) = %q, want %q", tt.bytes, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFormatSize_UsesConfiguredUnits verifies SetSizeUnits switches FormatSize's output.
+func TestFormatSize_UsesConfiguredUnits(t *testing.T) {
+	t.Cleanup(func() { SetSizeUnits(SizeUnitsLegacy) })
+
+	if got := FormatSize(2048); got != "2.00 KB" {
+		t.Errorf("default FormatSize(2048) = %q, want %q", got, "2.00 KB")
+	}
+	SetSizeUnits(SizeUnitsBinary)
+	if got := FormatSize(2048); got != "2.00 KiB" {
+		t.Errorf("binary FormatSize(2048) = %q, want %q", got, "2.00 KiB")
+	}
+}
+
+func TestParseSizeUnits(t *testing.T) {
+	for in, want := range map[string]SizeUnits{"": SizeUnitsLegacy, "binary": SizeUnitsBinary, "Decimal": SizeUnitsDecimal} {
+		if got, err := ParseSizeUnits(in); err != nil || got != want {
+			t.Errorf("ParseSizeUnits(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseSizeUnits("metric"); err == nil {
+		t.Error("ParseSizeUnits(\"metric\") error = nil, want error")
+	}
+}
+
+// newSelectFilesServer returns a mock RD server whose torrent reports each of statuses
+// in turn on /torrents/info and only accepts a file selection once it has reported
+// waiting_files_selection. It also returns the number of accepted selections.
+func newSelectFilesServer(t *testing.T, statuses ...string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var mu sync.Mutex
+	infoCalls, current := 0, ""
+	var selects atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/torrents/info/"):
+			current = statuses[min(infoCalls, len(statuses)-1)]
+			infoCalls++
+			_, _ = fmt.Fprintf(w, `{"id":"ABC","status":%q}`, current)
+		case strings.HasPrefix(r.URL.Path, "/torrents/selectFiles/"):
+			if current != "waiting_files_selection" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"action_already_done","error_code":19}`))
+				return
+			}
+			selects.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &selects
+}
+
+// TestSelectAllFilesWhenReady_WaitsForConversion verifies the selection is retried once the magnet is converted.
+func TestSelectAllFilesWhenReady_WaitsForConversion(t *testing.T) {
+	srv, selects := newSelectFilesServer(t, "magnet_conversion", "magnet_conversion", "waiting_files_selection")
+	c := NewClient(srv.URL, "token", "", 5*time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := SelectAllFilesWhenReady(ctx, c, "ABC", 10*time.Millisecond); err != nil {
+		t.Fatalf("SelectAllFilesWhenReady() error = %v", err)
+	}
+	if got := selects.Load(); got != 1 {
+		t.Errorf("accepted selections = %d, want 1", got)
+	}
+}
+
+// TestSelectAllFilesWhenReady_GivesUp verifies the last error is returned when the torrent never becomes selectable.
+func TestSelectAllFilesWhenReady_GivesUp(t *testing.T) {
+	srv, _ := newSelectFilesServer(t, "magnet_conversion")
+	c := NewClient(srv.URL, "token", "", 5*time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := SelectAllFilesWhenReady(ctx, c, "ABC", 10*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "gave up selecting files") {
+		t.Errorf("SelectAllFilesWhenReady() error = %v, want give-up error", err)
+	}
+}
+
+// TestSelectAllFilesWhenReady_FailedTorrent verifies a dead torrent stops polling with an error.
+func TestSelectAllFilesWhenReady_FailedTorrent(t *testing.T) {
+	srv, _ := newSelectFilesServer(t, "magnet_error")
+	c := NewClient(srv.URL, "token", "", 5*time.Second)
+
+	err := SelectAllFilesWhenReady(context.Background(), c, "ABC", 10*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "Magnet Error") {
+		t.Errorf("SelectAllFilesWhenReady() error = %v, want magnet error", err)
+	}
+}
+
+// TestListWithCount verifies paginated lists pass limit/offset and read X-Total-Count.
+func TestListWithCount(t *testing.T) {
+	var gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		w.Header().Set("X-Total-Count", "42")
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/torrents":
+			_, _ = w.Write([]byte(`[{"id":"T1","filename":"one"},{"id":"T2","filename":"two"}]`))
+		case "/downloads":
+			_, _ = w.Write([]byte(`[{"id":"D1","filename":"file"}]`))
+		}
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, "token", "", 5*time.Second)
+
+	torrents, err := c.GetTorrentsWithCount(2, 10)
+	if err != nil {
+		t.Fatalf("GetTorrentsWithCount() error = %v", err)
+	}
+	if gotQuery != "limit=2&offset=10" {
+		t.Errorf("query = %q, want limit=2&offset=10", gotQuery)
+	}
+	if torrents.TotalCount != 42 || len(torrents.Torrents) != 2 || torrents.Torrents[1].ID != "T2" {
+		t.Errorf("GetTorrentsWithCount() = %+v", torrents)
+	}
+
+	downloads, err := c.GetDownloadsWithCount(0, 0)
+	if err != nil {
+		t.Fatalf("GetDownloadsWithCount() error = %v", err)
+	}
+	if gotQuery != "" {
+		t.Errorf("query = %q, want no pagination parameters", gotQuery)
+	}
+	if downloads.TotalCount != 42 || len(downloads.Downloads) != 1 || downloads.Downloads[0].ID != "D1" {
+		t.Errorf("GetDownloadsWithCount() = %+v", downloads)
+	}
+
+	plain, err := c.GetTorrents(2, 0)
+	if err != nil || len(plain) != 2 {
+		t.Errorf("GetTorrents() = (%v, %v), want the 2 torrents", plain, err)
+	}
+}
+
+// TestListWithCount_EmptyList verifies RD's 204 No Content for an empty list is not a parse error.
+func TestListWithCount_EmptyList(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, "token", "", 5*time.Second)
+
+	result, err := c.GetTorrentsWithCount(10, 0)
+	if err != nil {
+		t.Fatalf("GetTorrentsWithCount() error = %v", err)
+	}
+	if len(result.Torrents) != 0 || result.TotalCount != 0 {
+		t.Errorf("GetTorrentsWithCount() = %+v, want empty", result)
+	}
+}
+
+// TestNewClientWithTransport verifies the pooling options are applied to the transport
+// and that HTTP/2 can be disabled.
+func TestNewClientWithTransport(t *testing.T) {
+	opts := TransportOptions{MaxIdleConns: 50, MaxIdleConnsPerHost: 10, IdleConnTimeout: 30 * time.Second, DisableHTTP2: true}
+	c := NewClientWithTransport("https://example.com", "token", "", 5*time.Second, opts)
+
+	tr, ok := c.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("transport is %T, want *http.Transport", c.httpClient.Transport)
+	}
+	if tr.MaxIdleConns != 50 || tr.MaxIdleConnsPerHost != 10 || tr.IdleConnTimeout != 30*time.Second {
+		t.Errorf("transport pool = (%d, %d, %s), want (50, 10, 30s)", tr.MaxIdleConns, tr.MaxIdleConnsPerHost, tr.IdleConnTimeout)
+	}
+	if tr.Protocols == nil || tr.Protocols.HTTP2() || !tr.Protocols.HTTP1() {
+		t.Errorf("transport protocols = %v, want HTTP/1 only", tr.Protocols)
+	}
+
+	def := NewClient("https://example.com", "token", "", 5*time.Second).httpClient.Transport.(*http.Transport)
+	if def.MaxIdleConns != 100 || def.MaxIdleConnsPerHost != http.DefaultMaxIdleConnsPerHost || def.IdleConnTimeout != 90*time.Second {
+		t.Errorf("default pool = (%d, %d, %s), want Go's defaults", def.MaxIdleConns, def.MaxIdleConnsPerHost, def.IdleConnTimeout)
+	}
+	if def.Protocols != nil {
+		t.Errorf("default protocols = %v, want unset so HTTP/2 stays available", def.Protocols)
+	}
+}
+
+// TestNonJSONResponse verifies HTML pages and truncated JSON yield a NonJSONResponseError
+// quoting a bounded snippet, for both successful and failed requests.
+func TestNonJSONResponse(t *testing.T) {
+	page := "<!DOCTYPE html>\n<html><head><title>Just a moment...</title></head><body>" + strings.Repeat("x", 500) + "</body></html>"
+	tests := []struct {
+		name   string
+		status int
+		ctype  string
+		body   string
+	}{
+		{"challenge page", http.StatusOK, "text/html; charset=UTF-8", page},
+		{"error page", http.StatusForbidden, "text/html", page},
+		{"truncated json", http.StatusOK, "application/json", `{"id":1,"username":"us`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", tt.ctype)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			c := NewClient(srv.URL, "token", "", 5*time.Second)
+			_, err := c.GetUser()
+			var nonJSON *NonJSONResponseError
+			if !errors.As(err, &nonJSON) {
+				t.Fatalf("GetUser() error = %v, want NonJSONResponseError", err)
+			}
+			if nonJSON.StatusCode != tt.status || nonJSON.ContentType != tt.ctype {
+				t.Errorf("error = %+v, want status %d and content type %q", nonJSON, tt.status, tt.ctype)
+			}
+			if len(nonJSON.Snippet) > maxErrorSnippet+len("…") {
+				t.Errorf("snippet is %d bytes, want at most %d", len(nonJSON.Snippet), maxErrorSnippet)
+			}
+			if !strings.Contains(err.Error(), "RD may be behind a challenge") {
+				t.Errorf("error message = %q", err.Error())
+			}
+		})
+	}
+}
+
+// TestEmptyResponse verifies empty successful bodies, as sent by 204 endpoints, are not errors.
+func TestEmptyResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "token", "", 5*time.Second)
+	if err := c.DeleteTorrent("ABC"); err != nil {
+		t.Errorf("DeleteTorrent() error = %v", err)
+	}
+}
+
+// fakeLinkChecker answers CheckLink from a table, tracking how many checks run at once.
+type fakeLinkChecker struct {
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+	results  map[string]*LinkCheck // nil entry means the check fails
+}
+
+func (f *fakeLinkChecker) CheckLink(link string) (*LinkCheck, error) {
+	f.mu.Lock()
+	f.inFlight++
+	f.peak = max(f.peak, f.inFlight)
+	f.mu.Unlock()
+	time.Sleep(10 * time.Millisecond)
+	f.mu.Lock()
+	f.inFlight--
+	f.mu.Unlock()
+
+	if check := f.results[link]; check != nil {
+		copied := *check
+		return &copied, nil
+	}
+	return nil, &APIError{ErrorCode: 19, ErrorMessage: "hoster_unavailable"}
+}
+
+// TestValidTorrentLinks verifies dead and unsupported links are skipped, order is kept
+// and no more than the given number of checks run at once.
+func TestValidTorrentLinks(t *testing.T) {
+	f := &fakeLinkChecker{results: map[string]*LinkCheck{
+		"https://rd/d/1": {Filename: "a.mkv", Filesize: 100, Supported: 1},
+		"https://rd/d/2": {Filename: "b.mkv", Supported: 0},
+		"https://rd/d/4": {Link: "https://rd/d/4", Filename: "d.mkv", Filesize: 400, Supported: 1},
+		"https://rd/d/5": {Filename: "e.mkv", Filesize: 500, Supported: 1},
+	}}
+	torrent := &Torrent{Links: []string{"https://rd/d/1", "https://rd/d/2", "https://rd/d/3", "https://rd/d/4", "", "https://rd/d/5"}}
+
+	valid := ValidTorrentLinks(f, torrent, 2)
+
+	var got []string
+	for _, l := range valid {
+		got = append(got, fmt.Sprintf("%s %d", l.Link, l.Filesize))
+	}
+	want := []string{"https://rd/d/1 100", "https://rd/d/4 400", "https://rd/d/5 500"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ValidTorrentLinks() = %v, want %v", got, want)
+	}
+	if f.peak > 2 {
+		t.Errorf("peak concurrent checks = %d, want at most 2", f.peak)
+	}
+}
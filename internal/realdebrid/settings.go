@@ -0,0 +1,61 @@
+package realdebrid
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// SettingNames lists the account settings that can be changed with UpdateSettings
+var SettingNames = []string{
+	"download_port",
+	"locale",
+	"streaming_language_preference",
+	"streaming_quality",
+	"mobile_streaming_quality",
+	"streaming_cast_audio_preference",
+}
+
+// Settings represents the current user's account settings
+type Settings struct {
+	DownloadPorts                []string          `json:"download_ports"`
+	DownloadPort                 string            `json:"download_port"`
+	Locales                      map[string]string `json:"locales"`
+	Locale                       string            `json:"locale"`
+	StreamingQualities           []string          `json:"streaming_qualities"`
+	StreamingQuality             string            `json:"streaming_quality"`
+	MobileStreamingQuality       string            `json:"mobile_streaming_quality"`
+	StreamingLanguagePreference  string            `json:"streaming_language_preference"`
+	StreamingCastAudioPreference string            `json:"streaming_cast_audio_preference"`
+}
+
+// GetSettings retrieves the current user's account settings
+func (c *Client) GetSettings() (*Settings, error) {
+	respBody, err := c.GET("/settings", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+
+	var settings Settings
+	if err := json.Unmarshal(respBody, &settings); err != nil {
+		return nil, fmt.Errorf("failed to decode settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// UpdateSettings changes a single account setting. name must be one of SettingNames.
+func (c *Client) UpdateSettings(name, value string) error {
+	if !slices.Contains(SettingNames, name) {
+		return fmt.Errorf("unknown setting %q", name)
+	}
+
+	formData := map[string]string{
+		"setting_name":  name,
+		"setting_value": value,
+	}
+	if _, err := c.POSTForm("/settings/update", formData); err != nil {
+		return fmt.Errorf("failed to update setting %s: %w", name, err)
+	}
+	return nil
+}
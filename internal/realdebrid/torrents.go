@@ -1,9 +1,11 @@
 package realdebrid
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/text/cases"
@@ -83,22 +85,9 @@ func (c *Client) GetTorrents(limit, offset int) ([]Torrent, error) {
 
 // GetTorrentsWithCount retrieves all torrents with total count from X-Total-Count header
 func (c *Client) GetTorrentsWithCount(limit, offset int) (*TorrentsResult, error) {
-	params := make(map[string]string)
-	if limit > 0 {
-		params["limit"] = fmt.Sprintf("%d", limit)
-	}
-	if offset > 0 {
-		params["offset"] = fmt.Sprintf("%d", offset)
-	}
-
-	data, totalCount, err := c.GETWithTotalCount("/torrents", params)
+	torrents, totalCount, err := listWithCount[Torrent](c, "/torrents", "torrents", limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get torrents: %w", err)
-	}
-
-	var torrents []Torrent
-	if err := json.Unmarshal(data, &torrents); err != nil {
-		return nil, fmt.Errorf("failed to parse torrents: %w", err)
+		return nil, err
 	}
 
 	return &TorrentsResult{
@@ -183,6 +172,51 @@ func (c *Client) SelectAllFiles(torrentID string) error {
 	return nil
 }
 
+// FileSelector is the part of the client used by SelectAllFilesWhenReady
+type FileSelector interface {
+	GetTorrentInfo(torrentID string) (*Torrent, error)
+	SelectAllFiles(torrentID string) error
+}
+
+// SelectAllFilesWhenReady selects all files of a newly added torrent. Right after a
+// magnet is added Real-Debrid may still be converting it and reject the selection,
+// so on failure the torrent is polled every interval and the selection retried once
+// it is waiting for one, until ctx is done. A torrent found already past file
+// selection counts as success.
+func SelectAllFilesWhenReady(ctx context.Context, c FileSelector, torrentID string, interval time.Duration) error {
+	lastErr := c.SelectAllFiles(torrentID)
+	if lastErr == nil {
+		return nil
+	}
+
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gave up selecting files: %w", lastErr)
+		case <-timer.C:
+		}
+
+		torrent, err := c.GetTorrentInfo(torrentID)
+		switch {
+		case err != nil:
+			lastErr = err
+		case torrent.Status == "waiting_files_selection":
+			if lastErr = c.SelectAllFiles(torrentID); lastErr == nil {
+				return nil
+			}
+		case torrent.Status == "magnet_conversion":
+			// Not ready yet, keep waiting
+		case torrent.Status == "magnet_error", torrent.Status == "error", torrent.Status == "virus", torrent.Status == "dead":
+			return fmt.Errorf("torrent failed before files could be selected: %s", FormatStatus(torrent.Status))
+		default:
+			return nil
+		}
+		timer.Reset(interval)
+	}
+}
+
 // DeleteTorrent deletes a torrent
 func (c *Client) DeleteTorrent(torrentID string) error {
 	if err := validateID(torrentID, "torrent"); err != nil {
@@ -212,18 +246,65 @@ func (c *Client) CheckInstantAvailability(hashes []string) (InstantAvailability,
 	return availability, nil
 }
 
-// FormatSize formats bytes to human-readable size
+// SizeUnits selects how FormatSize scales and labels byte counts
+type SizeUnits int32
+
+const (
+	// SizeUnitsLegacy divides by 1024 but labels units KB, MB, ... (the historical output)
+	SizeUnitsLegacy SizeUnits = iota
+	// SizeUnitsBinary divides by 1024 and uses IEC labels: KiB, MiB, ...
+	SizeUnitsBinary
+	// SizeUnitsDecimal divides by 1000 and uses SI labels: KB, MB, ...
+	SizeUnitsDecimal
+)
+
+// sizeUnits is the mode used by FormatSize, set once at startup via SetSizeUnits
+var sizeUnits atomic.Int32
+
+// ParseSizeUnits maps the app.size_units config value to a SizeUnits mode.
+// An empty value selects SizeUnitsLegacy.
+func ParseSizeUnits(s string) (SizeUnits, error) {
+	switch strings.ToLower(s) {
+	case "":
+		return SizeUnitsLegacy, nil
+	case "binary":
+		return SizeUnitsBinary, nil
+	case "decimal":
+		return SizeUnitsDecimal, nil
+	default:
+		return SizeUnitsLegacy, fmt.Errorf("invalid size units %q (must be binary or decimal)", s)
+	}
+}
+
+// SetSizeUnits sets the mode used by FormatSize
+func SetSizeUnits(u SizeUnits) {
+	sizeUnits.Store(int32(u))
+}
+
+// FormatSize formats bytes to human-readable size using the configured units
 func FormatSize(bytes int64) string {
-	const unit = 1024
+	return FormatSizeUnits(bytes, SizeUnits(sizeUnits.Load()))
+}
+
+// FormatSizeUnits formats bytes to human-readable size using the given units
+func FormatSizeUnits(bytes int64, u SizeUnits) string {
+	unit, suffix := int64(1024), "B"
+	switch u {
+	case SizeUnitsBinary:
+		suffix = "iB"
+	case SizeUnitsDecimal:
+		unit = 1000
+	}
+
 	if bytes < unit {
 		return fmt.Sprintf("%d B", bytes)
 	}
-	div, exp := int64(unit), 0
+	div, exp := unit, 0
 	for n := bytes / unit; n >= unit; n /= unit {
 		div *= unit
 		exp++
 	}
-	return fmt.Sprintf("%.2f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+	return fmt.Sprintf("%.2f %c%s", float64(bytes)/float64(div), "KMGTPE"[exp], suffix)
 }
 
 // FormatStatus formats a torrent status identifier into a user-friendly label.
@@ -0,0 +1,50 @@
+package realdebrid
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// HostTraffic is the traffic quota of a metered hoster
+type HostTraffic struct {
+	Left  int64  `json:"left"`  // Bytes or links still available
+	Bytes int64  `json:"bytes"` // Bytes downloaded in the current period
+	Links int64  `json:"links"` // Links unrestricted in the current period
+	Limit int64  `json:"limit"` // Quota of the period, in the unit given by Type
+	Type  string `json:"type"`  // "links", "gigabytes" or "bytes"
+	Extra int64  `json:"extra"` // Additional traffic or links bought
+	Reset string `json:"reset"` // "daily", "weekly" or "monthly"
+}
+
+// UsedPercent returns how much of the quota has been used, from 0 to 100. Hosts
+// without a known quota report 0.
+func (t HostTraffic) UsedPercent() float64 {
+	var used, total float64
+	if t.Type == "links" {
+		used, total = float64(t.Links), float64(t.Limit+t.Extra)
+	} else {
+		used, total = float64(t.Bytes), float64(t.Bytes+t.Left)
+	}
+	if total <= 0 {
+		return 0
+	}
+	return min(used/total*100, 100)
+}
+
+// Traffic maps hoster domains to their traffic quota
+type Traffic map[string]HostTraffic
+
+// GetTraffic retrieves the traffic quotas of the account's metered hosters
+func (c *Client) GetTraffic() (Traffic, error) {
+	respBody, err := c.GET("/traffic", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get traffic: %w", err)
+	}
+
+	var traffic Traffic
+	if err := json.Unmarshal(respBody, &traffic); err != nil {
+		return nil, fmt.Errorf("failed to decode traffic: %w", err)
+	}
+
+	return traffic, nil
+}
@@ -3,6 +3,7 @@ package realdebrid
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 )
 
@@ -50,6 +51,88 @@ func (c *Client) UnrestrictLink(link string) (*UnrestrictedLink, error) {
 	return &unrestricted, nil
 }
 
+// LinkCheck represents the result of checking a hoster link without unrestricting it
+type LinkCheck struct {
+	Host      string `json:"host"`
+	Link      string `json:"link"`
+	Filename  string `json:"filename"`
+	Filesize  int64  `json:"filesize"`
+	Supported int    `json:"supported"`
+}
+
+// IsSupported reports whether RD can unrestrict the checked link
+func (l *LinkCheck) IsSupported() bool {
+	return l.Supported == 1
+}
+
+// CheckLink checks whether a link is supported and returns its host and size.
+// Unlike UnrestrictLink, this does not generate a download or consume traffic.
+func (c *Client) CheckLink(link string) (*LinkCheck, error) {
+	formData := map[string]string{
+		"link": link,
+	}
+
+	data, err := c.POSTForm("/unrestrict/check", formData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check link: %w", err)
+	}
+
+	var check LinkCheck
+	if err := json.Unmarshal(data, &check); err != nil {
+		return nil, fmt.Errorf("failed to parse link check: %w", err)
+	}
+
+	return &check, nil
+}
+
+// LinkChecker is the part of the client used by ValidTorrentLinks
+type LinkChecker interface {
+	CheckLink(link string) (*LinkCheck, error)
+}
+
+// ValidTorrentLinks checks every hoster link of a finished torrent and returns the ones
+// Real-Debrid can still unrestrict, with their sizes, in the torrent's order. Links of
+// older torrents expire on the hoster side while still listed by /torrents/info. At most
+// concurrency checks run at once; a link whose check fails counts as dead.
+func ValidTorrentLinks(c LinkChecker, t *Torrent, concurrency int) []LinkCheck {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	checks := make([]*LinkCheck, len(t.Links))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, link := range t.Links {
+		if link == "" {
+			continue
+		}
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			check, err := c.CheckLink(link)
+			if err != nil || !check.IsSupported() {
+				return
+			}
+			if check.Link == "" {
+				check.Link = link
+			}
+			checks[i] = check
+		}()
+	}
+	wg.Wait()
+
+	valid := make([]LinkCheck, 0, len(checks))
+	for _, check := range checks {
+		if check != nil {
+			valid = append(valid, *check)
+		}
+	}
+	return valid
+}
+
 // DownloadsResult wraps downloads list with pagination metadata
 type DownloadsResult struct {
 	Downloads  []Download `json:"downloads"`
@@ -67,22 +150,9 @@ func (c *Client) GetDownloads(limit, offset int) ([]Download, error) {
 
 // GetDownloadsWithCount retrieves download history with total count from X-Total-Count header
 func (c *Client) GetDownloadsWithCount(limit, offset int) (*DownloadsResult, error) {
-	params := make(map[string]string)
-	if limit > 0 {
-		params["limit"] = fmt.Sprintf("%d", limit)
-	}
-	if offset > 0 {
-		params["offset"] = fmt.Sprintf("%d", offset)
-	}
-
-	data, totalCount, err := c.GETWithTotalCount("/downloads", params)
+	downloads, totalCount, err := listWithCount[Download](c, "/downloads", "downloads", limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get downloads: %w", err)
-	}
-
-	var downloads []Download
-	if err := json.Unmarshal(data, &downloads); err != nil {
-		return nil, fmt.Errorf("failed to parse downloads: %w", err)
+		return nil, err
 	}
 
 	return &DownloadsResult{
@@ -0,0 +1,80 @@
+// Package retry runs operations again after transient failures, waiting with
+// exponential backoff and jitter between attempts.
+package retry
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Policy describes how often and how patiently an operation is retried
+type Policy struct {
+	Attempts     int           // Total tries including the first; 0 or less retries until ctx is done
+	InitialDelay time.Duration // Wait after the first failure
+	MaxDelay     time.Duration // Upper bound for a single wait; 0 means no bound
+	Multiplier   float64       // Growth of the wait per failure; 0 means 2
+	Jitter       float64       // Randomizes each wait by up to +- this fraction, e.g. 0.2
+
+	// IsRetryable reports whether an error is worth another attempt; nil retries every error
+	IsRetryable func(error) bool
+	// OnRetry is called before waiting, e.g. to log the failure; optional
+	OnRetry func(attempt int, err error, wait time.Duration)
+}
+
+// Delay returns the wait before the next try after attempt failed attempts, without jitter
+func (p Policy) Delay(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+	d := float64(p.InitialDelay) * math.Pow(multiplier, float64(max(attempt-1, 0)))
+	if p.MaxDelay > 0 && d > float64(p.MaxDelay) {
+		return p.MaxDelay
+	}
+	if d > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(d)
+}
+
+// jittered spreads d by up to +- p.Jitter, never going below p.InitialDelay
+func (p Policy) jittered(d time.Duration) time.Duration {
+	if p.Jitter <= 0 {
+		return d
+	}
+	ratio := rand.Float64()*2 - 1 // -1.0 to +1.0
+	return max(d+time.Duration(float64(d)*p.Jitter*ratio), p.InitialDelay)
+}
+
+// Do calls fn until it succeeds, returns an error the policy does not retry, the
+// attempts are used up or ctx is done. It returns the last error from fn, or the
+// context error wrapping it when ctx ended the retries.
+func Do(ctx context.Context, p Policy, fn func(context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if p.IsRetryable != nil && !p.IsRetryable(err) {
+			return err
+		}
+		if p.Attempts > 0 && attempt >= p.Attempts {
+			return err
+		}
+
+		wait := p.jittered(p.Delay(attempt))
+		if p.OnRetry != nil {
+			p.OnRetry(attempt, err, wait)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestPolicy_Delay verifies the wait grows by the multiplier and stops at the cap.
+func TestPolicy_Delay(t *testing.T) {
+	p := Policy{InitialDelay: time.Second, MaxDelay: 5 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := p.Delay(i + 1); got != w {
+			t.Errorf("Delay(%d) = %s, want %s", i+1, got, w)
+		}
+	}
+
+	p = Policy{InitialDelay: 100 * time.Millisecond, Multiplier: 3}
+	if got := p.Delay(3); got != 900*time.Millisecond {
+		t.Errorf("Delay(3) with multiplier 3 = %s, want 900ms", got)
+	}
+}
+
+// TestPolicy_Jitter verifies jittered waits stay within the configured spread.
+func TestPolicy_Jitter(t *testing.T) {
+	p := Policy{InitialDelay: time.Second, Jitter: 0.2}
+	for range 100 {
+		got := p.jittered(10 * time.Second)
+		if got < 8*time.Second || got > 12*time.Second {
+			t.Fatalf("jittered(10s) = %s, want within 8s-12s", got)
+		}
+	}
+	if got := p.jittered(time.Second); got < time.Second {
+		t.Errorf("jittered(1s) = %s, want at least the initial delay", got)
+	}
+}
+
+// TestDo_MaxAttempts verifies fn is tried exactly Attempts times and the last error is returned.
+func TestDo_MaxAttempts(t *testing.T) {
+	errBoom := errors.New("boom")
+	calls := 0
+	var waits []time.Duration
+	p := Policy{
+		Attempts:     3,
+		InitialDelay: time.Millisecond,
+		OnRetry:      func(_ int, _ error, wait time.Duration) { waits = append(waits, wait) },
+	}
+
+	err := Do(context.Background(), p, func(context.Context) error {
+		calls++
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Errorf("Do() error = %v, want boom", err)
+	}
+	if calls != 3 {
+		t.Errorf("fn called %d times, want 3", calls)
+	}
+	if len(waits) != 2 || waits[0] != time.Millisecond || waits[1] != 2*time.Millisecond {
+		t.Errorf("waits = %v, want [1ms 2ms]", waits)
+	}
+}
+
+// TestDo_SucceedsAfterRetry verifies retries stop at the first success.
+func TestDo_SucceedsAfterRetry(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), Policy{Attempts: 5, InitialDelay: time.Millisecond}, func(context.Context) error {
+		calls++
+		if calls < 2 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	if err != nil || calls != 2 {
+		t.Errorf("Do() = %v after %d calls, want nil after 2", err, calls)
+	}
+}
+
+// TestDo_NotRetryable verifies errors rejected by IsRetryable are returned at once.
+func TestDo_NotRetryable(t *testing.T) {
+	errFatal := errors.New("fatal")
+	calls := 0
+	p := Policy{Attempts: 5, InitialDelay: time.Millisecond, IsRetryable: func(err error) bool { return !errors.Is(err, errFatal) }}
+
+	err := Do(context.Background(), p, func(context.Context) error {
+		calls++
+		return errFatal
+	})
+	if !errors.Is(err, errFatal) || calls != 1 {
+		t.Errorf("Do() = %v after %d calls, want fatal after 1", err, calls)
+	}
+}
+
+// TestDo_ContextCancelled verifies unlimited retries end when the context is done.
+func TestDo_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := Do(ctx, Policy{InitialDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}, func(context.Context) error {
+		return errors.New("down")
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Do() error = %v, want deadline exceeded", err)
+	}
+}
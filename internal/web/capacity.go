@@ -0,0 +1,57 @@
+package web
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// SlotUsage is how much of one limited account resource is in use
+type SlotUsage struct {
+	Used      int64 `json:"used"`
+	Limit     int64 `json:"limit"`     // 0 when Unlimited or Unknown
+	Available int64 `json:"available"` // -1 when Unlimited or Unknown
+	Unlimited bool  `json:"unlimited"`
+	Unknown   bool  `json:"unknown"` // the usage could not be retrieved
+}
+
+// Capacity is what the shared Real-Debrid account has left. Real-Debrid only publishes
+// a limit for active torrents; link generation has no documented concurrency limit, so
+// it is bounded by the premium time alone.
+type Capacity struct {
+	ActiveTorrents SlotUsage `json:"active_torrents"`
+	PremiumSeconds int64     `json:"premium_seconds"`
+	Premium        bool      `json:"premium"` // link generation is available
+	ScrapedAt      time.Time `json:"scraped_at"`
+}
+
+// newSlotUsage builds a SlotUsage, treating a non-positive limit as unlimited. When
+// the usage was never retrieved it is reported as unknown rather than unlimited.
+func newSlotUsage(used, limit int64, known bool) SlotUsage {
+	if !known {
+		return SlotUsage{Available: -1, Unknown: true}
+	}
+	if limit <= 0 {
+		return SlotUsage{Used: used, Available: -1, Unlimited: true}
+	}
+	return SlotUsage{Used: used, Limit: limit, Available: max(limit-used, 0)}
+}
+
+// BuildCapacity assembles the capacity view from a metrics summary
+func BuildCapacity(s MetricsSummary) Capacity {
+	return Capacity{
+		ActiveTorrents: newSlotUsage(s.ActiveCount, s.ActiveLimit, s.ActiveKnown),
+		PremiumSeconds: s.PremiumSeconds,
+		Premium:        s.PremiumSeconds > 0,
+		ScrapedAt:      s.ScrapedAt,
+	}
+}
+
+// GetCapacity returns the account's used and available capacity, served from the
+// metrics cache so polling it costs no extra Real-Debrid calls
+func (d *Dependencies) GetCapacity(c fiber.Ctx) error {
+	if d.Metrics == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Metrics are not available")
+	}
+	return c.JSON(fiber.Map{"success": true, "data": BuildCapacity(d.Metrics.Summary())})
+}
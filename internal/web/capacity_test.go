@@ -0,0 +1,62 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestBuildCapacity verifies limited and unlimited slot accounting.
+func TestBuildCapacity(t *testing.T) {
+	limited := BuildCapacity(MetricsSummary{ActiveCount: 3, ActiveLimit: 5, ActiveKnown: true, PremiumSeconds: 60})
+	if want := (SlotUsage{Used: 3, Limit: 5, Available: 2}); limited.ActiveTorrents != want {
+		t.Errorf("limited slots = %+v, want %+v", limited.ActiveTorrents, want)
+	}
+	if !limited.Premium {
+		t.Error("Premium = false with premium time left")
+	}
+
+	unlimited := BuildCapacity(MetricsSummary{ActiveCount: 12, ActiveKnown: true})
+	if want := (SlotUsage{Used: 12, Available: -1, Unlimited: true}); unlimited.ActiveTorrents != want {
+		t.Errorf("unlimited slots = %+v, want %+v", unlimited.ActiveTorrents, want)
+	}
+	if unlimited.Premium {
+		t.Error("Premium = true without premium time")
+	}
+
+	if over := BuildCapacity(MetricsSummary{ActiveCount: 7, ActiveLimit: 5, ActiveKnown: true}); over.ActiveTorrents.Available != 0 {
+		t.Errorf("over-limit available = %d, want 0", over.ActiveTorrents.Available)
+	}
+
+	if unknown := BuildCapacity(MetricsSummary{}); !unknown.ActiveTorrents.Unknown || unknown.ActiveTorrents.Unlimited {
+		t.Errorf("unscraped slots = %+v, want unknown, not unlimited", unknown.ActiveTorrents)
+	}
+}
+
+// TestGetCapacity verifies the endpoint assembles the view from the scraped active count and user.
+func TestGetCapacity(t *testing.T) {
+	fake := &fakeRDClient{
+		user:   &realdebrid.User{Premium: 3600},
+		active: &realdebrid.ActiveCount{Nb: 4, Limit: 10},
+	}
+	deps := &Dependencies{RDClient: fake}
+	deps.Metrics = NewRDCollector(*deps)
+	app := fiber.New()
+	app.Get("/api/capacity", deps.GetCapacity)
+
+	status, body := doRequest(t, app, httptest.NewRequest(http.MethodGet, "/api/capacity", nil))
+	if status != fiber.StatusOK {
+		t.Fatalf("status = %d, want %d", status, fiber.StatusOK)
+	}
+	data, _ := body["data"].(map[string]any)
+	slots, _ := data["active_torrents"].(map[string]any)
+	if slots["used"] != float64(4) || slots["limit"] != float64(10) || slots["available"] != float64(6) {
+		t.Errorf("active_torrents = %v, want 4 of 10 used", slots)
+	}
+	if data["premium"] != true {
+		t.Errorf("premium = %v, want true", data["premium"])
+	}
+}
@@ -22,6 +22,9 @@ type RDCollector struct {
 	cachedUserPoints     float64
 	cachedPremiumSeconds float64
 	cachedActiveCount    float64
+	cachedActiveLimit    int   // torrent slot limit; not exported to Prometheus
+	activeKnown          bool  // the active count and limit have been scraped at least once
+	userErr              error // outcome of the last account lookup, for the detailed health check
 
 	// Descriptors
 	torrentsCountDesc  *prometheus.Desc
@@ -85,11 +88,7 @@ func (c *RDCollector) Describe(ch chan<- *prometheus.Desc) {
 func (c *RDCollector) Collect(ch chan<- prometheus.Metric) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-
-	// Check cache
-	if time.Since(c.lastScrape) > c.cacheDuration {
-		c.scrape()
-	}
+	c.refreshLocked()
 
 	// Emit metrics from cache
 	ch <- prometheus.MustNewConstMetric(c.torrentsCountDesc, prometheus.GaugeValue, c.cachedTorrentCount)
@@ -100,6 +99,65 @@ func (c *RDCollector) Collect(ch chan<- prometheus.Metric) {
 	ch <- prometheus.MustNewConstMetric(c.activeCountDesc, prometheus.GaugeValue, c.cachedActiveCount)
 }
 
+// MetricsSummary is a point-in-time copy of the values exported by RDCollector
+type MetricsSummary struct {
+	TorrentCount   int64     `json:"torrent_count"`
+	DownloadCount  int64     `json:"download_count"`
+	TotalSizeBytes int64     `json:"total_size_bytes"`
+	FidelityPoints int64     `json:"fidelity_points"`
+	PremiumSeconds int64     `json:"premium_seconds"`
+	ActiveCount    int64     `json:"active_count"`
+	ActiveLimit    int64     `json:"active_limit"`       // 0 when the account has no slot limit
+	ActiveKnown    bool      `json:"active_limit_known"` // false until the active count has been scraped
+	ScrapedAt      time.Time `json:"scraped_at"`
+}
+
+// Summary returns the cached metric values, scraping first only if the cache has expired,
+// so it costs no extra Real-Debrid calls between Prometheus scrapes
+func (c *RDCollector) Summary() MetricsSummary {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refreshLocked()
+
+	return MetricsSummary{
+		TorrentCount:   int64(c.cachedTorrentCount),
+		DownloadCount:  int64(c.cachedDownloadCount),
+		TotalSizeBytes: int64(c.cachedTotalSize),
+		FidelityPoints: int64(c.cachedUserPoints),
+		PremiumSeconds: int64(c.cachedPremiumSeconds),
+		ActiveCount:    int64(c.cachedActiveCount),
+		ActiveLimit:    int64(c.cachedActiveLimit),
+		ActiveKnown:    c.activeKnown,
+		ScrapedAt:      c.lastScrape,
+	}
+}
+
+// UserStatus returns when Real-Debrid was last scraped and whether its account lookup
+// failed, scraping first only if the cache has expired
+func (c *RDCollector) UserStatus() (time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refreshLocked()
+	return c.lastScrape, c.userErr
+}
+
+// ForceScrape scrapes Real-Debrid now, regardless of the cache age, and returns how
+// long the scrape took. Concurrent Prometheus scrapes and summaries wait for it.
+func (c *RDCollector) ForceScrape() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	start := time.Now()
+	c.scrape()
+	return time.Since(start)
+}
+
+// refreshLocked scrapes Real-Debrid if the cache has expired; the caller must hold c.mu
+func (c *RDCollector) refreshLocked() {
+	if time.Since(c.lastScrape) > c.cacheDuration {
+		c.scrape()
+	}
+}
+
 func (c *RDCollector) scrape() {
 	log.Println("Scraping Real-Debrid metrics (refreshing cache)...")
 
@@ -148,6 +206,7 @@ func (c *RDCollector) scrape() {
 
 	// 3. User Info (Points, Premium)
 	user, err := c.deps.RDClient.GetUser()
+	c.userErr = err
 	if err == nil {
 		c.cachedUserPoints = float64(user.Points)
 		c.cachedPremiumSeconds = float64(user.Premium)
@@ -159,6 +218,8 @@ func (c *RDCollector) scrape() {
 	activeCount, err := c.deps.RDClient.GetActiveCount()
 	if err == nil {
 		c.cachedActiveCount = float64(activeCount.Nb)
+		c.cachedActiveLimit = activeCount.Limit
+		c.activeKnown = true
 	} else {
 		log.Printf("Error scraping active count: %v", err)
 	}
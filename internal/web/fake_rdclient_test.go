@@ -0,0 +1,135 @@
+package web
+
+import (
+	"fmt"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+)
+
+// fakeRDClient is an in-memory RealDebridClient for handler tests.
+type fakeRDClient struct {
+	torrents  []realdebrid.Torrent
+	downloads []realdebrid.Download
+	user      *realdebrid.User
+	active    *realdebrid.ActiveCount // returned by GetActiveCount when set
+	err       error                   // returned by every call when set
+	selectErr error                   // returned by SelectAllFiles when set
+
+	added    []string // magnets passed to AddMagnet
+	selected []string // torrent IDs passed to SelectAllFiles
+	deleted  []string // torrent IDs passed to DeleteTorrent
+
+	unrestricted []string // links passed to UnrestrictLink
+
+	userCalls int // number of GetUser calls
+}
+
+var _ RealDebridClient = (*fakeRDClient)(nil)
+var _ RealDebridClient = (*realdebrid.Client)(nil)
+
+func (f *fakeRDClient) GetTorrents(limit, offset int) ([]realdebrid.Torrent, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if offset >= len(f.torrents) {
+		return nil, nil
+	}
+	end := min(offset+limit, len(f.torrents))
+	return append([]realdebrid.Torrent(nil), f.torrents[offset:end]...), nil
+}
+
+func (f *fakeRDClient) GetTorrentsWithCount(limit, offset int) (*realdebrid.TorrentsResult, error) {
+	torrents, err := f.GetTorrents(limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &realdebrid.TorrentsResult{Torrents: torrents, TotalCount: len(f.torrents)}, nil
+}
+
+func (f *fakeRDClient) GetActiveCount() (*realdebrid.ActiveCount, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.active != nil {
+		return f.active, nil
+	}
+	return &realdebrid.ActiveCount{}, nil
+}
+
+func (f *fakeRDClient) GetTorrentInfo(torrentID string) (*realdebrid.Torrent, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	for i := range f.torrents {
+		if f.torrents[i].ID == torrentID {
+			t := f.torrents[i]
+			return &t, nil
+		}
+	}
+	return nil, &realdebrid.APIError{ErrorCode: 7, ErrorMessage: "unknown_ressource"}
+}
+
+func (f *fakeRDClient) AddMagnet(magnetURL string) (*realdebrid.AddMagnetResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.added = append(f.added, magnetURL)
+	return &realdebrid.AddMagnetResponse{ID: fmt.Sprintf("NEW%d", len(f.added))}, nil
+}
+
+func (f *fakeRDClient) SelectAllFiles(torrentID string) error {
+	if f.err != nil {
+		return f.err
+	}
+	if f.selectErr != nil {
+		return f.selectErr
+	}
+	f.selected = append(f.selected, torrentID)
+	return nil
+}
+
+func (f *fakeRDClient) DeleteTorrent(torrentID string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.deleted = append(f.deleted, torrentID)
+	return nil
+}
+
+func (f *fakeRDClient) GetUser() (*realdebrid.User, error) {
+	f.userCalls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.user, nil
+}
+
+func (f *fakeRDClient) GetDownloadsWithCount(limit, offset int) (*realdebrid.DownloadsResult, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if offset >= len(f.downloads) {
+		return &realdebrid.DownloadsResult{TotalCount: len(f.downloads)}, nil
+	}
+	end := min(offset+limit, len(f.downloads))
+	return &realdebrid.DownloadsResult{Downloads: f.downloads[offset:end], TotalCount: len(f.downloads)}, nil
+}
+
+func (f *fakeRDClient) UnrestrictLink(link string) (*realdebrid.UnrestrictedLink, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.unrestricted = append(f.unrestricted, link)
+	return &realdebrid.UnrestrictedLink{Link: link, Download: link}, nil
+}
+
+func (f *fakeRDClient) DeleteDownload(_ string) error {
+	return f.err
+}
+
+func (f *fakeRDClient) IsDomainSupported(domain string) (bool, string, error) {
+	if f.err != nil {
+		return false, "", f.err
+	}
+	return true, domain, nil
+}
@@ -1,10 +1,15 @@
 package web
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"log"
+	"net"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/crazyuploader/rdctl-bot/internal/db"
 	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
@@ -43,28 +48,140 @@ func (d *Dependencies) GetStatus(c fiber.Ctx) error {
 	return c.JSON(fiber.Map{"success": true, "data": user})
 }
 
-// GetTorrents retrieves the list of active torrents
+const (
+	// defaultPageSize is used when a paginated request has no usable limit
+	defaultPageSize = 50
+
+	// defaultMaxPageSize caps limit when no web.max_page_size is configured
+	defaultMaxPageSize = 200
+
+	// defaultMaxBodyBytes caps request bodies when no web.max_body_bytes is configured;
+	// large enough for .torrent files
+	defaultMaxBodyBytes = 25 << 20
+)
+
+// maxPageSize returns the configured cap for the limit query parameter
+func (d *Dependencies) maxPageSize() int {
+	if d.Config == nil || d.Config.Web.MaxPageSize <= 0 {
+		return defaultMaxPageSize
+	}
+	return d.Config.Web.MaxPageSize
+}
+
+// maxBodyBytes returns the configured cap on request body size
+func (d *Dependencies) maxBodyBytes() int {
+	if d.Config == nil || d.Config.Web.MaxBodyBytes <= 0 {
+		return defaultMaxBodyBytes
+	}
+	return d.Config.Web.MaxBodyBytes
+}
+
+// bindBody decodes the request body into out. Bodies over maxBodyBytes are rejected
+// with 413; the server's BodyLimit enforces the same cap, this keeps each handler safe
+// on its own and gives clients a JSON error.
+func (d *Dependencies) bindBody(c fiber.Ctx, out any) error {
+	if len(c.Body()) > d.maxBodyBytes() {
+		return fiber.NewError(fiber.StatusRequestEntityTooLarge, fmt.Sprintf("Request body exceeds %d bytes", d.maxBodyBytes()))
+	}
+	if err := c.Bind().Body(out); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	return nil
+}
+
+// parsePagination reads limit and offset from the query string. A missing, unparseable
+// or non-positive limit falls back to the default, and limit is clamped to maxPageSize.
+func parsePagination(c fiber.Ctx, maxPageSize int) (limit, offset int) {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	offset, err = strconv.Atoi(c.Query("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// torrentStatuses are the raw Real-Debrid torrent statuses accepted by ?status=
+var torrentStatuses = map[string]bool{
+	"magnet_error":            true,
+	"magnet_conversion":       true,
+	"waiting_files_selection": true,
+	"queued":                  true,
+	"downloading":             true,
+	"downloaded":              true,
+	"error":                   true,
+	"virus":                   true,
+	"compressing":             true,
+	"uploading":               true,
+	"dead":                    true,
+}
+
+// torrentScanPageSize is the page size used when every torrent has to be fetched
+const torrentScanPageSize = 2500
+
+// GetTorrents retrieves the list of torrents. With ?status=<raw status>, e.g.
+// downloading, only torrents in that state are returned; Real-Debrid cannot filter
+// by status, so every page is scanned and total_count is the size of the filtered set.
 func (d *Dependencies) GetTorrents(c fiber.Ctx) error {
-	limit, _ := strconv.Atoi(c.Query("limit", "50"))
-	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	limit, offset := parsePagination(c, d.maxPageSize())
 
-	result, err := d.RDClient.GetTorrentsWithCount(limit, offset)
-	if err != nil {
-		return err
+	var torrents []realdebrid.Torrent
+	var totalCount int
+	if status := c.Query("status"); status != "" {
+		if !torrentStatuses[status] {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Invalid status %q", status))
+		}
+		matching, err := d.torrentsWithStatus(status)
+		if err != nil {
+			return err
+		}
+		totalCount = len(matching)
+		torrents = matching[min(offset, len(matching)):min(offset+limit, len(matching))]
+	} else {
+		result, err := d.RDClient.GetTorrentsWithCount(limit, offset)
+		if err != nil {
+			return err
+		}
+		torrents, totalCount = result.Torrents, result.TotalCount
 	}
 
 	// Format status and size for frontend convenience
-	for i := range result.Torrents {
-		result.Torrents[i].Status = realdebrid.FormatStatus(result.Torrents[i].Status)
+	for i := range torrents {
+		torrents[i].Status = realdebrid.FormatStatus(torrents[i].Status)
 	}
 
 	return c.JSON(fiber.Map{
 		"success":     true,
-		"data":        result.Torrents,
-		"total_count": result.TotalCount,
+		"data":        torrents,
+		"total_count": totalCount,
 	})
 }
 
+// torrentsWithStatus pages through every torrent and keeps those with the raw status
+func (d *Dependencies) torrentsWithStatus(status string) ([]realdebrid.Torrent, error) {
+	var matching []realdebrid.Torrent
+	for offset := 0; ; offset += torrentScanPageSize {
+		page, err := d.RDClient.GetTorrents(torrentScanPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range page {
+			if t.Status == status {
+				matching = append(matching, t)
+			}
+		}
+		if len(page) < torrentScanPageSize {
+			return matching, nil
+		}
+	}
+}
+
 // GetTorrentInfo retrieves detailed information about a single torrent
 func (d *Dependencies) GetTorrentInfo(c fiber.Ctx) error {
 	id := c.Params("id")
@@ -84,8 +201,8 @@ func (d *Dependencies) AddTorrent(c fiber.Ctx) error {
 	var body struct {
 		Magnet string `json:"magnet"`
 	}
-	if err := c.Bind().Body(&body); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	if err := d.bindBody(c, &body); err != nil {
+		return err
 	}
 
 	if body.Magnet == "" {
@@ -97,13 +214,41 @@ func (d *Dependencies) AddTorrent(c fiber.Ctx) error {
 		return err
 	}
 
-	// Automatically select all files
-	if err := d.RDClient.SelectAllFiles(resp.ID); err != nil {
+	// Automatically select all files. The torrent was added either way, so a failure
+	// is reported as a warning rather than an error.
+	result := fiber.Map{"success": true, "data": resp}
+	if err := d.selectAllFiles(c.Context(), resp.ID); err != nil {
 		log.Printf("Failed to select files for torrent %s: %v", resp.ID, err)
-		// Non-fatal, just log it
+		result["warning"] = fmt.Sprintf("Torrent added, but its files could not be selected: %v", err)
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"success": true, "data": resp})
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// selectFilesPollInterval is how often AddTorrent re-checks a converting magnet
+var selectFilesPollInterval = 2 * time.Second
+
+// selectAllFiles selects all files of a newly added torrent, retrying while the magnet
+// converts for up to web.select_files.timeout_seconds. At most
+// web.select_files.concurrency requests wait at once.
+func (d *Dependencies) selectAllFiles(ctx context.Context, torrentID string) error {
+	timeout := 30 * time.Second
+	if d.Config != nil && d.Config.Web.SelectFiles.TimeoutSeconds > 0 {
+		timeout = time.Duration(d.Config.Web.SelectFiles.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if d.selectSlots != nil {
+		select {
+		case d.selectSlots <- struct{}{}:
+			defer func() { <-d.selectSlots }()
+		case <-ctx.Done():
+			return fmt.Errorf("too many torrents waiting for file selection: %w", ctx.Err())
+		}
+	}
+
+	return realdebrid.SelectAllFilesWhenReady(ctx, d.RDClient, torrentID, selectFilesPollInterval)
 }
 
 // DeleteTorrent deletes a torrent
@@ -121,8 +266,7 @@ func (d *Dependencies) DeleteTorrent(c fiber.Ctx) error {
 
 // GetDownloads retrieves the download history
 func (d *Dependencies) GetDownloads(c fiber.Ctx) error {
-	limit, _ := strconv.Atoi(c.Query("limit", "50"))
-	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	limit, offset := parsePagination(c, d.maxPageSize())
 
 	result, err := d.RDClient.GetDownloadsWithCount(limit, offset)
 	if err != nil {
@@ -155,13 +299,18 @@ func (d *Dependencies) UnrestrictLink(c fiber.Ctx) error {
 	var body struct {
 		Link string `json:"link"`
 	}
-	if err := c.Bind().Body(&body); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	if err := d.bindBody(c, &body); err != nil {
+		return err
 	}
 
 	if body.Link == "" {
 		return fiber.NewError(fiber.StatusBadRequest, "Link is required")
 	}
+	if d.Config != nil {
+		if err := d.Config.CheckLinkHost(body.Link); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+	}
 
 	unrestricted, err := d.RDClient.UnrestrictLink(body.Link)
 	if err != nil {
@@ -201,9 +350,8 @@ func (d *Dependencies) GetStats(c fiber.Ctx) error {
 	var totalBytes int64
 	downloadingCount := 0
 	downloadedCount := 0
-	const pageSize = 2500
-	for offset := 0; ; offset += pageSize {
-		page, err := d.RDClient.GetTorrents(pageSize, offset)
+	for offset := 0; ; offset += torrentScanPageSize {
+		page, err := d.RDClient.GetTorrents(torrentScanPageSize, offset)
 		if err != nil {
 			break
 		}
@@ -216,7 +364,7 @@ func (d *Dependencies) GetStats(c fiber.Ctx) error {
 				downloadedCount++
 			}
 		}
-		if len(page) < pageSize {
+		if len(page) < torrentScanPageSize {
 			break
 		}
 	}
@@ -272,13 +420,77 @@ func (d *Dependencies) GetUserStats(c fiber.Ctx) error {
 	return c.JSON(fiber.Map{"success": true, "data": stats})
 }
 
+// GetUsers lists users with their usage counters for the admin dashboard, paginated
+// with limit and offset. ?sort=total_commands or ?sort=last_seen puts the most active
+// or most recently seen users first; without it users are listed as first recorded.
+func (d *Dependencies) GetUsers(c fiber.Ctx) error {
+	limit, offset := parsePagination(c, d.maxPageSize())
+	sortBy := c.Query("sort")
+	if sortBy != "" && !slices.Contains(db.UserSortFields, sortBy) {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Invalid sort %q, use one of: %s", sortBy, strings.Join(db.UserSortFields, ", ")))
+	}
+
+	users, total, err := d.UserRepo.ListUsers(c.Context(), limit, offset, sortBy)
+	if err != nil {
+		return err
+	}
+	data := make([]fiber.Map, len(users))
+	for i, u := range users {
+		data[i] = fiber.Map{
+			"user_id":         u.UserID,
+			"username":        u.Username,
+			"first_name":      u.FirstName,
+			"last_name":       u.LastName,
+			"is_super_admin":  u.IsSuperAdmin,
+			"is_allowed":      u.IsAllowed,
+			"total_commands":  u.TotalCommands,
+			"total_torrents":  u.TotalTorrents,
+			"total_downloads": u.TotalDownloads,
+			"first_seen_at":   u.FirstSeenAt,
+			"last_seen_at":    u.LastSeenAt,
+		}
+	}
+	return c.JSON(fiber.Map{
+		"success":     true,
+		"data":        data,
+		"total_count": total,
+	})
+}
+
+// GetGlobalStats returns usage totals across all users
+func (d *Dependencies) GetGlobalStats(c fiber.Ctx) error {
+	stats, err := d.CommandRepo.GetGlobalStats(c.Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"success": true, "data": stats})
+}
+
+// GetMetricsSummary returns the cached Prometheus metric values as JSON
+func (d *Dependencies) GetMetricsSummary(c fiber.Ctx) error {
+	if d.Metrics == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Metrics are not available")
+	}
+	return c.JSON(fiber.Map{"success": true, "data": d.Metrics.Summary()})
+}
+
+// RefreshMetrics scrapes Real-Debrid immediately, bypassing the metrics cache, and
+// returns the fresh values with how long the scrape took
+func (d *Dependencies) RefreshMetrics(c fiber.Ctx) error {
+	if d.Metrics == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Metrics are not available")
+	}
+	took := d.Metrics.ForceScrape()
+	return c.JSON(fiber.Map{"success": true, "data": d.Metrics.Summary(), "duration_ms": took.Milliseconds()})
+}
+
 // ExchangeToken exchanges a short-lived code for a real token
 func (d *Dependencies) ExchangeToken(c fiber.Ctx) error {
 	var body struct {
 		Code string `json:"code"`
 	}
-	if err := c.Bind().Body(&body); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	if err := d.bindBody(c, &body); err != nil {
+		return err
 	}
 
 	if body.Code == "" {
@@ -429,8 +641,8 @@ func (d *Dependencies) SetAutoDeleteSetting(c fiber.Ctx) error {
 	var body struct {
 		Value string `json:"value"`
 	}
-	if err := c.Bind().Body(&body); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	if err := d.bindBody(c, &body); err != nil {
+		return err
 	}
 
 	// Validate the value is a valid integer
@@ -462,3 +674,26 @@ func (d *Dependencies) SetAutoDeleteSetting(c fiber.Ctx) error {
 		"message": "Auto-delete setting updated",
 	})
 }
+
+// GetBans lists the IPs banned for repeated authentication failures
+func (d *Dependencies) GetBans(c fiber.Ctx) error {
+	if d.IPManager == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "IP banning is not available")
+	}
+	return c.JSON(fiber.Map{"success": true, "data": d.IPManager.ListBans()})
+}
+
+// DeleteBan lifts the ban of an IP, e.g. one banned by mistake
+func (d *Dependencies) DeleteBan(c fiber.Ctx) error {
+	if d.IPManager == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "IP banning is not available")
+	}
+	ip := c.Params("ip")
+	if net.ParseIP(ip) == nil {
+		return fiber.NewError(fiber.StatusBadRequest, "a valid IP address is required")
+	}
+	if !d.IPManager.Unban(ip) {
+		return fiber.NewError(fiber.StatusNotFound, "IP is not banned")
+	}
+	return c.JSON(fiber.Map{"success": true, "message": "IP unbanned"})
+}
@@ -0,0 +1,416 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/config"
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/gofiber/fiber/v3"
+)
+
+// doRequest runs req against app and decodes the JSON response body.
+func doRequest(t *testing.T, app *fiber.App, req *http.Request) (int, map[string]any) {
+	t.Helper()
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read response body: %v", err)
+	}
+	var body map[string]any
+	if len(raw) > 0 && json.Valid(raw) {
+		if err := json.Unmarshal(raw, &body); err != nil {
+			t.Fatalf("failed to decode response %q: %v", raw, err)
+		}
+	}
+	return resp.StatusCode, body
+}
+
+// TestGetTorrents_UsesClient verifies torrents come from the client with formatted statuses.
+func TestGetTorrents_UsesClient(t *testing.T) {
+	fake := &fakeRDClient{torrents: []realdebrid.Torrent{
+		{ID: "AAA", Filename: "one", Status: "downloaded"},
+		{ID: "BBB", Filename: "two", Status: "downloading"},
+		{ID: "CCC", Filename: "three", Status: "queued"},
+	}}
+	deps := &Dependencies{RDClient: fake}
+	app := fiber.New()
+	app.Get("/api/torrents", deps.GetTorrents)
+
+	status, body := doRequest(t, app, httptest.NewRequest(http.MethodGet, "/api/torrents?limit=2", nil))
+	if status != fiber.StatusOK {
+		t.Fatalf("status = %d, want %d", status, fiber.StatusOK)
+	}
+	if got := body["total_count"]; got != float64(3) {
+		t.Errorf("total_count = %v, want 3", got)
+	}
+	data, _ := body["data"].([]any)
+	if len(data) != 2 {
+		t.Fatalf("len(data) = %d, want 2", len(data))
+	}
+	first, _ := data[0].(map[string]any)
+	if first["status"] != realdebrid.FormatStatus("downloaded") {
+		t.Errorf("status = %v, want %q", first["status"], realdebrid.FormatStatus("downloaded"))
+	}
+}
+
+// TestGetTorrents_StatusFilter verifies ?status= filters by raw status, counts the filtered set and rejects unknown values.
+func TestGetTorrents_StatusFilter(t *testing.T) {
+	fake := &fakeRDClient{torrents: []realdebrid.Torrent{
+		{ID: "AAA", Status: "downloading"},
+		{ID: "BBB", Status: "downloaded"},
+		{ID: "CCC", Status: "downloading"},
+		{ID: "DDD", Status: "downloading"},
+	}}
+	deps := &Dependencies{RDClient: fake}
+	app := fiber.New()
+	app.Get("/api/torrents", deps.GetTorrents)
+
+	status, body := doRequest(t, app, httptest.NewRequest(http.MethodGet, "/api/torrents?status=downloading&limit=2&offset=1", nil))
+	if status != fiber.StatusOK {
+		t.Fatalf("status = %d, want %d", status, fiber.StatusOK)
+	}
+	if got := body["total_count"]; got != float64(3) {
+		t.Errorf("total_count = %v, want 3", got)
+	}
+	data, _ := body["data"].([]any)
+	var ids []any
+	for _, d := range data {
+		ids = append(ids, d.(map[string]any)["id"])
+	}
+	if len(ids) != 2 || ids[0] != "CCC" || ids[1] != "DDD" {
+		t.Errorf("ids = %v, want [CCC DDD]", ids)
+	}
+
+	if status, _ := doRequest(t, app, httptest.NewRequest(http.MethodGet, "/api/torrents?status=bogus", nil)); status != fiber.StatusBadRequest {
+		t.Errorf("unknown status: status = %d, want %d", status, fiber.StatusBadRequest)
+	}
+}
+
+// TestAddTorrent_SelectsAllFiles verifies a new magnet is added and its files selected.
+func TestAddTorrent_SelectsAllFiles(t *testing.T) {
+	fake := &fakeRDClient{}
+	deps := &Dependencies{RDClient: fake}
+	app := fiber.New()
+	app.Post("/api/torrents", deps.AddTorrent)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/torrents", strings.NewReader(`{"magnet":"magnet:?xt=urn:btih:abc"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	status, _ := doRequest(t, app, req)
+	if status != fiber.StatusCreated {
+		t.Fatalf("status = %d, want %d", status, fiber.StatusCreated)
+	}
+	if len(fake.added) != 1 || fake.added[0] != "magnet:?xt=urn:btih:abc" {
+		t.Errorf("added = %v, want the posted magnet", fake.added)
+	}
+	if len(fake.selected) != 1 || fake.selected[0] != "NEW1" {
+		t.Errorf("selected = %v, want [NEW1]", fake.selected)
+	}
+}
+
+// TestAddTorrent_SelectionWarning verifies a torrent whose files can't be selected is still
+// reported as added, with a warning explaining why.
+func TestAddTorrent_SelectionWarning(t *testing.T) {
+	fake := &fakeRDClient{
+		torrents:  []realdebrid.Torrent{{ID: "NEW1", Status: "magnet_error"}},
+		selectErr: &realdebrid.APIError{ErrorCode: 19, ErrorMessage: "action_already_done"},
+	}
+	deps := &Dependencies{RDClient: fake}
+	app := fiber.New()
+	app.Post("/api/torrents", deps.AddTorrent)
+
+	defer func(d time.Duration) { selectFilesPollInterval = d }(selectFilesPollInterval)
+	selectFilesPollInterval = time.Millisecond
+
+	req := httptest.NewRequest(http.MethodPost, "/api/torrents", strings.NewReader(`{"magnet":"magnet:?xt=urn:btih:abc"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	status, body := doRequest(t, app, req)
+	if status != fiber.StatusCreated {
+		t.Fatalf("status = %d, want %d", status, fiber.StatusCreated)
+	}
+	warning, _ := body["warning"].(string)
+	if !strings.Contains(warning, "Magnet Error") {
+		t.Errorf("warning = %q, want it to mention the magnet error", warning)
+	}
+}
+
+// TestAddTorrent_MissingMagnet verifies the client is not called without a magnet.
+func TestAddTorrent_MissingMagnet(t *testing.T) {
+	fake := &fakeRDClient{}
+	deps := &Dependencies{RDClient: fake}
+	app := fiber.New()
+	app.Post("/api/torrents", deps.AddTorrent)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/torrents", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	status, _ := doRequest(t, app, req)
+	if status != fiber.StatusBadRequest {
+		t.Errorf("status = %d, want %d", status, fiber.StatusBadRequest)
+	}
+	if len(fake.added) != 0 {
+		t.Errorf("AddMagnet called %d times, want 0", len(fake.added))
+	}
+}
+
+// TestParsePagination verifies limit defaults and clamping for paginated endpoints.
+func TestParsePagination(t *testing.T) {
+	app := fiber.New()
+	app.Get("/page", func(c fiber.Ctx) error {
+		limit, offset := parsePagination(c, 200)
+		return c.JSON(fiber.Map{"limit": limit, "offset": offset})
+	})
+
+	tests := []struct {
+		query      string
+		wantLimit  float64
+		wantOffset float64
+	}{
+		{"", 50, 0},
+		{"?limit=20&offset=40", 20, 40},
+		{"?limit=1000000", 200, 0},
+		{"?limit=200", 200, 0},
+		{"?limit=abc", 50, 0},
+		{"?limit=0", 50, 0},
+		{"?limit=-5&offset=-10", 50, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, body := doRequest(t, app, httptest.NewRequest(http.MethodGet, "/page"+tt.query, nil))
+			if body["limit"] != tt.wantLimit || body["offset"] != tt.wantOffset {
+				t.Errorf("parsePagination(%q) = (%v, %v), want (%v, %v)", tt.query, body["limit"], body["offset"], tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+}
+
+// TestGetTorrents_ClampsLimit verifies an oversized limit is capped at web.max_page_size.
+func TestGetTorrents_ClampsLimit(t *testing.T) {
+	torrents := make([]realdebrid.Torrent, 10)
+	for i := range torrents {
+		torrents[i] = realdebrid.Torrent{ID: fmt.Sprintf("T%d", i), Status: "downloaded"}
+	}
+	deps := &Dependencies{
+		RDClient: &fakeRDClient{torrents: torrents},
+		Config:   &config.Config{Web: config.WebConfig{MaxPageSize: 3}},
+	}
+	app := fiber.New()
+	app.Get("/api/torrents", deps.GetTorrents)
+
+	_, body := doRequest(t, app, httptest.NewRequest(http.MethodGet, "/api/torrents?limit=1000000", nil))
+	if data, _ := body["data"].([]any); len(data) != 3 {
+		t.Errorf("len(data) = %d, want 3", len(data))
+	}
+}
+
+// TestGetUsers_InvalidSort verifies a sort outside the allow-list is rejected with 400
+// before the database is touched.
+func TestGetUsers_InvalidSort(t *testing.T) {
+	deps := &Dependencies{}
+	app := fiber.New()
+	app.Get("/api/users", deps.GetUsers)
+
+	for _, sortBy := range []string{"id", "total_commands;DROP%20TABLE%20users", "TOTAL_COMMANDS"} {
+		status, _ := doRequest(t, app, httptest.NewRequest(http.MethodGet, "/api/users?sort="+sortBy, nil))
+		if status != fiber.StatusBadRequest {
+			t.Errorf("sort=%s: status = %d, want %d", sortBy, status, fiber.StatusBadRequest)
+		}
+	}
+}
+
+// TestMetricsSummary_UsesCache verifies the summary reports the scraped values and
+// serves repeat calls from the collector's cache instead of calling RD again.
+func TestMetricsSummary_UsesCache(t *testing.T) {
+	fake := &fakeRDClient{
+		torrents:  []realdebrid.Torrent{{ID: "A", Bytes: 100}, {ID: "B", Bytes: 250}},
+		downloads: []realdebrid.Download{{ID: "D"}},
+		user:      &realdebrid.User{Points: 42, Premium: 3600},
+	}
+	collector := NewRDCollector(Dependencies{RDClient: fake})
+
+	first := collector.Summary()
+	want := MetricsSummary{TorrentCount: 2, DownloadCount: 1, TotalSizeBytes: 350, FidelityPoints: 42, PremiumSeconds: 3600, ActiveKnown: true}
+	want.ScrapedAt = first.ScrapedAt
+	if first != want {
+		t.Errorf("Summary() = %+v, want %+v", first, want)
+	}
+	if first.ScrapedAt.IsZero() {
+		t.Error("ScrapedAt is zero after a scrape")
+	}
+
+	fake.user = &realdebrid.User{Points: 99}
+	if second := collector.Summary(); second != first {
+		t.Errorf("second Summary() = %+v, want cached %+v", second, first)
+	}
+	if fake.userCalls != 1 {
+		t.Errorf("GetUser called %d times, want 1", fake.userCalls)
+	}
+}
+
+// TestGetMetricsSummary verifies the endpoint returns the collector's cached values.
+func TestGetMetricsSummary(t *testing.T) {
+	fake := &fakeRDClient{user: &realdebrid.User{Points: 7}}
+	deps := &Dependencies{RDClient: fake}
+	deps.Metrics = NewRDCollector(*deps)
+	app := fiber.New()
+	app.Get("/api/metrics/summary", deps.GetMetricsSummary)
+
+	status, body := doRequest(t, app, httptest.NewRequest(http.MethodGet, "/api/metrics/summary", nil))
+	if status != fiber.StatusOK {
+		t.Fatalf("status = %d, want %d", status, fiber.StatusOK)
+	}
+	data, _ := body["data"].(map[string]any)
+	if data["fidelity_points"] != float64(7) {
+		t.Errorf("fidelity_points = %v, want 7 (body %v)", data["fidelity_points"], body)
+	}
+}
+
+// TestForceScrape_BypassesCache verifies a forced scrape calls RD again while the
+// cache is still fresh and resets lastScrape.
+func TestForceScrape_BypassesCache(t *testing.T) {
+	fake := &fakeRDClient{user: &realdebrid.User{Points: 1}}
+	collector := NewRDCollector(Dependencies{RDClient: fake})
+	collector.Summary()
+
+	stale := time.Now().Add(-time.Minute)
+	collector.lastScrape = stale
+	fake.user = &realdebrid.User{Points: 2}
+
+	if took := collector.ForceScrape(); took < 0 {
+		t.Errorf("ForceScrape() = %v, want a non-negative duration", took)
+	}
+	if !collector.lastScrape.After(stale) {
+		t.Errorf("lastScrape = %v, want after %v", collector.lastScrape, stale)
+	}
+	if fake.userCalls != 2 {
+		t.Errorf("GetUser called %d times, want 2", fake.userCalls)
+	}
+	if got := collector.Summary().FidelityPoints; got != 2 {
+		t.Errorf("FidelityPoints = %d, want 2 after the forced scrape", got)
+	}
+}
+
+// TestRefreshMetrics verifies the endpoint returns freshly scraped values.
+func TestRefreshMetrics(t *testing.T) {
+	fake := &fakeRDClient{user: &realdebrid.User{Points: 7}}
+	deps := &Dependencies{RDClient: fake}
+	deps.Metrics = NewRDCollector(*deps)
+	deps.Metrics.Summary()
+	fake.user = &realdebrid.User{Points: 8}
+	app := fiber.New()
+	app.Post("/api/metrics/refresh", deps.RefreshMetrics)
+
+	status, body := doRequest(t, app, httptest.NewRequest(http.MethodPost, "/api/metrics/refresh", nil))
+	if status != fiber.StatusOK {
+		t.Fatalf("status = %d, want %d", status, fiber.StatusOK)
+	}
+	data, _ := body["data"].(map[string]any)
+	if data["fidelity_points"] != float64(8) {
+		t.Errorf("fidelity_points = %v, want 8 (body %v)", data["fidelity_points"], body)
+	}
+	if _, ok := body["duration_ms"].(float64); !ok {
+		t.Errorf("duration_ms missing from %v", body)
+	}
+}
+
+// TestAddTorrent_OversizedBody verifies bodies over web.max_body_bytes are rejected unread.
+func TestAddTorrent_OversizedBody(t *testing.T) {
+	fake := &fakeRDClient{}
+	deps := &Dependencies{
+		RDClient: fake,
+		Config:   &config.Config{Web: config.WebConfig{MaxBodyBytes: 64}},
+	}
+	app := fiber.New()
+	app.Post("/api/torrents", deps.AddTorrent)
+
+	magnet := "magnet:?xt=urn:btih:abc&dn=" + strings.Repeat("x", 100)
+	req := httptest.NewRequest(http.MethodPost, "/api/torrents", strings.NewReader(`{"magnet":"`+magnet+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	status, _ := doRequest(t, app, req)
+	if status != fiber.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want %d", status, fiber.StatusRequestEntityTooLarge)
+	}
+	if len(fake.added) != 0 {
+		t.Errorf("AddMagnet called %d times, want 0", len(fake.added))
+	}
+}
+
+// TestNewServer_BodyLimit verifies the configured body limit is applied to the app.
+func TestNewServer_BodyLimit(t *testing.T) {
+	cfg := &config.Config{Web: config.WebConfig{MaxBodyBytes: 1024}}
+	s := NewServer(Dependencies{RDClient: &fakeRDClient{}, Config: cfg})
+	if got := s.app.Config().BodyLimit; got != 1024 {
+		t.Errorf("BodyLimit = %d, want 1024", got)
+	}
+}
+
+// TestServer_Ready verifies the ready signal fires once the listener is bound.
+func TestServer_Ready(t *testing.T) {
+	cfg := &config.Config{Web: config.WebConfig{ListenAddr: "127.0.0.1:0"}}
+	s := NewServer(Dependencies{RDClient: &fakeRDClient{}, Config: cfg})
+
+	select {
+	case <-s.Ready():
+		t.Fatal("Ready() closed before Start()")
+	default:
+	}
+
+	go func() { _ = s.Start() }()
+	select {
+	case <-s.Ready():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Ready() not closed after Start()")
+	}
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+}
+
+// TestUnrestrictLink_HostFilter verifies blocked and non-allowed hosts get 400 without reaching Real-Debrid.
+func TestUnrestrictLink_HostFilter(t *testing.T) {
+	fake := &fakeRDClient{}
+	deps := &Dependencies{
+		RDClient: fake,
+		Config: &config.Config{App: config.AppConfig{
+			BlockedHosts: []string{"bad.example"},
+			AllowedHosts: []string{"example.com", "bad.example"},
+		}},
+	}
+	app := fiber.New()
+	app.Post("/api/unrestrict", deps.UnrestrictLink)
+
+	tests := []struct {
+		link string
+		want int
+	}{
+		{"https://dl.BAD.example/file", fiber.StatusBadRequest},
+		{"https://other.net/file", fiber.StatusBadRequest},
+		{"https://cdn.example.com/file", fiber.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/api/unrestrict", strings.NewReader(`{"link":"`+tt.link+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		if status, _ := doRequest(t, app, req); status != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.link, status, tt.want)
+		}
+	}
+	if len(fake.unrestricted) != 1 || fake.unrestricted[0] != "https://cdn.example.com/file" {
+		t.Errorf("unrestricted = %v, want only the allowed link", fake.unrestricted)
+	}
+}
@@ -0,0 +1,103 @@
+package web
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Component states reported by GET /api/health/detailed
+const (
+	HealthOK       = "ok"
+	HealthDown     = "down"
+	HealthUnknown  = "unknown"  // not checked yet
+	HealthDisabled = "disabled" // not running in this process, e.g. Telegram with --web-only
+)
+
+// healthPingTimeout bounds the database ping of a detailed health check
+const healthPingTimeout = 5 * time.Second
+
+// ComponentHealth is the state of one subsystem
+type ComponentHealth struct {
+	Status    string    `json:"status"`
+	CheckedAt time.Time `json:"checked_at,omitzero"` // when the state was last observed
+	Detail    string    `json:"detail,omitempty"`
+}
+
+// BotHealth is implemented by the Telegram bot to report the subsystems it owns
+type BotHealth interface {
+	TelegramHealth() ComponentHealth          // last update received
+	ProxyHealth() ComponentHealth             // last outbound IP test
+	WorkerHealth() map[string]ComponentHealth // last run of each background worker
+}
+
+// Pinger is the part of the database pool used by the detailed health check
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// HealthReport is the body of GET /api/health/detailed
+type HealthReport struct {
+	Status     string                     `json:"status"` // "ok", or "degraded" when any component is down
+	CheckedAt  time.Time                  `json:"checked_at"`
+	Components map[string]ComponentHealth `json:"components"`
+	Workers    map[string]ComponentHealth `json:"workers,omitempty"`
+}
+
+// BuildHealthReport assembles the report from each component's state
+func BuildHealthReport(now time.Time, components, workers map[string]ComponentHealth) HealthReport {
+	report := HealthReport{Status: HealthOK, CheckedAt: now, Components: components, Workers: workers}
+	for _, set := range []map[string]ComponentHealth{components, workers} {
+		for _, c := range set {
+			if c.Status == HealthDown {
+				report.Status = "degraded"
+			}
+		}
+	}
+	return report
+}
+
+// databaseHealth pings the database
+func (d *Dependencies) databaseHealth(ctx context.Context) ComponentHealth {
+	if d.DB == nil {
+		return ComponentHealth{Status: HealthUnknown}
+	}
+	ctx, cancel := context.WithTimeout(ctx, healthPingTimeout)
+	defer cancel()
+	now := time.Now()
+	if err := d.DB.Ping(ctx); err != nil {
+		return ComponentHealth{Status: HealthDown, CheckedAt: now, Detail: err.Error()}
+	}
+	return ComponentHealth{Status: HealthOK, CheckedAt: now}
+}
+
+// realDebridHealth reports whether the last account lookup of the metrics cache worked
+func (d *Dependencies) realDebridHealth() ComponentHealth {
+	if d.Metrics == nil {
+		return ComponentHealth{Status: HealthUnknown}
+	}
+	checkedAt, err := d.Metrics.UserStatus()
+	if err != nil {
+		return ComponentHealth{Status: HealthDown, CheckedAt: checkedAt, Detail: err.Error()}
+	}
+	return ComponentHealth{Status: HealthOK, CheckedAt: checkedAt}
+}
+
+// GetDetailedHealth reports the state of every subsystem (admin only). Unlike /health it
+// needs authentication, as errors may reveal internal details.
+func (d *Dependencies) GetDetailedHealth(c fiber.Ctx) error {
+	components := map[string]ComponentHealth{
+		"database":   d.databaseHealth(c.Context()),
+		"realdebrid": d.realDebridHealth(),
+		"telegram":   {Status: HealthDisabled},
+		"proxy":      {Status: HealthDisabled},
+	}
+	var workers map[string]ComponentHealth
+	if d.Bot != nil {
+		components["telegram"] = d.Bot.TelegramHealth()
+		components["proxy"] = d.Bot.ProxyHealth()
+		workers = d.Bot.WorkerHealth()
+	}
+	return c.JSON(fiber.Map{"success": true, "data": BuildHealthReport(time.Now(), components, workers)})
+}
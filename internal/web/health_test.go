@@ -0,0 +1,82 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crazyuploader/rdctl-bot/internal/realdebrid"
+	"github.com/gofiber/fiber/v3"
+)
+
+// stubPinger is a Pinger returning err.
+type stubPinger struct{ err error }
+
+func (p stubPinger) Ping(context.Context) error { return p.err }
+
+// stubBotHealth is a BotHealth with fixed component states.
+type stubBotHealth struct {
+	telegram, proxy ComponentHealth
+	workers         map[string]ComponentHealth
+}
+
+func (s stubBotHealth) TelegramHealth() ComponentHealth          { return s.telegram }
+func (s stubBotHealth) ProxyHealth() ComponentHealth             { return s.proxy }
+func (s stubBotHealth) WorkerHealth() map[string]ComponentHealth { return s.workers }
+
+// TestGetDetailedHealth verifies the report combines every component and is degraded
+// when one of them is down.
+func TestGetDetailedHealth(t *testing.T) {
+	ran := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	deps := &Dependencies{
+		RDClient: &fakeRDClient{user: &realdebrid.User{Premium: 3600}},
+		DB:       stubPinger{err: errors.New("connection refused")},
+		Bot: stubBotHealth{
+			telegram: ComponentHealth{Status: HealthOK, CheckedAt: ran},
+			proxy:    ComponentHealth{Status: HealthOK, Detail: "outbound IP 203.0.113.7"},
+			workers:  map[string]ComponentHealth{"auto_delete": {Status: HealthOK, CheckedAt: ran}},
+		},
+	}
+	deps.Metrics = NewRDCollector(*deps)
+	app := fiber.New()
+	app.Get("/api/health/detailed", deps.GetDetailedHealth)
+
+	status, body := doRequest(t, app, httptest.NewRequest(http.MethodGet, "/api/health/detailed", nil))
+	if status != fiber.StatusOK {
+		t.Fatalf("status = %d, want %d", status, fiber.StatusOK)
+	}
+	data, _ := body["data"].(map[string]any)
+	if data["status"] != "degraded" {
+		t.Errorf("status = %v, want degraded", data["status"])
+	}
+	components, _ := data["components"].(map[string]any)
+	want := map[string]string{"database": HealthDown, "realdebrid": HealthOK, "telegram": HealthOK, "proxy": HealthOK}
+	for name, wantStatus := range want {
+		c, _ := components[name].(map[string]any)
+		if c["status"] != wantStatus {
+			t.Errorf("%s = %v, want status %q", name, c, wantStatus)
+		}
+	}
+	if db, _ := components["database"].(map[string]any); db["detail"] != "connection refused" {
+		t.Errorf("database detail = %v", db["detail"])
+	}
+	workers, _ := data["workers"].(map[string]any)
+	if w, _ := workers["auto_delete"].(map[string]any); w["checked_at"] != "2026-01-02T03:04:05Z" {
+		t.Errorf("auto_delete worker = %v", w)
+	}
+}
+
+// TestBuildHealthReport_WebOnly verifies disabled and unknown components don't degrade the report.
+func TestBuildHealthReport_WebOnly(t *testing.T) {
+	report := BuildHealthReport(time.Now(), map[string]ComponentHealth{
+		"database": {Status: HealthOK},
+		"telegram": {Status: HealthDisabled},
+		"proxy":    {Status: HealthUnknown},
+	}, nil)
+	if report.Status != HealthOK {
+		t.Errorf("status = %q, want ok", report.Status)
+	}
+}
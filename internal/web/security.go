@@ -2,6 +2,7 @@ package web
 
 import (
 	"log"
+	"sort"
 	"sync"
 	"time"
 
@@ -18,6 +19,12 @@ type IPManager struct {
 	failWindow   time.Duration
 }
 
+// Ban is a banned IP and when its ban ends
+type Ban struct {
+	IP        string    `json:"ip"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
 // NewIPManager creates a new IPManager
 func NewIPManager(banDurationSeconds, failLimit, failWindowSeconds int) *IPManager {
 	return &IPManager{
@@ -93,3 +100,44 @@ func (m *IPManager) IsBanned(ip string) bool {
 
 	return true
 }
+
+// ListBans returns the IPs currently banned, soonest to expire first. Expired bans are dropped.
+func (m *IPManager) ListBans() []Ban {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	bans := make([]Ban, 0, len(m.bannedIPs))
+	for ip, expiry := range m.bannedIPs {
+		if now.After(expiry) {
+			delete(m.bannedIPs, ip)
+			continue
+		}
+		bans = append(bans, Ban{IP: ip, ExpiresAt: expiry})
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		if !bans[i].ExpiresAt.Equal(bans[j].ExpiresAt) {
+			return bans[i].ExpiresAt.Before(bans[j].ExpiresAt)
+		}
+		return bans[i].IP < bans[j].IP
+	})
+	return bans
+}
+
+// Unban lifts the ban of ip and forgets its recent auth failures, so it isn't banned
+// again on its next mistake. It reports whether ip was banned.
+func (m *IPManager) Unban(ip string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expiry, banned := m.bannedIPs[ip]
+	delete(m.bannedIPs, ip)
+	delete(m.authFailures, ip)
+	if banned && time.Now().After(expiry) {
+		return false
+	}
+	if banned {
+		log.Printf("Unbanned IP %s", ip)
+	}
+	return banned
+}
@@ -0,0 +1,80 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestIPManager_ListBans verifies bans are listed soonest to expire first and expired ones dropped.
+func TestIPManager_ListBans(t *testing.T) {
+	m := NewIPManager(60, 1, 60)
+	now := time.Now()
+	m.bannedIPs["10.0.0.2"] = now.Add(2 * time.Minute)
+	m.bannedIPs["10.0.0.1"] = now.Add(time.Minute)
+	m.bannedIPs["10.0.0.3"] = now.Add(-time.Minute)
+
+	bans := m.ListBans()
+	if len(bans) != 2 || bans[0].IP != "10.0.0.1" || bans[1].IP != "10.0.0.2" {
+		t.Fatalf("ListBans() = %+v, want 10.0.0.1 then 10.0.0.2", bans)
+	}
+	if _, ok := m.bannedIPs["10.0.0.3"]; ok {
+		t.Error("ListBans() kept an expired ban")
+	}
+}
+
+// TestIPManager_Unban verifies an unbanned IP is let through and starts with a clean failure count.
+func TestIPManager_Unban(t *testing.T) {
+	m := NewIPManager(60, 2, 60)
+	m.RegisterAuthFailure("10.0.0.1")
+	m.RegisterAuthFailure("10.0.0.1")
+	if !m.IsBanned("10.0.0.1") {
+		t.Fatal("IP not banned after reaching the failure limit")
+	}
+
+	if !m.Unban("10.0.0.1") {
+		t.Error("Unban() = false for a banned IP")
+	}
+	if m.IsBanned("10.0.0.1") {
+		t.Error("IP still banned after Unban()")
+	}
+	m.RegisterAuthFailure("10.0.0.1")
+	if m.IsBanned("10.0.0.1") {
+		t.Error("IP banned again after a single failure")
+	}
+	if m.Unban("10.0.0.9") {
+		t.Error("Unban() = true for an IP that was never banned")
+	}
+}
+
+// TestBanEndpoints verifies bans can be listed and lifted over the API.
+func TestBanEndpoints(t *testing.T) {
+	m := NewIPManager(60, 1, 60)
+	m.RegisterAuthFailure("10.0.0.1")
+	deps := &Dependencies{IPManager: m}
+	app := fiber.New()
+	app.Get("/api/security/bans", deps.GetBans)
+	app.Delete("/api/security/bans/:ip", deps.DeleteBan)
+
+	status, body := doRequest(t, app, httptest.NewRequest(http.MethodGet, "/api/security/bans", nil))
+	data, _ := body["data"].([]any)
+	if status != fiber.StatusOK || len(data) != 1 || data[0].(map[string]any)["ip"] != "10.0.0.1" {
+		t.Fatalf("GET bans = %d %v, want 10.0.0.1 listed", status, body)
+	}
+
+	if status, _ := doRequest(t, app, httptest.NewRequest(http.MethodDelete, "/api/security/bans/not-an-ip", nil)); status != fiber.StatusBadRequest {
+		t.Errorf("DELETE invalid IP status = %d, want 400", status)
+	}
+	if status, _ := doRequest(t, app, httptest.NewRequest(http.MethodDelete, "/api/security/bans/10.0.0.1", nil)); status != fiber.StatusOK {
+		t.Errorf("DELETE ban status = %d, want 200", status)
+	}
+	if status, _ := doRequest(t, app, httptest.NewRequest(http.MethodDelete, "/api/security/bans/10.0.0.1", nil)); status != fiber.StatusNotFound {
+		t.Errorf("DELETE lifted ban status = %d, want 404", status)
+	}
+	if m.IsBanned("10.0.0.1") {
+		t.Error("IP still banned after DELETE")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"io/fs"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/Jeckerson/fiberprometheus/v3"
@@ -34,9 +35,26 @@ import (
 //go:embed static/*
 var staticFiles embed.FS
 
+// RealDebridClient defines the Real-Debrid operations used by the web handlers
+// and the metrics collector. This allows for mocking in unit tests.
+type RealDebridClient interface {
+	GetTorrents(limit, offset int) ([]realdebrid.Torrent, error)
+	GetTorrentsWithCount(limit, offset int) (*realdebrid.TorrentsResult, error)
+	GetActiveCount() (*realdebrid.ActiveCount, error)
+	GetTorrentInfo(torrentID string) (*realdebrid.Torrent, error)
+	AddMagnet(magnetURL string) (*realdebrid.AddMagnetResponse, error)
+	SelectAllFiles(torrentID string) error
+	DeleteTorrent(torrentID string) error
+	GetUser() (*realdebrid.User, error)
+	GetDownloadsWithCount(limit, offset int) (*realdebrid.DownloadsResult, error)
+	UnrestrictLink(link string) (*realdebrid.UnrestrictedLink, error)
+	DeleteDownload(downloadID string) error
+	IsDomainSupported(domain string) (bool, string, error)
+}
+
 // Dependencies struct to hold all dependencies for the web handlers
 type Dependencies struct {
-	RDClient     *realdebrid.Client
+	RDClient     RealDebridClient
 	UserRepo     *db.UserRepository
 	ActivityRepo *db.ActivityRepository
 	TorrentRepo  *db.TorrentRepository
@@ -46,6 +64,14 @@ type Dependencies struct {
 	KeptRepo     *db.KeptTorrentRepository
 	Config       *config.Config
 	TokenStore   *TokenStore
+	Metrics      *RDCollector // Shared Real-Debrid metrics cache; created by NewServer when nil
+	IPManager    *IPManager   // Auth failure tracking and IP bans; created by NewServer when nil
+	DB           Pinger       // Database pool, pinged by the detailed health check
+	Bot          BotHealth    // Telegram bot state for the detailed health check; nil with --web-only
+
+	// selectSlots bounds concurrent file-selection waits in AddTorrent; created by
+	// NewServer from web.select_files.concurrency, unbounded when nil
+	selectSlots chan struct{}
 }
 
 // Server represents the web server instance
@@ -53,11 +79,13 @@ type Server struct {
 	app        *fiber.App
 	config     *config.Config
 	tokenStore *TokenStore
+	ready      chan struct{} // closed once the listener is bound
 }
 
 // NewServer creates a new web server instance
 func NewServer(deps Dependencies) *Server {
 	app := fiber.New(fiber.Config{
+		BodyLimit:   deps.maxBodyBytes(),
 		ProxyHeader: "X-Forwarded-For",
 		TrustProxy:  true,
 		TrustProxyConfig: fiber.TrustProxyConfig{
@@ -70,10 +98,11 @@ func NewServer(deps Dependencies) *Server {
 			// Retrieve the custom status code if it's a *fiber.Error
 			var e *fiber.Error
 			var rdErr *realdebrid.APIError
+			var nonJSONErr *realdebrid.NonJSONResponseError
 
 			if errors.As(err, &e) {
 				code = e.Code
-			} else if errors.As(err, &rdErr) {
+			} else if errors.As(err, &rdErr) || errors.As(err, &nonJSONErr) {
 				// Map Real-Debrid API errors to 502 (Bad Gateway) to distinguish from internal server errors
 				// forcing the message to be shown below
 				code = fiber.StatusBadGateway
@@ -84,7 +113,7 @@ func NewServer(deps Dependencies) *Server {
 
 			// Sanitize error message for the client
 			var message string
-			if code < 500 || rdErr != nil {
+			if code < 500 || rdErr != nil || nonJSONErr != nil {
 				// Show message for client errors (< 500) or upstream API errors
 				message = err.Error()
 			} else {
@@ -114,6 +143,14 @@ func NewServer(deps Dependencies) *Server {
 	app.Use(recover.New())
 	app.Use(cors.New())
 
+	// The collector scrapes lazily, so it is cheap to create even when Prometheus is disabled
+	if deps.Metrics == nil {
+		deps.Metrics = NewRDCollector(deps)
+	}
+	if deps.selectSlots == nil && deps.Config.Web.SelectFiles.Concurrency > 0 {
+		deps.selectSlots = make(chan struct{}, deps.Config.Web.SelectFiles.Concurrency)
+	}
+
 	// Prometheus Metrics
 	if deps.Config.Web.Metrics.Enabled {
 		// Create a dedicated registry to avoid global state and double-registration panics
@@ -128,8 +165,7 @@ func NewServer(deps Dependencies) *Server {
 		app.Use(fiberProm.Middleware)
 
 		// Register custom collector
-		collector := NewRDCollector(deps)
-		registry.MustRegister(collector)
+		registry.MustRegister(deps.Metrics)
 
 		// Serve our dedicated registry
 		hashedPassword := sha256.Sum256([]byte(deps.Config.Web.Metrics.Password))
@@ -141,11 +177,14 @@ func NewServer(deps Dependencies) *Server {
 	}
 
 	// Initialize IP Manager for security
-	ipManager := NewIPManager(
-		deps.Config.Web.Limiter.BanDurationSeconds,
-		deps.Config.Web.Limiter.AuthFailLimit,
-		deps.Config.Web.Limiter.AuthFailWindow,
-	)
+	if deps.IPManager == nil {
+		deps.IPManager = NewIPManager(
+			deps.Config.Web.Limiter.BanDurationSeconds,
+			deps.Config.Web.Limiter.AuthFailLimit,
+			deps.Config.Web.Limiter.AuthFailWindow,
+		)
+	}
+	ipManager := deps.IPManager
 
 	// Security headers on all responses
 	app.Use(func(c fiber.Ctx) error {
@@ -200,7 +239,13 @@ func NewServer(deps Dependencies) *Server {
 	api.Post("/unrestrict", deps.UnrestrictLink)
 	api.Get("/check-domain", deps.CheckDomain)
 	api.Get("/stats", deps.GetStats)
+	api.Get("/capacity", deps.GetCapacity)
 	api.Get("/stats/user/:id", deps.GetUserStats)
+	api.Get("/stats/global", AdminOnly(deps.TokenStore, ipManager), deps.GetGlobalStats)
+	api.Get("/users", AdminOnly(deps.TokenStore, ipManager), deps.GetUsers)
+	api.Get("/metrics/summary", AdminOnly(deps.TokenStore, ipManager), deps.GetMetricsSummary)
+	api.Post("/metrics/refresh", AdminOnly(deps.TokenStore, ipManager), deps.RefreshMetrics)
+	api.Get("/health/detailed", AdminOnly(deps.TokenStore, ipManager), deps.GetDetailedHealth)
 	api.Get("/kept-torrents", deps.GetKeptTorrents)
 
 	// Keep management (Limits applied in handler)
@@ -215,6 +260,10 @@ func NewServer(deps Dependencies) *Server {
 	api.Get("/settings/autodelete", AdminOnly(deps.TokenStore, ipManager), deps.GetAutoDeleteSetting)
 	api.Put("/settings/autodelete", AdminOnly(deps.TokenStore, ipManager), deps.SetAutoDeleteSetting)
 
+	// IP bans - Admin only
+	api.Get("/security/bans", AdminOnly(deps.TokenStore, ipManager), deps.GetBans)
+	api.Delete("/security/bans/:ip", AdminOnly(deps.TokenStore, ipManager), deps.DeleteBan)
+
 	// Page routes — serve HTML files for each app page (clean URLs without .html)
 	staticFS, _ := fs.Sub(staticFiles, "static")
 	serveHTML := func(filename string) fiber.Handler {
@@ -242,11 +291,23 @@ func NewServer(deps Dependencies) *Server {
 		Browse: false,
 	}))
 
-	return &Server{
+	s := &Server{
 		app:        app,
 		config:     deps.Config,
 		tokenStore: deps.TokenStore,
+		ready:      make(chan struct{}),
 	}
+	var readyOnce sync.Once
+	app.Hooks().OnListen(func(fiber.ListenData) error {
+		readyOnce.Do(func() { close(s.ready) })
+		return nil
+	})
+	return s
+}
+
+// Ready returns a channel that is closed once the server is accepting connections
+func (s *Server) Ready() <-chan struct{} {
+	return s.ready
 }
 
 // Start starts the web server
@@ -220,12 +220,13 @@
       btn.textContent = "…";
 
       try {
-        await App.apiFetch("/torrents", {
+        var r = await App.apiFetch("/torrents", {
           method: "POST",
           body: JSON.stringify({ magnet: link }),
         });
         inp.value = "";
-        App.showToast("Torrent added", "success");
+        if (r.warning) App.showToast(r.warning, "error");
+        else App.showToast("Torrent added", "success");
       } catch (e) {
         App.showToast(e.message || "Failed to add torrent", "error");
       } finally {
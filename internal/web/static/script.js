@@ -912,12 +912,13 @@ function setupEventListeners() {
 
       btn.disabled = true;
       try {
-        await apiFetch(`${API_BASE_URL}/torrents`, {
+        const result = await apiFetch(`${API_BASE_URL}/torrents`, {
           method: "POST",
           body: JSON.stringify({ magnet: link }),
         });
         input.value = "";
-        showToast("Torrent added", "success");
+        if (result.warning) showToast(result.warning, "error");
+        else showToast("Torrent added", "success");
         fetchTorrents(true); // Reset to get fresh data including new torrent
       } catch (error) {
         showToast(